@@ -0,0 +1,146 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Health is a struct that represents the health of the server, the status is ok or unavailable
+type Health struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Principal is a struct that represents the caller, as authenticated by the server
+type Principal struct {
+	Subject string   `json:"subject"`
+	Method  string   `json:"method"`
+	KeyID   string   `json:"key_id,omitempty"`
+	Roles   []string `json:"roles"`
+	Tenant  string   `json:"tenant,omitempty"`
+}
+
+// CacheStats is a struct that represents the statistics of the cache of the vehicles of the tenant
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Expirations   uint64 `json:"expirations"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+// Aggregate is a struct that represents the running statistics of an aggregate
+type Aggregate struct {
+	Count      int     `json:"count"`
+	Sum        float64 `json:"sum"`
+	SumSquares float64 `json:"sum_squares"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+}
+
+// AggregateMismatch is a struct that represents an aggregate inconsistent with the vehicles
+type AggregateMismatch struct {
+	Group    string    `json:"group"`
+	Key      string    `json:"key"`
+	Metric   string    `json:"metric"`
+	Expected Aggregate `json:"expected"`
+	Actual   Aggregate `json:"actual"`
+}
+
+// Tenant is a struct that represents a tenant and its number of vehicles
+type Tenant struct {
+	ID       string `json:"id"`
+	Vehicles int    `json:"vehicles"`
+}
+
+// logLevel is a struct that represents the level of the logger of the server
+type logLevel struct {
+	Level string `json:"level"`
+}
+
+// Healthz is a method that returns the liveness of the server
+func (c *Client) Healthz(ctx context.Context) (h *Health, err error) {
+	err = c.get(ctx, "/healthz", nil, &h)
+	return
+}
+
+// Readyz is a method that returns the readiness of the server
+// - a server not ready is not an error, its status is unavailable and the checks tell why
+func (c *Client) Readyz(ctx context.Context) (h *Health, err error) {
+	err = c.do(ctx, request{method: http.MethodGet, path: "/readyz", accept: []int{http.StatusServiceUnavailable}}, &h)
+	return
+}
+
+// WhoAmI is a method that returns the caller, as authenticated by the server
+// - it fails with ErrNotFound if the authentication is disabled
+func (c *Client) WhoAmI(ctx context.Context) (p *Principal, err error) {
+	var body envelope[*Principal]
+	if err = c.get(ctx, "/auth/whoami", nil, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// CacheStats is a method that returns the statistics of the cache of the vehicles of the tenant
+// - it fails with ErrNotFound if the cache is disabled
+func (c *Client) CacheStats(ctx context.Context) (s *CacheStats, err error) {
+	var body envelope[*CacheStats]
+	if err = c.get(ctx, "/admin/cache", nil, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// CheckAggregates is a method that returns the aggregates of the tenant inconsistent with its vehicles, none if consistent
+func (c *Client) CheckAggregates(ctx context.Context) (mismatches []AggregateMismatch, err error) {
+	var body envelope[[]AggregateMismatch]
+	if err = c.get(ctx, "/admin/aggregates/check", nil, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// LogLevel is a method that returns the level of the logger of the server
+func (c *Client) LogLevel(ctx context.Context) (level string, err error) {
+	var body envelope[logLevel]
+	if err = c.get(ctx, "/admin/log_level", nil, &body); err != nil {
+		return
+	}
+	return body.Data.Level, nil
+}
+
+// SetLogLevel is a method that changes the level of the logger of the server: debug, info, warn or error
+func (c *Client) SetLogLevel(ctx context.Context, level string) (err error) {
+	return c.do(ctx, request{method: http.MethodPut, path: "/admin/log_level", body: logLevel{Level: level}, retry: true}, nil)
+}
+
+// Tenants is a method that returns the tenants and their number of vehicles
+func (c *Client) Tenants(ctx context.Context) (tenants []Tenant, err error) {
+	var body envelope[[]Tenant]
+	if err = c.get(ctx, "/admin/tenants", nil, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// CreateTenant is a method that creates a tenant without vehicles
+func (c *Client) CreateTenant(ctx context.Context, id string) (err error) {
+	return c.create(ctx, "/admin/tenants", Tenant{ID: id}, nil)
+}
+
+// SeedTenant is a method that loads the vehicles of a JSON file of the seed directory of the server into a tenant
+// - filePath is relative to the seed directory, the number of vehicles loaded is returned
+func (c *Client) SeedTenant(ctx context.Context, id, filePath string) (n int, err error) {
+	var body envelope[Tenant]
+	err = c.do(ctx, request{
+		method: http.MethodPost,
+		path:   "/admin/tenants/" + url.PathEscape(id) + "/seed",
+		body:   map[string]string{"file_path": filePath},
+	}, &body)
+	if err != nil {
+		return
+	}
+	return body.Data.Vehicles, nil
+}
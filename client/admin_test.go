@@ -0,0 +1,123 @@
+package client
+
+import (
+	"app/internal/application"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Admin(t *testing.T) {
+	ctx := context.Background()
+	srv := newTestServer(t, application.ConfigServerChi{
+		AuthAPIKeysFile: writeAPIKeys(t),
+		CacheCapacity:   100,
+		TenantSeedDir:   "../docs/db",
+	})
+	cl := newTestClient(t, srv, Config{APIKey: testKeys["system-admin"]})
+
+	t.Run("case 1: health", func(t *testing.T) {
+		// act
+		live, err1 := cl.Healthz(ctx)
+		ready, err2 := cl.Readyz(ctx)
+
+		// assert
+		require.NoError(t, err1)
+		require.Equal(t, "ok", live.Status)
+		require.NoError(t, err2)
+		require.Equal(t, "ok", ready.Status)
+		require.Equal(t, "ok", ready.Checks["repository"])
+	})
+
+	t.Run("case 2: who am I", func(t *testing.T) {
+		// act
+		p, err := cl.WhoAmI(ctx)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, &Principal{Subject: "system-admin", Method: "api_key", KeyID: "system-admin", Roles: []string{"system-admin"}}, p)
+	})
+
+	t.Run("case 3: cache statistics", func(t *testing.T) {
+		// arrange
+		_, err := cl.GetVehicle(ctx, 1, nil, nil)
+		require.NoError(t, err)
+		_, err = cl.GetVehicle(ctx, 1, nil, nil)
+		require.NoError(t, err)
+
+		// act
+		s, err := cl.CacheStats(ctx)
+
+		// assert
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, s.Hits, uint64(1))
+		require.GreaterOrEqual(t, s.Misses, uint64(1))
+		require.GreaterOrEqual(t, s.Entries, 1)
+	})
+
+	t.Run("case 4: aggregates", func(t *testing.T) {
+		// act
+		mismatches, err := cl.CheckAggregates(ctx)
+
+		// assert
+		require.NoError(t, err)
+		require.Empty(t, mismatches)
+	})
+
+	t.Run("case 5: log level", func(t *testing.T) {
+		// act and assert
+		level, err := cl.LogLevel(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "ERROR", level)
+
+		require.NoError(t, cl.SetLogLevel(ctx, "warn"))
+		level, err = cl.LogLevel(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "WARN", level)
+
+		require.ErrorIs(t, cl.SetLogLevel(ctx, "loud"), ErrBadRequest)
+		require.NoError(t, cl.SetLogLevel(ctx, "error"))
+	})
+
+	t.Run("case 6: tenants", func(t *testing.T) {
+		// act and assert
+		require.NoError(t, cl.CreateTenant(ctx, "acme"))
+		require.ErrorIs(t, cl.CreateTenant(ctx, "acme"), ErrConflict)
+
+		n, err := cl.SeedTenant(ctx, "acme", "vehicles_100.json")
+		require.NoError(t, err)
+		require.Equal(t, 100, n)
+
+		tenants, err := cl.Tenants(ctx)
+		require.NoError(t, err)
+		require.Contains(t, tenants, Tenant{ID: "acme", Vehicles: 100})
+
+		_, err = cl.SeedTenant(ctx, "unknown", "vehicles_100.json")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("case 7: the vehicles of a tenant", func(t *testing.T) {
+		// arrange
+		require.NoError(t, cl.CreateTenant(ctx, "empty"))
+		tc := newTestClient(t, srv, Config{APIKey: testKeys["system-admin"], Tenant: "empty"})
+
+		// act
+		p, err := tc.ListVehicles(ctx, ListOptions{})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 0, p.Total)
+	})
+
+	t.Run("case 8: who am I without authentication", func(t *testing.T) {
+		// arrange
+		open := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		_, err := open.WhoAmI(ctx)
+
+		// assert
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
@@ -0,0 +1,301 @@
+// Package client is a Go client of the HTTP API of the vehicles
+// - the vehicles are served by the v2 routes, the deprecated v1 routes are not covered
+// - failed requests are retried with backoff, creates carry an idempotency key so retries do not duplicate them
+// - errors answered by the server are returned as *Error, matching the error codes of the server
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	mrand "math/rand"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultMaxRetries is the number of retries of a failed request if not configured
+	defaultMaxRetries = 3
+	// defaultMinBackoff is the wait before the first retry if not configured
+	defaultMinBackoff = 100 * time.Millisecond
+	// defaultMaxBackoff is the maximum wait between retries if not configured
+	defaultMaxBackoff = 5 * time.Second
+	// defaultUserAgent is the user agent of the requests if not configured
+	defaultUserAgent = "vehicles-go-client"
+)
+
+// Config is a struct that represents the configuration of a client
+// - zero values keep the defaults
+type Config struct {
+	// BaseURL is the URL of the server, e.g. http://localhost:8080
+	BaseURL string
+	// HTTPClient sends the requests, http.DefaultClient by default
+	// - its timeout also bounds the streams of Watch, leave it unset to watch for long
+	HTTPClient *http.Client
+	// APIKey is the API key of the caller, sent as the X-API-Key header
+	APIKey string
+	// Token is the bearer token of the caller, sent as the Authorization header
+	Token string
+	// Tenant is the tenant of the vehicles, sent as the X-Tenant-ID header, the tenant of the caller by default
+	Tenant string
+	// Units is the unit system of the measures, metric (km/h, kg, cm) by default or imperial (mph, lb, in)
+	Units string
+	// MaxRetries is the number of retries of a failed request, 3 by default, negative disables retries
+	MaxRetries int
+	// MinBackoff is the wait before the first retry, doubled on every retry, 100ms by default
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum wait between retries, 5s by default
+	MaxBackoff time.Duration
+	// UserAgent is the user agent of the requests
+	UserAgent string
+}
+
+// NewClient is a function that returns a new instance of Client
+func NewClient(cfg Config) (c *Client, err error) {
+	// default config
+	defaultCfg := Config{
+		HTTPClient: http.DefaultClient,
+		MaxRetries: defaultMaxRetries,
+		MinBackoff: defaultMinBackoff,
+		MaxBackoff: defaultMaxBackoff,
+		UserAgent:  defaultUserAgent,
+	}
+	if cfg.HTTPClient != nil {
+		defaultCfg.HTTPClient = cfg.HTTPClient
+	}
+	if cfg.MaxRetries != 0 {
+		defaultCfg.MaxRetries = max(cfg.MaxRetries, 0)
+	}
+	if cfg.MinBackoff > 0 {
+		defaultCfg.MinBackoff = cfg.MinBackoff
+	}
+	if cfg.MaxBackoff > 0 {
+		defaultCfg.MaxBackoff = cfg.MaxBackoff
+	}
+	if cfg.UserAgent != "" {
+		defaultCfg.UserAgent = cfg.UserAgent
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(cfg.BaseURL, "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, errors.New("client: invalid base url " + strconv.Quote(cfg.BaseURL))
+	}
+	switch strings.ToLower(cfg.Units) {
+	case "", "metric", "imperial":
+	default:
+		return nil, errors.New("client: invalid units " + strconv.Quote(cfg.Units))
+	}
+
+	c = &Client{
+		base:       base,
+		http:       defaultCfg.HTTPClient,
+		apiKey:     cfg.APIKey,
+		token:      cfg.Token,
+		tenant:     cfg.Tenant,
+		units:      strings.ToLower(cfg.Units),
+		maxRetries: defaultCfg.MaxRetries,
+		minBackoff: defaultCfg.MinBackoff,
+		maxBackoff: defaultCfg.MaxBackoff,
+		userAgent:  defaultCfg.UserAgent,
+	}
+	return
+}
+
+// Client is a struct that represents a client of the HTTP API of the vehicles, safe for concurrent use
+type Client struct {
+	// base is the URL of the server
+	base *url.URL
+	// http sends the requests
+	http *http.Client
+	// apiKey and token are the credentials of the caller, if given
+	apiKey string
+	token  string
+	// tenant is the tenant of the vehicles, if given
+	tenant string
+	// units is the unit system of the measures, if given
+	units string
+	// maxRetries is the number of retries of a failed request
+	maxRetries int
+	// minBackoff and maxBackoff bound the wait between retries
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// userAgent is the user agent of the requests
+	userAgent string
+}
+
+// key is the type of the keys of the context
+type key int
+
+// keyIdempotency is the key of the idempotency key of a request
+const keyIdempotency key = iota
+
+// WithIdempotencyKey is a function that returns a context whose create requests carry the idempotency key
+// - by default every create carries a new key, reused by its retries only
+// - a key of your own makes the create idempotent across calls, e.g. across restarts of a job
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyIdempotency, key)
+}
+
+// newIdempotencyKey is a function that returns a random idempotency key
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// request is a struct that represents a request to the server
+type request struct {
+	// method and path are the method and the path of the request, relative to the base URL
+	method string
+	path   string
+	// query are the query parameters of the request
+	query url.Values
+	// body is encoded in JSON as the body of the request, if not nil
+	body any
+	// header are the headers of the request, beyond the ones of the client
+	header http.Header
+	// accept are the status codes answered with a body of the response instead of an error, beyond 2xx
+	accept []int
+	// retry is true if the request may be retried, e.g. it is idempotent
+	retry bool
+}
+
+// send is a method that sends a request, retrying it with backoff while it fails with a transient error
+// - transient errors are network errors, 429 Too Many Requests, 502, 503 and 504, Retry-After is honoured
+// - the response is the response of the last try, its body must be closed
+func (c *Client) send(ctx context.Context, req request) (res *http.Response, err error) {
+	// request
+	u := *c.base
+	u.Path += req.path
+	u.RawQuery = req.query.Encode()
+	var body []byte
+	if req.body != nil {
+		if body, err = json.Marshal(req.body); err != nil {
+			return
+		}
+	}
+	header := make(http.Header)
+	header.Set("Accept", "application/json")
+	header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		header.Set("X-API-Key", c.apiKey)
+	}
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.tenant != "" {
+		header.Set("X-Tenant-ID", c.tenant)
+	}
+	if c.units != "" {
+		header.Set("Accept-Units", c.units)
+	}
+	for name, values := range req.header {
+		header[name] = values
+	}
+
+	for attempt := 0; ; attempt++ {
+		// try
+		r, e := http.NewRequestWithContext(ctx, req.method, u.String(), bytes.NewReader(body))
+		if e != nil {
+			return nil, e
+		}
+		r.Header = header.Clone()
+		res, err = c.http.Do(r)
+
+		// result
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		case res.StatusCode < 300 || slices.Contains(req.accept, res.StatusCode):
+			return
+		default:
+			err = responseError(res)
+			res.Body.Close()
+			res = nil
+			var e *Error
+			if errors.As(err, &e) {
+				if !e.Temporary() {
+					return
+				}
+				wait = e.RetryAfter
+			}
+		}
+		if !req.retry || attempt >= c.maxRetries {
+			return
+		}
+
+		// backoff
+		// - exponential with jitter, so the retries of many clients spread, at least the Retry-After of the server
+		backoff := min(c.minBackoff<<attempt, c.maxBackoff)
+		backoff = backoff/2 + time.Duration(mrand.Int63n(int64(backoff/2)+1))
+		timer := time.NewTimer(max(backoff, wait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// do is a method that sends a request and decodes the JSON body of the response into out, if not nil
+func (c *Client) do(ctx context.Context, req request, out any) (err error) {
+	res, err := c.send(ctx, req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, res.Body)
+		return
+	}
+	if err = json.NewDecoder(res.Body).Decode(out); err != nil {
+		return errors.New("client: invalid response body: " + err.Error())
+	}
+	return
+}
+
+// get is a method that sends a GET request, retried on transient errors
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	return c.do(ctx, request{method: http.MethodGet, path: path, query: query, retry: true}, out)
+}
+
+// create is a method that sends a POST request creating a resource
+// - it carries an idempotency key, so it is retried on transient errors without creating the resource twice
+func (c *Client) create(ctx context.Context, path string, body any, out any) error {
+	key, ok := ctx.Value(keyIdempotency).(string)
+	if !ok || key == "" {
+		key = newIdempotencyKey()
+	}
+	header := http.Header{"Idempotency-Key": {key}}
+	return c.do(ctx, request{method: http.MethodPost, path: path, body: body, header: header, retry: true}, out)
+}
+
+// envelope is a struct that represents the body of a response: the data, and the metadata of a collection
+// - the v2 routes write meta, the others a message instead
+type envelope[T any] struct {
+	Data T     `json:"data"`
+	Meta *meta `json:"meta"`
+}
+
+// meta is a struct that represents the metadata of a collection
+type meta struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
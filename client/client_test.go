@@ -0,0 +1,505 @@
+package client
+
+import (
+	"app/internal/application"
+	"app/internal/auth"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLoaderFilePath is the path of the sample vehicles, relative to the directory of the package
+const testLoaderFilePath = "../docs/db/vehicles_100.json"
+
+// testKeys are the API keys of the tests, by role of the default policy
+var testKeys = map[string]string{
+	"viewer":       "viewer-key",
+	"editor":       "editor-key",
+	"fleet-admin":  "fleet-admin-key",
+	"system-admin": "system-admin-key",
+}
+
+// writeAPIKeys is a function that writes a file of the API keys of testKeys, each of a principal named by its role
+func writeAPIKeys(t *testing.T) (path string) {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("keys:\n")
+	for role, key := range testKeys {
+		fmt.Fprintf(&b, "  - id: %s\n    subject: %s\n    hash: %s\n    roles: [%s]\n", role, role, auth.HashAPIKey(key), role)
+	}
+	path = filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+	return
+}
+
+// newTestServer is a function that returns a server of the sample vehicles with the configuration, closed with the test
+// - the handler of the server is wrapped by the middlewares, if given
+// - authentication is disabled unless API keys are given
+func newTestServer(t *testing.T, cfg application.ConfigServerChi, mws ...func(http.Handler) http.Handler) *httptest.Server {
+	t.Helper()
+
+	if cfg.AuthAPIKeysFile == "" {
+		cfg.AuthDisabled = true
+	}
+	if cfg.LoaderFilePath == "" {
+		cfg.LoaderFilePath = testLoaderFilePath
+	}
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = "error"
+	}
+	h, err := application.NewServerChi(&cfg).Handler()
+	require.NoError(t, err)
+	for _, mw := range mws {
+		h = mw(h)
+	}
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newTestClient is a function that returns a client of the server with the configuration, retrying without waiting long
+func newTestClient(t *testing.T, srv *httptest.Server, cfg Config) *Client {
+	t.Helper()
+
+	cfg.BaseURL = srv.URL
+	cfg.HTTPClient = srv.Client()
+	if cfg.MinBackoff == 0 {
+		cfg.MinBackoff = time.Millisecond
+	}
+	c, err := NewClient(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+// failing is a struct that represents a middleware failing the first requests of a method and a path with a status
+type failing struct {
+	// method and path are the ones of the requests failed
+	method string
+	path   string
+	// status is the status of the failed responses, with the Retry-After header if given
+	status     int
+	retryAfter string
+	// failures is the number of requests failed before they are served
+	failures int
+	// served is true if the failed requests are served before failing, as if their responses were lost
+	served bool
+
+	// mu guards the requests
+	mu sync.Mutex
+	// requests are the requests of the method and the path, the failed ones included
+	requests []*http.Request
+}
+
+// handler is a method that returns the middleware
+func (f *failing) handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != f.method || r.URL.Path != f.path {
+			next.ServeHTTP(w, r)
+			return
+		}
+		f.mu.Lock()
+		f.requests = append(f.requests, r)
+		fail := len(f.requests) <= f.failures
+		f.mu.Unlock()
+
+		if !fail {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if f.served {
+			next.ServeHTTP(httptest.NewRecorder(), r)
+		}
+		if f.retryAfter != "" {
+			w.Header().Set("Retry-After", f.retryAfter)
+		}
+		http.Error(w, http.StatusText(f.status), f.status)
+	})
+}
+
+// attempts is a method that returns the number of requests of the method and the path
+func (f *failing) attempts() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.requests)
+}
+
+// idempotencyKeys is a method that returns the idempotency keys of the requests of the method and the path
+func (f *failing) idempotencyKeys() (keys []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.requests {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+	}
+	return
+}
+
+func TestNewClient(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		err  string
+	}{
+		{name: "case 1: valid", cfg: Config{BaseURL: "http://localhost:8080/"}},
+		{name: "case 2: imperial units", cfg: Config{BaseURL: "https://vehicles.example.com", Units: "Imperial"}},
+		{name: "case 3: missing base url", cfg: Config{}, err: `client: invalid base url ""`},
+		{name: "case 4: base url without scheme", cfg: Config{BaseURL: "localhost:8080"}, err: `client: invalid base url "localhost:8080"`},
+		{name: "case 5: unknown units", cfg: Config{BaseURL: "http://localhost", Units: "nautical"}, err: `client: invalid units "nautical"`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			_, err := NewClient(c.cfg)
+
+			// assert
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		retryAfter string
+		failures   int
+		maxRetries int
+		// attempts is the number of requests sent
+		attempts int
+		// err is the error returned, nil if the vehicle is returned
+		err error
+	}{
+		{name: "case 1: too many requests", status: http.StatusTooManyRequests, failures: 2, attempts: 3},
+		{name: "case 2: bad gateway", status: http.StatusBadGateway, failures: 2, attempts: 3},
+		{name: "case 3: service unavailable", status: http.StatusServiceUnavailable, failures: 2, attempts: 3},
+		{name: "case 4: gateway timeout", status: http.StatusGatewayTimeout, failures: 2, attempts: 3},
+		{name: "case 5: retries exhausted", status: http.StatusServiceUnavailable, failures: 10, attempts: 4, err: ErrUnavailable},
+		{name: "case 6: rate limited once the retries are exhausted", status: http.StatusTooManyRequests, failures: 10, maxRetries: 1, attempts: 2, err: ErrRateLimited},
+		{name: "case 7: internal errors are not retried", status: http.StatusInternalServerError, failures: 1, attempts: 1, err: ErrInternal},
+		{name: "case 8: retries disabled", status: http.StatusServiceUnavailable, failures: 1, maxRetries: -1, attempts: 1, err: ErrUnavailable},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			f := &failing{method: http.MethodGet, path: "/v2/vehicles/1", status: c.status, failures: c.failures}
+			srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+			cl := newTestClient(t, srv, Config{MaxRetries: c.maxRetries})
+
+			// act
+			v, err := cl.GetVehicle(context.Background(), 1, nil, nil)
+
+			// assert
+			require.Equal(t, c.attempts, f.attempts())
+			if c.err != nil {
+				require.ErrorIs(t, err, c.err)
+				var e *Error
+				require.ErrorAs(t, err, &e)
+				require.Equal(t, c.status, e.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 1, v.ID)
+		})
+	}
+
+	t.Run("case 9: Retry-After is honoured", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodGet, path: "/v2/vehicles/1", status: http.StatusTooManyRequests, retryAfter: "1", failures: 1}
+		srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+		cl := newTestClient(t, srv, Config{})
+
+		// act
+		start := time.Now()
+		_, err := cl.GetVehicle(context.Background(), 1, nil, nil)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 2, f.attempts())
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+
+	t.Run("case 10: Retry-After of the last try is returned", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodGet, path: "/v2/vehicles/1", status: http.StatusServiceUnavailable, retryAfter: "30", failures: 1}
+		srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+		cl := newTestClient(t, srv, Config{MaxRetries: -1})
+
+		// act
+		_, err := cl.GetVehicle(context.Background(), 1, nil, nil)
+
+		// assert
+		var e *Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, 30*time.Second, e.RetryAfter)
+		require.True(t, e.Temporary())
+	})
+
+	t.Run("case 11: the wait is cut short by the context", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodGet, path: "/v2/vehicles/1", status: http.StatusServiceUnavailable, retryAfter: "30", failures: 1}
+		srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+		cl := newTestClient(t, srv, Config{})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// act
+		_, err := cl.GetVehicle(ctx, 1, nil, nil)
+
+		// assert
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, f.attempts())
+	})
+}
+
+func TestClient_RetryNotIdempotent(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		call   func(cl *Client) error
+	}{
+		{name: "case 1: patch", method: http.MethodPatch, path: "/v2/vehicles/1", call: func(cl *Client) error {
+			_, err := cl.PatchVehicle(context.Background(), 1, VehiclePatch{Color: String("Red")})
+			return err
+		}},
+		{name: "case 2: graphql", method: http.MethodPost, path: "/graphql", call: func(cl *Client) error {
+			return cl.GraphQL(context.Background(), `mutation { updateVehicle(id: 1, input: {color: "Red"}) { id } }`, nil, nil)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			// - the request is applied, but its response is lost
+			f := &failing{method: c.method, path: c.path, status: http.StatusBadGateway, failures: 1, served: true}
+			srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+			cl := newTestClient(t, srv, Config{})
+
+			// act
+			err := c.call(cl)
+
+			// assert
+			require.ErrorIs(t, err, &Error{Code: CodeBadGateway})
+			require.Equal(t, 1, f.attempts())
+			v, err := cl.GetVehicle(context.Background(), 1, nil, nil)
+			require.NoError(t, err)
+			require.Equal(t, "Red", v.Color)
+		})
+	}
+}
+
+func TestClient_IdempotencyKey(t *testing.T) {
+	cfg := application.ConfigServerChi{IdempotencyTTL: time.Hour}
+
+	t.Run("case 1: the retries of a create reuse its key", func(t *testing.T) {
+		// arrange
+		// - the first tries are applied, but their responses are lost
+		f := &failing{method: http.MethodPost, path: "/v2/vehicles", status: http.StatusBadGateway, failures: 2, served: true}
+		srv := newTestServer(t, cfg, f.handler)
+		cl := newTestClient(t, srv, Config{})
+
+		// act
+		created, err := cl.CreateVehicle(context.Background(), testVehicle(1001))
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1001, created.ID)
+		keys := f.idempotencyKeys()
+		require.Len(t, keys, 3)
+		require.NotEmpty(t, keys[0])
+		require.Equal(t, []string{keys[0], keys[0], keys[0]}, keys)
+	})
+
+	t.Run("case 2: the retries of a batch reuse its key", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodPost, path: "/v2/vehicles/batch", status: http.StatusServiceUnavailable, failures: 1, served: true}
+		srv := newTestServer(t, cfg, f.handler)
+		cl := newTestClient(t, srv, Config{})
+
+		// act
+		created, err := cl.CreateVehicles(context.Background(), []Vehicle{testVehicle(1001), testVehicle(1002)})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, created, 2)
+		keys := f.idempotencyKeys()
+		require.Len(t, keys, 2)
+		require.Equal(t, keys[0], keys[1])
+	})
+
+	t.Run("case 3: every create has a key of its own", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodPost, path: "/v2/vehicles"}
+		srv := newTestServer(t, cfg, f.handler)
+		cl := newTestClient(t, srv, Config{})
+
+		// act
+		_, err1 := cl.CreateVehicle(context.Background(), testVehicle(1001))
+		_, err2 := cl.CreateVehicle(context.Background(), testVehicle(1002))
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		keys := f.idempotencyKeys()
+		require.Len(t, keys, 2)
+		require.NotEqual(t, keys[0], keys[1])
+	})
+
+	t.Run("case 4: a key of the context is reused across calls", func(t *testing.T) {
+		// arrange
+		f := &failing{method: http.MethodPost, path: "/v2/vehicles"}
+		srv := newTestServer(t, cfg, f.handler)
+		cl := newTestClient(t, srv, Config{})
+		ctx := WithIdempotencyKey(context.Background(), "job-1")
+
+		// act
+		// - the second create is replayed instead of conflicting
+		_, err1 := cl.CreateVehicle(ctx, testVehicle(1001))
+		_, err2 := cl.CreateVehicle(ctx, testVehicle(1001))
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.Equal(t, []string{"job-1", "job-1"}, f.idempotencyKeys())
+	})
+}
+
+func TestClient_Errors(t *testing.T) {
+	srv := newTestServer(t, application.ConfigServerChi{AuthAPIKeysFile: writeAPIKeys(t)})
+	clients := map[string]*Client{"": newTestClient(t, srv, Config{})}
+	for role, key := range testKeys {
+		clients[role] = newTestClient(t, srv, Config{APIKey: key})
+	}
+
+	cases := []struct {
+		name string
+		// role is the role of the caller, none if empty
+		role string
+		call func(cl *Client) error
+		// err is the error matched with errors.Is
+		err        error
+		statusCode int
+		message    string
+		// description is the description of the error
+		description string
+	}{
+		{
+			name: "case 1: not found",
+			role: "viewer",
+			call: func(cl *Client) error {
+				_, err := cl.GetVehicle(context.Background(), 1000, nil, nil)
+				return err
+			},
+			err: ErrNotFound, statusCode: http.StatusNotFound, message: "vehicle not found",
+			description: "client: not_found (404): vehicle not found",
+		},
+		{
+			name: "case 2: conflict",
+			role: "editor",
+			call: func(cl *Client) error {
+				_, err := cl.CreateVehicle(context.Background(), testVehicle(1))
+				return err
+			},
+			err: ErrConflict, statusCode: http.StatusConflict, message: "vehicle already exists",
+			description: "client: conflict (409): vehicle already exists",
+		},
+		{
+			name: "case 3: invalid vehicle",
+			role: "editor",
+			call: func(cl *Client) error {
+				v := testVehicle(1001)
+				v.Brand = ""
+				_, err := cl.CreateVehicle(context.Background(), v)
+				return err
+			},
+			err: ErrInvalid, statusCode: http.StatusUnprocessableEntity, message: "invalid vehicle: brand is required",
+			description: "client: unprocessable_entity (422): invalid vehicle: brand is required",
+		},
+		{
+			name: "case 4: invalid patch",
+			role: "editor",
+			call: func(cl *Client) error {
+				_, err := cl.PatchVehicle(context.Background(), 1, VehiclePatch{Model: String("")})
+				return err
+			},
+			err: ErrInvalid, statusCode: http.StatusUnprocessableEntity, message: "invalid vehicle: model is required",
+			description: "client: unprocessable_entity (422): invalid vehicle: model is required",
+		},
+		{
+			name: "case 5: bad request",
+			role: "viewer",
+			call: func(cl *Client) error {
+				_, err := cl.ListVehicles(context.Background(), ListOptions{Sort: "unknown"})
+				return err
+			},
+			err: ErrBadRequest, statusCode: http.StatusBadRequest, message: "invalid sort",
+			description: "client: bad_request (400): invalid sort",
+		},
+		{
+			name: "case 6: unauthorized, the message is the code",
+			role: "",
+			call: func(cl *Client) error {
+				_, err := cl.GetVehicle(context.Background(), 1, nil, nil)
+				return err
+			},
+			err: ErrUnauthorized, statusCode: http.StatusUnauthorized, message: "unauthorized",
+			description: "client: unauthorized (401)",
+		},
+		{
+			name: "case 7: forbidden, the message is the code",
+			role: "viewer",
+			call: func(cl *Client) error {
+				return cl.DeleteVehicle(context.Background(), 1)
+			},
+			err: ErrForbidden, statusCode: http.StatusForbidden, message: "forbidden",
+			description: "client: forbidden (403)",
+		},
+		{
+			name: "case 8: error of a route out of the v2 routes",
+			role: "system-admin",
+			call: func(cl *Client) error {
+				_, err := cl.CacheStats(context.Background())
+				return err
+			},
+			err: ErrNotFound, statusCode: http.StatusNotFound, message: "cache disabled",
+			description: "client: not_found (404): cache disabled",
+		},
+		{
+			name: "case 9: error of a GraphQL response",
+			role: "viewer",
+			call: func(cl *Client) error {
+				return cl.GraphQL(context.Background(), `mutation { deleteVehicle(id: 1) }`, nil, nil)
+			},
+			err: ErrForbidden, statusCode: http.StatusOK, message: "forbidden, requires vehicles:delete",
+			description: "client: forbidden (200): forbidden, requires vehicles:delete",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			err := c.call(clients[c.role])
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			var e *Error
+			require.ErrorAs(t, err, &e)
+			require.Equal(t, c.statusCode, e.StatusCode)
+			require.Equal(t, c.message, e.Message)
+			require.EqualError(t, err, c.description)
+			require.False(t, errors.Is(err, ErrInternal))
+		})
+	}
+}
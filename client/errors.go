@@ -0,0 +1,148 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The codes of the errors of the server: the status of the response in snake case, as the v2 routes write them
+const (
+	CodeBadRequest           = "bad_request"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeMethodNotAllowed     = "method_not_allowed"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeConflict             = "conflict"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeUnprocessableEntity  = "unprocessable_entity"
+	CodeTooManyRequests      = "too_many_requests"
+	CodeInternal             = "internal_server_error"
+	CodeBadGateway           = "bad_gateway"
+	CodeServiceUnavailable   = "service_unavailable"
+	CodeGatewayTimeout       = "gateway_timeout"
+)
+
+// The errors to match with errors.Is, by code
+var (
+	// ErrBadRequest is the error of an invalid request, e.g. an invalid filter
+	ErrBadRequest = &Error{Code: CodeBadRequest}
+	// ErrUnauthorized is the error of a request without valid credentials
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	// ErrForbidden is the error of a request the caller is not allowed to make
+	ErrForbidden = &Error{Code: CodeForbidden}
+	// ErrNotFound is the error of a request of a vehicle, or a resource, that does not exist
+	ErrNotFound = &Error{Code: CodeNotFound}
+	// ErrConflict is the error of a create of a vehicle that already exists, or of an idempotency key in use
+	ErrConflict = &Error{Code: CodeConflict}
+	// ErrInvalid is the error of a vehicle, or a patch, that is not valid
+	ErrInvalid = &Error{Code: CodeUnprocessableEntity}
+	// ErrRateLimited is the error of a request over the rate limit of the caller, once the retries are exhausted
+	ErrRateLimited = &Error{Code: CodeTooManyRequests}
+	// ErrInternal is the error of a request the server failed to serve
+	ErrInternal = &Error{Code: CodeInternal}
+	// ErrUnavailable is the error of a request the server can not serve yet, e.g. while the vehicles are loading
+	ErrUnavailable = &Error{Code: CodeServiceUnavailable}
+)
+
+// Error is a struct that represents an error answered by the server
+type Error struct {
+	// StatusCode is the status code of the response, 200 for the errors of a GraphQL response
+	StatusCode int
+	// Code is the code of the error, one of the Code constants
+	Code string
+	// Message is the description of the error
+	Message string
+	// RetryAfter is how long the server asked to wait before retrying, zero if it did not
+	RetryAfter time.Duration
+}
+
+// Error is a method that returns the description of the error
+// - the message is left out if it is the code, as the server writes some of them
+func (e *Error) Error() string {
+	msg := "client: " + e.Code
+	if e.StatusCode != 0 {
+		msg += " (" + strconv.Itoa(e.StatusCode) + ")"
+	}
+	if e.Message != "" && !strings.EqualFold(e.Message, strings.ReplaceAll(e.Code, "_", " ")) {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is is a method that returns true if the target is an error with the same code, so errors.Is matches the Err variables
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Temporary is a method that returns true if the request may succeed if retried
+func (e *Error) Temporary() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// statusCode is a function that returns the code of a status code, as the server writes it
+func statusCode(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// responseError is a function that returns the error of a response with an error status
+// - the body is the error envelope of the v2 routes, or the error of the other routes: a status and a message
+func responseError(res *http.Response) error {
+	var body struct {
+		Error *struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	json.Unmarshal(b, &body)
+
+	e := &Error{StatusCode: res.StatusCode, Code: statusCode(res.StatusCode), Message: body.Message}
+	if body.Error != nil {
+		e.Code, e.Message = body.Error.Code, body.Error.Message
+	}
+	if e.Message == "" {
+		e.Message = strings.TrimSpace(string(b))
+	}
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
+
+// graphQLCodes are the codes of the errors of the GraphQL API, by their extension code
+var graphQLCodes = map[string]string{
+	"BAD_USER_INPUT":   CodeUnprocessableEntity,
+	"NOT_FOUND":        CodeNotFound,
+	"CONFLICT":         CodeConflict,
+	"FORBIDDEN":        CodeForbidden,
+	"COMPLEXITY_LIMIT": CodeBadRequest,
+	"INTERNAL":         CodeInternal,
+}
+
+// graphQLError is a struct that represents an error of a GraphQL response
+type graphQLError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// err is a method that returns the error, with the code of the server of its extension code, bad_request if unknown
+func (g graphQLError) err() error {
+	code, ok := graphQLCodes[g.Extensions.Code]
+	if !ok {
+		code = CodeBadRequest
+	}
+	return &Error{StatusCode: http.StatusOK, Code: code, Message: g.Message}
+}
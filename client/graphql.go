@@ -0,0 +1,210 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// graphQLPath is the path of the GraphQL API
+const graphQLPath = "/graphql"
+
+// graphQLRequest is a struct that represents the body of a GraphQL request
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is a struct that represents the body of a GraphQL response
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// decode is a method that decodes the data of the response into out, or returns its first error
+func (r graphQLResponse) decode(out any) error {
+	if len(r.Errors) > 0 {
+		return r.Errors[0].err()
+	}
+	if out == nil || len(r.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.Data, out)
+}
+
+// GraphQL is a method that runs a query or a mutation of the GraphQL API and decodes its data into out, if not nil
+// - the errors of the response are returned as *Error, the first one only
+// - it is not retried, a mutation may fail after it was applied
+func (c *Client) GraphQL(ctx context.Context, query string, variables map[string]any, out any) (err error) {
+	var res graphQLResponse
+	if err = c.do(ctx, request{method: http.MethodPost, path: graphQLPath, body: graphQLRequest{Query: query, Variables: variables}}, &res); err != nil {
+		return
+	}
+	return res.decode(out)
+}
+
+// The types of the events of the vehicles
+const (
+	EventCreated = "CREATED"
+	EventUpdated = "UPDATED"
+	EventDeleted = "DELETED"
+)
+
+// Event is a struct that represents a change made to a vehicle
+type Event struct {
+	// Type is one of the Event constants
+	Type string `json:"type"`
+	// Vehicle is the vehicle after the change, nil if deleted
+	Vehicle *Vehicle `json:"vehicle"`
+	// Previous is the vehicle before the change, nil if created
+	Previous *Vehicle `json:"previous"`
+}
+
+// watchQuery is the subscription of the events, with the fields of the vehicles aliased to the names of the REST API
+const watchQuery = `subscription Watch($types: [VehicleEventType!], $units: UnitSystem) {
+	vehicleEvents(types: $types) { type vehicle { ...vehicle } previous { ...vehicle } }
+}
+fragment vehicle on Vehicle {
+	id brand model registration color year passengers
+	max_speed: maxSpeed(units: $units) fuel_type: fuelType transmission
+	weight(units: $units) height(units: $units) length(units: $units) width(units: $units)
+}`
+
+// Watcher is a struct that represents a stream of the events of the vehicles
+//
+//	w, err := c.Watch(ctx)
+//	defer w.Close()
+//	for w.Next() {
+//		e := w.Event()
+//	}
+//	if err := w.Err(); err != nil {
+type Watcher struct {
+	// body is the body of the response streaming the events
+	body io.ReadCloser
+	// scanner reads the lines of the server-sent events
+	scanner *bufio.Scanner
+	// current is the event of the iteration
+	current Event
+	// err is the error that ended the stream
+	err error
+	// closed is true once the watcher is closed, the stream then ends without an error
+	closed atomic.Bool
+}
+
+// Watch is a method that returns a stream of the changes made to the vehicles from now on, of the types only if given
+// - the events are streamed by a GraphQL subscription, as server-sent events
+// - the stream lasts until the context is done or the watcher closed, or the server ends it (e.g. if the watcher falls behind)
+func (c *Client) Watch(ctx context.Context, types ...string) (w *Watcher, err error) {
+	variables := map[string]any{"units": "METRIC"}
+	if c.units != "" {
+		variables["units"] = strings.ToUpper(c.units)
+	}
+	if len(types) > 0 {
+		variables["types"] = types
+	}
+	res, err := c.send(ctx, request{
+		method: http.MethodPost,
+		path:   graphQLPath,
+		body:   graphQLRequest{Query: watchQuery, Variables: variables},
+		header: http.Header{"Accept": {"text/event-stream"}},
+		retry:  true,
+	})
+	if err != nil {
+		return
+	}
+	if !strings.HasPrefix(res.Header.Get("Content-Type"), "text/event-stream") {
+		// - a response instead of a stream, e.g. the errors of the subscription
+		defer res.Body.Close()
+		var body graphQLResponse
+		if err = json.NewDecoder(res.Body).Decode(&body); err == nil {
+			err = body.decode(nil)
+		}
+		if err == nil {
+			err = errors.New("client: events not streamed")
+		}
+		return
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	return &Watcher{body: res.Body, scanner: scanner}, nil
+}
+
+// Next is a method that waits for the next event
+// - false once the stream ended, Err tells why
+func (w *Watcher) Next() bool {
+	if w.err != nil {
+		return false
+	}
+
+	var event string
+	var data strings.Builder
+	for w.scanner.Scan() {
+		line := w.scanner.Text()
+		switch {
+		case line == "":
+			// - end of an event
+			switch event {
+			case "next":
+				var body struct {
+					Data struct {
+						VehicleEvents Event `json:"vehicleEvents"`
+					} `json:"data"`
+					Errors []graphQLError `json:"errors"`
+				}
+				if err := json.Unmarshal([]byte(data.String()), &body); err != nil {
+					w.err = errors.New("client: invalid event: " + err.Error())
+					return false
+				}
+				if len(body.Errors) > 0 {
+					w.err = body.Errors[0].err()
+					return false
+				}
+				w.current = body.Data.VehicleEvents
+				return true
+			case "complete":
+				w.err = io.EOF
+				return false
+			}
+			event = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// - comment, e.g. keep-alive
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	switch w.err = w.scanner.Err(); {
+	case w.closed.Load():
+		w.err = io.EOF
+	case w.err == nil:
+		w.err = io.ErrUnexpectedEOF
+	}
+	return false
+}
+
+// Event is a method that returns the event of the iteration
+func (w *Watcher) Event() Event {
+	return w.current
+}
+
+// Err is a method that returns the error that ended the stream, nil if the server completed it or the watcher was closed
+func (w *Watcher) Err() error {
+	if errors.Is(w.err, io.EOF) {
+		return nil
+	}
+	return w.err
+}
+
+// Close is a method that ends the stream, it may be called while waiting for the next event
+func (w *Watcher) Close() error {
+	w.closed.Store(true)
+	return w.body.Close()
+}
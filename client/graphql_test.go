@@ -0,0 +1,117 @@
+package client
+
+import (
+	"app/internal/application"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GraphQL(t *testing.T) {
+	ctx := context.Background()
+	cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+	t.Run("case 1: query", func(t *testing.T) {
+		// act
+		var data struct {
+			Vehicle struct {
+				ID    int    `json:"id"`
+				Brand string `json:"brand"`
+			} `json:"vehicle"`
+		}
+		err := cl.GraphQL(ctx, `query Vehicle($id: Int!) { vehicle(id: $id) { id brand } }`, map[string]any{"id": 1}, &data)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, data.Vehicle.ID)
+		require.Equal(t, "Hummer", data.Vehicle.Brand)
+	})
+
+	t.Run("case 2: the first error is returned", func(t *testing.T) {
+		// act
+		err := cl.GraphQL(ctx, `mutation { deleteVehicle(id: 1000) }`, nil, nil)
+
+		// assert
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("case 3: invalid query", func(t *testing.T) {
+		// act
+		err := cl.GraphQL(ctx, `{ vehicle(id: 1) { unknown } }`, nil, nil)
+
+		// assert
+		require.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestClient_Watch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+	t.Run("case 1: the changes are streamed", func(t *testing.T) {
+		// arrange
+		w, err := cl.Watch(ctx)
+		require.NoError(t, err)
+		defer w.Close()
+
+		// act
+		_, err = cl.CreateVehicle(ctx, testVehicle(1001))
+		require.NoError(t, err)
+		_, err = cl.PatchVehicle(ctx, 1001, VehiclePatch{Color: String("Red")})
+		require.NoError(t, err)
+		require.NoError(t, cl.DeleteVehicle(ctx, 1001))
+
+		// assert
+		var events []Event
+		for len(events) < 3 && w.Next() {
+			events = append(events, w.Event())
+		}
+		require.NoError(t, w.Err())
+		require.Len(t, events, 3)
+		require.Equal(t, EventCreated, events[0].Type)
+		require.Equal(t, testVehicle(1001), *events[0].Vehicle)
+		require.Nil(t, events[0].Previous)
+		require.Equal(t, EventUpdated, events[1].Type)
+		require.Equal(t, "Red", events[1].Vehicle.Color)
+		require.Equal(t, "White", events[1].Previous.Color)
+		require.Equal(t, EventDeleted, events[2].Type)
+		require.Nil(t, events[2].Vehicle)
+	})
+
+	t.Run("case 2: the changes of the types only", func(t *testing.T) {
+		// arrange
+		w, err := cl.Watch(ctx, EventDeleted)
+		require.NoError(t, err)
+		defer w.Close()
+
+		// act
+		_, err = cl.CreateVehicle(ctx, testVehicle(1002))
+		require.NoError(t, err)
+		require.NoError(t, cl.DeleteVehicle(ctx, 1002))
+
+		// assert
+		require.True(t, w.Next())
+		require.Equal(t, EventDeleted, w.Event().Type)
+		require.Equal(t, 1002, w.Event().Previous.ID)
+	})
+
+	t.Run("case 3: the stream ends without an error once closed", func(t *testing.T) {
+		// arrange
+		w, err := cl.Watch(ctx)
+		require.NoError(t, err)
+
+		// act
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			w.Close()
+		}()
+		next := w.Next()
+
+		// assert
+		require.False(t, next)
+		require.NoError(t, w.Err())
+	})
+}
@@ -0,0 +1,362 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Vehicle is a struct that represents a vehicle, its measures in the unit system of the client
+type Vehicle struct {
+	ID    int    `json:"id"`
+	Brand string `json:"brand"`
+	Model string `json:"model"`
+	// Registration is empty if the caller may not read it
+	Registration string  `json:"registration,omitempty"`
+	Color        string  `json:"color"`
+	Year         int     `json:"year"`
+	Passengers   int     `json:"passengers"`
+	MaxSpeed     float64 `json:"max_speed"`
+	FuelType     string  `json:"fuel_type"`
+	Transmission string  `json:"transmission"`
+	Weight       float64 `json:"weight"`
+	Height       float64 `json:"height"`
+	Length       float64 `json:"length"`
+	Width        float64 `json:"width"`
+	// Footprint, Volume, Age and PowerToWeight are computed, only present if included
+	Footprint     *float64 `json:"footprint,omitempty"`
+	Volume        *float64 `json:"volume,omitempty"`
+	Age           *int     `json:"age,omitempty"`
+	PowerToWeight *float64 `json:"power_to_weight,omitempty"`
+}
+
+// VehiclePatch is a struct that represents a partial update of a vehicle
+// - nil fields are left as they are, see String, Int and Float to set them
+type VehiclePatch struct {
+	Brand        *string  `json:"brand,omitempty"`
+	Model        *string  `json:"model,omitempty"`
+	Registration *string  `json:"registration,omitempty"`
+	Color        *string  `json:"color,omitempty"`
+	Year         *int     `json:"year,omitempty"`
+	Passengers   *int     `json:"passengers,omitempty"`
+	MaxSpeed     *float64 `json:"max_speed,omitempty"`
+	FuelType     *string  `json:"fuel_type,omitempty"`
+	Transmission *string  `json:"transmission,omitempty"`
+	Weight       *float64 `json:"weight,omitempty"`
+	Height       *float64 `json:"height,omitempty"`
+	Length       *float64 `json:"length,omitempty"`
+	Width        *float64 `json:"width,omitempty"`
+}
+
+// String is a function that returns a pointer to the string, to set a field of a patch
+func String(s string) *string { return &s }
+
+// Int is a function that returns a pointer to the integer, to set a field of a patch
+func Int(i int) *int { return &i }
+
+// Float is a function that returns a pointer to the float, to set a field of a patch or a bound of a range
+func Float(f float64) *float64 { return &f }
+
+// Range is a struct that represents an inclusive range of a filter, open on the nil bounds
+type Range struct {
+	Min *float64
+	Max *float64
+}
+
+// Between is a function that returns the range from min to max
+func Between(min, max float64) Range { return Range{Min: &min, Max: &max} }
+
+// AtLeast is a function that returns the range of the values from min
+func AtLeast(min float64) Range { return Range{Min: &min} }
+
+// AtMost is a function that returns the range of the values up to max
+func AtMost(max float64) Range { return Range{Max: &max} }
+
+// String is a method that returns the range in the format of the filters of the server: min-max, min- or -max
+// - empty if the range is open on both bounds
+func (r Range) String() string {
+	if r.Min == nil && r.Max == nil {
+		return ""
+	}
+	format := func(value *float64) string {
+		if value == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*value, 'f', -1, 64)
+	}
+	return format(r.Min) + "-" + format(r.Max)
+}
+
+// ListOptions is a struct that represents the filters, the order and the page of a list of vehicles
+// - the zero value lists the first page of every vehicle, by id
+type ListOptions struct {
+	// Brand, Color, FuelType and Transmission match exactly, if given
+	Brand        string
+	Color        string
+	FuelType     string
+	Transmission string
+	// Year, MaxSpeed, Weight, Height, Length and Width match the ranges, in the unit system of the client
+	Year     Range
+	MaxSpeed Range
+	Weight   Range
+	Height   Range
+	Length   Range
+	Width    Range
+	// Sort is the field to sort by, or -field for descending order, id by default
+	Sort string
+	// Limit is the number of vehicles of a page, 50 by default and up to 500
+	Limit int
+	// Offset is the number of vehicles skipped before the page
+	Offset int
+	// Fields are the fields of the vehicles written, every field by default
+	Fields []string
+	// Include are the computed fields written: age, volume, power_to_weight or footprint
+	Include []string
+}
+
+// query is a method that returns the query parameters of the options
+func (o ListOptions) query() url.Values {
+	q := make(url.Values)
+	set := func(name, value string) {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	set("brand", o.Brand)
+	set("color", o.Color)
+	set("fuel_type", o.FuelType)
+	set("transmission", o.Transmission)
+	set("year", o.Year.String())
+	set("max_speed", o.MaxSpeed.String())
+	set("weight", o.Weight.String())
+	set("height", o.Height.String())
+	set("length", o.Length.String())
+	set("width", o.Width.String())
+	set("sort", o.Sort)
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		q.Set("offset", strconv.Itoa(o.Offset))
+	}
+	set("fields", strings.Join(o.Fields, ","))
+	set("include", strings.Join(o.Include, ","))
+	return q
+}
+
+// VehiclePage is a struct that represents a page of vehicles
+type VehiclePage struct {
+	// Vehicles are the vehicles of the page
+	Vehicles []Vehicle
+	// Total is the number of vehicles matching the filters, across every page
+	Total int
+	// Limit and Offset are the ones of the page
+	Limit  int
+	Offset int
+}
+
+// SearchResult is a struct that represents a vehicle matching a search, higher scores are more relevant
+type SearchResult struct {
+	Vehicle
+	Score float64 `json:"score"`
+}
+
+// SimilarVehicle is a struct that represents a vehicle similar to another, lower distances are more similar
+type SimilarVehicle struct {
+	Vehicle
+	Distance float64 `json:"distance"`
+}
+
+// Stats is a struct that represents the statistics of a metric over the vehicles of a key of a group
+type Stats struct {
+	Key    string  `json:"key"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	StdDev float64 `json:"stddev"`
+}
+
+// vehiclesPath is the path of the vehicles
+const vehiclesPath = "/v2/vehicles"
+
+// vehiclePath is a function that returns the path of a vehicle
+func vehiclePath(id int) string {
+	return vehiclesPath + "/" + strconv.Itoa(id)
+}
+
+// ListVehicles is a method that returns a page of the vehicles matching the options
+func (c *Client) ListVehicles(ctx context.Context, opts ListOptions) (p *VehiclePage, err error) {
+	var body envelope[[]Vehicle]
+	if err = c.get(ctx, vehiclesPath, opts.query(), &body); err != nil {
+		return
+	}
+	p = &VehiclePage{Vehicles: body.Data}
+	if body.Meta != nil {
+		p.Total, p.Limit, p.Offset = body.Meta.Total, body.Meta.Limit, body.Meta.Offset
+	}
+	return
+}
+
+// GetVehicle is a method that returns the vehicle with the id
+// - fields and include are as in ListOptions
+func (c *Client) GetVehicle(ctx context.Context, id int, fields, include []string) (v *Vehicle, err error) {
+	q := make(url.Values)
+	if len(fields) > 0 {
+		q.Set("fields", strings.Join(fields, ","))
+	}
+	if len(include) > 0 {
+		q.Set("include", strings.Join(include, ","))
+	}
+	var body envelope[*Vehicle]
+	if err = c.get(ctx, vehiclePath(id), q, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// CreateVehicle is a method that creates a vehicle and returns it as stored
+func (c *Client) CreateVehicle(ctx context.Context, v Vehicle) (created *Vehicle, err error) {
+	var body envelope[*Vehicle]
+	if err = c.create(ctx, vehiclesPath, v, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// CreateVehicles is a method that creates the vehicles and returns them as stored
+// - the vehicles are created in order, the ones before a vehicle that fails (e.g. it exists) stay created
+func (c *Client) CreateVehicles(ctx context.Context, vehicles []Vehicle) (created []Vehicle, err error) {
+	var body envelope[[]Vehicle]
+	if err = c.create(ctx, vehiclesPath+"/batch", vehicles, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// PatchVehicle is a method that changes the fields set in the patch of the vehicle with the id, and returns it
+// - it is not retried, a patch may fail after it was applied
+func (c *Client) PatchVehicle(ctx context.Context, id int, patch VehiclePatch) (v *Vehicle, err error) {
+	var body envelope[*Vehicle]
+	if err = c.do(ctx, request{method: http.MethodPatch, path: vehiclePath(id), body: patch}, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// DeleteVehicle is a method that deletes the vehicle with the id
+// - a retry of a delete that succeeded fails with ErrNotFound
+func (c *Client) DeleteVehicle(ctx context.Context, id int) error {
+	return c.do(ctx, request{method: http.MethodDelete, path: vehiclePath(id), retry: true}, nil)
+}
+
+// SearchVehicles is a method that returns the vehicles matching the text, by relevance
+// - limit is the maximum number of vehicles, 20 by default and up to 100
+func (c *Client) SearchVehicles(ctx context.Context, text string, limit int) (results []SearchResult, err error) {
+	q := url.Values{"q": {text}}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var body envelope[[]SearchResult]
+	if err = c.get(ctx, vehiclesPath+"/search", q, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// SimilarVehicles is a method that returns the k vehicles most similar to the vehicle with the id
+// - k is 5 by default and up to 100
+func (c *Client) SimilarVehicles(ctx context.Context, id, k int) (similar []SimilarVehicle, err error) {
+	q := make(url.Values)
+	if k > 0 {
+		q.Set("k", strconv.Itoa(k))
+	}
+	var body envelope[[]SimilarVehicle]
+	if err = c.get(ctx, vehiclePath(id)+"/similar", q, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// VehicleStats is a method that returns the statistics of a metric for every key of a group, by key
+// - groupBy is brand, fuel_type or year, brand by default
+// - metric is max_speed, capacity or weight, max_speed by default
+func (c *Client) VehicleStats(ctx context.Context, groupBy, metric string) (stats []Stats, err error) {
+	q := make(url.Values)
+	if groupBy != "" {
+		q.Set("group_by", groupBy)
+	}
+	if metric != "" {
+		q.Set("metric", metric)
+	}
+	var body envelope[[]Stats]
+	if err = c.get(ctx, vehiclesPath+"/stats", q, &body); err != nil {
+		return
+	}
+	return body.Data, nil
+}
+
+// VehicleIterator is a struct that represents an iteration over every vehicle matching the options of a list, page by page
+//
+//	it := c.Vehicles(opts)
+//	for it.Next(ctx) {
+//		v := it.Vehicle()
+//	}
+//	if err := it.Err(); err != nil {
+//
+// - the pages are read as the iteration goes, vehicles changed meanwhile may be skipped or repeated
+type VehicleIterator struct {
+	// c is the client
+	c *Client
+	// opts are the options of the next page
+	opts ListOptions
+	// page are the vehicles of the page not iterated yet
+	page []Vehicle
+	// current is the vehicle of the iteration
+	current Vehicle
+	// done is true once the last page was read
+	done bool
+	// err is the error that ended the iteration
+	err error
+}
+
+// Vehicles is a method that returns an iterator over every vehicle matching the options, from their offset
+// - the limit of the options is the size of the pages
+func (c *Client) Vehicles(opts ListOptions) *VehicleIterator {
+	return &VehicleIterator{c: c, opts: opts}
+}
+
+// Next is a method that advances to the next vehicle, reading the next page if needed
+// - false once every vehicle was iterated or on an error
+func (it *VehicleIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 && !it.done {
+		p, err := it.c.ListVehicles(ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = p.Vehicles
+		it.opts.Offset += len(p.Vehicles)
+		it.done = len(p.Vehicles) == 0 || it.opts.Offset >= p.Total
+	}
+	if len(it.page) == 0 {
+		return false
+	}
+	it.current, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Vehicle is a method that returns the vehicle of the iteration
+func (it *VehicleIterator) Vehicle() Vehicle {
+	return it.current
+}
+
+// Err is a method that returns the error that ended the iteration, nil if every vehicle was iterated
+func (it *VehicleIterator) Err() error {
+	return it.err
+}
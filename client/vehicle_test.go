@@ -0,0 +1,298 @@
+package client
+
+import (
+	"app/internal/application"
+	"context"
+	"net/http"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testVehicle is a function that returns a valid vehicle with the id, in metric units
+func testVehicle(id int) Vehicle {
+	return Vehicle{
+		ID: id, Brand: "Tesla", Model: "Model 3", Registration: "T-1", Color: "White", Year: 2020, Passengers: 5,
+		MaxSpeed: 225, FuelType: "electric", Transmission: "automatic", Weight: 1800, Height: 140, Length: 470, Width: 180,
+	}
+}
+
+func TestClient_Vehicles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("case 1: list a page", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		p, err := cl.ListVehicles(ctx, ListOptions{Limit: 10, Offset: 5, Sort: "-id", Fields: []string{"id", "brand"}})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 100, p.Total)
+		require.Equal(t, 10, p.Limit)
+		require.Equal(t, 5, p.Offset)
+		require.Len(t, p.Vehicles, 10)
+		require.Equal(t, 95, p.Vehicles[0].ID)
+		require.NotEmpty(t, p.Vehicles[0].Brand)
+		require.Empty(t, p.Vehicles[0].Model)
+	})
+
+	t.Run("case 2: list with filters", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		p, err := cl.ListVehicles(ctx, ListOptions{FuelType: "biodiesel", Year: Between(2000, 2010), Weight: AtLeast(100), Limit: 500})
+
+		// assert
+		require.NoError(t, err)
+		require.NotEmpty(t, p.Vehicles)
+		require.Equal(t, len(p.Vehicles), p.Total)
+		for _, v := range p.Vehicles {
+			require.Equal(t, "biodiesel", v.FuelType)
+			require.True(t, v.Year >= 2000 && v.Year <= 2010, v.Year)
+			require.GreaterOrEqual(t, v.Weight, 100.0)
+		}
+	})
+
+	t.Run("case 3: get with the computed fields", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		v, err := cl.GetVehicle(ctx, 1, nil, []string{"footprint", "volume"})
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, 1, v.ID)
+		require.Equal(t, "Hummer", v.Brand)
+		require.NotNil(t, v.Footprint)
+		require.InDelta(t, v.Length*v.Width, *v.Footprint, 1e-6)
+		require.NotNil(t, v.Volume)
+		require.Nil(t, v.Age)
+	})
+
+	t.Run("case 4: get in imperial units", func(t *testing.T) {
+		// arrange
+		srv := newTestServer(t, application.ConfigServerChi{})
+		metric := newTestClient(t, srv, Config{})
+		imperial := newTestClient(t, srv, Config{Units: "imperial"})
+
+		// act
+		vm, err1 := metric.GetVehicle(ctx, 1, nil, nil)
+		vi, err2 := imperial.GetVehicle(ctx, 1, nil, nil)
+
+		// assert
+		require.NoError(t, err1)
+		require.NoError(t, err2)
+		require.InDelta(t, vm.MaxSpeed/1.609344, vi.MaxSpeed, 0.01)
+		require.InDelta(t, vm.Length/2.54, vi.Length, 0.01)
+	})
+
+	t.Run("case 5: create, patch and delete", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act and assert
+		created, err := cl.CreateVehicle(ctx, testVehicle(1001))
+		require.NoError(t, err)
+		require.Equal(t, testVehicle(1001), *created)
+
+		patched, err := cl.PatchVehicle(ctx, 1001, VehiclePatch{Color: String("Red"), Passengers: Int(4), MaxSpeed: Float(250)})
+		require.NoError(t, err)
+		expected := testVehicle(1001)
+		expected.Color, expected.Passengers, expected.MaxSpeed = "Red", 4, 250
+		require.Equal(t, expected, *patched)
+
+		v, err := cl.GetVehicle(ctx, 1001, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, *v)
+
+		require.NoError(t, cl.DeleteVehicle(ctx, 1001))
+		_, err = cl.GetVehicle(ctx, 1001, nil, nil)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, cl.DeleteVehicle(ctx, 1001), ErrNotFound)
+	})
+
+	t.Run("case 6: create a batch", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+		vehicles := []Vehicle{testVehicle(1001), testVehicle(1002)}
+
+		// act
+		created, err := cl.CreateVehicles(ctx, vehicles)
+
+		// assert
+		require.NoError(t, err)
+		require.Equal(t, vehicles, created)
+		p, err := cl.ListVehicles(ctx, ListOptions{})
+		require.NoError(t, err)
+		require.Equal(t, 102, p.Total)
+	})
+
+	t.Run("case 7: a batch stops at the vehicle that exists", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		_, err := cl.CreateVehicles(ctx, []Vehicle{testVehicle(1001), testVehicle(1), testVehicle(1002)})
+
+		// assert
+		require.ErrorIs(t, err, ErrConflict)
+		_, err = cl.GetVehicle(ctx, 1001, nil, nil)
+		require.NoError(t, err)
+		_, err = cl.GetVehicle(ctx, 1002, nil, nil)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("case 8: search", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		results, err := cl.SearchVehicles(ctx, "ford", 3)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		for i, r := range results {
+			require.Equal(t, "Ford", r.Brand)
+			if i > 0 {
+				require.LessOrEqual(t, r.Score, results[i-1].Score)
+			}
+		}
+	})
+
+	t.Run("case 9: similar", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		similar, err := cl.SimilarVehicles(ctx, 1, 4)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, similar, 4)
+		for i, s := range similar {
+			require.NotEqual(t, 1, s.ID)
+			if i > 0 {
+				require.GreaterOrEqual(t, s.Distance, similar[i-1].Distance)
+			}
+		}
+		_, err = cl.SimilarVehicles(ctx, 1000, 4)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("case 10: stats", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		stats, err := cl.VehicleStats(ctx, "fuel_type", "weight")
+
+		// assert
+		require.NoError(t, err)
+		require.NotEmpty(t, stats)
+		count := 0
+		for _, s := range stats {
+			require.NotEmpty(t, s.Key)
+			require.LessOrEqual(t, s.Min, s.Mean)
+			require.LessOrEqual(t, s.Mean, s.Max)
+			count += s.Count
+		}
+		require.Equal(t, 100, count)
+
+		_, err = cl.VehicleStats(ctx, "color", "")
+		require.ErrorIs(t, err, ErrBadRequest)
+	})
+}
+
+func TestVehicleIterator(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		opts ListOptions
+		// pages is the number of pages read
+		pages int
+	}{
+		{name: "case 1: every vehicle", opts: ListOptions{Limit: 7}, pages: 15},
+		{name: "case 2: a single page", opts: ListOptions{Limit: 500}, pages: 1},
+		{name: "case 3: the pages are the default limit", opts: ListOptions{}, pages: 2},
+		{name: "case 4: from an offset", opts: ListOptions{Limit: 30, Offset: 40}, pages: 2},
+		{name: "case 5: filtered", opts: ListOptions{FuelType: "biodiesel", Limit: 3}},
+		{name: "case 6: sorted", opts: ListOptions{Sort: "-max_speed", Limit: 40}, pages: 3},
+		{name: "case 7: none", opts: ListOptions{Brand: "Unknown"}, pages: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			f := &failing{method: http.MethodGet, path: "/v2/vehicles"}
+			srv := newTestServer(t, application.ConfigServerChi{}, f.handler)
+			cl := newTestClient(t, srv, Config{})
+			// - every vehicle matching the options, in a page of its own
+			all := c.opts
+			all.Limit, all.Offset = 500, 0
+			p, err := cl.ListVehicles(ctx, all)
+			require.NoError(t, err)
+			expected := p.Vehicles[min(c.opts.Offset, len(p.Vehicles)):]
+			pages := c.pages
+			if pages == 0 {
+				pages = (len(expected) + c.opts.Limit - 1) / c.opts.Limit
+			}
+
+			// act
+			var vehicles []Vehicle
+			it := cl.Vehicles(c.opts)
+			for it.Next(ctx) {
+				vehicles = append(vehicles, it.Vehicle())
+			}
+
+			// assert
+			require.NoError(t, it.Err())
+			require.Equal(t, len(expected), len(vehicles))
+			if len(expected) > 0 {
+				require.Equal(t, expected, vehicles)
+			}
+			require.Equal(t, 1+pages, f.attempts())
+			// - exhausted
+			require.False(t, it.Next(ctx))
+			require.Equal(t, 1+pages, f.attempts())
+		})
+	}
+
+	t.Run("case 8: every id once", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		var ids []int
+		it := cl.Vehicles(ListOptions{Limit: 9})
+		for it.Next(ctx) {
+			ids = append(ids, it.Vehicle().ID)
+		}
+
+		// assert
+		require.NoError(t, it.Err())
+		require.Len(t, ids, 100)
+		require.True(t, sort.IntsAreSorted(ids))
+		require.Equal(t, 1, ids[0])
+		require.Equal(t, 100, ids[99])
+	})
+
+	t.Run("case 9: an error ends the iteration", func(t *testing.T) {
+		// arrange
+		cl := newTestClient(t, newTestServer(t, application.ConfigServerChi{}), Config{})
+
+		// act
+		it := cl.Vehicles(ListOptions{Sort: "unknown"})
+		next := it.Next(ctx)
+
+		// assert
+		require.False(t, next)
+		require.ErrorIs(t, it.Err(), ErrBadRequest)
+		require.False(t, it.Next(ctx))
+	})
+}
@@ -1,24 +1,38 @@
-package main
-
-import (
-	"app/internal/application"
-	"fmt"
-)
-
-func main() {
-	// env
-	// ...
-
-	// app
-	// - config
-	cfg := &application.ConfigServerChi{
-		ServerAddress:  ":8080",
-		LoaderFilePath: "../docs/db/vehicles_100.json",
-	}
-	app := application.NewServerChi(cfg)
-	// - run
-	if err := app.Run(); err != nil {
-		fmt.Println(err)
-		return
-	}
-}
+package main
+
+import (
+	"app/internal/application"
+	"app/internal/config"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	// env
+	// - config: defaults, then config file, then environment variables, then flags
+	cfg, printConfig, err := config.Load(os.Args[0], os.Args[1:], os.LookupEnv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if printConfig {
+		if err := cfg.Write(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// app
+	app := application.NewServerChi(cfg.ServerChi())
+	// - run
+	if err := app.Run(); err != nil {
+		fmt.Println(err)
+		return
+	}
+}
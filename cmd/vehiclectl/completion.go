@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"sort"
+	"strings"
+)
+
+// completionScripts are the completion scripts of the shells, by name
+// - the candidates are computed by the hidden command __complete, from the words before the cursor and the current one
+var completionScripts = map[string]string{
+	"bash": `# vehiclectl completion for bash, e.g. source <(vehiclectl completion bash)
+_vehiclectl() {
+	local IFS=$'\n'
+	COMPREPLY=($(vehiclectl __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _vehiclectl vehiclectl
+`,
+	"zsh": `#compdef vehiclectl
+# vehiclectl completion for zsh, e.g. source <(vehiclectl completion zsh)
+_vehiclectl() {
+	local -a candidates
+	candidates=("${(@f)$(vehiclectl __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+	if (( ${#candidates[@]} )) && [[ -n "${candidates[1]}" ]]; then
+		compadd -a candidates
+	else
+		_files
+	fi
+}
+compdef _vehiclectl vehiclectl
+`,
+	"fish": `# vehiclectl completion for fish, e.g. vehiclectl completion fish | source
+complete -c vehiclectl -f -a '(vehiclectl __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+}
+
+// flagValues are the values of the flags, completed after them, by name
+var flagValues = map[string][]string{
+	"o":        formats,
+	"units":    {"metric", "imperial"},
+	"group_by": {"brand", "fuel_type", "year"},
+	"metric":   {"max_speed", "capacity", "weight"},
+	"format":   {"csv", "json"},
+	"type":     {"created", "updated", "deleted"},
+	"include":  {"age", "volume", "power_to_weight", "footprint"},
+}
+
+// completeCommand is the hidden command printing the candidates of the completion of the current word, used by the scripts
+// - its arguments are the words of the command line, not parsed as flags
+const completeCommand = "__complete"
+
+// complete is a function that returns the candidates of the current word, the last one, after the others
+// - commands, then the flags of the command, the values of the flag before, or the subcommands and the profiles of profile
+func complete(e *env, words []string) (candidates []string) {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	current, words := words[len(words)-1], words[:len(words)-1]
+
+	// - the command is the first word that is neither a flag nor the value of a flag, the arguments follow
+	var cmd *command
+	var args []string
+	fs := commandFlags(e, nil)
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if strings.HasPrefix(w, "-") {
+			if name := strings.TrimLeft(w, "-"); !strings.Contains(name, "=") && isValueFlag(fs, name) {
+				i++
+			}
+			continue
+		}
+		if cmd == nil {
+			if c, ok := commands[w]; ok {
+				cmd = &c
+				fs = commandFlags(e, cmd)
+			}
+			continue
+		}
+		args = append(args, w)
+	}
+
+	switch {
+	case len(words) > 0 && strings.HasPrefix(words[len(words)-1], "-") && !strings.HasPrefix(current, "-"):
+		// - the value of a flag
+		name := strings.TrimLeft(words[len(words)-1], "-")
+		if name == "profile" {
+			return profileNames(e, current)
+		}
+		if values, ok := flagValues[name]; ok {
+			return prefixed(values, current)
+		}
+		if isValueFlag(fs, name) {
+			return nil
+		}
+	case strings.HasPrefix(current, "-"):
+		fs.VisitAll(func(f *flag.Flag) {
+			if len(f.Name) == 1 {
+				candidates = append(candidates, "-"+f.Name)
+				return
+			}
+			candidates = append(candidates, "--"+f.Name)
+		})
+		return prefixed(candidates, current)
+	}
+
+	switch {
+	case cmd == nil:
+		for name := range commands {
+			candidates = append(candidates, name)
+		}
+		return prefixed(append(candidates, "help"), current)
+	case cmd.name == "profile" && len(args) == 0:
+		return prefixed([]string{"list", "show", "use", "set", "delete"}, current)
+	case cmd.name == "profile" && len(args) == 1:
+		return profileNames(e, current)
+	case cmd.name == "completion" && len(args) == 0:
+		for name := range completionScripts {
+			candidates = append(candidates, name)
+		}
+		return prefixed(candidates, current)
+	}
+	return nil
+}
+
+// commandFlags is a function that returns the flags of a command, the global flags if none
+func commandFlags(e *env, cmd *command) *flag.FlagSet {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	if cmd != nil {
+		cmd.setup(fs, &env{lookupEnv: e.lookupEnv})
+	}
+	e.flags(fs)
+	return fs
+}
+
+// isValueFlag is a function that returns true if a flag takes a value, i.e. it is not a boolean
+func isValueFlag(fs *flag.FlagSet, name string) bool {
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !b.IsBoolFlag()
+}
+
+// profileNames is a function that returns the names of the profiles starting with the prefix
+func profileNames(e *env, prefix string) []string {
+	ps, err := e.load()
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(ps.Profiles))
+	for name := range ps.Profiles {
+		names = append(names, name)
+	}
+	return prefixed(names, prefix)
+}
+
+// prefixed is a function that returns the sorted values starting with the prefix
+func prefixed(values []string, prefix string) (matches []string) {
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			matches = append(matches, v)
+		}
+	}
+	sort.Strings(matches)
+	return
+}
+
+func init() {
+	register(command{
+		name:    "completion",
+		usage:   "bash | zsh | fish",
+		summary: "Print the completion script of a shell.",
+		setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+			return func(ctx context.Context, args []string) error {
+				if len(args) != 1 {
+					return usageError{"expected a shell"}
+				}
+				script, ok := completionScripts[args[0]]
+				if !ok {
+					return usageError{"unsupported shell " + args[0]}
+				}
+				_, err := io.WriteString(e.stdout, script)
+				return err
+			}
+		},
+	})
+}
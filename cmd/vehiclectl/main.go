@@ -0,0 +1,254 @@
+// Command vehiclectl is the command-line tool of the vehicles API, built on the Go client of the app/client package
+//
+//	vehiclectl [flags] <command> [flags] [args]
+//
+// The server and the credentials are the ones of a profile, see vehiclectl profile, overridden by the environment
+// variables VEHICLECTL_URL, VEHICLECTL_API_KEY, VEHICLECTL_TOKEN and VEHICLECTL_TENANT, then by the flags.
+package main
+
+import (
+	"app/client"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// envPrefix is the prefix of the environment variables of the tool
+const envPrefix = "VEHICLECTL_"
+
+// defaultTimeout is the default timeout of the requests of a command
+const defaultTimeout = 30 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.LookupEnv)
+	stop()
+	os.Exit(code)
+}
+
+// command is a struct that represents a subcommand of the tool
+type command struct {
+	// name is the name of the command
+	name string
+	// usage is the synopsis of the arguments of the command
+	usage string
+	// summary is the description of the command
+	summary string
+	// setup registers the flags of the command and returns its run function
+	setup func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error
+}
+
+// commands are the commands of the tool, by name
+var commands = map[string]command{}
+
+// register is a function that adds commands to the tool
+func register(cmds ...command) {
+	for _, c := range cmds {
+		commands[c.name] = c
+	}
+}
+
+// usageError is a struct that represents a command used wrongly, e.g. a missing argument
+type usageError struct {
+	// message is the description of the error
+	message string
+}
+
+// Error is a method that returns the description of the error
+func (e usageError) Error() string {
+	return e.message
+}
+
+// env is a struct that represents the environment of a command: the global flags, the profile and the streams
+type env struct {
+	// stdin, stdout and stderr are the streams of the tool
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// lookupEnv returns the environment variables
+	lookupEnv func(key string) (string, bool)
+
+	// configPath is the path of the file of the profiles
+	configPath string
+	// profile is the name of the profile, the current one if empty
+	profile string
+	// url, apiKey, token, tenant and units override the ones of the profile if given
+	url    string
+	apiKey string
+	token  string
+	tenant string
+	units  string
+	// output is the format of the output: table, json or yaml
+	output string
+	// timeout bounds the requests of a command, zero for no limit
+	timeout time.Duration
+}
+
+// flags is a method that registers the global flags, accepted by every command
+func (e *env) flags(fs *flag.FlagSet) {
+	fs.StringVar(&e.configPath, "config", e.configPath, "path of the file of the profiles")
+	fs.StringVar(&e.profile, "profile", e.profile, "profile of the server, the current one by default")
+	fs.StringVar(&e.url, "url", e.url, "URL of the server, overrides the profile")
+	fs.StringVar(&e.apiKey, "api_key", e.apiKey, "API key of the caller, overrides the profile")
+	fs.StringVar(&e.token, "token", e.token, "bearer token of the caller, overrides the profile")
+	fs.StringVar(&e.tenant, "tenant", e.tenant, "tenant of the vehicles, overrides the profile")
+	fs.StringVar(&e.units, "units", e.units, "unit system of the measures: metric or imperial, overrides the profile")
+	fs.StringVar(&e.output, "o", e.output, "output format: table, json or yaml, overrides the profile")
+	fs.DurationVar(&e.timeout, "timeout", e.timeout, "timeout of the requests, 0 for no limit")
+}
+
+// client is a method that returns the client of the server of the profile, with the overrides of the environment and the flags
+func (e *env) client() (c *client.Client, err error) {
+	p, err := e.resolve()
+	if err != nil {
+		return
+	}
+	return client.NewClient(client.Config{
+		BaseURL: p.URL,
+		APIKey:  p.APIKey,
+		Token:   p.Token,
+		Tenant:  p.Tenant,
+		Units:   p.Units,
+	})
+}
+
+// printer is a method that returns the printer of the output format
+func (e *env) printer() (p *printer, err error) {
+	pr, err := e.resolve()
+	if err != nil {
+		return
+	}
+	return newPrinter(e.stdout, pr.Output)
+}
+
+// withTimeout is a method that returns the context of the requests of a command, bounded by the timeout
+func (e *env) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, e.timeout)
+}
+
+// run is a function that runs the tool with the arguments and returns its exit code
+// - 0 on success, 1 on a failure, 2 on a wrong usage
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, lookupEnv func(key string) (string, bool)) int {
+	e := &env{stdin: stdin, stdout: stdout, stderr: stderr, lookupEnv: lookupEnv, timeout: defaultTimeout}
+
+	// global flags
+	fs := flag.NewFlagSet("vehiclectl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	e.flags(fs)
+	fs.Usage = func() { usage(stderr) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	// command
+	name, args := fs.Arg(0), fs.Args()[1:]
+	if name == completeCommand {
+		for _, c := range complete(e, args) {
+			fmt.Fprintln(stdout, c)
+		}
+		return 0
+	}
+	if name == "help" {
+		if len(args) == 0 {
+			usage(stdout)
+			return 0
+		}
+		name, args = args[0], []string{"-h"}
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "vehiclectl: unknown command %q\n", name)
+		usage(stderr)
+		return 2
+	}
+	cfs := flag.NewFlagSet("vehiclectl "+cmd.name, flag.ContinueOnError)
+	cfs.SetOutput(stderr)
+	e.flags(cfs)
+	runCmd := cmd.setup(cfs, e)
+	cfs.Usage = func() {
+		// - the defaults are the ones of the tool, not the values of the global flags already parsed
+		fmt.Fprintf(stderr, "Usage: vehiclectl %s %s\n\n%s\n\nFlags:\n", cmd.name, cmd.usage, cmd.summary)
+		dfs := commandFlags(&env{lookupEnv: lookupEnv, timeout: defaultTimeout}, &cmd)
+		dfs.SetOutput(stderr)
+		dfs.PrintDefaults()
+	}
+	positional, err := parse(cfs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	if err = runCmd(ctx, positional); err != nil {
+		fmt.Fprintln(stderr, "vehiclectl:", message(err))
+		var ue usageError
+		if errors.As(err, &ue) {
+			cfs.Usage()
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+// parse is a function that parses the flags of a command, before and after its positional arguments
+// - so the flags can follow the arguments, e.g. vehiclectl get 1 -o json
+func parse(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return
+		}
+		if fs.NArg() == 0 {
+			return
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// message is a function that returns the description of an error for the user
+// - the errors of the server are described by their message, with their code unless the message is the code
+func message(err error) string {
+	var ce *client.Error
+	if errors.As(err, &ce) {
+		if strings.EqualFold(ce.Message, strings.ReplaceAll(ce.Code, "_", " ")) {
+			return ce.Message
+		}
+		return ce.Message + " (" + ce.Code + ")"
+	}
+	return err.Error()
+}
+
+// usage is a function that writes the usage of the tool
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprint(w, "vehiclectl manages the vehicles of the vehicles API.\n\nUsage: vehiclectl [flags] <command> [flags] [args]\n\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-11s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprint(w, "\nRun vehiclectl help <command> for the flags of a command, every command accepts the flags of the server and the output.\n")
+}
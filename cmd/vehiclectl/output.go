@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formats are the output formats, the first is the default
+var formats = []string{"table", "json", "yaml"}
+
+// newPrinter is a function that returns a new instance of printer, writing in the format, table if empty
+func newPrinter(w io.Writer, format string) (p *printer, err error) {
+	switch format {
+	case "":
+		format = formats[0]
+	case "table", "json", "yaml":
+	default:
+		return nil, usageError{"invalid output format " + format + ", use " + strings.Join(formats, ", ")}
+	}
+	return &printer{w: w, format: format}, nil
+}
+
+// printer is a struct that represents the output of a command, as a table, JSON or YAML
+type printer struct {
+	// w is the writer of the output
+	w io.Writer
+	// format is the output format
+	format string
+	// streamed is true once the header of a stream was written
+	streamed bool
+}
+
+// print is a method that writes a value, the rows of its table under the header in the table format
+// - JSON is indented, YAML has the field names of JSON in the same order
+func (p *printer) print(v any, header []string, rows [][]string) error {
+	switch p.format {
+	case "json":
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	case "yaml":
+		return p.yaml(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 0, 3, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// stream is a method that writes a value of a stream as soon as it is known, e.g. an event
+// - a JSON document per line, YAML documents separated by ---, or a row of a table whose header is written first
+// - the columns of the rows are separated by tabs, as they can not be aligned in advance
+func (p *printer) stream(v any, header, row []string) (err error) {
+	switch p.format {
+	case "json":
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(p.w, "%s\n", b)
+		return err
+	case "yaml":
+		if p.streamed {
+			fmt.Fprintln(p.w, "---")
+		}
+		p.streamed = true
+		return p.yaml(v)
+	}
+
+	if !p.streamed {
+		fmt.Fprintln(p.w, strings.Join(header, "\t"))
+		p.streamed = true
+	}
+	_, err = fmt.Fprintln(p.w, strings.Join(row, "\t"))
+	return
+}
+
+// yaml is a method that writes a value as YAML, with the field names of JSON
+// - the JSON of the value is read as a YAML node, so the order of the fields is kept
+func (p *printer) yaml(v any) (err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return
+	}
+	plain(&node)
+	enc := yaml.NewEncoder(p.w)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return
+	}
+	return enc.Close()
+}
+
+// plain is a function that clears the styles of the nodes read from JSON, so they are written as block YAML
+func plain(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		plain(c)
+	}
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultURL is the URL of the server if no profile names one
+const defaultURL = "http://localhost:8080"
+
+// profile is a struct that represents a server and the credentials to call it
+type profile struct {
+	URL    string `yaml:"url"`
+	APIKey string `yaml:"api_key,omitempty"`
+	Token  string `yaml:"token,omitempty"`
+	Tenant string `yaml:"tenant,omitempty"`
+	Units  string `yaml:"units,omitempty"`
+	Output string `yaml:"output,omitempty"`
+}
+
+// profiles is a struct that represents the file of the profiles
+type profiles struct {
+	// Current is the name of the profile used if none is requested
+	Current string `yaml:"current,omitempty"`
+	// Profiles are the profiles, by name
+	Profiles map[string]profile `yaml:"profiles"`
+}
+
+// path is a method that returns the path of the file of the profiles
+// - the --config flag, then $VEHICLECTL_CONFIG, then vehiclectl/config.yaml in the user config directory
+func (e *env) path() (path string, err error) {
+	if e.configPath != "" {
+		return e.configPath, nil
+	}
+	if path, ok := e.lookupEnv(envPrefix + "CONFIG"); ok && path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return
+	}
+	return filepath.Join(dir, "vehiclectl", "config.yaml"), nil
+}
+
+// load is a method that reads the file of the profiles, no profiles if it does not exist
+func (e *env) load() (p *profiles, err error) {
+	path, err := e.path()
+	if err != nil {
+		return
+	}
+	p = &profiles{Profiles: make(map[string]profile)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return
+	}
+	if err = yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("profiles %s: %w", path, err)
+	}
+	if p.Profiles == nil {
+		p.Profiles = make(map[string]profile)
+	}
+	return
+}
+
+// save is a method that writes the file of the profiles, readable by the user only as it holds credentials
+func (e *env) save(p *profiles) (err error) {
+	path, err := e.path()
+	if err != nil {
+		return
+	}
+	data, err := yaml.Marshal(p)
+	if err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// resolve is a method that returns the effective profile, layered by precedence:
+// the profile (--profile, then $VEHICLECTL_PROFILE, then the current one), then the environment variables, then the flags
+func (e *env) resolve() (p profile, err error) {
+	// - profile
+	ps, err := e.load()
+	if err != nil {
+		return
+	}
+	name := e.profile
+	if name == "" {
+		name, _ = e.lookupEnv(envPrefix + "PROFILE")
+	}
+	if name != "" {
+		var ok bool
+		if p, ok = ps.Profiles[name]; !ok {
+			return p, fmt.Errorf("profile %q not found", name)
+		}
+	} else if ps.Current != "" {
+		p = ps.Profiles[ps.Current]
+	}
+
+	// - environment and flags
+	layers := []struct {
+		target *string
+		env    string
+		flag   string
+	}{
+		{&p.URL, "URL", e.url},
+		{&p.APIKey, "API_KEY", e.apiKey},
+		{&p.Token, "TOKEN", e.token},
+		{&p.Tenant, "TENANT", e.tenant},
+		{&p.Units, "UNITS", e.units},
+		{&p.Output, "OUTPUT", e.output},
+	}
+	for _, l := range layers {
+		if value, ok := e.lookupEnv(envPrefix + l.env); ok && value != "" {
+			*l.target = value
+		}
+		if l.flag != "" {
+			*l.target = l.flag
+		}
+	}
+	if p.URL == "" {
+		p.URL = defaultURL
+	}
+	return
+}
+
+func init() {
+	register(command{
+		name:    "profile",
+		usage:   "list | show [name] | use <name> | set <name> [--url ...] [--api_key ...] | delete <name>",
+		summary: "Manage the profiles of the servers. set stores the --url, --api_key, --token, --tenant, --units and -o flags given.",
+		setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+			return func(ctx context.Context, args []string) (err error) {
+				if len(args) == 0 {
+					return usageError{"missing subcommand"}
+				}
+				ps, err := e.load()
+				if err != nil {
+					return
+				}
+				sub, args := args[0], args[1:]
+				name := ""
+				if len(args) > 0 {
+					name = args[0]
+				}
+				if len(args) > 1 || (name == "" && sub != "list" && sub != "show") {
+					return usageError{"profile " + sub + ": expected one profile name"}
+				}
+
+				switch sub {
+				case "list":
+					return e.listProfiles(ps)
+				case "show":
+					if name == "" {
+						name = ps.Current
+					}
+					p, ok := ps.Profiles[name]
+					if !ok {
+						return fmt.Errorf("profile %q not found", name)
+					}
+					return e.listProfiles(&profiles{Current: ps.Current, Profiles: map[string]profile{name: p}})
+				case "use":
+					if _, ok := ps.Profiles[name]; !ok {
+						return fmt.Errorf("profile %q not found", name)
+					}
+					ps.Current = name
+				case "set":
+					// - the flags given, the others are kept
+					p := ps.Profiles[name]
+					fs.Visit(func(f *flag.Flag) {
+						switch f.Name {
+						case "url":
+							p.URL = e.url
+						case "api_key":
+							p.APIKey = e.apiKey
+						case "token":
+							p.Token = e.token
+						case "tenant":
+							p.Tenant = e.tenant
+						case "units":
+							p.Units = e.units
+						case "o":
+							p.Output = e.output
+						}
+					})
+					if p.URL == "" {
+						p.URL = defaultURL
+					}
+					ps.Profiles[name] = p
+					if ps.Current == "" {
+						ps.Current = name
+					}
+				case "delete":
+					if _, ok := ps.Profiles[name]; !ok {
+						return fmt.Errorf("profile %q not found", name)
+					}
+					delete(ps.Profiles, name)
+					if ps.Current == name {
+						ps.Current = ""
+					}
+				default:
+					return usageError{"unknown subcommand " + sub}
+				}
+				return e.save(ps)
+			}
+		},
+	})
+}
+
+// listProfiles is a method that prints the profiles, with their credentials redacted
+func (e *env) listProfiles(ps *profiles) error {
+	// - the output format of the flags only, the profiles are not resolved
+	pr, err := newPrinter(e.stdout, e.output)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(ps.Profiles))
+	for name := range ps.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	type profileView struct {
+		Name    string `json:"name" yaml:"name"`
+		Current bool   `json:"current" yaml:"current"`
+		URL     string `json:"url" yaml:"url"`
+		Auth    string `json:"auth,omitempty" yaml:"auth,omitempty"`
+		Tenant  string `json:"tenant,omitempty" yaml:"tenant,omitempty"`
+		Units   string `json:"units,omitempty" yaml:"units,omitempty"`
+		Output  string `json:"output,omitempty" yaml:"output,omitempty"`
+	}
+	views := make([]profileView, 0, len(names))
+	rows := make([][]string, 0, len(names))
+	for _, name := range names {
+		p := ps.Profiles[name]
+		v := profileView{Name: name, Current: name == ps.Current, URL: p.URL, Tenant: p.Tenant, Units: p.Units, Output: p.Output}
+		switch {
+		case p.APIKey != "":
+			v.Auth = "api_key"
+		case p.Token != "":
+			v.Auth = "token"
+		}
+		views = append(views, v)
+		current := ""
+		if v.Current {
+			current = "*"
+		}
+		rows = append(rows, []string{current, v.Name, v.URL, v.Auth, v.Tenant, v.Units, v.Output})
+	}
+	return pr.print(views, []string{"CURRENT", "NAME", "URL", "AUTH", "TENANT", "UNITS", "OUTPUT"}, rows)
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"app/client"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// csvColumns are the columns of the vehicles in CSV, named by their JSON fields as the CSV of the API
+var csvColumns = []string{"id", "brand", "model", "registration", "color", "year", "passengers", "max_speed", "fuel_type", "transmission", "weight", "height", "length", "width"}
+
+// exportPageSize is the number of vehicles of the pages read by an export
+const exportPageSize = 500
+
+// fileFormat is a function that returns the format of a file of vehicles: the requested one, or the one of its extension, JSON by default
+func fileFormat(requested, path string) (format string, err error) {
+	format = strings.ToLower(requested)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case "csv", "json":
+		return
+	case "", "-":
+		return "json", nil
+	}
+	return "", usageError{"invalid format " + format + ", use csv or json"}
+}
+
+// writeCSV is a function that writes vehicles as CSV, with a header row
+func writeCSV(w io.Writer, vehicles []client.Vehicle) (err error) {
+	cw := csv.NewWriter(w)
+	cw.Write(csvColumns)
+	for _, v := range vehicles {
+		var row []string
+		if row, err = columnValues(v, csvColumns); err != nil {
+			return
+		}
+		cw.Write(row)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCSV is a function that reads vehicles of CSV, with a header row naming the columns
+// - the columns may be in any order, empty cells are left to the zero value
+func readCSV(r io.Reader) (vehicles []client.Vehicle, err error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return
+	}
+	if len(records) == 0 {
+		return nil, errors.New("csv: header row missing")
+	}
+
+	header := records[0]
+	for _, name := range header {
+		if name != "id" && patchFields[name] == "" {
+			return nil, fmt.Errorf("csv: unknown column %q", name)
+		}
+	}
+	for i, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for j, value := range record {
+			if value == "" {
+				continue
+			}
+			switch name := header[j]; {
+			case name == "id" || patchFields[name] == "int":
+				if row[name], err = strconv.Atoi(value); err != nil {
+					return nil, fmt.Errorf("csv: line %d: %s must be an integer", i+2, name)
+				}
+			case patchFields[name] == "float":
+				if row[name], err = strconv.ParseFloat(value, 64); err != nil {
+					return nil, fmt.Errorf("csv: line %d: %s must be a number", i+2, name)
+				}
+			default:
+				row[name] = value
+			}
+		}
+		b, _ := json.Marshal(row)
+		var v client.Vehicle
+		if err = json.Unmarshal(b, &v); err != nil {
+			return
+		}
+		vehicles = append(vehicles, v)
+	}
+	return
+}
+
+func init() {
+	register(
+		command{
+			name:    "export",
+			usage:   "[filters] [--sort field] [-f file.csv|file.json] [--format csv|json]",
+			summary: "Write every vehicle matching the filters to a file, or the standard output.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				var lf listFlags
+				lf.register(fs)
+				file := fs.String("f", "-", "file written, - for the standard output")
+				format := fs.String("format", "", "format of the file: csv or json, by the extension of the file or json")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 0 {
+						return usageError{"unexpected arguments"}
+					}
+					ff, err := fileFormat(*format, *file)
+					if err != nil {
+						return
+					}
+					opts, err := lf.options()
+					if err != nil {
+						return
+					}
+					opts.Limit = exportPageSize
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+
+					// read
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					vehicles := []client.Vehicle{}
+					it := c.Vehicles(opts)
+					for it.Next(ctx) {
+						vehicles = append(vehicles, it.Vehicle())
+					}
+					if err = it.Err(); err != nil {
+						return
+					}
+
+					// write
+					w := e.stdout
+					if *file != "-" {
+						f, err := os.Create(*file)
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+						w = f
+					}
+					if ff == "csv" {
+						err = writeCSV(w, vehicles)
+					} else {
+						enc := json.NewEncoder(w)
+						enc.SetIndent("", "  ")
+						err = enc.Encode(vehicles)
+					}
+					if err != nil {
+						return
+					}
+					if *file != "-" {
+						fmt.Fprintf(e.stderr, "%d vehicles exported to %s\n", len(vehicles), *file)
+					}
+					return
+				}
+			},
+		},
+		command{
+			name:    "import",
+			usage:   "-f <file.csv|file.json|-> [--format csv|json] [--batch n]",
+			summary: "Create the vehicles of a file in batches, each batch is created entirely or not at all.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				file := fs.String("f", "", "file read, - for the standard input")
+				format := fs.String("format", "", "format of the file: csv or json, by the extension of the file or json")
+				batch := fs.Int("batch", 100, "number of vehicles created at once")
+				return func(ctx context.Context, args []string) (err error) {
+					if *file == "" || len(args) != 0 {
+						return usageError{"expected -f file"}
+					}
+					if *batch <= 0 {
+						return usageError{"--batch must be positive"}
+					}
+					ff, err := fileFormat(*format, *file)
+					if err != nil {
+						return
+					}
+
+					// read
+					var r io.Reader = e.stdin
+					if *file != "-" {
+						f, err := os.Open(*file)
+						if err != nil {
+							return err
+						}
+						defer f.Close()
+						r = f
+					}
+					var vehicles []client.Vehicle
+					if ff == "csv" {
+						vehicles, err = readCSV(r)
+					} else {
+						dec := json.NewDecoder(r)
+						dec.DisallowUnknownFields()
+						err = dec.Decode(&vehicles)
+					}
+					if err != nil {
+						return fmt.Errorf("%s: %w", *file, err)
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+
+					// create
+					// - the idempotency key of a batch is its hash, so an import run again replays the batches already created
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					imported := 0
+					for start := 0; start < len(vehicles); start += *batch {
+						chunk := vehicles[start:min(start+*batch, len(vehicles))]
+						b, _ := json.Marshal(chunk)
+						sum := sha256.Sum256(b)
+						if _, err = c.CreateVehicles(client.WithIdempotencyKey(ctx, "import-"+hex.EncodeToString(sum[:16])), chunk); err != nil {
+							return fmt.Errorf("%d of %d vehicles imported, batch from vehicle %d: %s", imported, len(vehicles), start, message(err))
+						}
+						imported += len(chunk)
+						fmt.Fprintf(e.stderr, "%d of %d vehicles imported\n", imported, len(vehicles))
+					}
+					return
+				}
+			},
+		},
+	)
+}
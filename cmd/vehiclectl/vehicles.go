@@ -0,0 +1,635 @@
+package main
+
+import (
+	"app/client"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultColumns are the columns of the vehicles in the table format, unless the fields are requested
+var defaultColumns = []string{"id", "brand", "model", "year", "color", "fuel_type", "transmission", "max_speed", "passengers"}
+
+// vehicleRows is a function that returns the rows of the vehicles in the table format, with the values of the columns
+func vehicleRows(vehicles []client.Vehicle, columns []string) (rows [][]string, err error) {
+	for _, v := range vehicles {
+		var row []string
+		if row, err = columnValues(v, columns); err != nil {
+			return
+		}
+		rows = append(rows, row)
+	}
+	return
+}
+
+// columnValues is a function that returns the values of the columns of a value, by the names of its JSON fields
+func columnValues(v any, columns []string) (row []string, err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	var fields map[string]any
+	if err = json.Unmarshal(b, &fields); err != nil {
+		return
+	}
+	row = make([]string, len(columns))
+	for i, c := range columns {
+		switch value := fields[c].(type) {
+		case nil:
+		case float64:
+			row[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			row[i] = fmt.Sprint(value)
+		}
+	}
+	return
+}
+
+// upper is a function that returns the names of the columns in upper case, as the header of a table
+func upper(columns []string) []string {
+	header := make([]string, len(columns))
+	for i, c := range columns {
+		header[i] = strings.ToUpper(c)
+	}
+	return header
+}
+
+// printVehicles is a function that prints vehicles, with the columns of the fields if requested
+func printVehicles(p *printer, v any, vehicles []client.Vehicle, fields, include []string) error {
+	columns := defaultColumns
+	if len(fields) > 0 {
+		columns = fields
+	}
+	columns = append(columns[:len(columns):len(columns)], include...)
+	rows, err := vehicleRows(vehicles, columns)
+	if err != nil {
+		return err
+	}
+
+	// - in JSON and YAML, the requested fields only, as the vehicles have every field
+	if len(fields) > 0 && p.format != "table" {
+		selected := make([]selection, len(vehicles))
+		for i, vehicle := range vehicles {
+			if selected[i], err = selectFields(vehicle, columns); err != nil {
+				return err
+			}
+		}
+		if _, ok := v.(*client.Vehicle); ok {
+			v = selected[0]
+		} else {
+			v = selected
+		}
+	}
+	return p.print(v, upper(columns), rows)
+}
+
+// selection is a struct that represents some fields of a value, written in JSON in the order of their names
+type selection struct {
+	// names are the names of the fields
+	names []string
+	// values are the JSON values of the fields, by name
+	values map[string]json.RawMessage
+}
+
+// selectFields is a function that returns the fields of a value, by the names of its JSON fields
+func selectFields(v any, names []string) (s selection, err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	s.names = names
+	err = json.Unmarshal(b, &s.values)
+	return
+}
+
+// MarshalJSON is a method that returns the JSON object of the fields, without the ones the value does not have
+func (s selection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, name := range s.names {
+		value, ok := s.values[name]
+		if !ok {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// split is a function that returns the comma separated values of a flag, none if empty
+func split(s string) (values []string) {
+	for _, value := range strings.Split(s, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return
+}
+
+// parseRange is a function that returns the range of a filter: a value, or a range in the format min-max, min- or -max
+func parseRange(name, s string) (r client.Range, err error) {
+	if s == "" {
+		return
+	}
+	invalid := usageError{"invalid --" + name + " " + strconv.Quote(s) + ", use a value, min-max, min- or -max"}
+	minStr, maxStr, isRange := strings.Cut(s, "-")
+	if !isRange {
+		value, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return r, invalid
+		}
+		return client.Between(value, value), nil
+	}
+	if minStr == "" && maxStr == "" {
+		return r, invalid
+	}
+	if minStr != "" {
+		value, err := strconv.ParseFloat(minStr, 64)
+		if err != nil {
+			return r, invalid
+		}
+		r.Min = &value
+	}
+	if maxStr != "" {
+		value, err := strconv.ParseFloat(maxStr, 64)
+		if err != nil {
+			return r, invalid
+		}
+		r.Max = &value
+	}
+	return
+}
+
+// vehicleID is a function that returns the id of a vehicle of the arguments
+func vehicleID(arg string) (id int, err error) {
+	id, err = strconv.Atoi(arg)
+	if err != nil {
+		return 0, usageError{"invalid vehicle id " + strconv.Quote(arg)}
+	}
+	return
+}
+
+// listFlags is a struct that represents the flags filtering and sorting the vehicles of a list or an export
+type listFlags struct {
+	brand, color, fuelType, transmission          string
+	year, maxSpeed, weight, height, length, width string
+	sort                                          string
+}
+
+// register is a method that registers the flags
+func (l *listFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&l.brand, "brand", "", "brand of the vehicles")
+	fs.StringVar(&l.color, "color", "", "color of the vehicles")
+	fs.StringVar(&l.fuelType, "fuel_type", "", "fuel type of the vehicles")
+	fs.StringVar(&l.transmission, "transmission", "", "transmission of the vehicles")
+	fs.StringVar(&l.year, "year", "", "fabrication year: a value, min-max, min- or -max")
+	fs.StringVar(&l.maxSpeed, "max_speed", "", "maximum speed: a value, min-max, min- or -max")
+	fs.StringVar(&l.weight, "weight", "", "weight: a value, min-max, min- or -max")
+	fs.StringVar(&l.height, "height", "", "height: a value, min-max, min- or -max")
+	fs.StringVar(&l.length, "length", "", "length: a value, min-max, min- or -max")
+	fs.StringVar(&l.width, "width", "", "width: a value, min-max, min- or -max")
+	fs.StringVar(&l.sort, "sort", "", "field to sort by, -field for descending order, id by default")
+}
+
+// options is a method that returns the options of the list of the flags
+func (l *listFlags) options() (opts client.ListOptions, err error) {
+	opts = client.ListOptions{Brand: l.brand, Color: l.color, FuelType: l.fuelType, Transmission: l.transmission, Sort: l.sort}
+	ranges := []struct {
+		name   string
+		value  string
+		target *client.Range
+	}{
+		{"year", l.year, &opts.Year},
+		{"max_speed", l.maxSpeed, &opts.MaxSpeed},
+		{"weight", l.weight, &opts.Weight},
+		{"height", l.height, &opts.Height},
+		{"length", l.length, &opts.Length},
+		{"width", l.width, &opts.Width},
+	}
+	for _, r := range ranges {
+		if *r.target, err = parseRange(r.name, r.value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// readDocument is a function that reads a JSON or YAML document from a file, or the standard input if "-", as JSON
+// - YAML has the field names of JSON, e.g. max_speed, the standard input may be either
+func readDocument(path string, stdin io.Reader) (data []byte, err error) {
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return
+	}
+
+	// - YAML is read as JSON, which is a subset of YAML
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" || path == "-" {
+		var doc any
+		if err = yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return
+}
+
+// decodeStrict is a function that decodes a JSON document into v, rejecting the unknown fields
+func decodeStrict(path string, data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// patchFields are the kinds of the fields of a patch, by name: string, int or float
+var patchFields = map[string]string{
+	"brand": "string", "model": "string", "registration": "string", "color": "string",
+	"year": "int", "passengers": "int",
+	"max_speed": "float", "fuel_type": "string", "transmission": "string",
+	"weight": "float", "height": "float", "length": "float", "width": "float",
+}
+
+// setFlag is a type that represents the repeatable --set field=value flag of a patch
+type setFlag map[string]any
+
+// String is a method that returns the fields set
+func (s setFlag) String() string {
+	return fmt.Sprint(map[string]any(s))
+}
+
+// Set is a method that sets a field, parsing its value by the kind of the field
+func (s setFlag) Set(value string) (err error) {
+	name, raw, ok := strings.Cut(value, "=")
+	if !ok {
+		return errors.New("expected field=value")
+	}
+	switch patchFields[name] {
+	case "string":
+		s[name] = raw
+	case "int":
+		if s[name], err = strconv.Atoi(raw); err != nil {
+			return errors.New(name + " must be an integer")
+		}
+	case "float":
+		if s[name], err = strconv.ParseFloat(raw, 64); err != nil {
+			return errors.New(name + " must be a number")
+		}
+	default:
+		return errors.New("unknown field " + name)
+	}
+	return
+}
+
+func init() {
+	register(
+		command{
+			name:    "get",
+			usage:   "<id> [--fields f1,f2] [--include age,volume]",
+			summary: "Print a vehicle.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				fields := fs.String("fields", "", "comma separated fields of the vehicle, every field by default")
+				include := fs.String("include", "", "comma separated computed fields: age, volume, power_to_weight, footprint")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 1 {
+						return usageError{"expected one vehicle id"}
+					}
+					id, err := vehicleID(args[0])
+					if err != nil {
+						return
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					v, err := c.GetVehicle(ctx, id, split(*fields), split(*include))
+					if err != nil {
+						return
+					}
+					return printVehicles(p, v, []client.Vehicle{*v}, split(*fields), split(*include))
+				}
+			},
+		},
+		command{
+			name:    "list",
+			usage:   "[filters] [--sort field] [--limit n] [--offset n] [--all]",
+			summary: "Print the vehicles matching the filters, a page or --all of them.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				var lf listFlags
+				lf.register(fs)
+				limit := fs.Int("limit", 50, "number of vehicles of the page, up to 500")
+				offset := fs.Int("offset", 0, "number of vehicles skipped")
+				all := fs.Bool("all", false, "every vehicle from the offset, read page by page")
+				fields := fs.String("fields", "", "comma separated fields of the vehicles, every field by default")
+				include := fs.String("include", "", "comma separated computed fields: age, volume, power_to_weight, footprint")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 0 {
+						return usageError{"unexpected arguments"}
+					}
+					opts, err := lf.options()
+					if err != nil {
+						return
+					}
+					opts.Limit, opts.Offset, opts.Fields, opts.Include = *limit, *offset, split(*fields), split(*include)
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					vehicles := []client.Vehicle{}
+					if *all {
+						it := c.Vehicles(opts)
+						for it.Next(ctx) {
+							vehicles = append(vehicles, it.Vehicle())
+						}
+						if err = it.Err(); err != nil {
+							return
+						}
+					} else {
+						page, err := c.ListVehicles(ctx, opts)
+						if err != nil {
+							return err
+						}
+						vehicles = page.Vehicles
+						if p.format == "table" && page.Offset+len(page.Vehicles) < page.Total {
+							defer fmt.Fprintf(e.stderr, "%d of %d vehicles, see --offset or --all\n", len(page.Vehicles), page.Total)
+						}
+					}
+					return printVehicles(p, vehicles, vehicles, opts.Fields, opts.Include)
+				}
+			},
+		},
+		command{
+			name:    "create",
+			usage:   "-f <file.json|file.yaml|->",
+			summary: "Create the vehicle of a file, or every vehicle of a list or none.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				file := fs.String("f", "", "JSON or YAML file of a vehicle or a list of vehicles, - for the standard input")
+				return func(ctx context.Context, args []string) (err error) {
+					if *file == "" || len(args) != 0 {
+						return usageError{"expected -f file"}
+					}
+					doc, err := readDocument(*file, e.stdin)
+					if err != nil {
+						return
+					}
+					var vehicles []client.Vehicle
+					list := bytes.HasPrefix(doc, []byte("["))
+					if list {
+						err = decodeStrict(*file, doc, &vehicles)
+					} else {
+						vehicles = make([]client.Vehicle, 1)
+						err = decodeStrict(*file, doc, &vehicles[0])
+					}
+					if err != nil {
+						return
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					if !list {
+						v, err := c.CreateVehicle(ctx, vehicles[0])
+						if err != nil {
+							return err
+						}
+						return printVehicles(p, v, []client.Vehicle{*v}, nil, nil)
+					}
+					created, err := c.CreateVehicles(ctx, vehicles)
+					if err != nil {
+						return
+					}
+					return printVehicles(p, created, created, nil, nil)
+				}
+			},
+		},
+		command{
+			name:    "patch",
+			usage:   "<id> --set field=value [--set ...] | -f <file.json|file.yaml|->",
+			summary: "Change the fields of a vehicle, the others are left as they are.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				set := make(setFlag)
+				fs.Var(set, "set", "field=value to change, repeatable")
+				file := fs.String("f", "", "JSON or YAML file of the fields to change, - for the standard input")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 1 {
+						return usageError{"expected one vehicle id"}
+					}
+					id, err := vehicleID(args[0])
+					if err != nil {
+						return
+					}
+					if (*file == "") == (len(set) == 0) {
+						return usageError{"expected either --set or -f"}
+					}
+					var patch client.VehiclePatch
+					if *file != "" {
+						var doc []byte
+						if doc, err = readDocument(*file, e.stdin); err == nil {
+							err = decodeStrict(*file, doc, &patch)
+						}
+					} else {
+						b, _ := json.Marshal(set)
+						err = json.Unmarshal(b, &patch)
+					}
+					if err != nil {
+						return
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					v, err := c.PatchVehicle(ctx, id, patch)
+					if err != nil {
+						return
+					}
+					return printVehicles(p, v, []client.Vehicle{*v}, nil, nil)
+				}
+			},
+		},
+		command{
+			name:    "delete",
+			usage:   "<id> [id ...]",
+			summary: "Delete vehicles, stopping at the first failure.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) == 0 {
+						return usageError{"expected vehicle ids"}
+					}
+					ids := make([]int, len(args))
+					for i, arg := range args {
+						if ids[i], err = vehicleID(arg); err != nil {
+							return
+						}
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					for _, id := range ids {
+						if err = c.DeleteVehicle(ctx, id); err != nil {
+							return fmt.Errorf("vehicle %d: %s", id, message(err))
+						}
+						fmt.Fprintf(e.stderr, "vehicle %d deleted\n", id)
+					}
+					return
+				}
+			},
+		},
+		command{
+			name:    "stats",
+			usage:   "[--group_by brand|fuel_type|year] [--metric max_speed|capacity|weight]",
+			summary: "Print the statistics of a metric for every key of a group.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				groupBy := fs.String("group_by", "brand", "group of the vehicles: brand, fuel_type or year")
+				metric := fs.String("metric", "max_speed", "metric: max_speed, capacity or weight")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 0 {
+						return usageError{"unexpected arguments"}
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					ctx, cancel := e.withTimeout(ctx)
+					defer cancel()
+					stats, err := c.VehicleStats(ctx, *groupBy, *metric)
+					if err != nil {
+						return
+					}
+					columns := []string{"key", "count", "mean", "min", "max", "stddev"}
+					rows := make([][]string, 0, len(stats))
+					for _, s := range stats {
+						rows = append(rows, []string{
+							s.Key,
+							strconv.Itoa(s.Count),
+							strconv.FormatFloat(s.Mean, 'f', 2, 64),
+							strconv.FormatFloat(s.Min, 'f', 2, 64),
+							strconv.FormatFloat(s.Max, 'f', 2, 64),
+							strconv.FormatFloat(s.StdDev, 'f', 2, 64),
+						})
+					}
+					return p.print(stats, upper(columns), rows)
+				}
+			},
+		},
+		command{
+			name:    "watch",
+			usage:   "[--type created,updated,deleted]",
+			summary: "Print the changes made to the vehicles from now on, until interrupted.",
+			setup: func(fs *flag.FlagSet, e *env) func(ctx context.Context, args []string) error {
+				types := fs.String("type", "", "comma separated types of the events: created, updated or deleted, every type by default")
+				return func(ctx context.Context, args []string) (err error) {
+					if len(args) != 0 {
+						return usageError{"unexpected arguments"}
+					}
+					var eventTypes []string
+					for _, t := range split(*types) {
+						t = strings.ToUpper(t)
+						if t != client.EventCreated && t != client.EventUpdated && t != client.EventDeleted {
+							return usageError{"invalid event type " + strings.ToLower(t)}
+						}
+						eventTypes = append(eventTypes, t)
+					}
+					c, err := e.client()
+					if err != nil {
+						return
+					}
+					p, err := e.printer()
+					if err != nil {
+						return
+					}
+
+					// - no timeout, the events are watched until interrupted
+					w, err := c.Watch(ctx, eventTypes...)
+					if err != nil {
+						return
+					}
+					defer w.Close()
+					go func() {
+						<-ctx.Done()
+						w.Close()
+					}()
+					header := append([]string{"EVENT"}, upper(defaultColumns)...)
+					for w.Next() {
+						event := w.Event()
+						v := event.Vehicle
+						if v == nil {
+							v = event.Previous
+						}
+						row, err := columnValues(v, defaultColumns)
+						if err != nil {
+							return err
+						}
+						if err = p.stream(event, header, append([]string{strings.ToLower(event.Type)}, row...)); err != nil {
+							return err
+						}
+					}
+					if ctx.Err() != nil {
+						return nil
+					}
+					return w.Err()
+				}
+			},
+		},
+	)
+}
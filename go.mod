@@ -3,6 +3,24 @@ module app
 go 1.21.2
 
 require (
-	github.com/bootcamp-go/web v1.0.0
+	github.com/BurntSushi/toml v1.4.0
+	github.com/andybalholm/brotli v1.1.1
 	github.com/go-chi/chi/v5 v5.0.11
+	github.com/graph-gophers/graphql-go v1.5.0
+	github.com/stretchr/testify v1.9.0
+	github.com/vektah/gqlparser/v2 v2.5.19
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+	google.golang.org/grpc v1.65.0
+	google.golang.org/protobuf v1.34.2
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.25.0 // indirect
+	golang.org/x/sys v0.20.0 // indirect
+	golang.org/x/text v0.15.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240528184218-531527333157 // indirect
 )
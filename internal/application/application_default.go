@@ -1,93 +1,934 @@
-package application
-
-import (
-	"app/internal/handler"
-	"app/internal/loader"
-	"app/internal/repository"
-	"app/internal/service"
-	"net/http"
-
-	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
-)
-
-// ConfigServerChi is a struct that represents the configuration for ServerChi
-type ConfigServerChi struct {
-	// ServerAddress is the address where the server will be listening
-	ServerAddress string
-	// LoaderFilePath is the path to the file that contains the vehicles
-	LoaderFilePath string
-}
-
-// NewServerChi is a function that returns a new instance of ServerChi
-func NewServerChi(cfg *ConfigServerChi) *ServerChi {
-	// default values
-	defaultConfig := &ConfigServerChi{
-		ServerAddress: ":8080",
-	}
-	if cfg != nil {
-		if cfg.ServerAddress != "" {
-			defaultConfig.ServerAddress = cfg.ServerAddress
-		}
-		if cfg.LoaderFilePath != "" {
-			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
-		}
-	}
-
-	return &ServerChi{
-		serverAddress:  defaultConfig.ServerAddress,
-		loaderFilePath: defaultConfig.LoaderFilePath,
-	}
-}
-
-// ServerChi is a struct that implements the Application interface
-type ServerChi struct {
-	// serverAddress is the address where the server will be listening
-	serverAddress string
-	// loaderFilePath is the path to the file that contains the vehicles
-	loaderFilePath string
-}
-
-// Run is a method that runs the application
-func (a *ServerChi) Run() (err error) {
-	// dependencies
-	// - loader
-	ld := loader.NewVehicleJSONFile(a.loaderFilePath)
-	db, err := ld.Load()
-	if err != nil {
-		return
-	}
-	// - repository
-	rp := repository.NewVehicleMap(db)
-	// - service
-	sv := service.NewVehicleDefault(rp)
-	// - handler
-	hd := handler.NewVehicleDefault(sv)
-	// router
-	rt := chi.NewRouter()
-	// - middlewares
-	rt.Use(middleware.Logger)
-	rt.Use(middleware.Recoverer)
-	// - endpoints
-	rt.Route("/vehicles", func(rt chi.Router) {
-		// - GET /vehicles
-		rt.Get("/", hd.GetAll())
-		rt.Post("/", hd.Create())
-		rt.Get("/color/{color}/year/{year}", hd.GetVehiclesByColorYear())
-		rt.Get("/brand/{brand}/between/{start_year}/{end_year}", hd.GetVehiclesByBrandYears())
-		rt.Get("/average_speed/brand/{brand}", hd.GetAverageSpeedByBrand())
-		rt.Post("/batch", hd.CreateVehicles())
-		rt.Put("/{id}/update_speed", hd.UpdateVehicleSpeed())
-		rt.Get("/fuel_type/{type}", hd.GetVehicleByFuelType())
-		rt.Delete("/{id}", hd.DeleteVehicle())
-		rt.Get("/transmission/{type}", hd.GetByTransmissionType())
-		rt.Put("/{id}/update_fuel", hd.UpdateFuelType())
-		rt.Get("/average_capacity/brand/{brand}", hd.GetAverageCapacityByBrand())
-		rt.Get("/dimensions", hd.GetByDimensions())
-		rt.Get("/weight", hd.GetByWeight())
-	})
-
-	// run server
-	err = http.ListenAndServe(a.serverAddress, rt)
-	return
-}
+package application
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"app/internal/handler"
+	"app/internal/httpcache"
+	"app/internal/idempotency"
+	"app/internal/index"
+	"app/internal/loader"
+	"app/internal/logger"
+	"app/internal/metrics"
+	"app/internal/ratelimit"
+	"app/internal/render"
+	"app/internal/repository"
+	"app/internal/service"
+	"app/internal/tenant"
+	"app/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+var (
+	// ErrAuthNotConfigured is the error returned when neither API keys nor JWT keys are configured and authentication is not disabled
+	ErrAuthNotConfigured = errors.New("authentication not configured: set the API keys or the JWT keys, or disable authentication")
+)
+
+// ConfigServerChi is a struct that represents the configuration for ServerChi
+type ConfigServerChi struct {
+	// ServerAddress is the address where the server will be listening
+	ServerAddress string
+	// ReadTimeout is the maximum duration for reading a request, including its body
+	ReadTimeout time.Duration
+	// WriteTimeout is the maximum duration before timing out writes of a response
+	WriteTimeout time.Duration
+	// IdleTimeout is the maximum duration to wait for the next request on a keep-alive connection
+	IdleTimeout time.Duration
+	// ShutdownTimeout is the maximum duration to drain the in-flight requests on shutdown
+	ShutdownTimeout time.Duration
+	// RepositoryBackend is the storage of the vehicles, only memory is available
+	RepositoryBackend string
+	// LoaderFilePath is the path to the file that contains the vehicles of the default tenant
+	LoaderFilePath string
+	// TenantSeedDir is the directory of the files tenants can be seeded from, empty disables seeding
+	TenantSeedDir string
+	// MaxBodyBytes is the maximum size of a request body, zero means unlimited
+	MaxBodyBytes int64
+	// MaxHeaderBytes is the maximum size of the request headers, zero means http.DefaultMaxHeaderBytes
+	MaxHeaderBytes int
+	// SimilarityWeights are the weights of the attributes used to find similar vehicles
+	SimilarityWeights *index.SimilarityWeights
+	// CacheCapacity is the maximum number of results cached by the service, zero disables the cache
+	CacheCapacity int
+	// CacheTTL is the time to live of a cached result, zero means results do not expire
+	CacheTTL time.Duration
+	// LogLevel is the initial level of the logger (debug, info, warn or error), it can be changed at runtime
+	LogLevel string
+	// AuthAPIKeysFile is the path to the YAML or JSON file of the hashed API keys
+	AuthAPIKeysFile string
+	// AuthPolicyFile is the path to the YAML or JSON file of the roles and the permissions of each route, the default policy if empty
+	AuthPolicyFile string
+	// AuthJWTSecret is the secret verifying HS256 JWTs
+	AuthJWTSecret string
+	// AuthJWTPublicKeyFile is the path to the PEM file of the RSA public key verifying RS256 JWTs
+	AuthJWTPublicKeyFile string
+	// AuthJWKSFile is the path to the JWKS file of the keys verifying JWTs
+	AuthJWKSFile string
+	// AuthJWTIssuer is the issuer required from the JWTs, empty to accept any
+	AuthJWTIssuer string
+	// AuthJWTAudience is the audience required from the JWTs, empty to accept any
+	AuthJWTAudience string
+	// AuthDisabled serves every route without authentication, it must be set if no API keys nor JWT keys are configured
+	AuthDisabled bool
+	// TraceExporter is where the spans are exported: stdout or otlp_file, empty disables tracing
+	TraceExporter string
+	// TraceFilePath is the path to the file the otlp_file exporter appends the spans to
+	TraceFilePath string
+	// TraceSampler is the sampler of the traces, named as in OTEL_TRACES_SAMPLER (default parentbased_always_on)
+	TraceSampler string
+	// TraceSamplerRatio is the ratio of the traces recorded by the traceidratio samplers (default 1)
+	TraceSamplerRatio float64
+	// RateLimits are the rates of each class of route and the daily write quota of every client, nil disables rate limiting
+	RateLimits *ratelimit.Limits
+	// IdempotencyTTL is how long the responses of the requests with an Idempotency-Key are replayed, zero disables idempotency keys
+	IdempotencyTTL time.Duration
+	// CompressionLevel is the level of the gzip, deflate and brotli compression of the responses, from 1 to 9 (default 5)
+	// - a negative level disables compression
+	CompressionLevel int
+	// V1Deprecation is the date the v1 routes were deprecated, announced in their Deprecation header, zero to not announce it
+	V1Deprecation time.Time
+	// V1Sunset is the date the v1 routes stop being served, announced in their Sunset header, zero to not announce it
+	V1Sunset time.Time
+	// GRPCAddress is the address of the gRPC server, empty disables it
+	GRPCAddress string
+	// GraphQLMaxDepth is the maximum depth of the GraphQL queries (default 10)
+	GraphQLMaxDepth int
+	// GraphQLMaxComplexity is the maximum complexity of the GraphQL queries, each field counted once per item of the lists above it (default 1000)
+	GraphQLMaxComplexity int
+}
+
+// NewServerChi is a function that returns a new instance of ServerChi
+func NewServerChi(cfg *ConfigServerChi) *ServerChi {
+	// default values
+	defaultWeights := index.DefaultSimilarityWeights()
+	defaultConfig := &ConfigServerChi{
+		ServerAddress:        ":8080",
+		ReadTimeout:          10 * time.Second,
+		WriteTimeout:         30 * time.Second,
+		IdleTimeout:          2 * time.Minute,
+		ShutdownTimeout:      30 * time.Second,
+		RepositoryBackend:    "memory",
+		SimilarityWeights:    &defaultWeights,
+		LogLevel:             "info",
+		TraceSampler:         "parentbased_always_on",
+		TraceSamplerRatio:    1,
+		GraphQLMaxDepth:      10,
+		GraphQLMaxComplexity: 1000,
+		CompressionLevel:     5,
+	}
+	if cfg != nil {
+		if cfg.ServerAddress != "" {
+			defaultConfig.ServerAddress = cfg.ServerAddress
+		}
+		if cfg.ReadTimeout > 0 {
+			defaultConfig.ReadTimeout = cfg.ReadTimeout
+		}
+		if cfg.WriteTimeout > 0 {
+			defaultConfig.WriteTimeout = cfg.WriteTimeout
+		}
+		if cfg.IdleTimeout > 0 {
+			defaultConfig.IdleTimeout = cfg.IdleTimeout
+		}
+		if cfg.ShutdownTimeout > 0 {
+			defaultConfig.ShutdownTimeout = cfg.ShutdownTimeout
+		}
+		if cfg.RepositoryBackend != "" {
+			defaultConfig.RepositoryBackend = cfg.RepositoryBackend
+		}
+		if cfg.LoaderFilePath != "" {
+			defaultConfig.LoaderFilePath = cfg.LoaderFilePath
+		}
+		if cfg.TenantSeedDir != "" {
+			defaultConfig.TenantSeedDir = cfg.TenantSeedDir
+		}
+		if cfg.MaxBodyBytes > 0 {
+			defaultConfig.MaxBodyBytes = cfg.MaxBodyBytes
+		}
+		if cfg.MaxHeaderBytes > 0 {
+			defaultConfig.MaxHeaderBytes = cfg.MaxHeaderBytes
+		}
+		if cfg.RateLimits != nil {
+			defaultConfig.RateLimits = cfg.RateLimits
+		}
+		if cfg.IdempotencyTTL > 0 {
+			defaultConfig.IdempotencyTTL = cfg.IdempotencyTTL
+		}
+		if cfg.CompressionLevel != 0 {
+			defaultConfig.CompressionLevel = cfg.CompressionLevel
+		}
+		if !cfg.V1Deprecation.IsZero() {
+			defaultConfig.V1Deprecation = cfg.V1Deprecation
+		}
+		if !cfg.V1Sunset.IsZero() {
+			defaultConfig.V1Sunset = cfg.V1Sunset
+		}
+		if cfg.GRPCAddress != "" {
+			defaultConfig.GRPCAddress = cfg.GRPCAddress
+		}
+		if cfg.GraphQLMaxDepth > 0 {
+			defaultConfig.GraphQLMaxDepth = cfg.GraphQLMaxDepth
+		}
+		if cfg.GraphQLMaxComplexity > 0 {
+			defaultConfig.GraphQLMaxComplexity = cfg.GraphQLMaxComplexity
+		}
+		if cfg.SimilarityWeights != nil {
+			defaultConfig.SimilarityWeights = cfg.SimilarityWeights
+		}
+		if cfg.CacheCapacity > 0 {
+			defaultConfig.CacheCapacity = cfg.CacheCapacity
+		}
+		if cfg.CacheTTL > 0 {
+			defaultConfig.CacheTTL = cfg.CacheTTL
+		}
+		if cfg.LogLevel != "" {
+			defaultConfig.LogLevel = cfg.LogLevel
+		}
+		if cfg.AuthAPIKeysFile != "" {
+			defaultConfig.AuthAPIKeysFile = cfg.AuthAPIKeysFile
+		}
+		if cfg.AuthPolicyFile != "" {
+			defaultConfig.AuthPolicyFile = cfg.AuthPolicyFile
+		}
+		if cfg.AuthJWTSecret != "" {
+			defaultConfig.AuthJWTSecret = cfg.AuthJWTSecret
+		}
+		if cfg.AuthJWTPublicKeyFile != "" {
+			defaultConfig.AuthJWTPublicKeyFile = cfg.AuthJWTPublicKeyFile
+		}
+		if cfg.AuthJWKSFile != "" {
+			defaultConfig.AuthJWKSFile = cfg.AuthJWKSFile
+		}
+		if cfg.AuthJWTIssuer != "" {
+			defaultConfig.AuthJWTIssuer = cfg.AuthJWTIssuer
+		}
+		if cfg.AuthJWTAudience != "" {
+			defaultConfig.AuthJWTAudience = cfg.AuthJWTAudience
+		}
+		defaultConfig.AuthDisabled = cfg.AuthDisabled
+		if cfg.TraceExporter != "" {
+			defaultConfig.TraceExporter = cfg.TraceExporter
+		}
+		if cfg.TraceFilePath != "" {
+			defaultConfig.TraceFilePath = cfg.TraceFilePath
+		}
+		if cfg.TraceSampler != "" {
+			defaultConfig.TraceSampler = cfg.TraceSampler
+		}
+		if cfg.TraceSamplerRatio > 0 {
+			defaultConfig.TraceSamplerRatio = cfg.TraceSamplerRatio
+		}
+	}
+
+	return &ServerChi{
+		serverAddress:        defaultConfig.ServerAddress,
+		readTimeout:          defaultConfig.ReadTimeout,
+		writeTimeout:         defaultConfig.WriteTimeout,
+		idleTimeout:          defaultConfig.IdleTimeout,
+		shutdownTimeout:      defaultConfig.ShutdownTimeout,
+		repositoryBackend:    defaultConfig.RepositoryBackend,
+		loaderFilePath:       defaultConfig.LoaderFilePath,
+		tenantSeedDir:        defaultConfig.TenantSeedDir,
+		maxBodyBytes:         defaultConfig.MaxBodyBytes,
+		maxHeaderBytes:       defaultConfig.MaxHeaderBytes,
+		similarityWeights:    *defaultConfig.SimilarityWeights,
+		cacheCapacity:        defaultConfig.CacheCapacity,
+		cacheTTL:             defaultConfig.CacheTTL,
+		logLevel:             defaultConfig.LogLevel,
+		authAPIKeysFile:      defaultConfig.AuthAPIKeysFile,
+		authPolicyFile:       defaultConfig.AuthPolicyFile,
+		authJWTSecret:        defaultConfig.AuthJWTSecret,
+		authJWTPublicKeyFile: defaultConfig.AuthJWTPublicKeyFile,
+		authJWKSFile:         defaultConfig.AuthJWKSFile,
+		authJWTIssuer:        defaultConfig.AuthJWTIssuer,
+		authJWTAudience:      defaultConfig.AuthJWTAudience,
+		authDisabled:         defaultConfig.AuthDisabled,
+		traceExporter:        defaultConfig.TraceExporter,
+		traceFilePath:        defaultConfig.TraceFilePath,
+		traceSampler:         defaultConfig.TraceSampler,
+		traceSamplerRatio:    defaultConfig.TraceSamplerRatio,
+		rateLimits:           defaultConfig.RateLimits,
+		idempotencyTTL:       defaultConfig.IdempotencyTTL,
+		compressionLevel:     defaultConfig.CompressionLevel,
+		v1Deprecation:        defaultConfig.V1Deprecation,
+		v1Sunset:             defaultConfig.V1Sunset,
+		grpcAddress:          defaultConfig.GRPCAddress,
+		graphQLMaxDepth:      defaultConfig.GraphQLMaxDepth,
+		graphQLMaxComplexity: defaultConfig.GraphQLMaxComplexity,
+	}
+}
+
+// ServerChi is a struct that implements the Application interface
+type ServerChi struct {
+	// serverAddress is the address where the server will be listening
+	serverAddress string
+	// readTimeout is the maximum duration for reading a request
+	readTimeout time.Duration
+	// writeTimeout is the maximum duration for writing a response
+	writeTimeout time.Duration
+	// idleTimeout is the maximum duration of an idle keep-alive connection
+	idleTimeout time.Duration
+	// shutdownTimeout is the maximum duration to drain the in-flight requests
+	shutdownTimeout time.Duration
+	// repositoryBackend is the storage of the vehicles
+	repositoryBackend string
+	// loaderFilePath is the path to the file that contains the vehicles
+	loaderFilePath string
+	// tenantSeedDir is the directory of the files tenants can be seeded from
+	tenantSeedDir string
+	// maxBodyBytes is the maximum size of a request body
+	maxBodyBytes int64
+	// maxHeaderBytes is the maximum size of the request headers
+	maxHeaderBytes int
+	// similarityWeights are the weights of the attributes used to find similar vehicles
+	similarityWeights index.SimilarityWeights
+	// cacheCapacity is the maximum number of results cached by the service
+	cacheCapacity int
+	// cacheTTL is the time to live of a cached result
+	cacheTTL time.Duration
+	// logLevel is the initial level of the logger
+	logLevel string
+	// authAPIKeysFile is the path to the file of the hashed API keys
+	authAPIKeysFile string
+	// authPolicyFile is the path to the file of the roles and the permissions of each route
+	authPolicyFile string
+	// authJWTSecret is the secret verifying HS256 JWTs
+	authJWTSecret string
+	// authJWTPublicKeyFile is the path to the public key verifying RS256 JWTs
+	authJWTPublicKeyFile string
+	// authJWKSFile is the path to the JWKS file verifying JWTs
+	authJWKSFile string
+	// authJWTIssuer is the issuer required from the JWTs
+	authJWTIssuer string
+	// authJWTAudience is the audience required from the JWTs
+	authJWTAudience string
+	// authDisabled is true if every route is served without authentication
+	authDisabled bool
+	// traceExporter is where the spans are exported, empty if tracing is disabled
+	traceExporter string
+	// traceFilePath is the path to the file of the otlp_file exporter
+	traceFilePath string
+	// traceSampler is the sampler of the traces
+	traceSampler string
+	// traceSamplerRatio is the ratio of the traceidratio samplers
+	traceSamplerRatio float64
+	// rateLimits are the limits of the clients, nil if rate limiting is disabled
+	rateLimits *ratelimit.Limits
+	// idempotencyTTL is how long the responses of the requests with an Idempotency-Key are replayed
+	idempotencyTTL time.Duration
+	// compressionLevel is the level of the compression of the responses, negative if compression is disabled
+	compressionLevel int
+	// v1Deprecation is the date the v1 routes were deprecated, zero if not announced
+	v1Deprecation time.Time
+	// v1Sunset is the date the v1 routes stop being served, zero if not announced
+	v1Sunset time.Time
+	// grpcAddress is the address of the gRPC server, empty if disabled
+	grpcAddress string
+	// graphQLMaxDepth is the maximum depth of the GraphQL queries
+	graphQLMaxDepth int
+	// graphQLMaxComplexity is the maximum complexity of the GraphQL queries
+	graphQLMaxComplexity int
+}
+
+// Run is a method that runs the application
+// - the probes are served while the vehicles load, the rest of the routes once they are loaded
+// - on SIGINT or SIGTERM the server stops being ready and drains the in-flight requests before returning
+// - the HTTP and gRPC servers are shut down together, also when one of them fails or the vehicles fail to load
+func (a *ServerChi) Run() (err error) {
+	if a.repositoryBackend != "memory" {
+		return fmt.Errorf("invalid repository backend: %q", a.repositoryBackend)
+	}
+
+	// dependencies
+	// - logger
+	logLevel := new(slog.LevelVar)
+	if err = logLevel.UnmarshalText([]byte(a.logLevel)); err != nil {
+		return
+	}
+	lg := logger.New(os.Stdout, logLevel)
+	slog.SetDefault(lg)
+	// - metrics
+	reg := metrics.NewRegistry()
+	// - tracing
+	tr, closeTracer, err := a.newTracer()
+	if err != nil {
+		return
+	}
+	defer closeTracer()
+	// - authentication
+	authenticators, reloadAuth, err := a.newAuthenticators()
+	if err != nil {
+		return
+	}
+	if len(authenticators) == 0 {
+		lg.Warn("authentication disabled, every route is public")
+	}
+	// - authorization, only with authentication
+	var policy *auth.Policy
+	if len(authenticators) > 0 {
+		if policy, err = a.newPolicy(); err != nil {
+			return
+		}
+	}
+	// - tenants, each with a fleet of its own, the default one created once the vehicles are loaded
+	tenants := tenant.NewRegistry(a.newFleetFactory(reg))
+	registerFleetMetrics(reg, tenants)
+	if a.cacheCapacity > 0 {
+		registerCacheMetrics(reg, tenants)
+	}
+	// - handlers
+	hh := handler.NewHealthDefault()
+	api := &lazyHandler{}
+	// - gRPC, served with the authentication, the policy and the logger of the HTTP server
+	var gs *grpcServer
+	if a.grpcAddress != "" {
+		if gs, err = newGRPCServer(a.grpcAddress, lg, authenticators, policy, tenants); err != nil {
+			return
+		}
+	}
+
+	// server
+	srv := &http.Server{
+		Addr:           a.serverAddress,
+		Handler:        a.newRouter(lg, reg, tr, hh, authenticators, api),
+		ReadTimeout:    a.readTimeout,
+		WriteTimeout:   a.writeTimeout,
+		IdleTimeout:    a.idleTimeout,
+		MaxHeaderBytes: a.maxHeaderBytes,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// reload the keys on SIGHUP, so they can be rotated without a restart
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for range hup {
+			if err := reloadAuth(); err != nil {
+				lg.Error("keys not reloaded", slog.String("error", err.Error()))
+				continue
+			}
+			lg.Info("keys reloaded")
+		}
+	}()
+
+	serveErr := make(chan error, 2)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+	if gs != nil {
+		go func() {
+			serveErr <- gs.serve()
+		}()
+	}
+
+	// load the vehicles while the probes are served
+	loadErr := make(chan error, 1)
+	go func() {
+		sv, h, err := a.newAPI(reg, logLevel, policy, tenants)
+		if err != nil {
+			loadErr <- err
+			return
+		}
+		api.set(h)
+		hh.Ready(map[string]internal.HealthChecker{"repository": tenants})
+		if gs != nil {
+			gs.ready(sv)
+		}
+		lg.Info("ready", slog.String("address", a.serverAddress), slog.String("grpc_address", a.grpcAddress))
+	}()
+
+	select {
+	case err = <-serveErr:
+		// - the other server is still serving, it is shut down below
+	case err = <-loadErr:
+	case <-ctx.Done():
+		lg.Info("shutting down", slog.Duration("timeout", a.shutdownTimeout))
+	}
+
+	// graceful shutdown
+	hh.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+	if gs != nil {
+		gs.shutdown(shutdownCtx)
+	}
+	if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+		err = shutdownErr
+	}
+	return
+}
+
+// Handler is a method that returns the handler of the HTTP server once the vehicles are loaded, without serving it
+// - e.g. to serve it with httptest, the server is ready at once and there is no gRPC server nor tracing
+func (a *ServerChi) Handler() (h http.Handler, err error) {
+	if a.repositoryBackend != "memory" {
+		return nil, fmt.Errorf("invalid repository backend: %q", a.repositoryBackend)
+	}
+
+	// dependencies
+	logLevel := new(slog.LevelVar)
+	if err = logLevel.UnmarshalText([]byte(a.logLevel)); err != nil {
+		return
+	}
+	lg := logger.New(os.Stdout, logLevel)
+	reg := metrics.NewRegistry()
+	authenticators, _, err := a.newAuthenticators()
+	if err != nil {
+		return
+	}
+	var policy *auth.Policy
+	if len(authenticators) > 0 {
+		if policy, err = a.newPolicy(); err != nil {
+			return
+		}
+	}
+	tenants := tenant.NewRegistry(a.newFleetFactory(reg))
+	registerFleetMetrics(reg, tenants)
+	if a.cacheCapacity > 0 {
+		registerCacheMetrics(reg, tenants)
+	}
+	_, api, err := a.newAPI(reg, logLevel, policy, tenants)
+	if err != nil {
+		return
+	}
+	hh := handler.NewHealthDefault()
+	hh.Ready(map[string]internal.HealthChecker{"repository": tenants})
+
+	return a.newRouter(lg, reg, nil, hh, authenticators, api), nil
+}
+
+// newRouter is a method that returns the router of the HTTP server: the probes, the metrics and the routes of api
+// - tr is the tracer of the requests, nil if tracing is disabled
+func (a *ServerChi) newRouter(lg *slog.Logger, reg *metrics.Registry, tr *tracing.Tracer, hh *handler.HealthDefault, authenticators []auth.Authenticator, api http.Handler) http.Handler {
+	rt := chi.NewRouter()
+	// - middlewares
+	rt.Use(middleware.RequestID)
+	if tr != nil {
+		rt.Use(tracing.Middleware(tr))
+	}
+	rt.Use(metrics.Middleware(reg))
+	rt.Use(logger.Middleware(lg))
+	rt.Use(middleware.Recoverer)
+	if a.maxBodyBytes > 0 {
+		rt.Use(middleware.RequestSize(a.maxBodyBytes))
+	}
+	if a.compressionLevel > 0 {
+		rt.Use(newCompressor(a.compressionLevel).Handler)
+	}
+	// - endpoints
+	rt.Get("/healthz", hh.Healthz())
+	rt.Get("/readyz", hh.Readyz())
+	rt.Get("/metrics", reg.Handler())
+	rt.Group(func(rt chi.Router) {
+		// - errors of the v2 routes in their envelope, the ones of authentication and rate limiting too
+		rt.Use(withPrefix("/v2/", render.Errors(handler.ErrorEnvelope)))
+		// - responses and request bodies in the format of the request: JSON, CSV, XML, MessagePack or YAML
+		// - except GraphQL, always JSON or a stream of events
+		rt.Use(withoutPrefix("/graphql", render.Middleware(render.NewNegotiator(render.Codecs()...))))
+		// - the rate of each IP before authentication, the rates and quota of each client after it
+		var limiter *ratelimit.Limiter
+		if a.rateLimits != nil {
+			limiter = ratelimit.NewLimiter(ratelimit.NewMemoryStore(), *a.rateLimits)
+			rt.Use(ratelimit.IPMiddleware(limiter))
+		}
+		if len(authenticators) > 0 {
+			rt.Use(auth.Middleware(authenticators...))
+		}
+		if limiter != nil {
+			rt.Use(ratelimit.Middleware(limiter))
+		}
+		rt.Mount("/", api)
+	})
+	return rt
+}
+
+// newAPI is a method that loads the vehicles and returns the service and the handler of the routes serving them
+// - policy authorizes the routes and hides the fields of the vehicles, nil if authorization is disabled
+// - tenants are the fleets of the tenants, the vehicles of the loader belong to the default tenant
+func (a *ServerChi) newAPI(reg *metrics.Registry, logLevel *slog.LevelVar, policy *auth.Policy, tenants *tenant.Registry) (sv internal.VehicleService, h http.Handler, err error) {
+	// dependencies
+	// - loader
+	ld := loader.NewVehicleJSONFile(a.loaderFilePath)
+	db, err := ld.Load()
+	if err != nil {
+		return
+	}
+	if _, err = tenants.Create(tenant.DefaultID, db); err != nil {
+		return
+	}
+	// - service, dispatching to the fleet of the tenant of the request
+	sv = service.NewVehicleTenants(tenants)
+	var vf *service.VehicleFields
+	if policy != nil {
+		// - fields hidden from the caller, after the cache so cached results are shared by every role
+		vf = service.NewVehicleFields(sv, policy)
+		sv = vf
+	}
+	// - handler
+	hd := handler.NewVehicleDefault(sv)
+	h2 := handler.NewVehicleV2(sv)
+	ad := handler.NewAdminDefault(tenants, logLevel)
+	tn := handler.NewTenantDefault(tenants, a.tenantSeedDir)
+	au := handler.NewAuthDefault()
+	gq, err := handler.NewGraphQL(sv, policy, a.graphQLMaxDepth, a.graphQLMaxComplexity)
+	if err != nil {
+		return
+	}
+
+	// router
+	rt := chi.NewRouter()
+	// - middlewares
+	rt.Use(httpcache.Policies(cachePolicies, httpcache.NoStore, rt))
+	if vf != nil {
+		// - fields hidden from the caller left out of the responses
+		rt.Use(handler.HiddenFields(vf.HiddenFields))
+	}
+	// - vehicles tagged by the version of the fleet of the tenant
+	version := func(r *http.Request) (v string, ok bool) {
+		f, err := tenants.FromContext(r.Context())
+		if err != nil || f.Version == nil {
+			return
+		}
+		return f.ID + ":" + strconv.FormatUint(f.Version.Version(), 10), true
+	}
+	// - creates replayed on retries with the same Idempotency-Key
+	idempotent := func(next http.Handler) http.Handler { return next }
+	if a.idempotencyTTL > 0 {
+		idempotent = idempotency.Middleware(idempotency.NewMemoryStore(), a.idempotencyTTL)
+	}
+	// - endpoints
+	// - v1, served under /v1 and unversioned as it always was
+	v1 := func(rt chi.Router) {
+		rt.Use(httpcache.Conditional(version))
+		// - GET /vehicles
+		rt.Get("/", hd.GetAll())
+		rt.With(idempotent).Post("/", hd.Create())
+		rt.Get("/color/{color}/year/{year}", hd.GetVehiclesByColorYear())
+		rt.Get("/brand/{brand}/between/{start_year}/{end_year}", hd.GetVehiclesByBrandYears())
+		rt.Get("/average_speed/brand/{brand}", hd.GetAverageSpeedByBrand())
+		rt.With(idempotent).Post("/batch", hd.CreateVehicles())
+		rt.Put("/{id}/update_speed", hd.UpdateVehicleSpeed())
+		rt.Get("/fuel_type/{type}", hd.GetVehicleByFuelType())
+		rt.Delete("/{id}", hd.DeleteVehicle())
+		rt.Get("/transmission/{type}", hd.GetByTransmissionType())
+		rt.Put("/{id}/update_fuel", hd.UpdateFuelType())
+		rt.Get("/average_capacity/brand/{brand}", hd.GetAverageCapacityByBrand())
+		rt.Get("/dimensions", hd.GetByDimensions())
+		rt.Get("/weight", hd.GetByWeight())
+		rt.Get("/{id}/similar", hd.GetSimilarVehicles())
+		rt.Get("/search", hd.SearchVehicles())
+	}
+	rt.Group(func(rt chi.Router) {
+		rt.Use(handler.Deprecated(a.v1Deprecation, a.v1Sunset, "/v2/vehicles"))
+		rt.Route("/vehicles", v1)
+		rt.Route("/v1/vehicles", v1)
+	})
+	// - v2, vehicles as a resource
+	rt.Route("/v2/vehicles", func(rt chi.Router) {
+		rt.Use(httpcache.Conditional(version))
+		rt.Get("/", h2.List())
+		rt.With(idempotent).Post("/", h2.Create())
+		rt.With(idempotent).Post("/batch", h2.CreateBatch())
+		rt.Get("/search", h2.Search())
+		rt.Get("/stats", h2.Stats())
+		rt.Get("/{id}", h2.Get())
+		rt.Patch("/{id}", h2.Patch())
+		rt.Delete("/{id}", h2.Delete())
+		rt.Get("/{id}/similar", h2.Similar())
+	})
+	// - GraphQL, each field authorized by the resolvers
+	rt.Get("/graphql", gq.Serve())
+	rt.Post("/graphql", gq.Serve())
+	rt.Get("/auth/whoami", au.GetPrincipal())
+	rt.Route("/admin", func(rt chi.Router) {
+		rt.Get("/cache", ad.GetCacheStats())
+		rt.Get("/aggregates/check", ad.CheckAggregates())
+		rt.Get("/log_level", ad.GetLogLevel())
+		rt.Put("/log_level", ad.UpdateLogLevel())
+		rt.Get("/tenants", tn.GetAll())
+		rt.Post("/tenants", tn.Create())
+		rt.Post("/tenants/{tenant}/seed", tn.Seed())
+	})
+
+	// - tenant of the request resolved once authorized, so unknown tenants are not disclosed
+	h = tenant.Middleware(tenants)(rt)
+	if policy != nil {
+		// - routes authorized in one place, every route must have a permission in the policy
+		if err = policy.CheckRoutes(rt); err != nil {
+			return
+		}
+		h = auth.Authorize(policy, rt)(h)
+	}
+	return
+}
+
+// cachePolicies are the Cache-Control policies of the routes of the API, by "METHOD pattern", other routes are not stored
+// - collections are revalidated on every use, by the ETag of the version of the fleet
+// - statistics can be used for a while without revalidation
+// - the v1 routes have the same policies under /v1
+var cachePolicies = func() map[string]string {
+	policies := map[string]string{
+		"GET /v2/vehicles":              httpcache.Revalidate,
+		"GET /v2/vehicles/search":       httpcache.Revalidate,
+		"GET /v2/vehicles/stats":        httpcache.MaxAge(30),
+		"GET /v2/vehicles/{id}":         httpcache.Revalidate,
+		"GET /v2/vehicles/{id}/similar": httpcache.Revalidate,
+	}
+	for route, policy := range v1CachePolicies {
+		policies[route] = policy
+		method, pattern, _ := strings.Cut(route, " ")
+		policies[method+" /v1"+pattern] = policy
+	}
+	return policies
+}()
+
+// v1CachePolicies are the Cache-Control policies of the unversioned v1 routes
+var v1CachePolicies = map[string]string{
+	"GET /vehicles": httpcache.Revalidate,
+	"GET /vehicles/color/{color}/year/{year}":                     httpcache.Revalidate,
+	"GET /vehicles/brand/{brand}/between/{start_year}/{end_year}": httpcache.Revalidate,
+	"GET /vehicles/average_speed/brand/{brand}":                   httpcache.MaxAge(30),
+	"GET /vehicles/fuel_type/{type}":                              httpcache.Revalidate,
+	"GET /vehicles/transmission/{type}":                           httpcache.Revalidate,
+	"GET /vehicles/average_capacity/brand/{brand}":                httpcache.MaxAge(30),
+	"GET /vehicles/dimensions":                                    httpcache.Revalidate,
+	"GET /vehicles/weight":                                        httpcache.Revalidate,
+	"GET /vehicles/{id}/similar":                                  httpcache.Revalidate,
+	"GET /vehicles/search":                                        httpcache.Revalidate,
+}
+
+// withPrefix is a function that returns a middleware applying mw to the requests whose path has the prefix only
+func withPrefix(prefix string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// withoutPrefix is a function that returns a middleware applying mw to the requests whose path does not have the prefix only
+func withoutPrefix(prefix string, mw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// newCompressor is a function that returns the compressor of the responses: brotli, gzip or deflate, as accepted by the client
+// - the responses in every format of the API are compressed, and the metrics
+func newCompressor(level int) *middleware.Compressor {
+	types := []string{"text/plain"}
+	for _, c := range render.Codecs() {
+		types = append(types, c.MediaTypes()...)
+	}
+	cp := middleware.NewCompressor(level, types...)
+	// - brotli is preferred, set last
+	cp.SetEncoder("br", func(w io.Writer, level int) io.Writer {
+		return brotli.NewWriterLevel(w, level)
+	})
+	return cp
+}
+
+// watchBuffer is the number of changes a watcher of the vehicles may fall behind before it is dropped
+const watchBuffer = 256
+
+// newFleetFactory is a method that returns the factory of the fleets of the tenants
+// - the operations of every fleet are recorded by the same metrics
+func (a *ServerChi) newFleetFactory(reg *metrics.Registry) tenant.Factory {
+	rpOps := reg.NewOperations("vehicle_repository", "vehicle repository")
+	svOps := reg.NewOperations("vehicle_service", "vehicle service")
+
+	return func(id string, db map[int]internal.Vehicle) (f *tenant.Fleet, err error) {
+		// - repository
+		rp := repository.NewVehicleMap(db)
+		rpm := repository.NewVehicleMetrics(rp, rpOps)
+		rpt := repository.NewVehicleTracing(rpm)
+		// - indexes
+		sm := index.NewSimilarity(a.similarityWeights)
+		rp.Observe(sm)
+		sr := index.NewText()
+		rp.Observe(sr)
+		// - changes, watched by the subscriptions
+		ev := repository.NewVehicleBroker(watchBuffer)
+		// - service
+		var sv internal.VehicleService = service.NewVehicleDefault(rpt, sm, sr, ev)
+		var cache internal.Cache
+		if a.cacheCapacity > 0 {
+			// - cache, invalidated by the changes made to the repository
+			sc := service.NewVehicleCache(sv, a.cacheCapacity, a.cacheTTL)
+			rp.Observe(sc)
+			sv, cache = sc, sc
+		}
+		// - observed last, so the indexes and the cache are up to date when the watchers are told
+		rp.Observe(ev)
+		sv = service.NewVehicleMetrics(sv, svOps)
+
+		return &tenant.Fleet{
+			ID:         id,
+			Repository: rpt,
+			Service:    sv,
+			Cache:      cache,
+			Checker:    rp,
+			Health:     rp,
+			Version:    rp,
+		}, nil
+	}
+}
+
+// newAuthenticators is a method that returns the authenticators of the application, none if authentication is disabled
+// - reload reloads the API keys and the JWT keys from their files
+// - the error is ErrAuthNotConfigured if no key is configured and authentication is not disabled, the routes are never public by default
+func (a *ServerChi) newAuthenticators() (authenticators []auth.Authenticator, reload func() error, err error) {
+	configured := a.authAPIKeysFile != "" || a.authJWTSecret != "" || a.authJWTPublicKeyFile != "" || a.authJWKSFile != ""
+	switch {
+	case a.authDisabled && configured:
+		err = errors.New("authentication disabled but keys configured")
+		return
+	case !a.authDisabled && !configured:
+		err = ErrAuthNotConfigured
+		return
+	}
+
+	var reloads []func() error
+	reload = func() error {
+		var errs []error
+		for _, r := range reloads {
+			errs = append(errs, r())
+		}
+		return errors.Join(errs...)
+	}
+
+	// - api keys
+	if a.authAPIKeysFile != "" {
+		var keys *auth.APIKeys
+		if keys, err = auth.NewAPIKeys(a.authAPIKeysFile); err != nil {
+			return
+		}
+		authenticators = append(authenticators, keys)
+		reloads = append(reloads, keys.Reload)
+	}
+
+	// - jwt
+	if a.authJWTSecret == "" && a.authJWTPublicKeyFile == "" && a.authJWKSFile == "" {
+		return
+	}
+	load := func() (ks *auth.KeySet, err error) {
+		ks = &auth.KeySet{}
+		if a.authJWTSecret != "" {
+			ks.AddHMAC("", []byte(a.authJWTSecret))
+		}
+		if a.authJWTPublicKeyFile != "" {
+			if err = ks.ReadPEMFile("", a.authJWTPublicKeyFile); err != nil {
+				return
+			}
+		}
+		if a.authJWKSFile != "" {
+			err = ks.ReadJWKSFile(a.authJWKSFile)
+		}
+		return
+	}
+	jwt, err := auth.NewJWT(load, auth.JWTOptions{
+		Issuer:   a.authJWTIssuer,
+		Audience: a.authJWTAudience,
+		Leeway:   time.Minute,
+	})
+	if err != nil {
+		return
+	}
+	authenticators = append(authenticators, jwt)
+	reloads = append(reloads, jwt.Reload)
+	return
+}
+
+// newPolicy is a method that returns the authorization policy, from its file or the default one
+func (a *ServerChi) newPolicy() (p *auth.Policy, err error) {
+	if a.authPolicyFile == "" {
+		return auth.DefaultPolicy(), nil
+	}
+	return auth.ReadPolicyFile(a.authPolicyFile)
+}
+
+// newTracer is a method that returns the tracer of the application, nil if tracing is disabled
+// - closeTracer releases the resources of the exporter
+func (a *ServerChi) newTracer() (tr *tracing.Tracer, closeTracer func(), err error) {
+	closeTracer = func() {}
+	if a.traceExporter == "" {
+		return
+	}
+
+	sampler, err := tracing.ParseSampler(a.traceSampler, a.traceSamplerRatio)
+	if err != nil {
+		return
+	}
+
+	var exporter tracing.Exporter
+	switch a.traceExporter {
+	case "stdout":
+		exporter = tracing.NewStdoutExporter(os.Stdout)
+	case "otlp_file":
+		var f *os.File
+		f, err = os.OpenFile(a.traceFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return
+		}
+		exporter = tracing.NewOTLPFileExporter(f)
+		closeTracer = func() { f.Close() }
+	default:
+		err = fmt.Errorf("invalid trace exporter: %q", a.traceExporter)
+		return
+	}
+
+	tr = tracing.NewTracer("api-vehicles", exporter, sampler)
+	return
+}
+
+// lazyHandler is a struct that represents a handler set once its dependencies are ready
+// - until then it responds with 503 Service Unavailable
+type lazyHandler struct {
+	// h is the handler, nil until set
+	h atomic.Pointer[http.Handler]
+}
+
+// set is a method that sets the handler
+func (l *lazyHandler) set(h http.Handler) {
+	l.h.Store(&h)
+}
+
+// ServeHTTP is a method that serves the request with the handler if set
+func (l *lazyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h := l.h.Load()
+	if h == nil {
+		w.Header().Set("Retry-After", "1")
+		render.Error(w, r, http.StatusServiceUnavailable, "vehicles are loading")
+		return
+	}
+	(*h).ServeHTTP(w, r)
+}
@@ -0,0 +1,365 @@
+package application
+
+import (
+	"app/internal/auth"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testLoaderFilePath is the path of the sample vehicles, relative to the directory of the package
+const testLoaderFilePath = "../../docs/db/vehicles_100.json"
+
+// testKeys are the API keys of the tests, by role of the default policy
+var testKeys = map[string]string{
+	"viewer":       "viewer-key",
+	"editor":       "editor-key",
+	"fleet-admin":  "fleet-admin-key",
+	"system-admin": "system-admin-key",
+}
+
+// writeAPIKeys is a function that writes a file of the API keys of testKeys, each of a principal named by its role
+func writeAPIKeys(t *testing.T) (path string) {
+	t.Helper()
+
+	var b strings.Builder
+	b.WriteString("keys:\n")
+	for role, key := range testKeys {
+		fmt.Fprintf(&b, "  - id: %s\n    subject: %s\n    hash: %s\n    roles: [%s]\n", role, role, auth.HashAPIKey(key), role)
+	}
+	path = filepath.Join(t.TempDir(), "keys.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(b.String()), 0o600))
+	return
+}
+
+// newTestHandler is a function that returns the handler of a server of the sample vehicles, with the configuration
+// - the sample vehicles are loaded unless another file is given, only the errors are logged unless another level is given
+// - authentication is disabled unless API keys are given
+func newTestHandler(t *testing.T, cfg ConfigServerChi) http.Handler {
+	t.Helper()
+
+	if cfg.AuthAPIKeysFile == "" {
+		cfg.AuthDisabled = true
+	}
+	if cfg.LoaderFilePath == "" {
+		cfg.LoaderFilePath = testLoaderFilePath
+	}
+	if cfg.LogLevel == "" {
+		cfg.LogLevel = "error"
+	}
+	h, err := NewServerChi(&cfg).Handler()
+	require.NoError(t, err)
+	return h
+}
+
+// serve is a function that serves a request with the handler, with the headers given as name and value pairs
+func serve(h http.Handler, method, target, body string, header ...string) *httptest.ResponseRecorder {
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, rd)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, req)
+	return res
+}
+
+// vehicleBody is a function that returns the JSON of a valid vehicle with the id
+func vehicleBody(id int) string {
+	return fmt.Sprintf(`{"id":%d,"brand":"Tesla","model":"Model 3","registration":"T-%d","color":"White","year":2020,"passengers":5,`+
+		`"max_speed":225,"fuel_type":"electric","transmission":"automatic","weight":1800,"height":140,"length":470,"width":180}`, id, id)
+}
+
+func TestServerChi_Compression(t *testing.T) {
+	cases := []struct {
+		name  string
+		level int
+		// encoding is the encoding of the response to a client accepting gzip
+		encoding string
+	}{
+		{name: "case 1: compressed by default", level: 0, encoding: "gzip"},
+		{name: "case 2: compressed with the level", level: 9, encoding: "gzip"},
+		{name: "case 3: a negative level disables compression", level: -1, encoding: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := newTestHandler(t, ConfigServerChi{CompressionLevel: c.level})
+
+			// act
+			res := serve(h, "GET", "/v2/vehicles", "", "Accept-Encoding", "gzip")
+
+			// assert
+			require.Equal(t, http.StatusOK, res.Code)
+			require.Equal(t, c.encoding, res.Header().Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestServerChi_Patch(t *testing.T) {
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		// message is the message of the error, if any
+		message string
+	}{
+		{name: "case 1: valid patch", body: `{"color":"Red"}`, status: http.StatusOK},
+		{name: "case 2: blank brand", body: `{"brand":" "}`, status: http.StatusUnprocessableEntity, message: "invalid vehicle: brand is required"},
+		{name: "case 3: blank model", body: `{"model":""}`, status: http.StatusUnprocessableEntity, message: "invalid vehicle: model is required"},
+		{name: "case 4: negative year", body: `{"year":-1}`, status: http.StatusUnprocessableEntity, message: "invalid vehicle: year must not be negative"},
+		{name: "case 5: negative passengers", body: `{"passengers":-1}`, status: http.StatusUnprocessableEntity, message: "invalid vehicle: passengers must not be negative"},
+		{name: "case 6: negative weight", body: `{"weight":-1}`, status: http.StatusUnprocessableEntity, message: "max_speed, weight, height, length and width must not be negative"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := newTestHandler(t, ConfigServerChi{})
+			before := serve(h, "GET", "/v2/vehicles/1", "").Body.String()
+
+			// act
+			res := serve(h, "PATCH", "/v2/vehicles/1", c.body)
+
+			// assert
+			require.Equal(t, c.status, res.Code, res.Body.String())
+			if c.status != http.StatusOK {
+				require.Contains(t, res.Body.String(), `"message":"`+c.message+`"`)
+				// - the vehicle is not updated
+				require.Equal(t, before, serve(h, "GET", "/v2/vehicles/1", "").Body.String())
+			}
+		})
+	}
+}
+
+func TestServerChi_ComputedRanges(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		// field is the computed field the vehicles are filtered and sorted by, descending
+		field string
+		min   float64
+		max   float64
+	}{
+		{name: "case 1: footprint", query: "footprint=10000-20000&sort=-footprint", field: "footprint", min: 10000, max: 20000},
+		{name: "case 2: volume", query: "volume=1000000-3000000&sort=-volume", field: "volume", min: 1000000, max: 3000000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := newTestHandler(t, ConfigServerChi{})
+
+			// act
+			res := serve(h, "GET", "/v2/vehicles?"+c.query+"&include="+c.field+"&fields=id,"+c.field+"&limit=500", "")
+
+			// assert
+			require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+			var body struct {
+				Data []map[string]float64 `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			require.NotEmpty(t, body.Data)
+			for i, v := range body.Data {
+				require.True(t, v[c.field] >= c.min && v[c.field] <= c.max, v[c.field])
+				if i > 0 {
+					require.LessOrEqual(t, v[c.field], body.Data[i-1][c.field])
+				}
+			}
+		})
+	}
+}
+
+func TestServerChi_Formats(t *testing.T) {
+	cases := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		header []string
+		status int
+		// contentType is the Content-Type of the response
+		contentType string
+		// contains is a part of the body of the response
+		contains string
+	}{
+		{
+			name: "case 1: ?format= takes precedence over Accept", method: "GET", target: "/v2/vehicles/2?format=yaml", header: []string{"Accept", "application/json"},
+			status: http.StatusOK, contentType: "application/yaml", contains: "id: 2\n",
+		},
+		{
+			name: "case 2: 406 in the default format", method: "GET", target: "/v2/vehicles/2?format=pdf",
+			status: http.StatusNotAcceptable, contentType: "application/json", contains: `"code":"not_acceptable"`,
+		},
+		{
+			name: "case 3: 415 in the negotiated format", method: "POST", target: "/v2/vehicles", body: vehicleBody(1000), header: []string{"Content-Type", "text/plain", "Accept", "application/yaml"},
+			status: http.StatusUnsupportedMediaType, contentType: "application/yaml", contains: "code: unsupported_media_type",
+		},
+		{
+			name: "case 4: a request body in another format", method: "POST", target: "/v2/vehicles?format=xml", body: "id: 1000\nbrand: Tesla\nmodel: Model 3\nregistration: T-1000\n", header: []string{"Content-Type", "application/yaml"},
+			status: http.StatusCreated, contentType: "application/xml", contains: "<id>1000</id>",
+		},
+		{
+			name: "case 5: an error of the service in the negotiated format", method: "GET", target: "/v2/vehicles/9999", header: []string{"Accept", "text/xml"},
+			status: http.StatusNotFound, contentType: "application/xml", contains: "<code>not_found</code>",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := newTestHandler(t, ConfigServerChi{})
+
+			// act
+			res := serve(h, c.method, c.target, c.body, c.header...)
+
+			// assert
+			require.Equal(t, c.status, res.Code, res.Body.String())
+			require.Equal(t, c.contentType, res.Header().Get("Content-Type"))
+			require.Contains(t, res.Body.String(), c.contains)
+		})
+	}
+}
+
+func TestServerChi_Fields(t *testing.T) {
+	h := newTestHandler(t, newAuthConfig(t))
+	res := serve(h, "POST", "/v2/vehicles", vehicleBody(1000), "X-API-Key", testKeys["editor"])
+	require.Equal(t, http.StatusCreated, res.Code, res.Body.String())
+	// - the vehicle 1000 is 470 x 180 x 140 cm, weighs 1800 kg and reaches 225 km/h
+	age := float64(time.Now().Year() - 2020)
+
+	cases := []struct {
+		name  string
+		role  string
+		query string
+		// status is the status of the response, data are the fields of the vehicle of a response with no error
+		status int
+		data   map[string]any
+		// absent are the fields left out of the vehicle, message is the message of the error, if any
+		absent  []string
+		message string
+	}{
+		{
+			name: "case 1: the requested fields only", role: "editor", query: "fields=id,brand",
+			status: http.StatusOK, data: map[string]any{"id": 1000.0, "brand": "Tesla"},
+		},
+		{
+			name: "case 2: the included fields are written next to the requested fields", role: "editor", query: "fields=id&include=age,volume",
+			status: http.StatusOK, data: map[string]any{"id": 1000.0, "age": age, "volume": 11844000.0},
+		},
+		{
+			name: "case 3: computed fields requested as fields", role: "editor", query: "fields=footprint,power_to_weight",
+			status: http.StatusOK, data: map[string]any{"footprint": 84600.0, "power_to_weight": 0.125},
+		},
+		{
+			name: "case 4: every field and the included fields", role: "editor", query: "include=age,footprint",
+			status: http.StatusOK, data: map[string]any{"id": 1000.0, "registration": "T-1000", "age": age, "footprint": 84600.0}, absent: []string{"volume", "power_to_weight"},
+		},
+		{
+			name: "case 5: no computed field unless included", role: "editor", query: "",
+			status: http.StatusOK, data: map[string]any{"id": 1000.0}, absent: []string{"age", "volume", "power_to_weight", "footprint"},
+		},
+		{
+			name: "case 6: unknown fields", role: "editor", query: "fields=id,colour,speed",
+			status: http.StatusBadRequest, message: "invalid fields: colour,speed",
+		},
+		{
+			name: "case 7: a field of another route", role: "editor", query: "fields=id,distance",
+			status: http.StatusBadRequest, message: "invalid fields: distance",
+		},
+		{
+			name: "case 8: unknown includes", role: "editor", query: "include=age,speed",
+			status: http.StatusBadRequest, message: "invalid include: speed",
+		},
+		{
+			name: "case 9: the registration requested by an editor", role: "editor", query: "fields=registration",
+			status: http.StatusOK, data: map[string]any{"registration": "T-1000"},
+		},
+		{
+			name: "case 10: the registration requested by a viewer", role: "viewer", query: "fields=registration",
+			status: http.StatusOK, data: map[string]any{}, absent: []string{"registration"},
+		},
+		{
+			name: "case 11: the registration with other fields requested by a viewer", role: "viewer", query: "fields=id,registration",
+			status: http.StatusOK, data: map[string]any{"id": 1000.0}, absent: []string{"registration"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			res := serve(h, "GET", "/v2/vehicles/1000?"+c.query, "", "X-API-Key", testKeys[c.role])
+
+			// assert
+			require.Equal(t, c.status, res.Code, res.Body.String())
+			if c.status != http.StatusOK {
+				require.Contains(t, res.Body.String(), `"message":"`+c.message+`"`)
+				return
+			}
+			var body struct {
+				Data map[string]any `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+			// - with fields requested, the vehicle has those fields only
+			if strings.Contains(c.query, "fields=") {
+				require.Equal(t, c.data, body.Data)
+			}
+			for name, value := range c.data {
+				require.Equal(t, value, body.Data[name], name)
+			}
+			for _, name := range c.absent {
+				require.NotContains(t, body.Data, name)
+			}
+		})
+	}
+}
+
+func TestServerChi_Run(t *testing.T) {
+	t.Run("case 1: the gRPC server is shut down when the HTTP server fails", func(t *testing.T) {
+		// arrange
+		// - the HTTP address is in use
+		busy, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer busy.Close()
+		// - the gRPC address is free
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		grpcAddress := ln.Addr().String()
+		require.NoError(t, ln.Close())
+		a := NewServerChi(&ConfigServerChi{
+			ServerAddress:  busy.Addr().String(),
+			GRPCAddress:    grpcAddress,
+			LoaderFilePath: testLoaderFilePath,
+			LogLevel:       "error",
+			AuthDisabled:   true,
+		})
+
+		// act
+		done := make(chan error, 1)
+		go func() { done <- a.Run() }()
+
+		// assert
+		select {
+		case err = <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("Run did not return")
+		}
+		require.ErrorContains(t, err, "address already in use")
+		// - the gRPC address is released
+		ln, err = net.Listen("tcp", grpcAddress)
+		require.NoError(t, err)
+		require.NoError(t, ln.Close())
+	})
+}
@@ -0,0 +1,459 @@
+package application
+
+import (
+	"app/internal/auth"
+	"app/internal/grpcserver/vehiclepb"
+	"app/internal/metrics"
+	"app/internal/ratelimit"
+	"app/internal/tenant"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// testRoles are the roles of the default policy, each granted the permissions of the ones before
+var testRoles = []string{"viewer", "editor", "fleet-admin", "system-admin"}
+
+// routeRequest is a struct that represents a request using a route of the policy, and its status once authorized
+type routeRequest struct {
+	// target is the path and the query of the request
+	target string
+	// body is the JSON body of the request, if any
+	body string
+	// status is the status of the response once authorized
+	status int
+	// roles are the roles allowed to use the route
+	roles []string
+}
+
+// routeRequests are the requests using every route of the default policy, by "METHOD pattern"
+// - each writes a vehicle of its own, so the requests do not depend on each other
+var routeRequests = map[string]routeRequest{
+	"GET /vehicles":  {target: "/vehicles", status: http.StatusOK, roles: testRoles},
+	"POST /vehicles": {target: "/vehicles", body: vehicleBody(1001), status: http.StatusCreated, roles: testRoles[1:]},
+	"GET /vehicles/color/{color}/year/{year}":                        {target: "/vehicles/color/Orange/year/2008", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/brand/{brand}/between/{start_year}/{end_year}":    {target: "/vehicles/brand/Hummer/between/2000/2010", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/average_speed/brand/{brand}":                      {target: "/vehicles/average_speed/brand/Hummer", status: http.StatusOK, roles: testRoles},
+	"POST /vehicles/batch":                                           {target: "/vehicles/batch", body: "[" + vehicleBody(1002) + "]", status: http.StatusCreated, roles: testRoles[2:]},
+	"PUT /vehicles/{id}/update_speed":                                {target: "/vehicles/2/update_speed", body: `{"max_speed":150}`, status: http.StatusOK, roles: testRoles[1:]},
+	"GET /vehicles/fuel_type/{type}":                                 {target: "/vehicles/fuel_type/biodiesel", status: http.StatusOK, roles: testRoles},
+	"DELETE /vehicles/{id}":                                          {target: "/vehicles/90", status: http.StatusNoContent, roles: testRoles[2:]},
+	"GET /vehicles/transmission/{type}":                              {target: "/vehicles/transmission/manual", status: http.StatusOK, roles: testRoles},
+	"PUT /vehicles/{id}/update_fuel":                                 {target: "/vehicles/3/update_fuel", body: `{"fuel_type":"electric"}`, status: http.StatusOK, roles: testRoles[1:]},
+	"GET /vehicles/average_capacity/brand/{brand}":                   {target: "/vehicles/average_capacity/brand/Hummer", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/dimensions":                                       {target: "/vehicles/dimensions?height=-100", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/weight":                                           {target: "/vehicles/weight?min=0&max=300", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/{id}/similar":                                     {target: "/vehicles/1/similar", status: http.StatusOK, roles: testRoles},
+	"GET /vehicles/search":                                           {target: "/vehicles/search?q=ford", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles":                                               {target: "/v1/vehicles", status: http.StatusOK, roles: testRoles},
+	"POST /v1/vehicles":                                              {target: "/v1/vehicles", body: vehicleBody(1003), status: http.StatusCreated, roles: testRoles[1:]},
+	"GET /v1/vehicles/color/{color}/year/{year}":                     {target: "/v1/vehicles/color/Orange/year/2008", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/brand/{brand}/between/{start_year}/{end_year}": {target: "/v1/vehicles/brand/Hummer/between/2000/2010", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/average_speed/brand/{brand}":                   {target: "/v1/vehicles/average_speed/brand/Hummer", status: http.StatusOK, roles: testRoles},
+	"POST /v1/vehicles/batch":                                        {target: "/v1/vehicles/batch", body: "[" + vehicleBody(1004) + "]", status: http.StatusCreated, roles: testRoles[2:]},
+	"PUT /v1/vehicles/{id}/update_speed":                             {target: "/v1/vehicles/4/update_speed", body: `{"max_speed":150}`, status: http.StatusOK, roles: testRoles[1:]},
+	"GET /v1/vehicles/fuel_type/{type}":                              {target: "/v1/vehicles/fuel_type/biodiesel", status: http.StatusOK, roles: testRoles},
+	"DELETE /v1/vehicles/{id}":                                       {target: "/v1/vehicles/91", status: http.StatusNoContent, roles: testRoles[2:]},
+	"GET /v1/vehicles/transmission/{type}":                           {target: "/v1/vehicles/transmission/manual", status: http.StatusOK, roles: testRoles},
+	"PUT /v1/vehicles/{id}/update_fuel":                              {target: "/v1/vehicles/5/update_fuel", body: `{"fuel_type":"electric"}`, status: http.StatusOK, roles: testRoles[1:]},
+	"GET /v1/vehicles/average_capacity/brand/{brand}":                {target: "/v1/vehicles/average_capacity/brand/Hummer", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/dimensions":                                    {target: "/v1/vehicles/dimensions?height=-100", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/weight":                                        {target: "/v1/vehicles/weight?min=0&max=300", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/{id}/similar":                                  {target: "/v1/vehicles/1/similar", status: http.StatusOK, roles: testRoles},
+	"GET /v1/vehicles/search":                                        {target: "/v1/vehicles/search?q=ford", status: http.StatusOK, roles: testRoles},
+	"GET /v2/vehicles":                                               {target: "/v2/vehicles?brand=Ford", status: http.StatusOK, roles: testRoles},
+	"POST /v2/vehicles":                                              {target: "/v2/vehicles", body: vehicleBody(1005), status: http.StatusCreated, roles: testRoles[1:]},
+	"POST /v2/vehicles/batch":                                        {target: "/v2/vehicles/batch", body: "[" + vehicleBody(1006) + "]", status: http.StatusCreated, roles: testRoles[2:]},
+	"GET /v2/vehicles/search":                                        {target: "/v2/vehicles/search?q=ford", status: http.StatusOK, roles: testRoles},
+	"GET /v2/vehicles/stats":                                         {target: "/v2/vehicles/stats?group_by=fuel_type", status: http.StatusOK, roles: testRoles},
+	"GET /v2/vehicles/{id}":                                          {target: "/v2/vehicles/1", status: http.StatusOK, roles: testRoles},
+	"PATCH /v2/vehicles/{id}":                                        {target: "/v2/vehicles/6", body: `{"color":"Red"}`, status: http.StatusOK, roles: testRoles[1:]},
+	"DELETE /v2/vehicles/{id}":                                       {target: "/v2/vehicles/92", status: http.StatusNoContent, roles: testRoles[2:]},
+	"GET /v2/vehicles/{id}/similar":                                  {target: "/v2/vehicles/1/similar", status: http.StatusOK, roles: testRoles},
+	"GET /graphql":                                                   {target: "/graphql?query=" + url.QueryEscape("{ vehicle(id: 1) { id } }"), status: http.StatusOK, roles: testRoles},
+	"POST /graphql":                                                  {target: "/graphql", body: `{"query":"{ vehicle(id: 1) { id } }"}`, status: http.StatusOK, roles: testRoles},
+	"GET /auth/whoami":                                               {target: "/auth/whoami", status: http.StatusOK, roles: testRoles},
+	"GET /admin/cache":                                               {target: "/admin/cache", status: http.StatusOK, roles: testRoles[3:]},
+	"GET /admin/aggregates/check":                                    {target: "/admin/aggregates/check", status: http.StatusOK, roles: testRoles[3:]},
+	"GET /admin/log_level":                                           {target: "/admin/log_level", status: http.StatusOK, roles: testRoles[3:]},
+	"PUT /admin/log_level":                                           {target: "/admin/log_level", body: `{"level":"error"}`, status: http.StatusOK, roles: testRoles[3:]},
+	"GET /admin/tenants":                                             {target: "/admin/tenants", status: http.StatusOK, roles: testRoles[3:]},
+	"POST /admin/tenants":                                            {target: "/admin/tenants", body: `{"id":"acme"}`, status: http.StatusCreated, roles: testRoles[3:]},
+	"POST /admin/tenants/{tenant}/seed":                              {target: "/admin/tenants/acme/seed", body: `{"file_path":"vehicles_100.json"}`, status: http.StatusOK, roles: testRoles[3:]},
+}
+
+// newAuthConfig is a function that returns the configuration of a server authenticating the API keys of testKeys
+func newAuthConfig(t *testing.T) ConfigServerChi {
+	return ConfigServerChi{
+		AuthAPIKeysFile: writeAPIKeys(t),
+		CacheCapacity:   100,
+		TenantSeedDir:   "../../docs/db",
+	}
+}
+
+func TestServerChi_Authorization(t *testing.T) {
+	// every route of the policy is requested, but the RPCs
+	var routes []string
+	for _, route := range auth.DefaultPolicy().Routes() {
+		if !strings.HasPrefix(route, "GRPC ") {
+			routes = append(routes, route)
+		}
+	}
+	for _, route := range routes {
+		require.Contains(t, routeRequests, route, "route of the policy without a request")
+	}
+	require.Len(t, routeRequests, len(routes))
+
+	for _, role := range testRoles {
+		t.Run(role, func(t *testing.T) {
+			// - a server of its own for each role, as the requests change the vehicles
+			h := newTestHandler(t, newAuthConfig(t))
+
+			// - in the order of the routes, so the tenant is created before it is seeded
+			for _, route := range routes {
+				rq := routeRequests[route]
+				method, _, _ := strings.Cut(route, " ")
+
+				// act
+				res := serve(h, method, rq.target, rq.body, "X-API-Key", testKeys[role])
+
+				// assert
+				if !slices.Contains(rq.roles, role) {
+					require.Equal(t, http.StatusForbidden, res.Code, "%s: %s", route, res.Body)
+					require.Contains(t, res.Body.String(), "forbidden", route)
+					continue
+				}
+				require.Equal(t, rq.status, res.Code, "%s: %s", route, res.Body)
+			}
+		})
+	}
+
+	t.Run("unauthenticated requests are rejected", func(t *testing.T) {
+		h := newTestHandler(t, newAuthConfig(t))
+
+		require.Equal(t, http.StatusUnauthorized, serve(h, "GET", "/vehicles", "").Code)
+		require.Equal(t, http.StatusUnauthorized, serve(h, "GET", "/v2/vehicles/1", "", "X-API-Key", "unknown-key").Code)
+		// - the probes are public
+		require.Equal(t, http.StatusOK, serve(h, "GET", "/healthz", "").Code)
+	})
+}
+
+func TestServerChi_AuthConfiguration(t *testing.T) {
+	cases := []struct {
+		name     string
+		keys     bool
+		disabled bool
+		// err is the error of the handler, nil if it is served
+		err string
+	}{
+		{name: "case 1: keys configured", keys: true},
+		{name: "case 2: authentication disabled", disabled: true},
+		{name: "case 3: no keys fails closed", err: ErrAuthNotConfigured.Error()},
+		{name: "case 4: keys configured and authentication disabled", keys: true, disabled: true, err: "authentication disabled but keys configured"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			cfg := ConfigServerChi{LoaderFilePath: testLoaderFilePath, LogLevel: "error", AuthDisabled: c.disabled}
+			if c.keys {
+				cfg.AuthAPIKeysFile = writeAPIKeys(t)
+			}
+
+			// act
+			h, err := NewServerChi(&cfg).Handler()
+
+			// assert
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				require.Nil(t, h)
+				return
+			}
+			require.NoError(t, err)
+			// - the writes need a key, unless authentication is disabled
+			status := http.StatusUnauthorized
+			if c.disabled {
+				status = http.StatusNoContent
+			}
+			require.Equal(t, status, serve(h, "DELETE", "/v2/vehicles/1", "").Code)
+		})
+	}
+
+	t.Run("case 5: Run fails closed", func(t *testing.T) {
+		// arrange
+		a := NewServerChi(&ConfigServerChi{ServerAddress: "127.0.0.1:0", LoaderFilePath: testLoaderFilePath, LogLevel: "error"})
+
+		// act
+		err := a.Run()
+
+		// assert
+		require.ErrorIs(t, err, ErrAuthNotConfigured)
+	})
+}
+
+func TestServerChi_RateLimits(t *testing.T) {
+	// request is a struct that represents a request with an API key and its expected status
+	type request struct {
+		key    string
+		status int
+	}
+	cases := []struct {
+		name     string
+		ip       ratelimit.Rate
+		requests []request
+	}{
+		{
+			name: "case 1: invalid keys are limited by IP before authentication",
+			ip:   ratelimit.Rate{Burst: 2},
+			requests: []request{
+				{key: "invalid-key", status: http.StatusUnauthorized},
+				{key: "invalid-key", status: http.StatusUnauthorized},
+				{key: "invalid-key", status: http.StatusTooManyRequests},
+				{key: "viewer-key", status: http.StatusTooManyRequests},
+			},
+		},
+		{
+			name: "case 2: the keys of an IP are limited each after authentication",
+			ip:   ratelimit.Rate{Burst: 10},
+			requests: []request{
+				{key: "viewer-key", status: http.StatusOK},
+				{key: "viewer-key", status: http.StatusTooManyRequests},
+				{key: "editor-key", status: http.StatusOK},
+				{key: "invalid-key", status: http.StatusUnauthorized},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			cfg := newAuthConfig(t)
+			cfg.RateLimits = &ratelimit.Limits{Rates: map[ratelimit.Class]ratelimit.Rate{ratelimit.ClassRead: {Burst: 1}}, IP: c.ip}
+			h := newTestHandler(t, cfg)
+
+			for i, rq := range c.requests {
+				// act
+				res := serve(h, "GET", "/v2/vehicles/1", "", "X-API-Key", rq.key)
+
+				// assert
+				require.Equal(t, rq.status, res.Code, "request %d", i)
+			}
+		})
+	}
+}
+
+func TestServerChi_Registration(t *testing.T) {
+	h := newTestHandler(t, newAuthConfig(t))
+
+	// the vehicle 1 is read by each role from each API
+	cases := []struct {
+		name string
+		// target is the route reading the vehicle 1
+		target string
+		// registration returns the registration of the vehicle 1 of the body, ok is false if absent
+		registration func(body []byte) (registration any, ok bool)
+	}{
+		{
+			name:   "v1",
+			target: "/vehicles",
+			registration: func(body []byte) (any, bool) {
+				var res struct {
+					Data map[string]map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &res))
+				r, ok := res.Data["1"]["registration"]
+				return r, ok
+			},
+		},
+		{
+			name:   "v1 fields",
+			target: "/vehicles?fields=id,registration",
+			registration: func(body []byte) (any, bool) {
+				var res struct {
+					Data map[string]map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &res))
+				r, ok := res.Data["1"]["registration"]
+				return r, ok
+			},
+		},
+		{
+			name:   "v2",
+			target: "/v2/vehicles/1",
+			registration: func(body []byte) (any, bool) {
+				var res struct {
+					Data map[string]any `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &res))
+				r, ok := res.Data["registration"]
+				return r, ok
+			},
+		},
+		{
+			name:   "graphql",
+			target: "/graphql?query=" + url.QueryEscape("{ vehicle(id: 1) { registration } }"),
+			registration: func(body []byte) (any, bool) {
+				var res struct {
+					Data struct {
+						Vehicle map[string]any `json:"vehicle"`
+					} `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(body, &res))
+				// - the field is null when hidden, as the schema says
+				r := res.Data.Vehicle["registration"]
+				return r, r != nil
+			},
+		},
+	}
+	for _, c := range cases {
+		for _, role := range testRoles {
+			t.Run(c.name+" "+role, func(t *testing.T) {
+				// act
+				res := serve(h, "GET", c.target, "", "X-API-Key", testKeys[role])
+
+				// assert
+				require.Equal(t, http.StatusOK, res.Code, res.Body.String())
+				registration, ok := c.registration(res.Body.Bytes())
+				if role == "viewer" {
+					require.False(t, ok, "registration %v read by a viewer", registration)
+					return
+				}
+				require.True(t, ok)
+				require.Equal(t, "0", registration)
+			})
+		}
+	}
+}
+
+func TestServerChi_GraphQLAuthorization(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		// roles are the roles allowed to resolve the field
+		roles []string
+	}{
+		{name: "vehicles", query: `{ vehicles(first: 1) { edges { node { id } } } }`, roles: testRoles},
+		{name: "createVehicle", query: `mutation { createVehicle(input: {id: 1001, brand: "Tesla", model: "Model 3", color: "White", year: 2020, passengers: 5, maxSpeed: 225, fuelType: "electric", transmission: "automatic", weight: 1800, height: 140, length: 470, width: 180}) { id } }`, roles: testRoles[1:]},
+		{name: "updateVehicle", query: `mutation { updateVehicle(id: 2, input: {color: "Red"}) { id } }`, roles: testRoles[1:]},
+		{name: "deleteVehicle", query: `mutation { deleteVehicle(id: 3) }`, roles: testRoles[2:]},
+	}
+	for _, role := range testRoles {
+		h := newTestHandler(t, newAuthConfig(t))
+		for _, c := range cases {
+			t.Run(role+" "+c.name, func(t *testing.T) {
+				// act
+				body, _ := json.Marshal(map[string]string{"query": c.query})
+				res := serve(h, "POST", "/graphql", string(body), "X-API-Key", testKeys[role])
+
+				// assert
+				require.Equal(t, http.StatusOK, res.Code)
+				var gr struct {
+					Data   map[string]any `json:"data"`
+					Errors []struct {
+						Message    string         `json:"message"`
+						Extensions map[string]any `json:"extensions"`
+					} `json:"errors"`
+				}
+				require.NoError(t, json.Unmarshal(res.Body.Bytes(), &gr))
+				if slices.Contains(c.roles, role) {
+					require.Empty(t, gr.Errors, res.Body.String())
+					return
+				}
+				require.NotEmpty(t, gr.Errors, res.Body.String())
+				require.Equal(t, "FORBIDDEN", gr.Errors[0].Extensions["code"], res.Body.String())
+			})
+		}
+	}
+}
+
+func TestServerChi_GRPCAuthorization(t *testing.T) {
+	// server
+	a := NewServerChi(&ConfigServerChi{AuthAPIKeysFile: writeAPIKeys(t), LoaderFilePath: testLoaderFilePath})
+	authenticators, _, err := a.newAuthenticators()
+	require.NoError(t, err)
+	policy, err := a.newPolicy()
+	require.NoError(t, err)
+	reg := metrics.NewRegistry()
+	tenants := tenant.NewRegistry(a.newFleetFactory(reg))
+	sv, _, err := a.newAPI(reg, new(slog.LevelVar), policy, tenants)
+	require.NoError(t, err)
+	gs, err := newGRPCServer("127.0.0.1:0", slog.New(slog.NewTextHandler(io.Discard, nil)), authenticators, policy, tenants)
+	require.NoError(t, err)
+	gs.ready(sv)
+	go gs.serve()
+	t.Cleanup(func() { gs.shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient(gs.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	client := vehiclepb.NewVehicleServiceClient(conn)
+
+	cases := []struct {
+		name string
+		call func(ctx context.Context) error
+		// roles are the roles allowed to call the RPC
+		roles []string
+	}{
+		{name: "GetVehicle", roles: testRoles, call: func(ctx context.Context) error {
+			_, err := client.GetVehicle(ctx, &vehiclepb.GetVehicleRequest{Id: 1})
+			return err
+		}},
+		{name: "ListVehicles", roles: testRoles, call: func(ctx context.Context) error {
+			_, err := client.ListVehicles(ctx, &vehiclepb.ListVehiclesRequest{PageSize: 1})
+			return err
+		}},
+		{name: "UpdateVehicle", roles: testRoles[1:], call: func(ctx context.Context) error {
+			color := "Red"
+			_, err := client.UpdateVehicle(ctx, &vehiclepb.UpdateVehicleRequest{Id: 2, Patch: &vehiclepb.VehiclePatch{Color: &color}})
+			return err
+		}},
+		{name: "DeleteVehicle", roles: testRoles[2:], call: func(ctx context.Context) error {
+			// - the vehicles deleted by the previous roles are not found, once authorized
+			_, err := client.DeleteVehicle(ctx, &vehiclepb.DeleteVehicleRequest{Id: 3})
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		}},
+	}
+	for _, role := range testRoles {
+		for _, c := range cases {
+			t.Run(role+" "+c.name, func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", testKeys[role])
+
+				// act
+				err := c.call(ctx)
+
+				// assert
+				if slices.Contains(c.roles, role) {
+					require.NoError(t, err)
+					return
+				}
+				require.Equal(t, codes.PermissionDenied, status.Code(err), err)
+			})
+		}
+	}
+
+	t.Run("registration hidden from viewers", func(t *testing.T) {
+		for _, role := range testRoles {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", testKeys[role])
+			v, err := client.GetVehicle(ctx, &vehiclepb.GetVehicleRequest{Id: 1})
+			require.NoError(t, err)
+			require.Equal(t, role != "viewer", v.Registration != "", role)
+		}
+	})
+}
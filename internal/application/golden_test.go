@@ -0,0 +1,102 @@
+package application
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files with the responses of the tests, instead of comparing them
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// goldenV1Prefixes are the prefixes the v1 routes are served at, with the same responses
+var goldenV1Prefixes = []string{"/vehicles", "/v1/vehicles"}
+
+func TestServerChi_V1Golden(t *testing.T) {
+	// the golden files are the responses of the baseline server, the v1 routes must not change
+	// - but for the responses changed on purpose: the malformed ids and weights are a 400 with the reason,
+	// the dimensions match the length on the length instead of the height, similar and search are new routes
+	deprecation := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 19, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		// golden is the name of the file of the body of the response, in testdata/v1
+		golden string
+		method string
+		// path is the path of the request, after the prefix of the routes
+		path   string
+		body   string
+		status int
+		// created is the body of a vehicle created before the request, if any
+		created string
+	}{
+		{golden: "find_all", method: "GET", path: "", status: http.StatusOK},
+		{golden: "create", method: "POST", path: "", body: vehicleBody(1001), status: http.StatusCreated},
+		{golden: "create_exists", method: "POST", path: "", body: vehicleBody(1), status: http.StatusConflict},
+		{golden: "color_year", method: "GET", path: "/color/Orange/year/2008", status: http.StatusOK},
+		{golden: "brand_years", method: "GET", path: "/brand/Hummer/between/2000/2010", status: http.StatusOK},
+		{golden: "average_speed", method: "GET", path: "/average_speed/brand/Hummer", status: http.StatusOK},
+		{golden: "batch", method: "POST", path: "/batch", body: "[" + vehicleBody(1001) + "," + vehicleBody(1002) + "]", status: http.StatusCreated},
+		{golden: "update_speed", method: "PUT", path: "/1/update_speed", body: `{"max_speed":150}`, status: http.StatusOK},
+		{golden: "update_speed_not_found", method: "PUT", path: "/1001/update_speed", body: `{"max_speed":150}`, status: http.StatusNotFound},
+		{golden: "fuel_type", method: "GET", path: "/fuel_type/biodiesel", status: http.StatusOK},
+		{golden: "delete", method: "DELETE", path: "/1", status: http.StatusNoContent},
+		{golden: "delete_invalid_id", method: "DELETE", path: "/abc", status: http.StatusBadRequest},
+		{golden: "transmission", method: "GET", path: "/transmission/manual", status: http.StatusOK},
+		{golden: "update_fuel", method: "PUT", path: "/1/update_fuel", body: `{"fuel_type":"electric"}`, status: http.StatusOK},
+		{golden: "update_fuel_invalid_id", method: "PUT", path: "/abc/update_fuel", body: `{"fuel_type":"electric"}`, status: http.StatusBadRequest},
+		{golden: "average_capacity", method: "GET", path: "/average_capacity/brand/Hummer", status: http.StatusOK},
+		{golden: "dimensions", method: "GET", path: "/dimensions?length=100-150&width=100-150", status: http.StatusOK},
+		{golden: "weight", method: "GET", path: "/weight?min=100&max=300", status: http.StatusOK},
+		{golden: "weight_invalid", method: "GET", path: "/weight?min=abc&max=300", status: http.StatusBadRequest},
+		{golden: "similar", method: "GET", path: "/1/similar?k=3", status: http.StatusOK},
+		{golden: "search", method: "GET", path: "/search?q=ford", status: http.StatusOK},
+		{golden: "empty_registration", method: "GET", path: "/color/Teal/year/2020", status: http.StatusOK, created: strings.Replace(strings.Replace(vehicleBody(1001), `"T-1001"`, `""`, 1), `"White"`, `"Teal"`, 1)},
+	}
+	for _, prefix := range goldenV1Prefixes {
+		for _, c := range cases {
+			t.Run(c.method+" "+prefix+c.path, func(t *testing.T) {
+				// arrange
+				h := newTestHandler(t, ConfigServerChi{V1Deprecation: deprecation, V1Sunset: sunset})
+				path := filepath.Join("testdata", "v1", c.golden+".golden")
+				if c.created != "" {
+					require.Equal(t, http.StatusCreated, serve(h, "POST", prefix, c.created).Code)
+				}
+
+				// act
+				res := serve(h, c.method, prefix+c.path, c.body)
+
+				// assert
+				require.Equal(t, c.status, res.Code, res.Body.String())
+				require.Equal(t, "@1792368000", res.Header().Get("Deprecation"))
+				require.Equal(t, "Mon, 19 Apr 2027 00:00:00 GMT", res.Header().Get("Sunset"))
+				require.Equal(t, `</v2/vehicles>; rel="successor-version"`, res.Header().Get("Link"))
+				// - the same golden file for every prefix, the routes are the same
+				if *update && prefix == goldenV1Prefixes[0] {
+					require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+					require.NoError(t, os.WriteFile(path, res.Body.Bytes(), 0o644))
+				}
+				expected, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.Equal(t, string(expected), res.Body.String())
+			})
+		}
+	}
+
+	t.Run("the v2 routes are not deprecated", func(t *testing.T) {
+		h := newTestHandler(t, ConfigServerChi{V1Deprecation: deprecation, V1Sunset: sunset})
+
+		res := serve(h, "GET", "/v2/vehicles/1", "")
+
+		require.Equal(t, http.StatusOK, res.Code)
+		require.Empty(t, res.Header().Get("Deprecation"))
+		require.Empty(t, res.Header().Get("Sunset"))
+		require.Empty(t, res.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,106 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"app/internal/grpcserver"
+	"app/internal/grpcserver/vehiclepb"
+	"app/internal/tenant"
+	"context"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// newGRPCServer is a function that returns the gRPC server of the vehicles, listening on the address
+// - the RPCs are authenticated with the authenticators and authorized by the policy as the routes, if given
+// - the health checks report NOT_SERVING until the vehicles are loaded, and once the server is shutting down
+func newGRPCServer(address string, lg *slog.Logger, authenticators []auth.Authenticator, policy *auth.Policy, tenants *tenant.Registry) (s *grpcServer, err error) {
+	vs := grpcserver.NewVehicleServer()
+
+	// interceptors
+	var interceptors []grpcserver.Interceptor
+	if len(authenticators) > 0 {
+		interceptors = append(interceptors, grpcserver.Auth(authenticators, policy))
+	}
+	interceptors = append(interceptors, vs.Available, grpcserver.Tenant(tenants))
+	unary, stream := grpcserver.Interceptors(lg, interceptors...)
+
+	// services
+	gs := grpc.NewServer(grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream))
+	vehiclepb.RegisterVehicleServiceServer(gs, vs)
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	hs.SetServingStatus(vehiclepb.VehicleService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(gs, hs)
+	reflection.Register(gs)
+	if policy != nil {
+		// - RPCs authorized in one place, every RPC must have a permission in the policy
+		var methods []string
+		for name, info := range gs.GetServiceInfo() {
+			if name == healthpb.Health_ServiceDesc.ServiceName {
+				continue
+			}
+			for _, m := range info.Methods {
+				methods = append(methods, "/"+name+"/"+m.Name)
+			}
+		}
+		if err = policy.CheckMethods(grpcserver.Method, methods); err != nil {
+			return
+		}
+	}
+
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		return
+	}
+	s = &grpcServer{server: gs, health: hs, vehicles: vs, listener: ln}
+	return
+}
+
+// grpcServer is a struct that represents the gRPC server of the vehicles
+type grpcServer struct {
+	// server is the gRPC server
+	server *grpc.Server
+	// health reports the status of the services
+	health *health.Server
+	// vehicles is the vehicle service, served once the vehicles are loaded
+	vehicles *grpcserver.VehicleServer
+	// listener is the listener of the address of the server
+	listener net.Listener
+}
+
+// serve is a method that serves the RPCs until the server stops
+func (s *grpcServer) serve() error {
+	return s.server.Serve(s.listener)
+}
+
+// ready is a method that serves the vehicles of the service, once they are loaded
+func (s *grpcServer) ready(sv internal.VehicleService) {
+	s.vehicles.Ready(sv)
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(vehiclepb.VehicleService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+// shutdown is a method that stops the server, draining the in-flight RPCs until the context is done
+// - the streams still open then, e.g. the watchers, are closed
+// - the listener is closed even if the server did not serve yet
+func (s *grpcServer) shutdown(ctx context.Context) {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.server.Stop()
+	}
+	s.listener.Close()
+}
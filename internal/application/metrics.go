@@ -0,0 +1,71 @@
+package application
+
+import (
+	"app/internal"
+	"app/internal/metrics"
+	"app/internal/tenant"
+	"context"
+)
+
+// registerFleetMetrics is a function that registers the gauges of the size of the fleet of each tenant
+// - they are collected from the aggregates of the repositories when the metrics are scraped
+func registerFleetMetrics(reg *metrics.Registry, tenants *tenant.Registry) {
+	// countBy returns the number of vehicles for every tenant and key of the group
+	countBy := func(group internal.AggregateGroup) (samples []metrics.Sample) {
+		for _, f := range tenants.Fleets() {
+			aggregates, err := f.Repository.Aggregates(context.Background(), group, internal.MetricMaxSpeed)
+			if err != nil {
+				continue
+			}
+			for key, a := range aggregates {
+				samples = append(samples, metrics.Sample{LabelValues: []string{f.ID, key}, Value: float64(a.Count)})
+			}
+		}
+		return
+	}
+
+	reg.NewGaugeFunc("vehicle_fleet_size", "Number of vehicles in the fleet.", []string{"tenant"}, func() []metrics.Sample {
+		totals := make(map[string]float64)
+		for _, s := range countBy(internal.AggregateByBrand) {
+			totals[s.LabelValues[0]] += s.Value
+		}
+		samples := make([]metrics.Sample, 0, len(totals))
+		for _, f := range tenants.Fleets() {
+			samples = append(samples, metrics.Sample{LabelValues: []string{f.ID}, Value: totals[f.ID]})
+		}
+		return samples
+	})
+	reg.NewGaugeFunc("vehicle_fleet_size_by_brand", "Number of vehicles in the fleet by brand.", []string{"tenant", "brand"}, func() []metrics.Sample {
+		return countBy(internal.AggregateByBrand)
+	})
+	reg.NewGaugeFunc("vehicle_fleet_size_by_fuel_type", "Number of vehicles in the fleet by fuel type.", []string{"tenant", "fuel_type"}, func() []metrics.Sample {
+		return countBy(internal.AggregateByFuelType)
+	})
+}
+
+// registerCacheMetrics is a function that registers the counters of the cache of each tenant
+func registerCacheMetrics(reg *metrics.Registry, tenants *tenant.Registry) {
+	// collect returns a sample of the stats of the cache of every tenant
+	collect := func(value func(s internal.CacheStats) float64) (samples []metrics.Sample) {
+		for _, f := range tenants.Fleets() {
+			if f.Cache == nil {
+				continue
+			}
+			samples = append(samples, metrics.Sample{LabelValues: []string{f.ID}, Value: value(f.Cache.Stats())})
+		}
+		return
+	}
+	counter := func(name, help string, value func(s internal.CacheStats) uint64) {
+		reg.NewCounterFunc(name, help, []string{"tenant"}, func() []metrics.Sample {
+			return collect(func(s internal.CacheStats) float64 { return float64(value(s)) })
+		})
+	}
+	counter("vehicle_cache_hits_total", "Number of lookups served from the cache.", func(s internal.CacheStats) uint64 { return s.Hits })
+	counter("vehicle_cache_misses_total", "Number of lookups that had to be computed.", func(s internal.CacheStats) uint64 { return s.Misses })
+	counter("vehicle_cache_evictions_total", "Number of entries evicted to make room.", func(s internal.CacheStats) uint64 { return s.Evictions })
+	counter("vehicle_cache_expirations_total", "Number of entries expired.", func(s internal.CacheStats) uint64 { return s.Expirations })
+	counter("vehicle_cache_invalidations_total", "Number of entries invalidated by changes to vehicles.", func(s internal.CacheStats) uint64 { return s.Invalidations })
+	reg.NewGaugeFunc("vehicle_cache_entries", "Number of entries in the cache.", []string{"tenant"}, func() []metrics.Sample {
+		return collect(func(s internal.CacheStats) float64 { return float64(s.Entries) })
+	})
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HashAPIKey is a function that returns the hash of an API key, as stored at rest
+// - keys are random and long, so an unsalted SHA-256 is enough to make a leaked file useless
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// apiKeyFile is a struct that represents the file of API keys
+// - several keys can share a subject, so a key is rotated by adding the new one and removing the old one later
+type apiKeyFile struct {
+	Keys []struct {
+		// ID identifies the key, e.g. ci-2026-10
+		ID string `yaml:"id"`
+		// Subject is the principal authenticated by the key
+		Subject string `yaml:"subject"`
+		// Hash is the hash of the key returned by HashAPIKey
+		Hash string `yaml:"hash"`
+		// Roles are the roles granted to the principal
+		Roles []string `yaml:"roles"`
+		// Tenant is the tenant of the principal, empty if it may act on any tenant
+		Tenant string `yaml:"tenant"`
+		// ExpiresAt is when the key stops being valid, zero if it does not expire
+		ExpiresAt time.Time `yaml:"expires_at"`
+	} `yaml:"keys"`
+}
+
+// apiKey is a struct that represents a valid API key
+type apiKey struct {
+	// principal is the principal authenticated by the key
+	principal Principal
+	// expiresAt is when the key stops being valid, zero if it does not expire
+	expiresAt time.Time
+}
+
+// NewAPIKeys is a function that returns a new instance of APIKeys, loading the keys of the file
+func NewAPIKeys(path string) (a *APIKeys, err error) {
+	a = &APIKeys{path: path, now: time.Now}
+	if err = a.Reload(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// APIKeys is a struct that represents an authenticator of static API keys, stored hashed in a YAML or JSON file
+// - the key is read from the X-API-Key header or an "Authorization: ApiKey <key>" header
+type APIKeys struct {
+	// path is the path to the file of the keys
+	path string
+	// mu is the mutex that guards the keys
+	mu sync.RWMutex
+	// keys are the valid keys by hash
+	keys map[string]apiKey
+	// now returns the current time
+	now func() time.Time
+}
+
+// Reload is a method that reloads the keys of the file, so keys can be rotated without a restart
+// - the keys in use are kept if the file is invalid
+func (a *APIKeys) Reload() (err error) {
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return
+	}
+	var f apiKeyFile
+	if err = yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("api keys file %s: %w", a.path, err)
+	}
+
+	keys := make(map[string]apiKey, len(f.Keys))
+	for i, k := range f.Keys {
+		if k.ID == "" || k.Subject == "" || !strings.HasPrefix(k.Hash, "sha256:") {
+			return fmt.Errorf("api keys file %s: key %d: id, subject and a sha256: hash are required", a.path, i)
+		}
+		keys[k.Hash] = apiKey{
+			principal: Principal{Subject: k.Subject, Method: MethodAPIKey, KeyID: k.ID, Roles: k.Roles, Tenant: k.Tenant},
+			expiresAt: k.ExpiresAt,
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = keys
+	return
+}
+
+// Authenticate is a method that returns the principal of the API key of the request
+func (a *APIKeys) Authenticate(r *http.Request) (p Principal, err error) {
+	key := r.Header.Get("X-API-Key")
+	if scheme, value, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "ApiKey") {
+		key = value
+	}
+	if key == "" {
+		return Principal{}, ErrNoCredentials
+	}
+
+	a.mu.RLock()
+	k, ok := a.keys[HashAPIKey(key)]
+	a.mu.RUnlock()
+	if !ok {
+		return Principal{}, fmt.Errorf("%w: unknown api key", ErrInvalidCredentials)
+	}
+	if !k.expiresAt.IsZero() && !a.now().Before(k.expiresAt) {
+		return Principal{}, fmt.Errorf("%w: api key %s expired", ErrInvalidCredentials, k.principal.KeyID)
+	}
+	return k.principal, nil
+}
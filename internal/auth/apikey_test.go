@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// apiKeysYAML is a function that returns a file of API keys, each given as id, key and expiry (RFC 3339, empty if none)
+func apiKeysYAML(keys ...[3]string) []byte {
+	var b strings.Builder
+	b.WriteString("keys:\n")
+	for _, k := range keys {
+		fmt.Fprintf(&b, "  - id: %s\n    subject: ci\n    hash: %s\n    roles: [editor]\n    tenant: acme\n", k[0], HashAPIKey(k[1]))
+		if k[2] != "" {
+			fmt.Fprintf(&b, "    expires_at: %s\n", k[2])
+		}
+	}
+	return []byte(b.String())
+}
+
+// authenticateKey is a function that authenticates a request with the header and the value
+func authenticateKey(a *APIKeys, header, value string) (Principal, error) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if header != "" {
+		r.Header.Set(header, value)
+	}
+	return a.Authenticate(r)
+}
+
+func TestAPIKeys_Authenticate(t *testing.T) {
+	path := writeFile(t, "keys.yaml", apiKeysYAML(
+		[3]string{"ci-2026-10", "key-1", ""},
+		[3]string{"ci-2026-09", "key-0", testNow.Format(time.RFC3339)},
+	))
+	a, err := NewAPIKeys(path)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		// now is the time of the request
+		now       time.Time
+		principal Principal
+		err       error
+		message   string
+	}{
+		{
+			name: "case 1: X-API-Key", header: "X-API-Key", value: "key-1", now: testNow,
+			principal: Principal{Subject: "ci", Method: MethodAPIKey, KeyID: "ci-2026-10", Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name: "case 2: Authorization ApiKey", header: "Authorization", value: "ApiKey key-1", now: testNow,
+			principal: Principal{Subject: "ci", Method: MethodAPIKey, KeyID: "ci-2026-10", Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name: "case 3: a key before its expiry", header: "X-API-Key", value: "key-0", now: testNow.Add(-time.Second),
+			principal: Principal{Subject: "ci", Method: MethodAPIKey, KeyID: "ci-2026-09", Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{name: "case 4: a key at its expiry", header: "X-API-Key", value: "key-0", now: testNow, err: ErrInvalidCredentials, message: "api key ci-2026-09 expired"},
+		{name: "case 5: unknown key", header: "X-API-Key", value: "key-2", now: testNow, err: ErrInvalidCredentials, message: "unknown api key"},
+		{name: "case 6: no key", now: testNow, err: ErrNoCredentials},
+		{name: "case 7: a bearer token is for another authenticator", header: "Authorization", value: "Bearer key-1", now: testNow, err: ErrNoCredentials},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			a.now = func() time.Time { return c.now }
+
+			// act
+			p, err := authenticateKey(a, c.header, c.value)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			require.Equal(t, c.principal, p)
+			if c.message != "" {
+				require.ErrorContains(t, err, c.message)
+			}
+		})
+	}
+}
+
+func TestAPIKeys_Reload(t *testing.T) {
+	t.Run("case 1: a key is rotated", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "keys.yaml", apiKeysYAML([3]string{"ci-2026-09", "old", ""}))
+		a, err := NewAPIKeys(path)
+		require.NoError(t, err)
+
+		// act and assert
+		// - the new key is added, both are valid for the same subject
+		require.NoError(t, os.WriteFile(path, apiKeysYAML([3]string{"ci-2026-09", "old", ""}, [3]string{"ci-2026-10", "new", ""}), 0o600))
+		require.NoError(t, a.Reload())
+		p, err := authenticateKey(a, "X-API-Key", "old")
+		require.NoError(t, err)
+		require.Equal(t, "ci-2026-09", p.KeyID)
+		p, err = authenticateKey(a, "X-API-Key", "new")
+		require.NoError(t, err)
+		require.Equal(t, "ci-2026-10", p.KeyID)
+		require.Equal(t, "ci", p.Subject)
+
+		// - the old key is removed
+		require.NoError(t, os.WriteFile(path, apiKeysYAML([3]string{"ci-2026-10", "new", ""}), 0o600))
+		require.NoError(t, a.Reload())
+		_, err = authenticateKey(a, "X-API-Key", "old")
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+		_, err = authenticateKey(a, "X-API-Key", "new")
+		require.NoError(t, err)
+	})
+
+	cases := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "case 2: invalid yaml", data: "keys: [", err: "yaml"},
+		{name: "case 3: no id", data: "keys:\n  - subject: ci\n    hash: " + HashAPIKey("new") + "\n", err: "key 0: id, subject and a sha256: hash are required"},
+		{name: "case 4: a key in clear", data: "keys:\n  - id: ci\n    subject: ci\n    hash: new\n", err: "key 0: id, subject and a sha256: hash are required"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			path := writeFile(t, "keys.yaml", apiKeysYAML([3]string{"ci-2026-09", "old", ""}))
+			a, err := NewAPIKeys(path)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(path, []byte(c.data), 0o600))
+
+			// act
+			err = a.Reload()
+
+			// assert
+			require.ErrorContains(t, err, c.err)
+			// - the keys in use are kept
+			_, err = authenticateKey(a, "X-API-Key", "old")
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("case 5: no file", func(t *testing.T) {
+		// act
+		_, err := NewAPIKeys(t.TempDir() + "/keys.yaml")
+
+		// assert
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
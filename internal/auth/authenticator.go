@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"app/internal/logger"
+	"app/internal/render"
+	"app/internal/tracing"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+var (
+	// ErrNoCredentials is the error returned when a request has no credentials for an authenticator
+	ErrNoCredentials = errors.New("no credentials")
+	// ErrInvalidCredentials is the error returned when the credentials of a request are not valid
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Authenticator is an interface that authenticates the caller of a request
+type Authenticator interface {
+	// Authenticate is a method that returns the principal of the request
+	// - it returns ErrNoCredentials if the request has no credentials it handles, so the next authenticator is tried
+	Authenticate(r *http.Request) (p Principal, err error)
+}
+
+// Middleware is a function that returns a middleware requiring every request to be authenticated
+// - the authenticators are tried in order, the first one handling the credentials of the request decides
+// - the principal is carried in the context of the request, and added to its logger so writes are audited
+func Middleware(authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := Authenticate(r, authenticators)
+			if err != nil {
+				logger.FromContext(r.Context()).Warn("authentication failed", slog.String("error", err.Error()))
+				w.Header().Set("WWW-Authenticate", `Bearer realm="api-vehicles"`)
+				render.Error(w, r, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			ctx := ContextWithPrincipal(r.Context(), p)
+			ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(
+				slog.String("principal", p.Subject),
+				slog.String("auth_method", string(p.Method)),
+			))
+			tracing.SpanFromContext(ctx).SetAttributes(tracing.String("enduser.id", p.Subject))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Authenticate is a function that returns the principal of the first authenticator handling the request
+// - the authenticators read the headers of the request only, so other transports authenticate through it too
+func Authenticate(r *http.Request, authenticators []Authenticator) (p Principal, err error) {
+	for _, a := range authenticators {
+		p, err = a.Authenticate(r)
+		if !errors.Is(err, ErrNoCredentials) {
+			return
+		}
+	}
+	return Principal{}, ErrNoCredentials
+}
@@ -0,0 +1,198 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWTOptions is a struct that represents the claims required from the JWTs
+type JWTOptions struct {
+	// Issuer is the required iss claim, empty to accept any
+	Issuer string
+	// Audience is the audience required in the aud claim, empty to accept any
+	Audience string
+	// Leeway is the clock skew tolerated on the exp and nbf claims
+	Leeway time.Duration
+}
+
+// NewJWT is a function that returns a new instance of JWT, loading its keys
+// - load returns the keys, it is called again on every reload so the keys can be rotated
+func NewJWT(load func() (*KeySet, error), opts JWTOptions) (j *JWT, err error) {
+	j = &JWT{load: load, opts: opts, now: time.Now}
+	if err = j.Reload(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// JWT is a struct that represents an authenticator of HS256 and RS256 JSON Web Tokens
+// - the token is read from an "Authorization: Bearer <token>" header
+// - the sub and exp claims are required, the roles are read from the roles (or role) claim and the tenant from the tenant claim
+type JWT struct {
+	// load returns the keys
+	load func() (*KeySet, error)
+	// opts are the claims required from the tokens
+	opts JWTOptions
+
+	// mu is the mutex that guards the keys
+	mu sync.RWMutex
+	// keys are the keys verifying the signatures
+	keys *KeySet
+	// now returns the current time
+	now func() time.Time
+}
+
+// Reload is a method that reloads the keys, the keys in use are kept if they cannot be loaded
+func (j *JWT) Reload() (err error) {
+	keys, err := j.load()
+	if err != nil {
+		return
+	}
+	if keys.Len() == 0 {
+		return fmt.Errorf("no jwt keys")
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.keys = keys
+	return
+}
+
+// jwtHeader is a struct that represents the header of a JWT
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims is a struct that represents the claims of a JWT used to authenticate
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ExpiresAt *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Roles     []string        `json:"roles"`
+	Role      string          `json:"role"`
+	Tenant    string          `json:"tenant"`
+}
+
+// Authenticate is a method that returns the principal of the bearer token of the request
+func (j *JWT) Authenticate(r *http.Request) (p Principal, err error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return Principal{}, ErrNoCredentials
+	}
+
+	invalid := func(format string, args ...any) (Principal, error) {
+		return Principal{}, fmt.Errorf("%w: jwt: %s", ErrInvalidCredentials, fmt.Sprintf(format, args...))
+	}
+
+	// decode
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return invalid("malformed token")
+	}
+	var header jwtHeader
+	var claims jwtClaims
+	if err = decodeSegment(parts[0], &header); err != nil {
+		return invalid("malformed header")
+	}
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return invalid("malformed claims")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return invalid("malformed signature")
+	}
+
+	// signature
+	key, ok := j.verify(header, []byte(parts[0]+"."+parts[1]), signature)
+	if !ok {
+		return invalid("signature not verified (alg %q, kid %q)", header.Alg, header.Kid)
+	}
+
+	// claims
+	now := j.now()
+	if claims.Subject == "" {
+		return invalid("missing sub claim")
+	}
+	if claims.ExpiresAt == nil {
+		return invalid("missing exp claim")
+	}
+	if now.After(time.Unix(int64(*claims.ExpiresAt), 0).Add(j.opts.Leeway)) {
+		return invalid("token expired")
+	}
+	if claims.NotBefore != nil && now.Add(j.opts.Leeway).Before(time.Unix(int64(*claims.NotBefore), 0)) {
+		return invalid("token not valid yet")
+	}
+	if j.opts.Issuer != "" && claims.Issuer != j.opts.Issuer {
+		return invalid("unexpected issuer %q", claims.Issuer)
+	}
+	if j.opts.Audience != "" && !audienceContains(claims.Audience, j.opts.Audience) {
+		return invalid("unexpected audience")
+	}
+
+	roles := claims.Roles
+	if len(roles) == 0 && claims.Role != "" {
+		roles = []string{claims.Role}
+	}
+	return Principal{Subject: claims.Subject, Method: MethodJWT, KeyID: key.id, Roles: roles, Tenant: claims.Tenant}, nil
+}
+
+// verify is a method that returns the key verifying the signature, ok is false if none does
+func (j *JWT) verify(header jwtHeader, signed, signature []byte) (key jwtKey, ok bool) {
+	j.mu.RLock()
+	keys := j.keys.keys
+	j.mu.RUnlock()
+
+	digest := sha256.Sum256(signed)
+	for _, k := range keys {
+		if k.alg != header.Alg || (header.Kid != "" && k.id != "" && k.id != header.Kid) {
+			continue
+		}
+		switch k.alg {
+		case AlgHS256:
+			mac := hmac.New(sha256.New, k.secret)
+			mac.Write(signed)
+			ok = hmac.Equal(mac.Sum(nil), signature)
+		case AlgRS256:
+			ok = rsa.VerifyPKCS1v15(k.public, crypto.SHA256, digest[:], signature) == nil
+		}
+		if ok {
+			return k, true
+		}
+	}
+	return
+}
+
+// decodeSegment is a function that decodes a base64url-encoded JSON segment of a JWT
+func decodeSegment(segment string, v any) (err error) {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(data, v)
+}
+
+// audienceContains is a function that returns true if the aud claim, a string or an array, contains the audience
+func audienceContains(aud json.RawMessage, audience string) bool {
+	var one string
+	if json.Unmarshal(aud, &one) == nil {
+		return one == audience
+	}
+	var many []string
+	if json.Unmarshal(aud, &many) == nil {
+		return slices.Contains(many, audience)
+	}
+	return false
+}
@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testNow is the current time of the tests of the tokens
+var testNow = time.Unix(1_800_000_000, 0)
+
+// testSecret is the HS256 secret of the tests
+var testSecret = []byte("test-secret")
+
+// signer is a function that returns the signature of the header and the claims of a token
+type signer func(signed []byte) []byte
+
+// hs256 is a function that returns a signer of HS256 tokens with the secret
+func hs256(secret []byte) signer {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(signed)
+		return mac.Sum(nil)
+	}
+}
+
+// rs256 is a function that returns a signer of RS256 tokens with the private key
+func rs256(t *testing.T, key *rsa.PrivateKey) signer {
+	return func(signed []byte) []byte {
+		digest := sha256.Sum256(signed)
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+		return signature
+	}
+}
+
+// newToken is a function that returns a token of the header and the claims, signed by sign
+func newToken(t *testing.T, header, claims map[string]any, sign signer) string {
+	t.Helper()
+
+	encode := func(v any) string {
+		data, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := encode(header) + "." + encode(claims)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(signed)))
+}
+
+// testClaims is a function that returns valid claims, overridden by the given ones (removed if nil)
+func testClaims(overrides map[string]any) map[string]any {
+	claims := map[string]any{
+		"sub":    "alice",
+		"iss":    "https://issuer.example",
+		"aud":    "api-vehicles",
+		"exp":    testNow.Add(time.Hour).Unix(),
+		"roles":  []string{"editor"},
+		"tenant": "acme",
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+// newTestJWT is a function that returns a JWT authenticator of the keys at testNow, requiring the issuer and the audience of testClaims
+func newTestJWT(t *testing.T, keys *KeySet) *JWT {
+	t.Helper()
+
+	j, err := NewJWT(func() (*KeySet, error) { return keys, nil }, JWTOptions{
+		Issuer:   "https://issuer.example",
+		Audience: "api-vehicles",
+		Leeway:   time.Minute,
+	})
+	require.NoError(t, err)
+	j.now = func() time.Time { return testNow }
+	return j
+}
+
+func TestJWT_Authenticate(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys := &KeySet{}
+	keys.AddHMAC("", testSecret)
+	keys.AddRSA("rsa-1", &private.PublicKey)
+	j := newTestJWT(t, keys)
+	// - the public key, as an attacker would use it as an HMAC secret
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+
+	hsHeader := map[string]any{"alg": "HS256", "typ": "JWT"}
+	rsHeader := map[string]any{"alg": "RS256", "kid": "rsa-1"}
+	cases := []struct {
+		name string
+		// authorization is the Authorization header of the request
+		authorization string
+		principal     Principal
+		err           error
+		// message is the reason of an invalid token
+		message string
+	}{
+		{
+			name:          "case 1: HS256",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(nil), hs256(testSecret)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 2: RS256",
+			authorization: "Bearer " + newToken(t, rsHeader, testClaims(nil), rs256(t, private)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, KeyID: "rsa-1", Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 3: RS256 without kid",
+			authorization: "Bearer " + newToken(t, map[string]any{"alg": "RS256"}, testClaims(nil), rs256(t, private)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, KeyID: "rsa-1", Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 4: a single role",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"roles": nil, "role": "viewer"}), hs256(testSecret)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, Roles: []string{"viewer"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 5: an audience among others",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"aud": []string{"other", "api-vehicles"}}), hs256(testSecret)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 6: expired within the leeway",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"exp": testNow.Add(-30 * time.Second).Unix()}), hs256(testSecret)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{
+			name:          "case 7: not valid yet within the leeway",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"nbf": testNow.Add(30 * time.Second).Unix()}), hs256(testSecret)),
+			principal:     Principal{Subject: "alice", Method: MethodJWT, Roles: []string{"editor"}, Tenant: "acme"},
+		},
+		{name: "case 8: no authorization", authorization: "", err: ErrNoCredentials},
+		{name: "case 9: another scheme", authorization: "ApiKey key", err: ErrNoCredentials},
+		{name: "case 10: malformed token", authorization: "Bearer a.b", err: ErrInvalidCredentials, message: "malformed token"},
+		{
+			name:          "case 11: wrong secret",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(nil), hs256([]byte("wrong"))),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 12: wrong RSA key",
+			authorization: "Bearer " + newToken(t, rsHeader, testClaims(nil), rs256(t, other)),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 13: HS256 signed with the RSA public key",
+			authorization: "Bearer " + newToken(t, map[string]any{"alg": "HS256", "kid": "rsa-1"}, testClaims(nil), hs256(public)),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 14: alg none",
+			authorization: "Bearer " + newToken(t, map[string]any{"alg": "none"}, testClaims(nil), func([]byte) []byte { return nil }),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 15: unknown kid",
+			authorization: "Bearer " + newToken(t, map[string]any{"alg": "RS256", "kid": "rsa-2"}, testClaims(nil), rs256(t, private)),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 16: tampered claims",
+			authorization: "Bearer " + tamper(t, newToken(t, hsHeader, testClaims(nil), hs256(testSecret)), testClaims(map[string]any{"roles": []string{"system-admin"}})),
+			err:           ErrInvalidCredentials, message: "signature not verified",
+		},
+		{
+			name:          "case 17: expired",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"exp": testNow.Add(-2 * time.Minute).Unix()}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: "token expired",
+		},
+		{
+			name:          "case 18: no exp",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"exp": nil}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: "missing exp claim",
+		},
+		{
+			name:          "case 19: not valid yet",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"nbf": testNow.Add(2 * time.Minute).Unix()}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: "token not valid yet",
+		},
+		{
+			name:          "case 20: another issuer",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"iss": "https://other.example"}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: `unexpected issuer "https://other.example"`,
+		},
+		{
+			name:          "case 21: another audience",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"aud": []string{"other"}}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: "unexpected audience",
+		},
+		{
+			name:          "case 22: no sub",
+			authorization: "Bearer " + newToken(t, hsHeader, testClaims(map[string]any{"sub": nil}), hs256(testSecret)),
+			err:           ErrInvalidCredentials, message: "missing sub claim",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := httptest.NewRequest("GET", "/", nil)
+			if c.authorization != "" {
+				r.Header.Set("Authorization", c.authorization)
+			}
+
+			// act
+			p, err := j.Authenticate(r)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			require.Equal(t, c.principal, p)
+			if c.message != "" {
+				require.ErrorContains(t, err, c.message)
+			}
+		})
+	}
+}
+
+// tamper is a function that returns the token with its claims replaced, keeping its signature
+func tamper(t *testing.T, token string, claims map[string]any) string {
+	data, err := json.Marshal(claims)
+	require.NoError(t, err)
+	parts := strings.Split(token, ".")
+	return parts[0] + "." + base64.RawURLEncoding.EncodeToString(data) + "." + parts[2]
+}
+
+func TestJWT_Reload(t *testing.T) {
+	t.Run("case 1: the keys are rotated", func(t *testing.T) {
+		// arrange
+		keys := &KeySet{}
+		keys.AddHMAC("2026-01", testSecret)
+		j, err := NewJWT(func() (*KeySet, error) { return keys, nil }, JWTOptions{})
+		require.NoError(t, err)
+		j.now = func() time.Time { return testNow }
+		old := newToken(t, map[string]any{"alg": "HS256"}, testClaims(nil), hs256(testSecret))
+		rotated := newToken(t, map[string]any{"alg": "HS256"}, testClaims(nil), hs256([]byte("rotated")))
+		keys = &KeySet{}
+		keys.AddHMAC("2026-02", []byte("rotated"))
+
+		// act
+		err = j.Reload()
+
+		// assert
+		require.NoError(t, err)
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+old)
+		_, err = j.Authenticate(r)
+		require.ErrorIs(t, err, ErrInvalidCredentials)
+		r.Header.Set("Authorization", "Bearer "+rotated)
+		p, err := j.Authenticate(r)
+		require.NoError(t, err)
+		require.Equal(t, "2026-02", p.KeyID)
+	})
+
+	t.Run("case 2: the keys in use are kept if they cannot be loaded", func(t *testing.T) {
+		// arrange
+		var loadErr error
+		keys := &KeySet{}
+		keys.AddHMAC("", testSecret)
+		j, err := NewJWT(func() (*KeySet, error) { return keys, loadErr }, JWTOptions{})
+		require.NoError(t, err)
+		j.now = func() time.Time { return testNow }
+		loadErr, keys = errors.New("unreadable"), &KeySet{}
+
+		// act
+		err = j.Reload()
+
+		// assert
+		require.EqualError(t, err, "unreadable")
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+newToken(t, map[string]any{"alg": "HS256"}, testClaims(nil), hs256(testSecret)))
+		_, err = j.Authenticate(r)
+		require.NoError(t, err)
+	})
+
+	t.Run("case 3: no keys", func(t *testing.T) {
+		// act
+		_, err := NewJWT(func() (*KeySet, error) { return &KeySet{}, nil }, JWTOptions{})
+
+		// assert
+		require.EqualError(t, err, "no jwt keys")
+	})
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	// AlgHS256 is the HMAC SHA-256 signature of a JWT, with a shared secret
+	AlgHS256 = "HS256"
+	// AlgRS256 is the RSA PKCS#1 v1.5 SHA-256 signature of a JWT, with a public key
+	AlgRS256 = "RS256"
+)
+
+// jwtKey is a struct that represents a key verifying the signatures of JWTs
+type jwtKey struct {
+	// id is the kid of the key, empty if the key matches tokens of any kid
+	id string
+	// alg is the only algorithm the key verifies, so a public key is never used as an HMAC secret
+	alg string
+	// secret is the secret of an HS256 key
+	secret []byte
+	// public is the public key of an RS256 key
+	public *rsa.PublicKey
+}
+
+// KeySet is a struct that represents the keys verifying the signatures of JWTs
+type KeySet struct {
+	keys []jwtKey
+}
+
+// AddHMAC is a method that adds an HS256 secret
+func (s *KeySet) AddHMAC(id string, secret []byte) {
+	s.keys = append(s.keys, jwtKey{id: id, alg: AlgHS256, secret: secret})
+}
+
+// AddRSA is a method that adds an RS256 public key
+func (s *KeySet) AddRSA(id string, public *rsa.PublicKey) {
+	s.keys = append(s.keys, jwtKey{id: id, alg: AlgRS256, public: public})
+}
+
+// Len is a method that returns the number of keys
+func (s *KeySet) Len() int {
+	return len(s.keys)
+}
+
+// ReadPEMFile is a method that adds the RS256 public key of a PEM file (PKIX or PKCS#1 public key, or certificate)
+func (s *KeySet) ReadPEMFile(id, path string) (err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return fmt.Errorf("public key file %s: no PEM block", path)
+	}
+
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		err = fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return fmt.Errorf("public key file %s: %w", path, err)
+	}
+	public, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("public key file %s: not an RSA public key", path)
+	}
+	s.AddRSA(id, public)
+	return
+}
+
+// jwksFile is a struct that represents a JSON Web Key Set (RFC 7517)
+type jwksFile struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		Use string `json:"use"`
+		// N and E are the modulus and exponent of an RSA key
+		N string `json:"n"`
+		E string `json:"e"`
+		// K is the secret of a symmetric key
+		K string `json:"k"`
+	} `json:"keys"`
+}
+
+// ReadJWKSFile is a method that adds the keys of a JWKS file, RSA keys as RS256 and symmetric keys as HS256
+// - keys for other uses or algorithms are skipped
+func (s *KeySet) ReadJWKSFile(path string) (err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var f jwksFile
+	if err = json.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("jwks file %s: %w", path, err)
+	}
+
+	decode := base64.RawURLEncoding.DecodeString
+	for _, k := range f.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch {
+		case k.Kty == "RSA" && (k.Alg == "" || k.Alg == AlgRS256):
+			n, errN := decode(k.N)
+			e, errE := decode(k.E)
+			if err = errors.Join(errN, errE); err != nil || len(e) > 4 {
+				return fmt.Errorf("jwks file %s: key %q: invalid modulus or exponent", path, k.Kid)
+			}
+			s.AddRSA(k.Kid, &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			})
+		case k.Kty == "oct" && (k.Alg == "" || k.Alg == AlgHS256):
+			secret, errK := decode(k.K)
+			if errK != nil {
+				return fmt.Errorf("jwks file %s: key %q: invalid secret", path, k.Kid)
+			}
+			s.AddHMAC(k.Kid, secret)
+		}
+	}
+	return
+}
@@ -0,0 +1,215 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeFile is a function that writes the data to a file of the directory of the test, returning its path
+func writeFile(t *testing.T, name string, data []byte) (path string) {
+	t.Helper()
+
+	path = filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return
+}
+
+// rsaJWK is a function that returns the JWK of the RSA public key
+func rsaJWK(kid, alg, use string, public *rsa.PublicKey) map[string]any {
+	return map[string]any{
+		"kty": "RSA", "kid": kid, "alg": alg, "use": use,
+		"n": base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+	}
+}
+
+func TestKeySet_ReadPEMFile(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkix, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecPKIX, err := x509.MarshalPKIXPublicKey(&ec.PublicKey)
+	require.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &private.PublicKey, private)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		data []byte
+		// err is the error reading the file, empty if the key is read
+		err string
+	}{
+		{name: "case 1: PKIX public key", data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix})},
+		{name: "case 2: PKCS#1 public key", data: pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&private.PublicKey)})},
+		{name: "case 3: certificate", data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert})},
+		{name: "case 4: not PEM", data: []byte("key"), err: "no PEM block"},
+		{name: "case 5: private key", data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}), err: `unsupported PEM block "RSA PRIVATE KEY"`},
+		{name: "case 6: not an RSA key", data: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecPKIX}), err: "not an RSA public key"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			path := writeFile(t, "key.pem", c.data)
+			ks := &KeySet{}
+
+			// act
+			err := ks.ReadPEMFile("pem-1", path)
+
+			// assert
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				require.Equal(t, 0, ks.Len())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, []jwtKey{{id: "pem-1", alg: AlgRS256, public: &private.PublicKey}}, ks.keys)
+		})
+	}
+}
+
+func TestKeySet_ReadJWKSFile(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name string
+		keys []map[string]any
+		// expected are the keys read
+		expected []jwtKey
+		err      string
+	}{
+		{
+			name:     "case 1: RSA key",
+			keys:     []map[string]any{rsaJWK("rsa-1", "RS256", "sig", &private.PublicKey)},
+			expected: []jwtKey{{id: "rsa-1", alg: AlgRS256, public: &private.PublicKey}},
+		},
+		{
+			name:     "case 2: RSA key without alg nor use",
+			keys:     []map[string]any{rsaJWK("rsa-1", "", "", &private.PublicKey)},
+			expected: []jwtKey{{id: "rsa-1", alg: AlgRS256, public: &private.PublicKey}},
+		},
+		{
+			name:     "case 3: symmetric key",
+			keys:     []map[string]any{{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString(testSecret)}},
+			expected: []jwtKey{{id: "hmac-1", alg: AlgHS256, secret: testSecret}},
+		},
+		{
+			name: "case 4: keys for encryption and other algorithms are skipped",
+			keys: []map[string]any{
+				rsaJWK("enc", "RS256", "enc", &private.PublicKey),
+				rsaJWK("ps256", "PS256", "sig", &private.PublicKey),
+				{"kty": "oct", "kid": "hs512", "alg": "HS512", "k": "c2VjcmV0"},
+				{"kty": "EC", "kid": "ec", "crv": "P-256"},
+			},
+			expected: nil,
+		},
+		{
+			name: "case 5: invalid modulus",
+			keys: []map[string]any{{"kty": "RSA", "kid": "rsa-1", "n": "%%%", "e": "AQAB"}},
+			err:  `key "rsa-1": invalid modulus or exponent`,
+		},
+		{
+			name: "case 6: invalid secret",
+			keys: []map[string]any{{"kty": "oct", "kid": "hmac-1", "k": "%%%"}},
+			err:  `key "hmac-1": invalid secret`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			data, err := json.Marshal(map[string]any{"keys": c.keys})
+			require.NoError(t, err)
+			path := writeFile(t, "jwks.json", data)
+			ks := &KeySet{}
+
+			// act
+			err = ks.ReadJWKSFile(path)
+
+			// assert
+			if c.err != "" {
+				require.ErrorContains(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, ks.keys)
+		})
+	}
+
+	t.Run("case 7: not JSON", func(t *testing.T) {
+		// arrange
+		path := writeFile(t, "jwks.json", []byte("keys"))
+
+		// act
+		err := (&KeySet{}).ReadJWKSFile(path)
+
+		// assert
+		require.ErrorContains(t, err, "jwks file "+path)
+	})
+}
+
+func TestKeySet_KidLookup(t *testing.T) {
+	// arrange
+	first, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	second, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]any{"keys": []map[string]any{
+		rsaJWK("2026-01", "RS256", "sig", &first.PublicKey),
+		rsaJWK("2026-02", "RS256", "sig", &second.PublicKey),
+	}})
+	require.NoError(t, err)
+	ks := &KeySet{}
+	require.NoError(t, ks.ReadJWKSFile(writeFile(t, "jwks.json", data)))
+	j := newTestJWT(t, ks)
+
+	cases := []struct {
+		name string
+		kid  string
+		key  *rsa.PrivateKey
+		// keyID is the id of the key verifying the token, empty if none does
+		keyID string
+	}{
+		{name: "case 1: the first key", kid: "2026-01", key: first, keyID: "2026-01"},
+		{name: "case 2: the second key", kid: "2026-02", key: second, keyID: "2026-02"},
+		{name: "case 3: no kid tries every key", kid: "", key: second, keyID: "2026-02"},
+		{name: "case 4: the kid of another key", kid: "2026-01", key: second},
+		{name: "case 5: unknown kid", kid: "2026-03", key: first},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			header := map[string]any{"alg": "RS256"}
+			if c.kid != "" {
+				header["kid"] = c.kid
+			}
+			r := httptest.NewRequest("GET", "/", nil)
+			r.Header.Set("Authorization", "Bearer "+newToken(t, header, testClaims(nil), rs256(t, c.key)))
+
+			// act
+			p, err := j.Authenticate(r)
+
+			// assert
+			if c.keyID == "" {
+				require.ErrorIs(t, err, ErrInvalidCredentials)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.keyID, p.KeyID)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package auth
+
+import (
+	"app/internal/logger"
+	"app/internal/render"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Permission is an operation a role may perform
+type Permission string
+
+const (
+	// PermissionAuthenticated is granted to every authenticated principal, for routes any caller may use
+	PermissionAuthenticated Permission = "authenticated"
+	// PermissionVehiclesRead allows reading vehicles
+	PermissionVehiclesRead Permission = "vehicles:read"
+	// PermissionVehiclesReadRegistration allows reading the registration of vehicles, hidden otherwise
+	PermissionVehiclesReadRegistration Permission = "vehicles:read:registration"
+	// PermissionVehiclesWrite allows creating and updating vehicles one at a time
+	PermissionVehiclesWrite Permission = "vehicles:write"
+	// PermissionVehiclesBatch allows batch imports of vehicles
+	PermissionVehiclesBatch Permission = "vehicles:batch"
+	// PermissionVehiclesDelete allows deleting vehicles
+	PermissionVehiclesDelete Permission = "vehicles:delete"
+	// PermissionAdminRead allows reading the state of the application (cache, aggregates, log level)
+	PermissionAdminRead Permission = "admin:read"
+	// PermissionAdminWrite allows changing the state of the application (log level)
+	PermissionAdminWrite Permission = "admin:write"
+)
+
+// policyFile is a struct that represents a policy file
+type policyFile struct {
+	// Roles are the roles by name
+	Roles map[string]struct {
+		// Inherits are the roles whose permissions are granted as well
+		Inherits []string `yaml:"inherits"`
+		// Permissions are the permissions granted
+		Permissions []Permission `yaml:"permissions"`
+	} `yaml:"roles"`
+	// Routes are the permissions required by each route, by "METHOD pattern" (e.g. "DELETE /vehicles/{id}"),
+	// and by each RPC, by "GRPC /package.Service/Method"
+	Routes map[string]Permission `yaml:"routes"`
+}
+
+// DefaultPolicyYAML is the default policy
+const DefaultPolicyYAML = `roles:
+  viewer:
+    permissions: [vehicles:read]
+  editor:
+    inherits: [viewer]
+    permissions: [vehicles:read:registration, vehicles:write]
+  fleet-admin:
+    inherits: [editor]
+    permissions: [vehicles:batch, vehicles:delete]
+  system-admin:
+    inherits: [fleet-admin]
+    permissions: [admin:read, admin:write]
+routes:
+  GET /vehicles: vehicles:read
+  POST /vehicles: vehicles:write
+  GET /vehicles/color/{color}/year/{year}: vehicles:read
+  GET /vehicles/brand/{brand}/between/{start_year}/{end_year}: vehicles:read
+  GET /vehicles/average_speed/brand/{brand}: vehicles:read
+  POST /vehicles/batch: vehicles:batch
+  PUT /vehicles/{id}/update_speed: vehicles:write
+  GET /vehicles/fuel_type/{type}: vehicles:read
+  DELETE /vehicles/{id}: vehicles:delete
+  GET /vehicles/transmission/{type}: vehicles:read
+  PUT /vehicles/{id}/update_fuel: vehicles:write
+  GET /vehicles/average_capacity/brand/{brand}: vehicles:read
+  GET /vehicles/dimensions: vehicles:read
+  GET /vehicles/weight: vehicles:read
+  GET /vehicles/{id}/similar: vehicles:read
+  GET /vehicles/search: vehicles:read
+  GET /v1/vehicles: vehicles:read
+  POST /v1/vehicles: vehicles:write
+  GET /v1/vehicles/color/{color}/year/{year}: vehicles:read
+  GET /v1/vehicles/brand/{brand}/between/{start_year}/{end_year}: vehicles:read
+  GET /v1/vehicles/average_speed/brand/{brand}: vehicles:read
+  POST /v1/vehicles/batch: vehicles:batch
+  PUT /v1/vehicles/{id}/update_speed: vehicles:write
+  GET /v1/vehicles/fuel_type/{type}: vehicles:read
+  DELETE /v1/vehicles/{id}: vehicles:delete
+  GET /v1/vehicles/transmission/{type}: vehicles:read
+  PUT /v1/vehicles/{id}/update_fuel: vehicles:write
+  GET /v1/vehicles/average_capacity/brand/{brand}: vehicles:read
+  GET /v1/vehicles/dimensions: vehicles:read
+  GET /v1/vehicles/weight: vehicles:read
+  GET /v1/vehicles/{id}/similar: vehicles:read
+  GET /v1/vehicles/search: vehicles:read
+  GET /v2/vehicles: vehicles:read
+  POST /v2/vehicles: vehicles:write
+  POST /v2/vehicles/batch: vehicles:batch
+  GET /v2/vehicles/search: vehicles:read
+  GET /v2/vehicles/stats: vehicles:read
+  GET /v2/vehicles/{id}: vehicles:read
+  PATCH /v2/vehicles/{id}: vehicles:write
+  DELETE /v2/vehicles/{id}: vehicles:delete
+  GET /v2/vehicles/{id}/similar: vehicles:read
+  GET /graphql: authenticated
+  POST /graphql: authenticated
+  GET /auth/whoami: authenticated
+  GRPC /vehicle.v1.VehicleService/GetVehicle: vehicles:read
+  GRPC /vehicle.v1.VehicleService/ListVehicles: vehicles:read
+  GRPC /vehicle.v1.VehicleService/StreamVehicles: vehicles:read
+  GRPC /vehicle.v1.VehicleService/CreateVehicle: vehicles:write
+  GRPC /vehicle.v1.VehicleService/CreateVehicles: vehicles:batch
+  GRPC /vehicle.v1.VehicleService/UpdateVehicle: vehicles:write
+  GRPC /vehicle.v1.VehicleService/DeleteVehicle: vehicles:delete
+  GRPC /vehicle.v1.VehicleService/SearchVehicles: vehicles:read
+  GRPC /vehicle.v1.VehicleService/GetSimilarVehicles: vehicles:read
+  GRPC /vehicle.v1.VehicleService/GetStats: vehicles:read
+  GRPC /vehicle.v1.VehicleService/WatchVehicles: vehicles:read
+  GRPC /grpc.reflection.v1.ServerReflection/ServerReflectionInfo: authenticated
+  GRPC /grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo: authenticated
+  GET /admin/cache: admin:read
+  GET /admin/aggregates/check: admin:read
+  GET /admin/log_level: admin:read
+  PUT /admin/log_level: admin:write
+  GET /admin/tenants: admin:read
+  POST /admin/tenants: admin:write
+  POST /admin/tenants/{tenant}/seed: admin:write
+`
+
+// DefaultPolicy is a function that returns the default policy
+func DefaultPolicy() *Policy {
+	p, err := ParsePolicy([]byte(DefaultPolicyYAML))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// ReadPolicyFile is a function that returns the policy of a YAML or JSON file
+func ReadPolicyFile(path string) (p *Policy, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if p, err = ParsePolicy(data); err != nil {
+		return nil, fmt.Errorf("policy file %s: %w", path, err)
+	}
+	return
+}
+
+// ParsePolicy is a function that returns the policy of its YAML or JSON representation
+// - the permissions of each role are resolved with the ones it inherits, cycles are an error
+func ParsePolicy(data []byte) (p *Policy, err error) {
+	var f policyFile
+	if err = yaml.Unmarshal(data, &f); err != nil {
+		return
+	}
+
+	p = &Policy{roles: make(map[string]map[Permission]bool), routes: f.Routes}
+	// resolve resolves the permissions of a role, visiting marks the roles being resolved
+	visiting := make(map[string]bool)
+	var resolve func(role string) (map[Permission]bool, error)
+	resolve = func(role string) (map[Permission]bool, error) {
+		if perms, ok := p.roles[role]; ok {
+			return perms, nil
+		}
+		r, ok := f.Roles[role]
+		if !ok {
+			return nil, fmt.Errorf("unknown role %q", role)
+		}
+		if visiting[role] {
+			return nil, fmt.Errorf("role %q inherits itself", role)
+		}
+		visiting[role] = true
+
+		perms := map[Permission]bool{PermissionAuthenticated: true}
+		for _, parent := range r.Inherits {
+			inherited, err := resolve(parent)
+			if err != nil {
+				return nil, err
+			}
+			for perm := range inherited {
+				perms[perm] = true
+			}
+		}
+		for _, perm := range r.Permissions {
+			perms[perm] = true
+		}
+		p.roles[role] = perms
+		return perms, nil
+	}
+	for role := range f.Roles {
+		if _, err = resolve(role); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// Policy is a struct that represents the permissions of each role, and the permission required by each route
+// - principals are granted the union of the permissions of their roles, unknown roles grant nothing
+type Policy struct {
+	// roles are the resolved permissions of each role
+	roles map[string]map[Permission]bool
+	// routes are the permissions required by each route, by "METHOD pattern"
+	routes map[string]Permission
+}
+
+// Allowed is a method that returns true if the principal has the permission
+func (p *Policy) Allowed(pr Principal, perm Permission) bool {
+	for _, role := range pr.Roles {
+		if p.roles[role][perm] {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles is a method that returns the names of the roles, sorted
+func (p *Policy) Roles() (roles []string) {
+	for role := range p.roles {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return
+}
+
+// Routes is a method that returns the routes of the policy, by "METHOD pattern", sorted
+func (p *Policy) Routes() (routes []string) {
+	for route := range p.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return
+}
+
+// routeKey is a function that returns the key of a route in the policy
+func routeKey(method, pattern string) string {
+	return method + " " + pattern
+}
+
+// CheckRoutes is a method that returns an error listing the routes without a permission in the policy
+// - routes without a permission are denied to everyone, so a route added without a policy is caught on start
+func (p *Policy) CheckRoutes(routes chi.Routes) error {
+	var missing []string
+	err := chi.Walk(routes, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		pattern := strings.TrimSuffix(route, "/")
+		if _, ok := p.routes[routeKey(method, pattern)]; !ok {
+			missing = append(missing, routeKey(method, pattern))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("routes without permission in the policy: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Permission is a method that returns the permission the policy requires for a route or a method of another transport
+// - ok is false if the policy has none, the route is then denied to everyone
+func (p *Policy) Permission(method, pattern string) (perm Permission, ok bool) {
+	perm, ok = p.routes[routeKey(method, pattern)]
+	return
+}
+
+// CheckMethods is a method that returns an error listing the patterns of a method without a permission in the policy
+// - e.g. the full names of the RPCs, with the method GRPC
+func (p *Policy) CheckMethods(method string, patterns []string) error {
+	var missing []string
+	for _, pattern := range patterns {
+		if _, ok := p.routes[routeKey(method, pattern)]; !ok {
+			missing = append(missing, routeKey(method, pattern))
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("methods without permission in the policy: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// Authorize is a function that returns a middleware enforcing the permission the policy requires for each route
+// - it is the single place routes are authorized, the route is matched before serving the request
+// - it must be used after Middleware, requests without a principal are served as they are
+func Authorize(p *Policy, routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pr, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				// authentication is disabled
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			path := r.URL.Path
+			if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePath != "" {
+				path = rc.RoutePath
+			}
+			match := chi.NewRouteContext()
+			if !routes.Match(match, r.Method, path) {
+				// not found or method not allowed, answered by the router
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			perm, ok := p.routes[routeKey(r.Method, match.RoutePattern())]
+			if !ok || !p.Allowed(pr, perm) {
+				logger.FromContext(r.Context()).Warn("authorization denied", slog.String("route", match.RoutePattern()), slog.String("permission", string(perm)))
+				render.Error(w, r, http.StatusForbidden, "forbidden")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
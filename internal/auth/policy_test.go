@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// defaultRoles are the roles of the default policy, each granted the permissions of the ones before
+var defaultRoles = []string{"viewer", "editor", "fleet-admin", "system-admin"}
+
+// defaultGrants are the permissions of each role of the default policy
+var defaultGrants = map[string][]Permission{
+	"viewer":       {PermissionAuthenticated, PermissionVehiclesRead},
+	"editor":       {PermissionAuthenticated, PermissionVehiclesRead, PermissionVehiclesReadRegistration, PermissionVehiclesWrite},
+	"fleet-admin":  {PermissionAuthenticated, PermissionVehiclesRead, PermissionVehiclesReadRegistration, PermissionVehiclesWrite, PermissionVehiclesBatch, PermissionVehiclesDelete},
+	"system-admin": {PermissionAuthenticated, PermissionVehiclesRead, PermissionVehiclesReadRegistration, PermissionVehiclesWrite, PermissionVehiclesBatch, PermissionVehiclesDelete, PermissionAdminRead, PermissionAdminWrite},
+}
+
+// allPermissions are the permissions a policy may grant
+var allPermissions = []Permission{
+	PermissionAuthenticated, PermissionVehiclesRead, PermissionVehiclesReadRegistration, PermissionVehiclesWrite,
+	PermissionVehiclesBatch, PermissionVehiclesDelete, PermissionAdminRead, PermissionAdminWrite,
+}
+
+func TestDefaultPolicy_Allowed(t *testing.T) {
+	p := DefaultPolicy()
+	require.Equal(t, []string{"editor", "fleet-admin", "system-admin", "viewer"}, p.Roles())
+
+	for _, role := range defaultRoles {
+		for _, perm := range allPermissions {
+			t.Run(role+" "+string(perm), func(t *testing.T) {
+				// act
+				allowed := p.Allowed(Principal{Subject: "alice", Roles: []string{role}}, perm)
+
+				// assert
+				require.Equal(t, slices.Contains(defaultGrants[role], perm), allowed)
+			})
+		}
+	}
+
+	t.Run("the permissions of several roles are granted together", func(t *testing.T) {
+		pr := Principal{Roles: []string{"unknown", "viewer"}}
+		require.True(t, p.Allowed(pr, PermissionVehiclesRead))
+		require.False(t, p.Allowed(pr, PermissionVehiclesWrite))
+	})
+
+	t.Run("unknown roles and principals without roles are granted nothing", func(t *testing.T) {
+		require.False(t, p.Allowed(Principal{Roles: []string{"unknown"}}, PermissionAuthenticated))
+		require.False(t, p.Allowed(Principal{}, PermissionAuthenticated))
+	})
+}
+
+func TestDefaultPolicy_Permission(t *testing.T) {
+	p := DefaultPolicy()
+
+	// every route and RPC requires a known permission
+	for _, route := range p.Routes() {
+		method, pattern, _ := strings.Cut(route, " ")
+		perm, ok := p.Permission(method, pattern)
+		require.True(t, ok, route)
+		require.Contains(t, allPermissions, perm, route)
+	}
+
+	cases := []struct {
+		method  string
+		pattern string
+		// roles are the roles of the default policy allowed to use the route
+		roles []string
+	}{
+		// - GraphQL is open to every role, its fields are authorized by the resolvers
+		{method: "GET", pattern: "/graphql", roles: defaultRoles},
+		{method: "POST", pattern: "/graphql", roles: defaultRoles},
+		// - gRPC
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/GetVehicle", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/ListVehicles", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/StreamVehicles", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/SearchVehicles", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/GetSimilarVehicles", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/GetStats", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/WatchVehicles", roles: defaultRoles},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/CreateVehicle", roles: defaultRoles[1:]},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/UpdateVehicle", roles: defaultRoles[1:]},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/CreateVehicles", roles: defaultRoles[2:]},
+		{method: "GRPC", pattern: "/vehicle.v1.VehicleService/DeleteVehicle", roles: defaultRoles[2:]},
+		{method: "GRPC", pattern: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", roles: defaultRoles},
+		{method: "GRPC", pattern: "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", roles: defaultRoles},
+	}
+	for _, c := range cases {
+		t.Run(c.method+" "+c.pattern, func(t *testing.T) {
+			// act
+			perm, ok := p.Permission(c.method, c.pattern)
+
+			// assert
+			require.True(t, ok)
+			for _, role := range defaultRoles {
+				require.Equal(t, slices.Contains(c.roles, role), p.Allowed(Principal{Roles: []string{role}}, perm), role)
+			}
+		})
+	}
+
+	t.Run("routes missing from the policy have no permission", func(t *testing.T) {
+		_, ok := p.Permission("GRPC", "/vehicle.v1.VehicleService/Unknown")
+		require.False(t, ok)
+		_, ok = p.Permission("PUT", "/v2/vehicles/{id}")
+		require.False(t, ok)
+	})
+
+	t.Run("methods missing from the policy are listed", func(t *testing.T) {
+		err := p.CheckMethods("GRPC", []string{"/vehicle.v1.VehicleService/GetVehicle", "/vehicle.v1.VehicleService/Unknown"})
+		require.EqualError(t, err, "methods without permission in the policy: GRPC /vehicle.v1.VehicleService/Unknown")
+	})
+}
+
+func TestParsePolicy(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{name: "case 1: unknown inherited role", data: "roles:\n  a:\n    inherits: [b]\n", err: `unknown role "b"`},
+		{name: "case 2: inheritance cycle", data: "roles:\n  a:\n    inherits: [b]\n  b:\n    inherits: [a]\n", err: "inherits itself"},
+		{name: "case 3: invalid yaml", data: "roles: [", err: "yaml"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			_, err := ParsePolicy([]byte(c.data))
+
+			// assert
+			require.ErrorContains(t, err, c.err)
+		})
+	}
+}
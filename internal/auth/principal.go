@@ -0,0 +1,42 @@
+package auth
+
+import "context"
+
+// Method is how a principal authenticated
+type Method string
+
+const (
+	// MethodAPIKey is the authentication with a static API key
+	MethodAPIKey Method = "api_key"
+	// MethodJWT is the authentication with a JSON Web Token
+	MethodJWT Method = "jwt"
+)
+
+// Principal is a struct that represents an authenticated caller
+type Principal struct {
+	// Subject identifies the caller, e.g. the subject of the API key or the sub claim of the JWT
+	Subject string
+	// Method is how the caller authenticated
+	Method Method
+	// KeyID identifies the API key or the JWT signing key, so rotations can be audited
+	KeyID string
+	// Roles are the roles granted to the caller
+	Roles []string
+	// Tenant is the tenant the caller belongs to, empty if the caller may act on any tenant
+	Tenant string
+}
+
+// principalKey is the key of the principal in a context
+type principalKey struct{}
+
+// ContextWithPrincipal is a function that returns a copy of the context carrying the principal
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext is a function that returns the principal carried by the context
+// - ok is false if the request was not authenticated, e.g. authentication is disabled
+func PrincipalFromContext(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(principalKey{}).(Principal)
+	return
+}
@@ -0,0 +1,23 @@
+package internal
+
+// CacheStats is a struct that represents the metrics of a cache
+type CacheStats struct {
+	// Hits is the number of lookups served from the cache
+	Hits uint64
+	// Misses is the number of lookups that had to be computed
+	Misses uint64
+	// Evictions is the number of entries removed to make room for new ones
+	Evictions uint64
+	// Expirations is the number of entries removed because their time to live elapsed
+	Expirations uint64
+	// Invalidations is the number of entries removed because a vehicle they depend on changed
+	Invalidations uint64
+	// Entries is the number of entries in the cache
+	Entries int
+}
+
+// Cache is an interface that represents a cache exposing its metrics
+type Cache interface {
+	// Stats is a method that returns the metrics of the cache
+	Stats() CacheStats
+}
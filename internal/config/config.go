@@ -0,0 +1,468 @@
+package config
+
+import (
+	"app/internal/application"
+	"app/internal/index"
+	"app/internal/ratelimit"
+	"app/internal/tracing"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Duration is a time.Duration written as text (e.g. 30s) in config files
+type Duration time.Duration
+
+// MarshalText is a method that returns the duration as text
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText is a method that parses the duration from text
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Server is a struct that represents the configuration of the HTTP server
+type Server struct {
+	// Address is the address where the server will be listening
+	Address string `json:"address" yaml:"address" toml:"address"`
+	// ReadTimeout is the maximum duration for reading a request
+	ReadTimeout Duration `json:"read_timeout" yaml:"read_timeout" toml:"read_timeout"`
+	// WriteTimeout is the maximum duration for writing a response
+	WriteTimeout Duration `json:"write_timeout" yaml:"write_timeout" toml:"write_timeout"`
+	// IdleTimeout is the maximum duration of an idle keep-alive connection
+	IdleTimeout Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout"`
+	// ShutdownTimeout is the maximum duration to drain the in-flight requests on shutdown
+	ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// Repository is a struct that represents the configuration of the vehicle repository
+type Repository struct {
+	// Backend is the storage of the vehicles, only memory is available
+	Backend string `json:"backend" yaml:"backend" toml:"backend"`
+}
+
+// Loader is a struct that represents the configuration of the vehicle loader
+type Loader struct {
+	// FilePath is the path to the JSON file that contains the vehicles of the default tenant
+	FilePath string `json:"file_path" yaml:"file_path" toml:"file_path"`
+	// SeedDir is the directory of the JSON files tenants can be seeded from, empty disables seeding
+	SeedDir string `json:"seed_dir" yaml:"seed_dir" toml:"seed_dir"`
+}
+
+// Limits is a struct that represents the limits on the requests
+type Limits struct {
+	// MaxBodyBytes is the maximum size of a request body, zero means unlimited
+	MaxBodyBytes int64 `json:"max_body_bytes" yaml:"max_body_bytes" toml:"max_body_bytes"`
+	// MaxHeaderBytes is the maximum size of the request headers
+	MaxHeaderBytes int `json:"max_header_bytes" yaml:"max_header_bytes" toml:"max_header_bytes"`
+}
+
+// Rate is a struct that represents the token bucket of a class of routes, a zero burst disables it
+type Rate struct {
+	// PerSecond is the number of requests a client regains each second
+	PerSecond float64 `json:"per_second" yaml:"per_second" toml:"per_second"`
+	// Burst is the number of requests a client can make at once
+	Burst int `json:"burst" yaml:"burst" toml:"burst"`
+}
+
+// RateLimit is a struct that represents the configuration of the rate limits of every client
+// - a client is the API key (or JWT subject) of the request, or its IP if not authenticated
+type RateLimit struct {
+	// Enabled is true if the requests are rate limited
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	// Read is the rate of the requests reading vehicles
+	Read Rate `json:"read" yaml:"read" toml:"read"`
+	// Write is the rate of the requests creating, updating or deleting a vehicle
+	Write Rate `json:"write" yaml:"write" toml:"write"`
+	// Batch is the rate of the batch imports
+	Batch Rate `json:"batch" yaml:"batch" toml:"batch"`
+	// Admin is the rate of the administration requests
+	Admin Rate `json:"admin" yaml:"admin" toml:"admin"`
+	// DailyWriteQuota is the number of write and batch requests per UTC day, zero means unlimited
+	DailyWriteQuota int `json:"daily_write_quota" yaml:"daily_write_quota" toml:"daily_write_quota"`
+	// IP is the rate of every request of an IP, before authentication
+	IP Rate `json:"ip" yaml:"ip" toml:"ip"`
+}
+
+// Idempotency is a struct that represents the configuration of the idempotency keys
+type Idempotency struct {
+	// TTL is how long the responses of the requests with an Idempotency-Key are replayed, zero disables idempotency keys
+	TTL Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+// Compression is a struct that represents the configuration of the compression of the responses
+type Compression struct {
+	// Level is the level of the gzip, deflate and brotli compression, from 1 (fastest) to 9 (smallest), 5 by default
+	// - zero disables compression
+	Level int `json:"level" yaml:"level" toml:"level"`
+}
+
+// GRPC is a struct that represents the configuration of the gRPC server
+type GRPC struct {
+	// Address is the address of the gRPC server, empty disables it
+	Address string `json:"address" yaml:"address" toml:"address"`
+}
+
+// GraphQL is a struct that represents the configuration of the GraphQL route
+type GraphQL struct {
+	// MaxDepth is the maximum depth of the queries
+	MaxDepth int `json:"max_depth" yaml:"max_depth" toml:"max_depth"`
+	// MaxComplexity is the maximum complexity of the queries, each field counted once per item of the lists above it
+	MaxComplexity int `json:"max_complexity" yaml:"max_complexity" toml:"max_complexity"`
+}
+
+// API is a struct that represents the configuration of the versions of the API
+type API struct {
+	// V1Deprecation is the date (YYYY-MM-DD) the v1 routes were deprecated, announced in their Deprecation header, empty to not announce it
+	V1Deprecation string `json:"v1_deprecation" yaml:"v1_deprecation" toml:"v1_deprecation"`
+	// V1Sunset is the date (YYYY-MM-DD) the v1 routes stop being served, announced in their Sunset header, empty to not announce it
+	V1Sunset string `json:"v1_sunset" yaml:"v1_sunset" toml:"v1_sunset"`
+}
+
+// dateLayout is the layout of the dates of the configuration
+const dateLayout = "2006-01-02"
+
+// date is a function that returns the date of a setting, zero if empty or invalid
+func date(s string) time.Time {
+	t, _ := time.Parse(dateLayout, s)
+	return t
+}
+
+// Cache is a struct that represents the configuration of the service cache
+type Cache struct {
+	// Capacity is the maximum number of cached results, zero disables the cache
+	Capacity int `json:"capacity" yaml:"capacity" toml:"capacity"`
+	// TTL is the time to live of a cached result, zero means results do not expire
+	TTL Duration `json:"ttl" yaml:"ttl" toml:"ttl"`
+}
+
+// Similarity is a struct that represents the weight of each attribute in the distance between similar vehicles
+// - numeric attributes count after being normalized by their standard deviation, categorical ones when they do not match
+type Similarity struct {
+	// MaxSpeed is the weight of the maximum speed
+	MaxSpeed float64 `json:"max_speed" yaml:"max_speed" toml:"max_speed"`
+	// Capacity is the weight of the capacity of people
+	Capacity float64 `json:"capacity" yaml:"capacity" toml:"capacity"`
+	// Weight is the weight of the weight
+	Weight float64 `json:"weight" yaml:"weight" toml:"weight"`
+	// Height is the weight of the height
+	Height float64 `json:"height" yaml:"height" toml:"height"`
+	// Length is the weight of the length
+	Length float64 `json:"length" yaml:"length" toml:"length"`
+	// Width is the weight of the width
+	Width float64 `json:"width" yaml:"width" toml:"width"`
+	// FuelType is the weight of a different fuel type
+	FuelType float64 `json:"fuel_type" yaml:"fuel_type" toml:"fuel_type"`
+	// Transmission is the weight of a different transmission
+	Transmission float64 `json:"transmission" yaml:"transmission" toml:"transmission"`
+}
+
+// Log is a struct that represents the configuration of the logger
+type Log struct {
+	// Level is the initial level of the logger: debug, info, warn or error
+	Level string `json:"level" yaml:"level" toml:"level"`
+}
+
+// Auth is a struct that represents the configuration of the authentication
+// - the server does not start without a key, unless authentication is disabled
+type Auth struct {
+	// Disabled serves every route without authentication, only if no key is configured
+	Disabled bool `json:"disabled" yaml:"disabled" toml:"disabled"`
+	// APIKeysFile is the path to the YAML or JSON file of the hashed API keys
+	APIKeysFile string `json:"api_keys_file" yaml:"api_keys_file" toml:"api_keys_file"`
+	// PolicyFile is the path to the YAML or JSON file of the roles and the permissions of each route, the default policy if empty
+	PolicyFile string `json:"policy_file" yaml:"policy_file" toml:"policy_file"`
+	// JWTSecret is the secret verifying HS256 JWTs, better set through the environment
+	JWTSecret string `json:"jwt_secret" yaml:"jwt_secret" toml:"jwt_secret"`
+	// JWTPublicKeyFile is the path to the PEM file of the RSA public key verifying RS256 JWTs
+	JWTPublicKeyFile string `json:"jwt_public_key_file" yaml:"jwt_public_key_file" toml:"jwt_public_key_file"`
+	// JWKSFile is the path to the JWKS file of the keys verifying JWTs
+	JWKSFile string `json:"jwks_file" yaml:"jwks_file" toml:"jwks_file"`
+	// JWTIssuer is the issuer required from the JWTs, empty to accept any
+	JWTIssuer string `json:"jwt_issuer" yaml:"jwt_issuer" toml:"jwt_issuer"`
+	// JWTAudience is the audience required from the JWTs, empty to accept any
+	JWTAudience string `json:"jwt_audience" yaml:"jwt_audience" toml:"jwt_audience"`
+}
+
+// Tracing is a struct that represents the configuration of the tracer
+type Tracing struct {
+	// Exporter is where the spans are exported: stdout or otlp_file, empty disables tracing
+	Exporter string `json:"exporter" yaml:"exporter" toml:"exporter"`
+	// FilePath is the path to the file of the otlp_file exporter
+	FilePath string `json:"file_path" yaml:"file_path" toml:"file_path"`
+	// Sampler is the sampler of the traces, named as in OTEL_TRACES_SAMPLER
+	Sampler string `json:"sampler" yaml:"sampler" toml:"sampler"`
+	// SamplerRatio is the ratio of the traces recorded by the traceidratio samplers
+	SamplerRatio float64 `json:"sampler_ratio" yaml:"sampler_ratio" toml:"sampler_ratio"`
+}
+
+// Config is a struct that represents the configuration of the application
+type Config struct {
+	Server      Server      `json:"server" yaml:"server" toml:"server"`
+	Repository  Repository  `json:"repository" yaml:"repository" toml:"repository"`
+	Loader      Loader      `json:"loader" yaml:"loader" toml:"loader"`
+	Limits      Limits      `json:"limits" yaml:"limits" toml:"limits"`
+	RateLimit   RateLimit   `json:"rate_limit" yaml:"rate_limit" toml:"rate_limit"`
+	Idempotency Idempotency `json:"idempotency" yaml:"idempotency" toml:"idempotency"`
+	Compression Compression `json:"compression" yaml:"compression" toml:"compression"`
+	API         API         `json:"api" yaml:"api" toml:"api"`
+	GraphQL     GraphQL     `json:"graphql" yaml:"graphql" toml:"graphql"`
+	GRPC        GRPC        `json:"grpc" yaml:"grpc" toml:"grpc"`
+	Cache       Cache       `json:"cache" yaml:"cache" toml:"cache"`
+	Similarity  Similarity  `json:"similarity" yaml:"similarity" toml:"similarity"`
+	Log         Log         `json:"log" yaml:"log" toml:"log"`
+	Auth        Auth        `json:"auth" yaml:"auth" toml:"auth"`
+	Tracing     Tracing     `json:"tracing" yaml:"tracing" toml:"tracing"`
+}
+
+// Default is a function that returns the default configuration
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Address:         ":8080",
+			ReadTimeout:     Duration(10 * time.Second),
+			WriteTimeout:    Duration(30 * time.Second),
+			IdleTimeout:     Duration(2 * time.Minute),
+			ShutdownTimeout: Duration(30 * time.Second),
+		},
+		Repository: Repository{Backend: "memory"},
+		Loader: Loader{
+			FilePath: "../docs/db/vehicles_100.json",
+			SeedDir:  "../docs/db",
+		},
+		Limits: Limits{
+			MaxBodyBytes:   1 << 20,
+			MaxHeaderBytes: 1 << 20,
+		},
+		RateLimit: RateLimit{
+			Enabled:         true,
+			Read:            Rate{PerSecond: 50, Burst: 100},
+			Write:           Rate{PerSecond: 10, Burst: 20},
+			Batch:           Rate{PerSecond: 0.2, Burst: 2},
+			Admin:           Rate{PerSecond: 2, Burst: 10},
+			DailyWriteQuota: 10000,
+			IP:              Rate{PerSecond: 100, Burst: 200},
+		},
+		Idempotency: Idempotency{TTL: Duration(24 * time.Hour)},
+		Compression: Compression{Level: 5},
+		API: API{
+			V1Deprecation: "2026-10-19",
+			V1Sunset:      "2027-04-19",
+		},
+		GraphQL: GraphQL{MaxDepth: 10, MaxComplexity: 1000},
+		GRPC:    GRPC{Address: ":9090"},
+		Cache: Cache{
+			Capacity: 1024,
+			TTL:      Duration(time.Minute),
+		},
+		Similarity: Similarity{
+			MaxSpeed:     1,
+			Capacity:     1,
+			Weight:       1,
+			Height:       1,
+			Length:       1,
+			Width:        1,
+			FuelType:     1,
+			Transmission: 1,
+		},
+		Log: Log{Level: "info"},
+		Tracing: Tracing{
+			Sampler:      "parentbased_always_on",
+			SamplerRatio: 1,
+		},
+	}
+}
+
+// Validate is a method that returns an error listing every invalid setting, nil if all are valid
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(name, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...)))
+	}
+	nonNegative := func(name string, d Duration) {
+		if d < 0 {
+			invalid(name, "must not be negative, got %s", time.Duration(d))
+		}
+	}
+
+	if c.Server.Address == "" {
+		invalid("server.address", "must not be empty")
+	}
+	nonNegative("server.read_timeout", c.Server.ReadTimeout)
+	nonNegative("server.write_timeout", c.Server.WriteTimeout)
+	nonNegative("server.idle_timeout", c.Server.IdleTimeout)
+	nonNegative("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	if c.Repository.Backend != "memory" {
+		invalid("repository.backend", "must be memory, got %q", c.Repository.Backend)
+	}
+	if c.Loader.FilePath == "" {
+		invalid("loader.file_path", "must not be empty")
+	}
+	if c.Limits.MaxBodyBytes < 0 {
+		invalid("limits.max_body_bytes", "must not be negative, got %d", c.Limits.MaxBodyBytes)
+	}
+	if c.Limits.MaxHeaderBytes < 0 {
+		invalid("limits.max_header_bytes", "must not be negative, got %d", c.Limits.MaxHeaderBytes)
+	}
+	for name, r := range map[string]Rate{"read": c.RateLimit.Read, "write": c.RateLimit.Write, "batch": c.RateLimit.Batch, "admin": c.RateLimit.Admin, "ip": c.RateLimit.IP} {
+		if r.PerSecond < 0 {
+			invalid("rate_limit."+name+".per_second", "must not be negative, got %g", r.PerSecond)
+		}
+		if r.Burst < 0 {
+			invalid("rate_limit."+name+".burst", "must not be negative, got %d", r.Burst)
+		}
+	}
+	if c.RateLimit.DailyWriteQuota < 0 {
+		invalid("rate_limit.daily_write_quota", "must not be negative, got %d", c.RateLimit.DailyWriteQuota)
+	}
+	for name, w := range map[string]float64{
+		"max_speed": c.Similarity.MaxSpeed, "capacity": c.Similarity.Capacity, "weight": c.Similarity.Weight,
+		"height": c.Similarity.Height, "length": c.Similarity.Length, "width": c.Similarity.Width,
+		"fuel_type": c.Similarity.FuelType, "transmission": c.Similarity.Transmission,
+	} {
+		if w < 0 {
+			invalid("similarity."+name, "must not be negative, got %g", w)
+		}
+	}
+	nonNegative("idempotency.ttl", c.Idempotency.TTL)
+	if c.Compression.Level < 0 || c.Compression.Level > 9 {
+		invalid("compression.level", "must be between 0 and 9, got %d", c.Compression.Level)
+	}
+	for name, value := range map[string]string{"api.v1_deprecation": c.API.V1Deprecation, "api.v1_sunset": c.API.V1Sunset} {
+		if _, err := time.Parse(dateLayout, value); value != "" && err != nil {
+			invalid(name, "must be a date as YYYY-MM-DD, got %q", value)
+		}
+	}
+	if d, s := date(c.API.V1Deprecation), date(c.API.V1Sunset); !d.IsZero() && !s.IsZero() && s.Before(d) {
+		invalid("api.v1_sunset", "must not be before api.v1_deprecation")
+	}
+	if c.GraphQL.MaxDepth < 1 {
+		invalid("graphql.max_depth", "must be positive, got %d", c.GraphQL.MaxDepth)
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		invalid("graphql.max_complexity", "must be positive, got %d", c.GraphQL.MaxComplexity)
+	}
+	keys := c.Auth.APIKeysFile != "" || c.Auth.JWTSecret != "" || c.Auth.JWTPublicKeyFile != "" || c.Auth.JWKSFile != ""
+	switch {
+	case c.Auth.Disabled && keys:
+		invalid("auth.disabled", "must be false with API keys or JWT keys configured")
+	case !c.Auth.Disabled && !keys:
+		invalid("auth", "must configure api_keys_file, jwt_secret, jwt_public_key_file or jwks_file, or set disabled")
+	}
+	if c.Cache.Capacity < 0 {
+		invalid("cache.capacity", "must not be negative, got %d", c.Cache.Capacity)
+	}
+	nonNegative("cache.ttl", c.Cache.TTL)
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		invalid("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Tracing.Exporter {
+	case "", "stdout":
+	case "otlp_file":
+		if c.Tracing.FilePath == "" {
+			invalid("tracing.file_path", "must not be empty with the otlp_file exporter")
+		}
+	default:
+		invalid("tracing.exporter", "must be stdout, otlp_file or empty, got %q", c.Tracing.Exporter)
+	}
+	if _, err := tracing.ParseSampler(c.Tracing.Sampler, c.Tracing.SamplerRatio); err != nil {
+		invalid("tracing.sampler", "%s", err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// compressionLevel is a method that returns the level of the compression of the responses
+// - the server disables compression on a negative level, as zero keeps its default level
+func (c *Config) compressionLevel() int {
+	if c.Compression.Level == 0 {
+		return -1
+	}
+	return c.Compression.Level
+}
+
+// rateLimits is a method that returns the limits of the clients, nil if rate limiting is disabled
+func (c *Config) rateLimits() *ratelimit.Limits {
+	if !c.RateLimit.Enabled {
+		return nil
+	}
+	limits := &ratelimit.Limits{
+		Rates:           make(map[ratelimit.Class]ratelimit.Rate),
+		DailyWriteQuota: c.RateLimit.DailyWriteQuota,
+		IP:              ratelimit.Rate{PerSecond: c.RateLimit.IP.PerSecond, Burst: c.RateLimit.IP.Burst},
+	}
+	for class, r := range map[ratelimit.Class]Rate{
+		ratelimit.ClassRead:  c.RateLimit.Read,
+		ratelimit.ClassWrite: c.RateLimit.Write,
+		ratelimit.ClassBatch: c.RateLimit.Batch,
+		ratelimit.ClassAdmin: c.RateLimit.Admin,
+	} {
+		if r.Burst > 0 {
+			limits.Rates[class] = ratelimit.Rate{PerSecond: r.PerSecond, Burst: r.Burst}
+		}
+	}
+	return limits
+}
+
+// similarityWeights is a method that returns the weights of the attributes used to find similar vehicles
+func (c *Config) similarityWeights() *index.SimilarityWeights {
+	return &index.SimilarityWeights{
+		MaxSpeed:     c.Similarity.MaxSpeed,
+		Capacity:     c.Similarity.Capacity,
+		Weight:       c.Similarity.Weight,
+		Height:       c.Similarity.Height,
+		Length:       c.Similarity.Length,
+		Width:        c.Similarity.Width,
+		FuelType:     c.Similarity.FuelType,
+		Transmission: c.Similarity.Transmission,
+	}
+}
+
+// ServerChi is a method that returns the configuration of the application server
+func (c *Config) ServerChi() *application.ConfigServerChi {
+	return &application.ConfigServerChi{
+		ServerAddress:        c.Server.Address,
+		ReadTimeout:          time.Duration(c.Server.ReadTimeout),
+		WriteTimeout:         time.Duration(c.Server.WriteTimeout),
+		IdleTimeout:          time.Duration(c.Server.IdleTimeout),
+		ShutdownTimeout:      time.Duration(c.Server.ShutdownTimeout),
+		RepositoryBackend:    c.Repository.Backend,
+		LoaderFilePath:       c.Loader.FilePath,
+		TenantSeedDir:        c.Loader.SeedDir,
+		MaxBodyBytes:         c.Limits.MaxBodyBytes,
+		MaxHeaderBytes:       c.Limits.MaxHeaderBytes,
+		CacheCapacity:        c.Cache.Capacity,
+		CacheTTL:             time.Duration(c.Cache.TTL),
+		SimilarityWeights:    c.similarityWeights(),
+		LogLevel:             c.Log.Level,
+		AuthAPIKeysFile:      c.Auth.APIKeysFile,
+		AuthPolicyFile:       c.Auth.PolicyFile,
+		AuthJWTSecret:        c.Auth.JWTSecret,
+		AuthJWTPublicKeyFile: c.Auth.JWTPublicKeyFile,
+		AuthJWKSFile:         c.Auth.JWKSFile,
+		AuthJWTIssuer:        c.Auth.JWTIssuer,
+		AuthJWTAudience:      c.Auth.JWTAudience,
+		AuthDisabled:         c.Auth.Disabled,
+		TraceExporter:        c.Tracing.Exporter,
+		TraceFilePath:        c.Tracing.FilePath,
+		TraceSampler:         c.Tracing.Sampler,
+		TraceSamplerRatio:    c.Tracing.SamplerRatio,
+		RateLimits:           c.rateLimits(),
+		IdempotencyTTL:       time.Duration(c.Idempotency.TTL),
+		CompressionLevel:     c.compressionLevel(),
+		V1Deprecation:        date(c.API.V1Deprecation),
+		V1Sunset:             date(c.API.V1Sunset),
+		GRPCAddress:          c.GRPC.Address,
+		GraphQLMaxDepth:      c.GraphQL.MaxDepth,
+		GraphQLMaxComplexity: c.GraphQL.MaxComplexity,
+	}
+}
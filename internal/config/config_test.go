@@ -0,0 +1,86 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(c *Config)
+		// messages are the invalid settings reported, none if the configuration is valid
+		messages []string
+	}{
+		{name: "case 1: the defaults without authentication", change: func(c *Config) { c.Auth.Disabled = true }},
+		{name: "case 2: the defaults with API keys", change: func(c *Config) { c.Auth.APIKeysFile = "keys.yaml" }},
+		{
+			name:     "case 3: no key and authentication enabled",
+			change:   func(c *Config) {},
+			messages: []string{"auth: must configure api_keys_file, jwt_secret, jwt_public_key_file or jwks_file, or set disabled"},
+		},
+		{
+			name:     "case 4: authentication disabled with a JWT secret",
+			change:   func(c *Config) { c.Auth.Disabled, c.Auth.JWTSecret = true, "secret" },
+			messages: []string{"auth.disabled: must be false with API keys or JWT keys configured"},
+		},
+		{
+			name: "case 5: every invalid setting is reported",
+			change: func(c *Config) {
+				c.Auth.Disabled = true
+				c.Server.Address = ""
+				c.Server.ReadTimeout = Duration(-time.Second)
+				c.Repository.Backend = "postgres"
+				c.RateLimit.IP.Burst = -1
+				c.Similarity.FuelType = -0.5
+				c.Compression.Level = 10
+				c.API.V1Deprecation = "19/10/2026"
+				c.Log.Level = "trace"
+			},
+			messages: []string{
+				"server.address: must not be empty",
+				"server.read_timeout: must not be negative, got -1s",
+				`repository.backend: must be memory, got "postgres"`,
+				"rate_limit.ip.burst: must not be negative, got -1",
+				"similarity.fuel_type: must not be negative, got -0.5",
+				"compression.level: must be between 0 and 9, got 10",
+				`api.v1_deprecation: must be a date as YYYY-MM-DD, got "19/10/2026"`,
+				`log.level: must be debug, info, warn or error, got "trace"`,
+			},
+		},
+		{
+			name:     "case 6: a sunset before the deprecation",
+			change:   func(c *Config) { c.Auth.Disabled, c.API.V1Sunset = true, "2026-01-01" },
+			messages: []string{"api.v1_sunset: must not be before api.v1_deprecation"},
+		},
+		{
+			name:     "case 7: the otlp_file exporter without a file",
+			change:   func(c *Config) { c.Auth.Disabled, c.Tracing.Exporter = true, "otlp_file" },
+			messages: []string{"tracing.file_path: must not be empty with the otlp_file exporter"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			cfg := Default()
+			c.change(cfg)
+
+			// act
+			err := cfg.Validate()
+
+			// assert
+			if len(c.messages) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, message := range c.messages {
+				require.Contains(t, err.Error(), message)
+			}
+			require.Len(t, strings.Split(err.Error(), "\n"), len(c.messages), err.Error())
+		})
+	}
+}
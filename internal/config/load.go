@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix is the prefix of the environment variables, e.g. VEHICLES_SERVER_ADDRESS sets server.address
+const EnvPrefix = "VEHICLES_"
+
+var (
+	// ErrFileFormat is the error returned when the format of a config file is not supported
+	ErrFileFormat = errors.New("unsupported config file format, use .yaml, .yml, .toml or .json")
+)
+
+// flagSet is a function that returns the flags setting the configuration, one per setting
+// - the name of a flag is the path of its setting, e.g. server.address
+func flagSet(name string, c *Config, configPath *string, printConfig *bool) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(configPath, "config", "", "path to a YAML, TOML or JSON config file (env "+EnvPrefix+"CONFIG)")
+	fs.BoolVar(printConfig, "print-config", false, "print the effective configuration as YAML and exit")
+
+	fs.StringVar(&c.Server.Address, "server.address", c.Server.Address, "address where the server will be listening")
+	fs.DurationVar((*time.Duration)(&c.Server.ReadTimeout), "server.read_timeout", time.Duration(c.Server.ReadTimeout), "maximum duration for reading a request")
+	fs.DurationVar((*time.Duration)(&c.Server.WriteTimeout), "server.write_timeout", time.Duration(c.Server.WriteTimeout), "maximum duration for writing a response")
+	fs.DurationVar((*time.Duration)(&c.Server.IdleTimeout), "server.idle_timeout", time.Duration(c.Server.IdleTimeout), "maximum duration of an idle keep-alive connection")
+	fs.DurationVar((*time.Duration)(&c.Server.ShutdownTimeout), "server.shutdown_timeout", time.Duration(c.Server.ShutdownTimeout), "maximum duration to drain the in-flight requests on shutdown")
+	fs.StringVar(&c.Repository.Backend, "repository.backend", c.Repository.Backend, "storage of the vehicles (memory)")
+	fs.StringVar(&c.Loader.FilePath, "loader.file_path", c.Loader.FilePath, "path to the JSON file that contains the vehicles")
+	fs.StringVar(&c.Loader.SeedDir, "loader.seed_dir", c.Loader.SeedDir, "directory of the JSON files tenants can be seeded from, empty disables seeding")
+	fs.Int64Var(&c.Limits.MaxBodyBytes, "limits.max_body_bytes", c.Limits.MaxBodyBytes, "maximum size of a request body, 0 for unlimited")
+	fs.IntVar(&c.Limits.MaxHeaderBytes, "limits.max_header_bytes", c.Limits.MaxHeaderBytes, "maximum size of the request headers")
+	fs.BoolVar(&c.RateLimit.Enabled, "rate_limit.enabled", c.RateLimit.Enabled, "rate limit the requests of every client")
+	for _, r := range []struct {
+		name string
+		rate *Rate
+	}{{"read", &c.RateLimit.Read}, {"write", &c.RateLimit.Write}, {"batch", &c.RateLimit.Batch}, {"admin", &c.RateLimit.Admin}} {
+		fs.Float64Var(&r.rate.PerSecond, "rate_limit."+r.name+".per_second", r.rate.PerSecond, "requests per second of a client to the "+r.name+" routes")
+		fs.IntVar(&r.rate.Burst, "rate_limit."+r.name+".burst", r.rate.Burst, "requests at once of a client to the "+r.name+" routes, 0 for unlimited")
+	}
+	fs.Float64Var(&c.RateLimit.IP.PerSecond, "rate_limit.ip.per_second", c.RateLimit.IP.PerSecond, "requests per second of an IP, before authentication")
+	fs.IntVar(&c.RateLimit.IP.Burst, "rate_limit.ip.burst", c.RateLimit.IP.Burst, "requests at once of an IP, before authentication, 0 for unlimited")
+	fs.IntVar(&c.RateLimit.DailyWriteQuota, "rate_limit.daily_write_quota", c.RateLimit.DailyWriteQuota, "write and batch requests of a client per UTC day, 0 for unlimited")
+	fs.DurationVar((*time.Duration)(&c.Idempotency.TTL), "idempotency.ttl", time.Duration(c.Idempotency.TTL), "how long the responses of the requests with an Idempotency-Key are replayed, 0 disables idempotency keys")
+	fs.IntVar(&c.Compression.Level, "compression.level", c.Compression.Level, "level of the compression of the responses, from 1 to 9, 0 disables compression")
+	fs.StringVar(&c.API.V1Deprecation, "api.v1_deprecation", c.API.V1Deprecation, "date (YYYY-MM-DD) the v1 routes were deprecated, empty to not announce it")
+	fs.StringVar(&c.API.V1Sunset, "api.v1_sunset", c.API.V1Sunset, "date (YYYY-MM-DD) the v1 routes stop being served, empty to not announce it")
+	fs.IntVar(&c.GraphQL.MaxDepth, "graphql.max_depth", c.GraphQL.MaxDepth, "maximum depth of the GraphQL queries")
+	fs.IntVar(&c.GraphQL.MaxComplexity, "graphql.max_complexity", c.GraphQL.MaxComplexity, "maximum complexity of the GraphQL queries, each field counted once per item of the lists above it")
+	fs.StringVar(&c.GRPC.Address, "grpc.address", c.GRPC.Address, "address of the gRPC server, empty disables it")
+	fs.IntVar(&c.Cache.Capacity, "cache.capacity", c.Cache.Capacity, "maximum number of cached results, 0 disables the cache")
+	fs.DurationVar((*time.Duration)(&c.Cache.TTL), "cache.ttl", time.Duration(c.Cache.TTL), "time to live of a cached result, 0 for no expiration")
+	for _, w := range []struct {
+		name   string
+		weight *float64
+	}{
+		{"max_speed", &c.Similarity.MaxSpeed}, {"capacity", &c.Similarity.Capacity}, {"weight", &c.Similarity.Weight},
+		{"height", &c.Similarity.Height}, {"length", &c.Similarity.Length}, {"width", &c.Similarity.Width},
+		{"fuel_type", &c.Similarity.FuelType}, {"transmission", &c.Similarity.Transmission},
+	} {
+		fs.Float64Var(w.weight, "similarity."+w.name, *w.weight, "weight of the "+strings.ReplaceAll(w.name, "_", " ")+" in the distance between similar vehicles")
+	}
+	fs.StringVar(&c.Log.Level, "log.level", c.Log.Level, "initial level of the logger (debug, info, warn or error)")
+	fs.BoolVar(&c.Auth.Disabled, "auth.disabled", c.Auth.Disabled, "serve every route without authentication, only if no key is configured")
+	fs.StringVar(&c.Auth.APIKeysFile, "auth.api_keys_file", c.Auth.APIKeysFile, "path to the YAML or JSON file of the hashed API keys")
+	fs.StringVar(&c.Auth.PolicyFile, "auth.policy_file", c.Auth.PolicyFile, "path to the YAML or JSON file of the roles and the permissions of each route")
+	fs.StringVar(&c.Auth.JWTSecret, "auth.jwt_secret", c.Auth.JWTSecret, "secret verifying HS256 JWTs")
+	fs.StringVar(&c.Auth.JWTPublicKeyFile, "auth.jwt_public_key_file", c.Auth.JWTPublicKeyFile, "path to the PEM file of the RSA public key verifying RS256 JWTs")
+	fs.StringVar(&c.Auth.JWKSFile, "auth.jwks_file", c.Auth.JWKSFile, "path to the JWKS file of the keys verifying JWTs")
+	fs.StringVar(&c.Auth.JWTIssuer, "auth.jwt_issuer", c.Auth.JWTIssuer, "issuer required from the JWTs")
+	fs.StringVar(&c.Auth.JWTAudience, "auth.jwt_audience", c.Auth.JWTAudience, "audience required from the JWTs")
+	fs.StringVar(&c.Tracing.Exporter, "tracing.exporter", c.Tracing.Exporter, "exporter of the spans (stdout or otlp_file), empty disables tracing")
+	fs.StringVar(&c.Tracing.FilePath, "tracing.file_path", c.Tracing.FilePath, "path to the file of the otlp_file exporter")
+	fs.StringVar(&c.Tracing.Sampler, "tracing.sampler", c.Tracing.Sampler, "sampler of the traces, as in OTEL_TRACES_SAMPLER")
+	fs.Float64Var(&c.Tracing.SamplerRatio, "tracing.sampler_ratio", c.Tracing.SamplerRatio, "ratio of the traces recorded by the traceidratio samplers")
+	return fs
+}
+
+// envName is a function that returns the environment variable of a flag
+func envName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, ".", "_"))
+}
+
+// Load is a function that returns the configuration of the application, layered by precedence:
+// defaults, then the config file, then the environment variables, then the command-line flags
+// - printConfig is true if the effective configuration must be printed instead of running the application
+// - the configuration is validated, every invalid setting is reported
+func Load(name string, args []string, lookupEnv func(key string) (string, bool)) (c *Config, printConfig bool, err error) {
+	// the flags are parsed first to find the config file, and again on top of the other layers
+	var configPath string
+	if err = flagSet(name, Default(), &configPath, &printConfig).Parse(args); err != nil {
+		return
+	}
+	if configPath == "" {
+		configPath, _ = lookupEnv(EnvPrefix + "CONFIG")
+	}
+
+	// - defaults
+	c = Default()
+	// - file
+	if configPath != "" {
+		if err = c.ReadFile(configPath); err != nil {
+			return nil, false, err
+		}
+	}
+	fs := flagSet(name, c, new(string), new(bool))
+	fs.SetOutput(io.Discard)
+	// - environment
+	fs.VisitAll(func(f *flag.Flag) {
+		if f.Name == "config" || f.Name == "print-config" || err != nil {
+			return
+		}
+		key := envName(f.Name)
+		if value, ok := lookupEnv(key); ok {
+			if setErr := fs.Set(f.Name, value); setErr != nil {
+				err = fmt.Errorf("env %s: invalid value %q: %w", key, value, setErr)
+			}
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	// - flags
+	if err = fs.Parse(args); err != nil {
+		return nil, false, err
+	}
+
+	if err = c.Validate(); err != nil {
+		return nil, false, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return
+}
+
+// ReadFile is a method that overrides the configuration with the settings of a file, by its extension
+// - settings missing from the file are kept, unknown settings are an error
+func (c *Config) ReadFile(path string) (err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(c); errors.Is(err, io.EOF) {
+			// empty file
+			err = nil
+		}
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), c)
+		if err == nil && len(md.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown setting %q", md.Undecoded()[0].String())
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	default:
+		err = ErrFileFormat
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return
+}
+
+// Write is a method that writes the configuration as YAML, with the secrets redacted
+func (c *Config) Write(w io.Writer) error {
+	redacted := *c
+	if redacted.Auth.JWTSecret != "" {
+		redacted.Auth.JWTSecret = "REDACTED"
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&redacted); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile is a function that writes a config file with the name and the content in a temporary directory
+func writeConfigFile(t *testing.T, name, content string) (path string) {
+	t.Helper()
+
+	path = filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return
+}
+
+// lookupEnv is a function that returns a lookup of the environment variables of a map
+func lookupEnv(env map[string]string) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	file := writeConfigFile(t, "config.yaml", "server:\n  address: \":1000\"\ncache:\n  capacity: 10\ncompression:\n  level: 3\nauth:\n  disabled: true\n")
+
+	cases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		// expected changes the defaults into the loaded configuration
+		expected func(c *Config)
+	}{
+		{
+			name:     "case 1: the defaults",
+			args:     []string{"--auth.disabled"},
+			expected: func(c *Config) { c.Auth.Disabled = true },
+		},
+		{
+			name: "case 2: the file over the defaults",
+			args: []string{"--config", file},
+			expected: func(c *Config) {
+				c.Server.Address, c.Cache.Capacity, c.Compression.Level, c.Auth.Disabled = ":1000", 10, 3, true
+			},
+		},
+		{
+			name: "case 3: the file named by the environment",
+			env:  map[string]string{"VEHICLES_CONFIG": file},
+			expected: func(c *Config) {
+				c.Server.Address, c.Cache.Capacity, c.Compression.Level, c.Auth.Disabled = ":1000", 10, 3, true
+			},
+		},
+		{
+			name: "case 4: the environment over the file",
+			args: []string{"--config", file},
+			env:  map[string]string{"VEHICLES_CACHE_CAPACITY": "20", "VEHICLES_COMPRESSION_LEVEL": "4", "VEHICLES_RATE_LIMIT_IP_BURST": "50"},
+			expected: func(c *Config) {
+				c.Server.Address, c.Cache.Capacity, c.Compression.Level, c.Auth.Disabled = ":1000", 20, 4, true
+				c.RateLimit.IP.Burst = 50
+			},
+		},
+		{
+			name: "case 5: the flags over the environment",
+			args: []string{"--config", file, "--compression.level", "6", "--cache.ttl", "5s"},
+			env:  map[string]string{"VEHICLES_CACHE_CAPACITY": "20", "VEHICLES_COMPRESSION_LEVEL": "4", "VEHICLES_CACHE_TTL": "1s"},
+			expected: func(c *Config) {
+				c.Server.Address, c.Cache.Capacity, c.Compression.Level, c.Auth.Disabled = ":1000", 20, 6, true
+				c.Cache.TTL = Duration(5 * time.Second)
+			},
+		},
+		{
+			name: "case 6: the flag of the file over the environment",
+			args: []string{"--config", file},
+			env:  map[string]string{"VEHICLES_CONFIG": "missing.yaml"},
+			expected: func(c *Config) {
+				c.Server.Address, c.Cache.Capacity, c.Compression.Level, c.Auth.Disabled = ":1000", 10, 3, true
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			expected := Default()
+			c.expected(expected)
+
+			// act
+			cfg, printConfig, err := Load("vehicles", c.args, lookupEnv(c.env))
+
+			// assert
+			require.NoError(t, err)
+			require.False(t, printConfig)
+			require.Equal(t, expected, cfg)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		env  map[string]string
+		// messages are parts of the message of the error
+		messages []string
+	}{
+		{
+			name:     "case 1: an invalid configuration",
+			args:     []string{"--compression.level", "10", "--similarity.weight", "-1"},
+			messages: []string{"invalid configuration:\n", "compression.level: must be between 0 and 9, got 10", "similarity.weight: must not be negative, got -1", "auth: must configure"},
+		},
+		{
+			name:     "case 2: authentication disabled with API keys",
+			args:     []string{"--auth.disabled", "--auth.api_keys_file", "keys.yaml"},
+			messages: []string{"invalid configuration:\n", "auth.disabled: must be false with API keys or JWT keys configured"},
+		},
+		{
+			name:     "case 3: an invalid environment variable",
+			env:      map[string]string{"VEHICLES_CACHE_CAPACITY": "many"},
+			messages: []string{`env VEHICLES_CACHE_CAPACITY: invalid value "many"`},
+		},
+		{
+			name:     "case 4: an unknown flag",
+			args:     []string{"--cache.size", "10"},
+			messages: []string{"flag provided but not defined: -cache.size"},
+		},
+		{
+			name:     "case 5: an unknown setting in the file",
+			args:     []string{"--config", writeConfigFile(t, "config.yaml", "cache:\n  size: 10\n")},
+			messages: []string{"config.yaml", "field size not found"},
+		},
+		{
+			name:     "case 6: an unsupported file format",
+			args:     []string{"--config", writeConfigFile(t, "config.ini", "[cache]\n")},
+			messages: []string{"config.ini", ErrFileFormat.Error()},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			cfg, _, err := Load("vehicles", c.args, lookupEnv(c.env))
+
+			// assert
+			require.Error(t, err)
+			require.Nil(t, cfg)
+			for _, message := range c.messages {
+				require.Contains(t, err.Error(), message)
+			}
+		})
+	}
+}
+
+func TestConfig_ReadFile(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		err     error
+	}{
+		{name: "case 1: YAML", file: "config.yaml", content: "cache:\n  capacity: 10\n  ttl: 5s\n"},
+		{name: "case 2: YML", file: "config.yml", content: "cache:\n  capacity: 10\n  ttl: 5s\n"},
+		{name: "case 3: TOML", file: "config.toml", content: "[cache]\ncapacity = 10\nttl = \"5s\"\n"},
+		{name: "case 4: JSON", file: "config.json", content: `{"cache":{"capacity":10,"ttl":"5s"}}`},
+		{name: "case 5: unsupported format", file: "config.ini", content: "[cache]\n", err: ErrFileFormat},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			cfg := Default()
+
+			// act
+			err := cfg.ReadFile(writeConfigFile(t, c.file, c.content))
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			if c.err != nil {
+				return
+			}
+			expected := Default()
+			expected.Cache.Capacity, expected.Cache.TTL = 10, Duration(5*time.Second)
+			require.Equal(t, expected, cfg)
+		})
+	}
+}
+
+func TestLoad_PrintConfig(t *testing.T) {
+	// arrange
+	args := []string{"--print-config", "--auth.jwt_secret", "s3cr3t", "--cache.capacity", "10"}
+
+	// act
+	cfg, printConfig, err := Load("vehicles", args, lookupEnv(nil))
+	require.NoError(t, err)
+	var b bytes.Buffer
+	require.NoError(t, cfg.Write(&b))
+
+	// assert
+	require.True(t, printConfig)
+	require.NotContains(t, b.String(), "s3cr3t")
+	require.Contains(t, b.String(), "jwt_secret: REDACTED")
+	require.Equal(t, "s3cr3t", cfg.Auth.JWTSecret)
+	// - the printed configuration is read back as the effective one, but for the secret
+	read := Default()
+	require.NoError(t, read.ReadFile(writeConfigFile(t, "config.yaml", b.String())))
+	read.Auth.JWTSecret = cfg.Auth.JWTSecret
+	require.Equal(t, cfg, read)
+}
@@ -0,0 +1,107 @@
+package graphql
+
+import (
+	"errors"
+	"math"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// multipliers are the arguments bounding the number of items of the list fields, with their default value
+// - the fields selected under a list field are counted once per item
+var multipliers = map[string]struct {
+	argument string
+	fallback int
+}{
+	"vehicles": {argument: "first", fallback: 20},
+	"search":   {argument: "first", fallback: 20},
+	"similar":  {argument: "k", fallback: 5},
+}
+
+// Complexity is a function that returns the type and the complexity of the operation of a query
+// - each field selected costs one, multiplied by the items of the list fields above it
+// - the query is not validated against the schema, the errors of invalid queries are left to the execution
+func Complexity(query, operationName string, variables map[string]interface{}) (operation ast.Operation, complexity int, err error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		err = errors.New("unknown operation")
+		return
+	}
+
+	c := &complexityWalker{doc: doc, op: op, variables: variables, visiting: make(map[string]bool)}
+	return op.Operation, c.selections(op.SelectionSet), nil
+}
+
+// complexityWalker is a struct that computes the complexity of the selections of an operation
+type complexityWalker struct {
+	doc       *ast.QueryDocument
+	op        *ast.OperationDefinition
+	variables map[string]interface{}
+	// visiting are the fragments being walked, to stop at cycles
+	visiting map[string]bool
+}
+
+// selections is a method that returns the complexity of a selection set
+func (c *complexityWalker) selections(set ast.SelectionSet) (complexity int) {
+	for _, s := range set {
+		switch s := s.(type) {
+		case *ast.Field:
+			complexity = saturate(complexity + 1 + saturate(c.multiplier(s)*c.selections(s.SelectionSet)))
+		case *ast.InlineFragment:
+			complexity = saturate(complexity + c.selections(s.SelectionSet))
+		case *ast.FragmentSpread:
+			f := c.doc.Fragments.ForName(s.Name)
+			if f == nil || c.visiting[s.Name] {
+				continue
+			}
+			c.visiting[s.Name] = true
+			complexity = saturate(complexity + c.selections(f.SelectionSet))
+			c.visiting[s.Name] = false
+		}
+	}
+	return
+}
+
+// saturate is a function that bounds a complexity, so deep lists of many items do not overflow
+func saturate(complexity int) int {
+	return min(complexity, math.MaxInt32)
+}
+
+// multiplier is a method that returns the number of items of a field, one if it is not a list field
+// - the number is bounded by the maximum the resolvers accept, larger values are rejected by them
+func (c *complexityWalker) multiplier(f *ast.Field) int {
+	m, ok := multipliers[f.Name]
+	if !ok {
+		return 1
+	}
+	arg := f.Arguments.ForName(m.argument)
+	if arg == nil {
+		return m.fallback
+	}
+
+	value := arg.Value
+	if value.Kind == ast.Variable {
+		if _, ok := c.variables[value.Raw]; !ok {
+			if def := c.op.VariableDefinitions.ForName(value.Raw); def != nil && def.DefaultValue != nil {
+				value = def.DefaultValue
+			}
+		}
+	}
+	v, err := value.Value(c.variables)
+	if err != nil {
+		return m.fallback
+	}
+	// - values of other types are rejected by the execution, they are counted as the default
+	switch v := v.(type) {
+	case int64:
+		return int(min(max(v, 1), maxFirst))
+	case float64:
+		return int(min(max(v, 1), maxFirst))
+	}
+	return m.fallback
+}
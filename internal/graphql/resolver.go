@@ -0,0 +1,480 @@
+package graphql
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxFirst is the maximum number of vehicles of a page or a search
+	maxFirst = 100
+	// maxSimilar is the maximum number of similar vehicles
+	maxSimilar = 100
+)
+
+// Error is a struct that represents an error of a resolver, with a code in its extensions
+type Error struct {
+	// Code is the kind of error: BAD_USER_INPUT, FORBIDDEN, NOT_FOUND, CONFLICT or INTERNAL
+	Code string
+	// Message is the description of the error
+	Message string
+}
+
+// Error is a method that returns the description of the error
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Extensions is a method that returns the extensions of the error in the response
+func (e *Error) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// inputError is a function that returns an error of the arguments of a field
+func inputError(message string) *Error {
+	return &Error{Code: "BAD_USER_INPUT", Message: message}
+}
+
+// serviceError is a function that returns the error of the service with its code
+func serviceError(err error) *Error {
+	switch {
+	case errors.Is(err, internal.ErrVehicleNotFound):
+		return &Error{Code: "NOT_FOUND", Message: "vehicle not found"}
+	case errors.Is(err, internal.ErrVehicleExists):
+		return &Error{Code: "CONFLICT", Message: "vehicle already exists"}
+	case errors.Is(err, internal.ErrVehicleInvalid), errors.Is(err, internal.ErrRangeInvalid), errors.Is(err, internal.ErrSortFieldInvalid):
+		return inputError(err.Error())
+	}
+	return &Error{Code: "INTERNAL", Message: "internal error"}
+}
+
+// NewResolver is a function that returns a new instance of Resolver
+// - policy grants the permissions of the fields, nil if authorization is disabled
+func NewResolver(sv internal.VehicleService, policy *auth.Policy) *Resolver {
+	return &Resolver{sv: sv, policy: policy}
+}
+
+// Resolver is a struct that resolves the queries, mutations and subscriptions of the schema through the vehicle service
+// - the route authorizes any authenticated caller, each field requires the permission of the equivalent route
+type Resolver struct {
+	// sv is the service that will be used by the resolver
+	sv internal.VehicleService
+	// policy grants the permissions of the fields
+	policy *auth.Policy
+}
+
+// allow is a method that returns an error if the caller lacks the permission
+// - requests without a principal, i.e. authentication is disabled, are allowed
+func (r *Resolver) allow(ctx context.Context, perm auth.Permission) error {
+	p, ok := auth.PrincipalFromContext(ctx)
+	if !ok || r.policy == nil || r.policy.Allowed(p, perm) {
+		return nil
+	}
+	return &Error{Code: "FORBIDDEN", Message: "forbidden, requires " + string(perm)}
+}
+
+// vehicle is a method that returns the resolver of a vehicle
+func (r *Resolver) vehicle(v internal.Vehicle) *vehicleResolver {
+	return &vehicleResolver{v: v, r: r}
+}
+
+// Vehicle is a method that returns the vehicle with the id, nil if there is none
+func (r *Resolver) Vehicle(ctx context.Context, args struct{ ID int32 }) (*vehicleResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesRead); err != nil {
+		return nil, err
+	}
+
+	v, err := r.sv.FindOne(ctx, int(args.ID))
+	if err != nil {
+		if errors.Is(err, internal.ErrVehicleNotFound) {
+			return nil, nil
+		}
+		return nil, serviceError(err)
+	}
+	return r.vehicle(v), nil
+}
+
+// intRange is a struct that represents an IntRange input
+type intRange struct {
+	Min *int32
+	Max *int32
+}
+
+// floatRange is a struct that represents a FloatRange input
+type floatRange struct {
+	Min *float64
+	Max *float64
+}
+
+// vehicleFilter is a struct that represents a VehicleFilter input
+type vehicleFilter struct {
+	Brand        *string
+	Color        *string
+	FuelType     *string
+	Transmission *string
+	Year         *intRange
+	MaxSpeed     *floatRange
+	Weight       *floatRange
+	Height       *floatRange
+	Length       *floatRange
+	Width        *floatRange
+	Units        string
+}
+
+// query is a method that returns the query of the filter, in metric units
+func (f *vehicleFilter) query() (q internal.VehicleQuery) {
+	if f == nil {
+		return
+	}
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	q.Brand, q.Color, q.FuelType, q.Transmission = value(f.Brand), value(f.Color), value(f.FuelType), value(f.Transmission)
+
+	if f.Year != nil {
+		if f.Year.Min != nil {
+			min := float64(*f.Year.Min)
+			q.FabricationYear.Min = &min
+		}
+		if f.Year.Max != nil {
+			max := float64(*f.Year.Max)
+			q.FabricationYear.Max = &max
+		}
+	}
+	u := units(f.Units)
+	ranges := []struct {
+		source  *floatRange
+		target  *internal.Range
+		convert func(float64) float64
+	}{
+		{f.MaxSpeed, &q.MaxSpeed, u.SpeedToMetric},
+		{f.Weight, &q.Weight, u.WeightToMetric},
+		{f.Height, &q.Height, u.LengthToMetric},
+		{f.Length, &q.Length, u.LengthToMetric},
+		{f.Width, &q.Width, u.LengthToMetric},
+	}
+	for _, rg := range ranges {
+		if rg.source != nil {
+			*rg.target = internal.Range{Min: rg.source.Min, Max: rg.source.Max}.Map(rg.convert)
+		}
+	}
+	return
+}
+
+// vehicleSort is a struct that represents a VehicleSort input
+type vehicleSort struct {
+	Field     string
+	Direction string
+}
+
+// cursor is a function that returns the opaque cursor of the position of a vehicle in the sorted vehicles
+func cursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// parseCursor is a function that returns the position of a cursor
+func parseCursor(c string) (offset int, err error) {
+	data, err := base64.StdEncoding.DecodeString(c)
+	if err != nil {
+		return
+	}
+	value, ok := strings.CutPrefix(string(data), "offset:")
+	if !ok {
+		return 0, errors.New("invalid cursor")
+	}
+	offset, err = strconv.Atoi(value)
+	if err == nil && offset < 0 {
+		err = errors.New("invalid cursor")
+	}
+	return
+}
+
+// Vehicles is a method that returns a page of the vehicles matching the filter
+// - the vehicles are sorted by the field, by id by default, the page starts after the cursor
+func (r *Resolver) Vehicles(ctx context.Context, args struct {
+	Filter *vehicleFilter
+	Sort   *vehicleSort
+	First  int32
+	After  *string
+}) (*connectionResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesRead); err != nil {
+		return nil, err
+	}
+
+	// arguments
+	if args.First < 0 || args.First > maxFirst {
+		return nil, inputError("first must be between 0 and 100")
+	}
+	offset := 0
+	if args.After != nil {
+		after, err := parseCursor(*args.After)
+		if err != nil {
+			return nil, inputError("invalid cursor")
+		}
+		offset = after + 1
+	}
+	field, descending := "id", false
+	if args.Sort != nil {
+		field, descending = strings.ToLower(args.Sort.Field), args.Sort.Direction == "DESC"
+	}
+
+	// process
+	v, err := r.sv.Query(ctx, args.Filter.query())
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	sorted, err := internal.SortVehicles(v, field, descending)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	// page
+	c := &connectionResolver{total: len(sorted), offset: min(offset, len(sorted))}
+	for i := c.offset; i < len(sorted) && i < c.offset+int(args.First); i++ {
+		c.edges = append(c.edges, &edgeResolver{cursor: cursor(i), node: r.vehicle(sorted[i])})
+	}
+	return c, nil
+}
+
+// Search is a method that returns the vehicles matching the text, sorted by relevance
+func (r *Resolver) Search(ctx context.Context, args struct {
+	Text  string
+	First int32
+}) ([]*searchResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesRead); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(args.Text) == "" {
+		return nil, inputError("missing search text")
+	}
+	if args.First < 1 || args.First > maxFirst {
+		return nil, inputError("first must be between 1 and 100")
+	}
+
+	results, err := r.sv.SearchVehicles(ctx, args.Text, int(args.First))
+	if err != nil && !errors.Is(err, internal.ErrVehicleNotFound) {
+		return nil, serviceError(err)
+	}
+
+	resolvers := make([]*searchResolver, 0, len(results))
+	for _, value := range results {
+		resolvers = append(resolvers, &searchResolver{vehicle: r.vehicle(value.Vehicle), score: value.Score})
+	}
+	return resolvers, nil
+}
+
+// Aggregates is a method that returns the statistics of the metric for every key of the group, or for the keys only
+func (r *Resolver) Aggregates(ctx context.Context, args struct {
+	GroupBy string
+	Metric  string
+	Keys    *[]string
+	Units   string
+}) ([]*aggregateResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesRead); err != nil {
+		return nil, err
+	}
+
+	metric := internal.AggregateMetric(strings.ToLower(args.Metric))
+	aggregates, err := r.sv.GetAggregates(ctx, internal.AggregateGroup(strings.ToLower(args.GroupBy)), metric)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	// - the statistics are linear in the values, converted as they are
+	convert := func(value float64) float64 { return value }
+	switch metric {
+	case internal.MetricMaxSpeed:
+		convert = units(args.Units).SpeedFromMetric
+	case internal.MetricWeight:
+		convert = units(args.Units).WeightFromMetric
+	}
+
+	var keys []string
+	if args.Keys != nil {
+		keys = *args.Keys
+	} else {
+		for key, a := range aggregates {
+			if a.Count > 0 {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+	}
+	resolvers := make([]*aggregateResolver, 0, len(keys))
+	for _, key := range keys {
+		resolvers = append(resolvers, &aggregateResolver{key: key, a: aggregates[key], convert: convert})
+	}
+	return resolvers, nil
+}
+
+// vehicleInput is a struct that represents a VehicleInput input
+type vehicleInput struct {
+	ID           int32
+	Brand        string
+	Model        string
+	Registration *string
+	Color        *string
+	Year         *int32
+	Passengers   *int32
+	MaxSpeed     *float64
+	FuelType     *string
+	Transmission *string
+	Weight       *float64
+	Height       *float64
+	Length       *float64
+	Width        *float64
+	Units        string
+}
+
+// patch is a method that returns the attributes of the input as a patch, in metric units
+func (in vehiclePatchInput) patch() (p internal.VehiclePatch) {
+	integer := func(value *int32) *int {
+		if value == nil {
+			return nil
+		}
+		i := int(*value)
+		return &i
+	}
+	u := units(in.Units)
+	convert := func(value *float64, fn func(float64) float64) *float64 {
+		if value == nil {
+			return nil
+		}
+		converted := fn(*value)
+		return &converted
+	}
+	return internal.VehiclePatch{
+		Brand:           in.Brand,
+		Model:           in.Model,
+		Registration:    in.Registration,
+		Color:           in.Color,
+		FabricationYear: integer(in.Year),
+		Capacity:        integer(in.Passengers),
+		MaxSpeed:        convert(in.MaxSpeed, u.SpeedToMetric),
+		FuelType:        in.FuelType,
+		Transmission:    in.Transmission,
+		Weight:          convert(in.Weight, u.WeightToMetric),
+		Height:          convert(in.Height, u.LengthToMetric),
+		Length:          convert(in.Length, u.LengthToMetric),
+		Width:           convert(in.Width, u.LengthToMetric),
+	}
+}
+
+// vehiclePatchInput is a struct that represents a VehiclePatchInput input
+type vehiclePatchInput struct {
+	Brand        *string
+	Model        *string
+	Registration *string
+	Color        *string
+	Year         *int32
+	Passengers   *int32
+	MaxSpeed     *float64
+	FuelType     *string
+	Transmission *string
+	Weight       *float64
+	Height       *float64
+	Length       *float64
+	Width        *float64
+	Units        string
+}
+
+// CreateVehicle is a method that creates a vehicle and returns it
+func (r *Resolver) CreateVehicle(ctx context.Context, args struct{ Input vehicleInput }) (*vehicleResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesWrite); err != nil {
+		return nil, err
+	}
+
+	// - the attributes of a new vehicle are the ones of an empty vehicle patched with the input
+	in := args.Input
+	v := vehiclePatchInput{
+		Brand: &in.Brand, Model: &in.Model, Registration: in.Registration, Color: in.Color,
+		Year: in.Year, Passengers: in.Passengers, MaxSpeed: in.MaxSpeed, FuelType: in.FuelType, Transmission: in.Transmission,
+		Weight: in.Weight, Height: in.Height, Length: in.Length, Width: in.Width, Units: in.Units,
+	}.patch().Apply(internal.Vehicle{Id: int(in.ID)})
+	if err := v.Validate(); err != nil {
+		return nil, serviceError(err)
+	}
+
+	if err := r.sv.Create(ctx, v); err != nil {
+		return nil, serviceError(err)
+	}
+	return r.vehicle(v), nil
+}
+
+// UpdateVehicle is a method that changes the attributes of the input of a vehicle and returns it
+func (r *Resolver) UpdateVehicle(ctx context.Context, args struct {
+	ID    int32
+	Input vehiclePatchInput
+}) (*vehicleResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesWrite); err != nil {
+		return nil, err
+	}
+
+	p := args.Input.patch()
+	for _, value := range []*float64{p.MaxSpeed, p.Weight, p.Height, p.Length, p.Width} {
+		if value != nil && *value < 0 {
+			return nil, inputError("maxSpeed, weight, height, length and width must not be negative")
+		}
+	}
+
+	v, err := r.sv.Patch(ctx, int(args.ID), p)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return r.vehicle(v), nil
+}
+
+// DeleteVehicle is a method that deletes a vehicle and returns its id
+func (r *Resolver) DeleteVehicle(ctx context.Context, args struct{ ID int32 }) (int32, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesDelete); err != nil {
+		return 0, err
+	}
+
+	if err := r.sv.DeleteVehicle(ctx, int(args.ID)); err != nil {
+		return 0, serviceError(err)
+	}
+	return args.ID, nil
+}
+
+// VehicleEvents is a method that returns the changes made to the vehicles from now on, of the types only if given
+// - the channel is closed when the subscription ends, or when the subscriber falls behind
+func (r *Resolver) VehicleEvents(ctx context.Context, args struct{ Types *[]string }) (<-chan *eventResolver, error) {
+	if err := r.allow(ctx, auth.PermissionVehiclesRead); err != nil {
+		return nil, err
+	}
+
+	events, err := r.sv.WatchVehicles(ctx)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	types := make(map[internal.VehicleEventType]bool)
+	if args.Types != nil {
+		for _, t := range *args.Types {
+			types[internal.VehicleEventType(strings.ToLower(t))] = true
+		}
+	}
+
+	resolvers := make(chan *eventResolver)
+	go func() {
+		defer close(resolvers)
+		for e := range events {
+			if len(types) > 0 && !types[e.Type] {
+				continue
+			}
+			select {
+			case resolvers <- &eventResolver{e: e, r: r}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return resolvers, nil
+}
@@ -0,0 +1,222 @@
+package graphql
+
+import gql "github.com/graph-gophers/graphql-go"
+
+// NewSchema is a function that returns the schema resolved by the resolver
+// - queries nested deeper than maxDepth are rejected on validation, zero means unlimited
+func NewSchema(r *Resolver, maxDepth int) (*gql.Schema, error) {
+	return gql.ParseSchema(Schema, r, gql.UseStringDescriptions(), gql.MaxDepth(maxDepth))
+}
+
+// Schema is the GraphQL schema of the vehicles
+// - measures are in metric units (km/h, kg, cm) unless other units are requested
+const Schema = `
+schema {
+	query: Query
+	mutation: Mutation
+	subscription: Subscription
+}
+
+type Query {
+	"The vehicle with the id, null if there is none."
+	vehicle(id: Int!): Vehicle
+	"The vehicles matching the filter, paginated forward with first and after."
+	vehicles(filter: VehicleFilter, sort: VehicleSort, first: Int = 20, after: String): VehicleConnection!
+	"The vehicles matching the text, sorted by relevance."
+	search(text: String!, first: Int = 20): [VehicleSearchResult!]!
+	"The statistics of the metric for every key of the group, or for the keys only."
+	aggregates(groupBy: AggregateGroup = BRAND, metric: AggregateMetric = MAX_SPEED, keys: [String!], units: UnitSystem = METRIC): [Aggregate!]!
+}
+
+type Mutation {
+	createVehicle(input: VehicleInput!): Vehicle!
+	"Changes the attributes present in the input, the others are left as they are."
+	updateVehicle(id: Int!, input: VehiclePatchInput!): Vehicle!
+	"Deletes the vehicle and returns its id."
+	deleteVehicle(id: Int!): Int!
+}
+
+type Subscription {
+	"The changes made to the vehicles from now on, of the types only if given."
+	vehicleEvents(types: [VehicleEventType!]): VehicleEvent!
+}
+
+enum UnitSystem {
+	METRIC
+	IMPERIAL
+}
+
+type Vehicle {
+	id: Int!
+	brand: String!
+	model: String!
+	"Null if the caller may not read it."
+	registration: String
+	color: String!
+	year: Int!
+	passengers: Int!
+	maxSpeed(units: UnitSystem = METRIC): Float!
+	fuelType: String!
+	transmission: String!
+	weight(units: UnitSystem = METRIC): Float!
+	height(units: UnitSystem = METRIC): Float!
+	length(units: UnitSystem = METRIC): Float!
+	width(units: UnitSystem = METRIC): Float!
+	footprint(units: UnitSystem = METRIC): Float!
+	volume(units: UnitSystem = METRIC): Float!
+	"Years since the fabrication year."
+	age: Int!
+	"The k vehicles most similar to this one."
+	similar(k: Int = 5): [SimilarVehicle!]!
+}
+
+type SimilarVehicle {
+	vehicle: Vehicle!
+	distance: Float!
+}
+
+type VehicleSearchResult {
+	vehicle: Vehicle!
+	score: Float!
+}
+
+input IntRange {
+	min: Int
+	max: Int
+}
+
+input FloatRange {
+	min: Float
+	max: Float
+}
+
+input VehicleFilter {
+	brand: String
+	color: String
+	fuelType: String
+	transmission: String
+	year: IntRange
+	maxSpeed: FloatRange
+	weight: FloatRange
+	height: FloatRange
+	length: FloatRange
+	width: FloatRange
+	"The units of the ranges."
+	units: UnitSystem = METRIC
+}
+
+enum VehicleSortField {
+	ID
+	BRAND
+	MODEL
+	YEAR
+	PASSENGERS
+	MAX_SPEED
+	WEIGHT
+	HEIGHT
+	LENGTH
+	WIDTH
+}
+
+enum SortDirection {
+	ASC
+	DESC
+}
+
+input VehicleSort {
+	field: VehicleSortField!
+	direction: SortDirection = ASC
+}
+
+type VehicleConnection {
+	edges: [VehicleEdge!]!
+	nodes: [Vehicle!]!
+	pageInfo: PageInfo!
+	"The number of vehicles matching the filter, across every page."
+	totalCount: Int!
+}
+
+type VehicleEdge {
+	cursor: String!
+	node: Vehicle!
+}
+
+type PageInfo {
+	hasNextPage: Boolean!
+	hasPreviousPage: Boolean!
+	startCursor: String
+	endCursor: String
+}
+
+enum AggregateGroup {
+	BRAND
+	FUEL_TYPE
+	YEAR
+}
+
+enum AggregateMetric {
+	MAX_SPEED
+	CAPACITY
+	WEIGHT
+}
+
+type Aggregate {
+	key: String!
+	count: Int!
+	mean: Float!
+	min: Float!
+	max: Float!
+	stddev: Float!
+}
+
+input VehicleInput {
+	id: Int!
+	brand: String!
+	model: String!
+	registration: String
+	color: String
+	year: Int
+	passengers: Int
+	maxSpeed: Float
+	fuelType: String
+	transmission: String
+	weight: Float
+	height: Float
+	length: Float
+	width: Float
+	"The units of the measures."
+	units: UnitSystem = METRIC
+}
+
+input VehiclePatchInput {
+	brand: String
+	model: String
+	registration: String
+	color: String
+	year: Int
+	passengers: Int
+	maxSpeed: Float
+	fuelType: String
+	transmission: String
+	weight: Float
+	height: Float
+	length: Float
+	width: Float
+	"The units of the measures."
+	units: UnitSystem = METRIC
+}
+
+enum VehicleEventType {
+	CREATED
+	UPDATED
+	DELETED
+}
+
+type VehicleEvent {
+	type: VehicleEventType!
+	"The vehicle after the change, null if deleted."
+	vehicle: Vehicle
+	"The vehicle before the change, null if created."
+	previous: Vehicle
+}
+`
@@ -0,0 +1,309 @@
+package graphql
+
+import (
+	"app/internal"
+	"context"
+	"strings"
+	"time"
+)
+
+// units is a function that returns the unit system of an UnitSystem enum value
+func units(name string) internal.UnitSystem {
+	return internal.UnitSystem(strings.ToLower(name))
+}
+
+// unitsArgs are the arguments of the measures of a vehicle
+type unitsArgs struct {
+	Units string
+}
+
+// vehicleResolver is a struct that resolves the fields of a vehicle
+type vehicleResolver struct {
+	// v is the vehicle, in metric units
+	v internal.Vehicle
+	// r is the root resolver, for the fields resolved through the service
+	r *Resolver
+}
+
+// ID is a method that returns the id of the vehicle
+func (v *vehicleResolver) ID() int32 {
+	return int32(v.v.Id)
+}
+
+// Brand is a method that returns the brand of the vehicle
+func (v *vehicleResolver) Brand() string {
+	return v.v.Brand
+}
+
+// Model is a method that returns the model of the vehicle
+func (v *vehicleResolver) Model() string {
+	return v.v.Model
+}
+
+// Registration is a method that returns the registration of the vehicle, nil if hidden from the caller
+func (v *vehicleResolver) Registration() *string {
+	if v.v.Registration == "" {
+		return nil
+	}
+	return &v.v.Registration
+}
+
+// Color is a method that returns the color of the vehicle
+func (v *vehicleResolver) Color() string {
+	return v.v.Color
+}
+
+// Year is a method that returns the fabrication year of the vehicle
+func (v *vehicleResolver) Year() int32 {
+	return int32(v.v.FabricationYear)
+}
+
+// Passengers is a method that returns the capacity of people of the vehicle
+func (v *vehicleResolver) Passengers() int32 {
+	return int32(v.v.Capacity)
+}
+
+// MaxSpeed is a method that returns the maximum speed of the vehicle in the requested units
+func (v *vehicleResolver) MaxSpeed(args unitsArgs) float64 {
+	return units(args.Units).SpeedFromMetric(v.v.MaxSpeed)
+}
+
+// FuelType is a method that returns the fuel type of the vehicle
+func (v *vehicleResolver) FuelType() string {
+	return v.v.FuelType
+}
+
+// Transmission is a method that returns the transmission of the vehicle
+func (v *vehicleResolver) Transmission() string {
+	return v.v.Transmission
+}
+
+// Weight is a method that returns the weight of the vehicle in the requested units
+func (v *vehicleResolver) Weight(args unitsArgs) float64 {
+	return units(args.Units).WeightFromMetric(v.v.Weight)
+}
+
+// Height is a method that returns the height of the vehicle in the requested units
+func (v *vehicleResolver) Height(args unitsArgs) float64 {
+	return units(args.Units).LengthFromMetric(v.v.Height)
+}
+
+// Length is a method that returns the length of the vehicle in the requested units
+func (v *vehicleResolver) Length(args unitsArgs) float64 {
+	return units(args.Units).LengthFromMetric(v.v.Length)
+}
+
+// Width is a method that returns the width of the vehicle in the requested units
+func (v *vehicleResolver) Width(args unitsArgs) float64 {
+	return units(args.Units).LengthFromMetric(v.v.Width)
+}
+
+// Footprint is a method that returns the footprint of the vehicle in the requested units
+func (v *vehicleResolver) Footprint(args unitsArgs) float64 {
+	return units(args.Units).AreaFromMetric(v.v.Footprint())
+}
+
+// Volume is a method that returns the volume of the vehicle in the requested units
+func (v *vehicleResolver) Volume(args unitsArgs) float64 {
+	return units(args.Units).VolumeFromMetric(v.v.Volume())
+}
+
+// Age is a method that returns the years since the fabrication year of the vehicle
+func (v *vehicleResolver) Age() int32 {
+	return int32(time.Now().Year() - v.v.FabricationYear)
+}
+
+// Similar is a method that returns the k vehicles most similar to the vehicle
+func (v *vehicleResolver) Similar(ctx context.Context, args struct{ K int32 }) ([]*similarResolver, error) {
+	if args.K < 1 || args.K > maxSimilar {
+		return nil, inputError("k must be between 1 and 100")
+	}
+	similar, err := v.r.sv.GetSimilarVehicles(ctx, v.v.Id, int(args.K))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	resolvers := make([]*similarResolver, 0, len(similar))
+	for _, value := range similar {
+		resolvers = append(resolvers, &similarResolver{vehicle: v.r.vehicle(value.Vehicle), distance: value.Distance})
+	}
+	return resolvers, nil
+}
+
+// similarResolver is a struct that resolves the fields of a similar vehicle
+type similarResolver struct {
+	vehicle  *vehicleResolver
+	distance float64
+}
+
+// Vehicle is a method that returns the similar vehicle
+func (s *similarResolver) Vehicle() *vehicleResolver {
+	return s.vehicle
+}
+
+// Distance is a method that returns the distance to the vehicle, lower is more similar
+func (s *similarResolver) Distance() float64 {
+	return s.distance
+}
+
+// searchResolver is a struct that resolves the fields of a vehicle found by a text search
+type searchResolver struct {
+	vehicle *vehicleResolver
+	score   float64
+}
+
+// Vehicle is a method that returns the vehicle found
+func (s *searchResolver) Vehicle() *vehicleResolver {
+	return s.vehicle
+}
+
+// Score is a method that returns the relevance of the vehicle for the search, higher is more relevant
+func (s *searchResolver) Score() float64 {
+	return s.score
+}
+
+// connectionResolver is a struct that resolves the fields of a page of vehicles
+type connectionResolver struct {
+	// edges are the vehicles of the page
+	edges []*edgeResolver
+	// total is the number of vehicles across every page
+	total int
+	// offset is the position of the first vehicle of the page
+	offset int
+}
+
+// Edges is a method that returns the vehicles of the page with their cursors
+func (c *connectionResolver) Edges() []*edgeResolver {
+	return c.edges
+}
+
+// Nodes is a method that returns the vehicles of the page
+func (c *connectionResolver) Nodes() []*vehicleResolver {
+	nodes := make([]*vehicleResolver, 0, len(c.edges))
+	for _, e := range c.edges {
+		nodes = append(nodes, e.node)
+	}
+	return nodes
+}
+
+// PageInfo is a method that returns the position of the page
+func (c *connectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{c: c}
+}
+
+// TotalCount is a method that returns the number of vehicles across every page
+func (c *connectionResolver) TotalCount() int32 {
+	return int32(c.total)
+}
+
+// edgeResolver is a struct that resolves the fields of a vehicle of a page
+type edgeResolver struct {
+	cursor string
+	node   *vehicleResolver
+}
+
+// Cursor is a method that returns the cursor of the vehicle, to request the vehicles after it
+func (e *edgeResolver) Cursor() string {
+	return e.cursor
+}
+
+// Node is a method that returns the vehicle
+func (e *edgeResolver) Node() *vehicleResolver {
+	return e.node
+}
+
+// pageInfoResolver is a struct that resolves the fields of the position of a page
+type pageInfoResolver struct {
+	c *connectionResolver
+}
+
+// HasNextPage is a method that returns true if there are vehicles after the page
+func (p *pageInfoResolver) HasNextPage() bool {
+	return p.c.offset+len(p.c.edges) < p.c.total
+}
+
+// HasPreviousPage is a method that returns true if there are vehicles before the page
+func (p *pageInfoResolver) HasPreviousPage() bool {
+	return p.c.offset > 0
+}
+
+// StartCursor is a method that returns the cursor of the first vehicle of the page, nil if empty
+func (p *pageInfoResolver) StartCursor() *string {
+	if len(p.c.edges) == 0 {
+		return nil
+	}
+	return &p.c.edges[0].cursor
+}
+
+// EndCursor is a method that returns the cursor of the last vehicle of the page, nil if empty
+func (p *pageInfoResolver) EndCursor() *string {
+	if len(p.c.edges) == 0 {
+		return nil
+	}
+	return &p.c.edges[len(p.c.edges)-1].cursor
+}
+
+// aggregateResolver is a struct that resolves the fields of the statistics of a group of vehicles
+type aggregateResolver struct {
+	key string
+	a   internal.Aggregate
+	// convert converts the values of the metric to the requested units
+	convert func(float64) float64
+}
+
+// Key is a method that returns the key of the group
+func (a *aggregateResolver) Key() string {
+	return a.key
+}
+
+// Count is a method that returns the number of vehicles of the group
+func (a *aggregateResolver) Count() int32 {
+	return int32(a.a.Count)
+}
+
+// Mean is a method that returns the mean of the metric
+func (a *aggregateResolver) Mean() float64 {
+	return a.convert(a.a.Mean())
+}
+
+// Min is a method that returns the lowest value of the metric
+func (a *aggregateResolver) Min() float64 {
+	return a.convert(a.a.Min)
+}
+
+// Max is a method that returns the highest value of the metric
+func (a *aggregateResolver) Max() float64 {
+	return a.convert(a.a.Max)
+}
+
+// Stddev is a method that returns the standard deviation of the metric
+func (a *aggregateResolver) Stddev() float64 {
+	return a.convert(a.a.StdDev())
+}
+
+// eventResolver is a struct that resolves the fields of a change made to a vehicle
+type eventResolver struct {
+	e internal.VehicleEvent
+	r *Resolver
+}
+
+// Type is a method that returns the kind of change
+func (e *eventResolver) Type() string {
+	return strings.ToUpper(string(e.e.Type))
+}
+
+// Vehicle is a method that returns the vehicle after the change, nil if deleted
+func (e *eventResolver) Vehicle() *vehicleResolver {
+	if e.e.Type == internal.VehicleDeleted {
+		return nil
+	}
+	return e.r.vehicle(e.e.Vehicle)
+}
+
+// Previous is a method that returns the vehicle before the change, nil if created
+func (e *eventResolver) Previous() *vehicleResolver {
+	if e.e.Type == internal.VehicleCreated {
+		return nil
+	}
+	return e.r.vehicle(e.e.Previous)
+}
@@ -0,0 +1,103 @@
+package grpcserver
+
+import (
+	"app/internal"
+	"app/internal/grpcserver/vehiclepb"
+)
+
+// toProto is a function that returns the message of a vehicle
+func toProto(v internal.Vehicle) *vehiclepb.Vehicle {
+	return &vehiclepb.Vehicle{
+		Id:           int64(v.Id),
+		Brand:        v.Brand,
+		Model:        v.Model,
+		Registration: v.Registration,
+		Color:        v.Color,
+		Year:         int32(v.FabricationYear),
+		Passengers:   int32(v.Capacity),
+		MaxSpeed:     v.MaxSpeed,
+		FuelType:     v.FuelType,
+		Transmission: v.Transmission,
+		Weight:       v.Weight,
+		Height:       v.Height,
+		Length:       v.Length,
+		Width:        v.Width,
+	}
+}
+
+// fromProto is a function that returns the vehicle of a message
+func fromProto(p *vehiclepb.Vehicle) internal.Vehicle {
+	return internal.Vehicle{
+		Id: int(p.GetId()),
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           p.GetBrand(),
+			Model:           p.GetModel(),
+			Registration:    p.GetRegistration(),
+			Color:           p.GetColor(),
+			FabricationYear: int(p.GetYear()),
+			Capacity:        int(p.GetPassengers()),
+			MaxSpeed:        p.GetMaxSpeed(),
+			FuelType:        p.GetFuelType(),
+			Transmission:    p.GetTransmission(),
+			Weight:          p.GetWeight(),
+			Dimensions: internal.Dimensions{
+				Height: p.GetHeight(),
+				Length: p.GetLength(),
+				Width:  p.GetWidth(),
+			},
+		},
+	}
+}
+
+// toPatch is a function that returns the patch of a message, the absent fields are left as they are
+func toPatch(p *vehiclepb.VehiclePatch) internal.VehiclePatch {
+	integer := func(value *int32) *int {
+		if value == nil {
+			return nil
+		}
+		i := int(*value)
+		return &i
+	}
+	if p == nil {
+		return internal.VehiclePatch{}
+	}
+	return internal.VehiclePatch{
+		Brand:           p.Brand,
+		Model:           p.Model,
+		Registration:    p.Registration,
+		Color:           p.Color,
+		FabricationYear: integer(p.Year),
+		Capacity:        integer(p.Passengers),
+		MaxSpeed:        p.MaxSpeed,
+		FuelType:        p.FuelType,
+		Transmission:    p.Transmission,
+		Weight:          p.Weight,
+		Height:          p.Height,
+		Length:          p.Length,
+		Width:           p.Width,
+	}
+}
+
+// toRange is a function that returns the range of a message, open if absent
+func toRange(r *vehiclepb.Range) internal.Range {
+	if r == nil {
+		return internal.Range{}
+	}
+	return internal.Range{Min: r.Min, Max: r.Max}
+}
+
+// toQuery is a function that returns the query of a filter, matching every vehicle if absent
+func toQuery(f *vehiclepb.VehicleFilter) internal.VehicleQuery {
+	return internal.VehicleQuery{
+		Brand:           f.GetBrand(),
+		Color:           f.GetColor(),
+		FuelType:        f.GetFuelType(),
+		Transmission:    f.GetTransmission(),
+		FabricationYear: toRange(f.GetYear()),
+		MaxSpeed:        toRange(f.GetMaxSpeed()),
+		Weight:          toRange(f.GetWeight()),
+		Height:          toRange(f.GetHeight()),
+		Length:          toRange(f.GetLength()),
+		Width:           toRange(f.GetWidth()),
+	}
+}
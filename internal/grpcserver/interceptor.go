@@ -0,0 +1,165 @@
+package grpcserver
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/tenant"
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Method is the method of the RPCs in the policy, e.g. "GRPC /vehicle.v1.VehicleService/GetVehicle"
+const Method = "GRPC"
+
+// healthPrefix is the prefix of the RPCs of the health checks, served to every caller as the probes of the HTTP server
+const healthPrefix = "/grpc.health.v1.Health/"
+
+// Interceptor is a function that prepares the context of an RPC, or rejects it with an error status
+type Interceptor func(ctx context.Context, method string) (context.Context, error)
+
+// Interceptors is a function that returns the unary and stream interceptors logging every RPC and running the interceptors in order
+// - the context carries a request-scoped logger, the request id is the x-request-id metadata or a new one
+// - the RPC is logged with the logger the interceptors enriched, e.g. with the principal and the tenant, as an HTTP request
+// - panics are recovered and answered as internal errors
+func Interceptors(l *slog.Logger, interceptors ...Interceptor) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	serve := func(ctx context.Context, method string, handle func(ctx context.Context) error) (err error) {
+		start := time.Now()
+
+		// request-scoped logger
+		id := strconv.FormatUint(middleware.NextRequestID(), 10)
+		if values := metadata.ValueFromIncomingContext(ctx, "x-request-id"); len(values) > 0 && values[0] != "" {
+			id = values[0]
+		}
+		ctx = logger.WithContext(ctx, l.With(slog.String("request_id", id)))
+
+		defer func() {
+			code := status.Code(err)
+			level := slog.LevelInfo
+			switch code {
+			case codes.OK, codes.Canceled:
+			case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+				level = slog.LevelError
+			default:
+				level = slog.LevelWarn
+			}
+			logger.FromContext(ctx).Log(ctx, level, "rpc",
+				slog.String("method", method),
+				slog.String("code", code.String()),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			)
+		}()
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(ctx).Error("panic", slog.Any("panic", r))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		for _, i := range interceptors {
+			if ctx, err = i(ctx, method); err != nil {
+				return
+			}
+		}
+		return handle(ctx)
+	}
+
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		err = serve(ctx, info.FullMethod, func(ctx context.Context) (err error) {
+			res, err = handler(ctx, req)
+			return
+		})
+		return
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return serve(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+			return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		})
+	}
+	return unary, stream
+}
+
+// serverStream is a struct that represents a stream with the context prepared by the interceptors
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context is a method that returns the context of the stream
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// request is a function that returns a request carrying the metadata of an RPC as headers
+// - so the authenticators and the tenant resolution of the HTTP server are shared
+func request(ctx context.Context, method string) *http.Request {
+	header := make(http.Header)
+	md, _ := metadata.FromIncomingContext(ctx)
+	for key, values := range md {
+		for _, value := range values {
+			header.Add(key, value)
+		}
+	}
+	return (&http.Request{Method: Method, RequestURI: method, Header: header}).WithContext(ctx)
+}
+
+// Auth is a function that returns the interceptor authenticating every RPC, and authorizing it by the policy if given
+// - the credentials are the metadata of the RPC, as the headers of an HTTP request
+// - the RPCs are authorized by the policy as "GRPC /package.Service/Method", the health checks are served to every caller
+func Auth(authenticators []auth.Authenticator, policy *auth.Policy) Interceptor {
+	return func(ctx context.Context, method string) (context.Context, error) {
+		if strings.HasPrefix(method, healthPrefix) {
+			return ctx, nil
+		}
+
+		p, err := auth.Authenticate(request(ctx, method), authenticators)
+		if err != nil {
+			logger.FromContext(ctx).Warn("authentication failed", slog.String("error", err.Error()))
+			return ctx, status.Error(codes.Unauthenticated, "unauthenticated")
+		}
+		ctx = auth.ContextWithPrincipal(ctx, p)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(
+			slog.String("principal", p.Subject),
+			slog.String("auth_method", string(p.Method)),
+		))
+
+		if policy != nil {
+			perm, ok := policy.Permission(Method, method)
+			if !ok || !policy.Allowed(p, perm) {
+				logger.FromContext(ctx).Warn("authorization denied", slog.String("route", method), slog.String("permission", string(perm)))
+				return ctx, status.Error(codes.PermissionDenied, "forbidden")
+			}
+		}
+		return ctx, nil
+	}
+}
+
+// Tenant is a function that returns the interceptor resolving the tenant of every RPC, as the HTTP server does
+// - the tenant is named by the x-tenant-id metadata, it must be used after Auth
+func Tenant(r *tenant.Registry) Interceptor {
+	return func(ctx context.Context, method string) (context.Context, error) {
+		if strings.HasPrefix(method, healthPrefix) {
+			return ctx, nil
+		}
+
+		id, err := tenant.Resolve(request(ctx, method))
+		if err != nil {
+			return ctx, status.Error(codes.PermissionDenied, err.Error())
+		}
+		if _, err = r.Get(id); err != nil {
+			return ctx, status.Error(codes.NotFound, err.Error())
+		}
+		ctx = tenant.WithContext(ctx, id)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(slog.String("tenant", id)))
+		return ctx, nil
+	}
+}
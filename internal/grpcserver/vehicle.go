@@ -0,0 +1,445 @@
+// Package grpcserver serves the vehicles over gRPC, with the service defined in proto/vehicle/v1/vehicle.proto
+package grpcserver
+
+//go:generate protoc -I ../../proto --go_out=. --go_opt=module=app/internal/grpcserver --go-grpc_out=. --go-grpc_opt=module=app/internal/grpcserver vehicle/v1/vehicle.proto
+
+import (
+	"app/internal"
+	"app/internal/grpcserver/vehiclepb"
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultPageSize is the number of vehicles of a page if not requested
+	defaultPageSize = 50
+	// maxPageSize is the maximum number of vehicles of a page
+	maxPageSize = 500
+	// defaultSearchLimit is the number of vehicles of a search if not requested
+	defaultSearchLimit = 20
+	// defaultSimilar is the number of similar vehicles if not requested
+	defaultSimilar = 5
+	// maxResults is the maximum number of vehicles of a search or of similar vehicles
+	maxResults = 100
+)
+
+// NewVehicleServer is a function that returns a new instance of VehicleServer
+// - the vehicles are not served until Ready is called
+func NewVehicleServer() *VehicleServer {
+	return &VehicleServer{}
+}
+
+// VehicleServer is a struct that implements the gRPC VehicleService through the vehicle service
+type VehicleServer struct {
+	vehiclepb.UnimplementedVehicleServiceServer
+	// sv is the service that will be used by the server, nil until the vehicles are loaded
+	sv atomic.Pointer[internal.VehicleService]
+}
+
+// Ready is a method that sets the service once the vehicles are loaded
+func (s *VehicleServer) Ready(sv internal.VehicleService) {
+	s.sv.Store(&sv)
+}
+
+// service is a method that returns the service, or an unavailable error while the vehicles are loading
+func (s *VehicleServer) service() (sv internal.VehicleService, err error) {
+	p := s.sv.Load()
+	if p == nil {
+		return nil, status.Error(codes.Unavailable, "vehicles are loading")
+	}
+	return *p, nil
+}
+
+// Available is a method that implements Interceptor, rejecting the RPCs with an unavailable error while the vehicles are loading
+// - the health checks are served meanwhile, it must be used before Tenant as the tenants are created on load
+func (s *VehicleServer) Available(ctx context.Context, method string) (context.Context, error) {
+	if strings.HasPrefix(method, healthPrefix) {
+		return ctx, nil
+	}
+	_, err := s.service()
+	return ctx, err
+}
+
+// serviceError is a function that returns the status of an error of the service
+func serviceError(err error) error {
+	switch {
+	case errors.Is(err, internal.ErrVehicleNotFound):
+		return status.Error(codes.NotFound, "vehicle not found")
+	case errors.Is(err, internal.ErrVehicleExists):
+		return status.Error(codes.AlreadyExists, "vehicle already exists")
+	case errors.Is(err, internal.ErrVehicleInvalid), errors.Is(err, internal.ErrRangeInvalid), errors.Is(err, internal.ErrSortFieldInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+	return status.Error(codes.Internal, "internal error")
+}
+
+// GetVehicle is a method that returns the vehicle with the id
+func (s *VehicleServer) GetVehicle(ctx context.Context, req *vehiclepb.GetVehicleRequest) (*vehiclepb.Vehicle, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := sv.FindOne(ctx, int(req.GetId()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toProto(v), nil
+}
+
+// list is a method that returns the vehicles matching the filter of a request, sorted as requested
+func (s *VehicleServer) list(ctx context.Context, req *vehiclepb.ListVehiclesRequest) (sorted []internal.Vehicle, err error) {
+	sv, err := s.service()
+	if err != nil {
+		return
+	}
+	v, err := sv.Query(ctx, toQuery(req.GetFilter()))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	field, descending := strings.CutPrefix(req.GetSort(), "-")
+	if sorted, err = internal.SortVehicles(v, field, descending); err != nil {
+		return nil, serviceError(err)
+	}
+	return
+}
+
+// ListVehicles is a method that returns a page of the vehicles matching the filter
+func (s *VehicleServer) ListVehicles(ctx context.Context, req *vehiclepb.ListVehiclesRequest) (*vehiclepb.ListVehiclesResponse, error) {
+	// request
+	size := int(req.GetPageSize())
+	switch {
+	case size == 0:
+		size = defaultPageSize
+	case size < 0 || size > maxPageSize:
+		return nil, status.Error(codes.InvalidArgument, "page_size must be between 1 and 500")
+	}
+	offset := 0
+	if req.GetPageToken() != "" {
+		var err error
+		if offset, err = parsePageToken(req.GetPageToken()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+	}
+
+	// process
+	sorted, err := s.list(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// response
+	res := &vehiclepb.ListVehiclesResponse{TotalSize: int32(len(sorted))}
+	end := min(offset+size, len(sorted))
+	for _, v := range sorted[min(offset, end):end] {
+		res.Vehicles = append(res.Vehicles, toProto(v))
+	}
+	if end < len(sorted) {
+		res.NextPageToken = pageToken(end)
+	}
+	return res, nil
+}
+
+// StreamVehicles is a method that streams the vehicles matching the filter
+func (s *VehicleServer) StreamVehicles(req *vehiclepb.ListVehiclesRequest, stream grpc.ServerStreamingServer[vehiclepb.Vehicle]) error {
+	sorted, err := s.list(stream.Context(), req)
+	if err != nil {
+		return err
+	}
+	for _, v := range sorted {
+		if err := stream.Send(toProto(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateVehicle is a method that creates a vehicle and returns it
+func (s *VehicleServer) CreateVehicle(ctx context.Context, req *vehiclepb.CreateVehicleRequest) (*vehiclepb.Vehicle, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	v := fromProto(req.GetVehicle())
+	if err := v.Validate(); err != nil {
+		return nil, serviceError(err)
+	}
+	if err := sv.Create(ctx, v); err != nil {
+		return nil, serviceError(err)
+	}
+	return toProto(v), nil
+}
+
+// CreateVehicles is a method that creates every vehicle or none
+func (s *VehicleServer) CreateVehicles(ctx context.Context, req *vehiclepb.CreateVehiclesRequest) (*vehiclepb.CreateVehiclesResponse, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	// request
+	if len(req.GetVehicles()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no vehicles")
+	}
+	vehicles := make([]internal.Vehicle, 0, len(req.GetVehicles()))
+	ids := make(map[int]bool, len(req.GetVehicles()))
+	for i, value := range req.GetVehicles() {
+		v := fromProto(value)
+		if err := v.Validate(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "vehicle "+strconv.Itoa(i)+": "+err.Error())
+		}
+		if ids[v.Id] {
+			return nil, status.Error(codes.InvalidArgument, "vehicle "+strconv.Itoa(i)+": duplicated id")
+		}
+		ids[v.Id] = true
+		vehicles = append(vehicles, v)
+	}
+
+	// process
+	if err := sv.CreateVehicles(ctx, vehicles); err != nil {
+		return nil, serviceError(err)
+	}
+	return &vehiclepb.CreateVehiclesResponse{Vehicles: req.GetVehicles()}, nil
+}
+
+// UpdateVehicle is a method that changes the attributes present in the patch and returns the vehicle
+func (s *VehicleServer) UpdateVehicle(ctx context.Context, req *vehiclepb.UpdateVehicleRequest) (*vehiclepb.Vehicle, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	p := toPatch(req.GetPatch())
+	if p.Empty() {
+		return nil, status.Error(codes.InvalidArgument, "empty patch")
+	}
+	for _, value := range []*float64{p.MaxSpeed, p.Weight, p.Height, p.Length, p.Width} {
+		if value != nil && *value < 0 {
+			return nil, status.Error(codes.InvalidArgument, "max_speed, weight, height, length and width must not be negative")
+		}
+	}
+
+	v, err := sv.Patch(ctx, int(req.GetId()), p)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return toProto(v), nil
+}
+
+// DeleteVehicle is a method that deletes the vehicle with the id
+func (s *VehicleServer) DeleteVehicle(ctx context.Context, req *vehiclepb.DeleteVehicleRequest) (*vehiclepb.DeleteVehicleResponse, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sv.DeleteVehicle(ctx, int(req.GetId())); err != nil {
+		return nil, serviceError(err)
+	}
+	return &vehiclepb.DeleteVehicleResponse{}, nil
+}
+
+// SearchVehicles is a method that returns the vehicles matching the text, sorted by relevance
+func (s *VehicleServer) SearchVehicles(ctx context.Context, req *vehiclepb.SearchVehiclesRequest) (*vehiclepb.SearchVehiclesResponse, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	// request
+	if strings.TrimSpace(req.GetText()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing search text")
+	}
+	limit, err := bounded("limit", req.GetLimit(), defaultSearchLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// process
+	results, err := sv.SearchVehicles(ctx, req.GetText(), limit)
+	if err != nil && !errors.Is(err, internal.ErrVehicleNotFound) {
+		return nil, serviceError(err)
+	}
+
+	// response
+	res := &vehiclepb.SearchVehiclesResponse{}
+	for _, value := range results {
+		res.Results = append(res.Results, &vehiclepb.SearchVehiclesResponse_Result{Vehicle: toProto(value.Vehicle), Score: value.Score})
+	}
+	return res, nil
+}
+
+// GetSimilarVehicles is a method that returns the vehicles most similar to the vehicle with the id
+func (s *VehicleServer) GetSimilarVehicles(ctx context.Context, req *vehiclepb.GetSimilarVehiclesRequest) (*vehiclepb.GetSimilarVehiclesResponse, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	k, err := bounded("k", req.GetK(), defaultSimilar)
+	if err != nil {
+		return nil, err
+	}
+	similar, err := sv.GetSimilarVehicles(ctx, int(req.GetId()), k)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	res := &vehiclepb.GetSimilarVehiclesResponse{}
+	for _, value := range similar {
+		res.Results = append(res.Results, &vehiclepb.GetSimilarVehiclesResponse_Result{Vehicle: toProto(value.Vehicle), Distance: value.Distance})
+	}
+	return res, nil
+}
+
+// aggregateGroups are the groups of the statistics, by their value in the protocol
+var aggregateGroups = map[vehiclepb.AggregateGroup]internal.AggregateGroup{
+	vehiclepb.AggregateGroup_AGGREGATE_GROUP_UNSPECIFIED: internal.AggregateByBrand,
+	vehiclepb.AggregateGroup_AGGREGATE_GROUP_BRAND:       internal.AggregateByBrand,
+	vehiclepb.AggregateGroup_AGGREGATE_GROUP_FUEL_TYPE:   internal.AggregateByFuelType,
+	vehiclepb.AggregateGroup_AGGREGATE_GROUP_YEAR:        internal.AggregateByYear,
+}
+
+// aggregateMetrics are the metrics of the statistics, by their value in the protocol
+var aggregateMetrics = map[vehiclepb.AggregateMetric]internal.AggregateMetric{
+	vehiclepb.AggregateMetric_AGGREGATE_METRIC_UNSPECIFIED: internal.MetricMaxSpeed,
+	vehiclepb.AggregateMetric_AGGREGATE_METRIC_MAX_SPEED:   internal.MetricMaxSpeed,
+	vehiclepb.AggregateMetric_AGGREGATE_METRIC_CAPACITY:    internal.MetricCapacity,
+	vehiclepb.AggregateMetric_AGGREGATE_METRIC_WEIGHT:      internal.MetricWeight,
+}
+
+// GetStats is a method that returns the statistics of a metric for every key of a group
+func (s *VehicleServer) GetStats(ctx context.Context, req *vehiclepb.GetStatsRequest) (*vehiclepb.GetStatsResponse, error) {
+	sv, err := s.service()
+	if err != nil {
+		return nil, err
+	}
+
+	// request
+	group, ok := aggregateGroups[req.GetGroupBy()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid group_by")
+	}
+	metric, ok := aggregateMetrics[req.GetMetric()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "invalid metric")
+	}
+
+	// process
+	aggregates, err := sv.GetAggregates(ctx, group, metric)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	// response
+	keys := make([]string, 0, len(aggregates))
+	for key, a := range aggregates {
+		if a.Count > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	res := &vehiclepb.GetStatsResponse{}
+	for _, key := range keys {
+		a := aggregates[key]
+		res.Aggregates = append(res.Aggregates, &vehiclepb.Aggregate{
+			Key:    key,
+			Count:  int32(a.Count),
+			Mean:   a.Mean(),
+			Min:    a.Min,
+			Max:    a.Max,
+			Stddev: a.StdDev(),
+		})
+	}
+	return res, nil
+}
+
+// eventTypes are the types of the events, by their value in the protocol
+var eventTypes = map[internal.VehicleEventType]vehiclepb.VehicleEventType{
+	internal.VehicleCreated: vehiclepb.VehicleEventType_VEHICLE_EVENT_TYPE_CREATED,
+	internal.VehicleUpdated: vehiclepb.VehicleEventType_VEHICLE_EVENT_TYPE_UPDATED,
+	internal.VehicleDeleted: vehiclepb.VehicleEventType_VEHICLE_EVENT_TYPE_DELETED,
+}
+
+// WatchVehicles is a method that streams the changes made to the vehicles from now on
+// - the stream ends with the caller, or with RESOURCE_EXHAUSTED if the caller falls behind
+func (s *VehicleServer) WatchVehicles(req *vehiclepb.WatchVehiclesRequest, stream grpc.ServerStreamingServer[vehiclepb.VehicleEvent]) error {
+	sv, err := s.service()
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	events, err := sv.WatchVehicles(ctx)
+	if err != nil {
+		return serviceError(err)
+	}
+	for e := range events {
+		t := eventTypes[e.Type]
+		if len(req.GetTypes()) > 0 && !slices.Contains(req.GetTypes(), t) {
+			continue
+		}
+		res := &vehiclepb.VehicleEvent{Type: t}
+		if e.Type != internal.VehicleDeleted {
+			res.Vehicle = toProto(e.Vehicle)
+		}
+		if e.Type != internal.VehicleCreated {
+			res.Previous = toProto(e.Previous)
+		}
+		if err := stream.Send(res); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Error(codes.ResourceExhausted, "watcher fell behind")
+}
+
+// bounded is a function that returns the value of a limit between 1 and maxResults, def if absent
+func bounded(name string, value int32, def int) (n int, err error) {
+	switch {
+	case value == 0:
+		return def, nil
+	case value < 0 || value > maxResults:
+		return 0, status.Error(codes.InvalidArgument, name+" must be between 1 and 100")
+	}
+	return int(value), nil
+}
+
+// pageToken is a function that returns the opaque token of the page starting at the offset
+func pageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+// parsePageToken is a function that returns the offset of a page token
+func parsePageToken(token string) (offset int, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return
+	}
+	value, ok := strings.CutPrefix(string(data), "offset:")
+	if !ok {
+		return 0, errors.New("invalid page token")
+	}
+	offset, err = strconv.Atoi(value)
+	if err == nil && offset < 0 {
+		err = errors.New("invalid page token")
+	}
+	return
+}
@@ -0,0 +1,2448 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: vehicle/v1/vehicle.proto
+
+package vehiclepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AggregateGroup int32
+
+const (
+	AggregateGroup_AGGREGATE_GROUP_UNSPECIFIED AggregateGroup = 0
+	AggregateGroup_AGGREGATE_GROUP_BRAND       AggregateGroup = 1
+	AggregateGroup_AGGREGATE_GROUP_FUEL_TYPE   AggregateGroup = 2
+	AggregateGroup_AGGREGATE_GROUP_YEAR        AggregateGroup = 3
+)
+
+// Enum value maps for AggregateGroup.
+var (
+	AggregateGroup_name = map[int32]string{
+		0: "AGGREGATE_GROUP_UNSPECIFIED",
+		1: "AGGREGATE_GROUP_BRAND",
+		2: "AGGREGATE_GROUP_FUEL_TYPE",
+		3: "AGGREGATE_GROUP_YEAR",
+	}
+	AggregateGroup_value = map[string]int32{
+		"AGGREGATE_GROUP_UNSPECIFIED": 0,
+		"AGGREGATE_GROUP_BRAND":       1,
+		"AGGREGATE_GROUP_FUEL_TYPE":   2,
+		"AGGREGATE_GROUP_YEAR":        3,
+	}
+)
+
+func (x AggregateGroup) Enum() *AggregateGroup {
+	p := new(AggregateGroup)
+	*p = x
+	return p
+}
+
+func (x AggregateGroup) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AggregateGroup) Descriptor() protoreflect.EnumDescriptor {
+	return file_vehicle_v1_vehicle_proto_enumTypes[0].Descriptor()
+}
+
+func (AggregateGroup) Type() protoreflect.EnumType {
+	return &file_vehicle_v1_vehicle_proto_enumTypes[0]
+}
+
+func (x AggregateGroup) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AggregateGroup.Descriptor instead.
+func (AggregateGroup) EnumDescriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{0}
+}
+
+type AggregateMetric int32
+
+const (
+	AggregateMetric_AGGREGATE_METRIC_UNSPECIFIED AggregateMetric = 0
+	AggregateMetric_AGGREGATE_METRIC_MAX_SPEED   AggregateMetric = 1
+	AggregateMetric_AGGREGATE_METRIC_CAPACITY    AggregateMetric = 2
+	AggregateMetric_AGGREGATE_METRIC_WEIGHT      AggregateMetric = 3
+)
+
+// Enum value maps for AggregateMetric.
+var (
+	AggregateMetric_name = map[int32]string{
+		0: "AGGREGATE_METRIC_UNSPECIFIED",
+		1: "AGGREGATE_METRIC_MAX_SPEED",
+		2: "AGGREGATE_METRIC_CAPACITY",
+		3: "AGGREGATE_METRIC_WEIGHT",
+	}
+	AggregateMetric_value = map[string]int32{
+		"AGGREGATE_METRIC_UNSPECIFIED": 0,
+		"AGGREGATE_METRIC_MAX_SPEED":   1,
+		"AGGREGATE_METRIC_CAPACITY":    2,
+		"AGGREGATE_METRIC_WEIGHT":      3,
+	}
+)
+
+func (x AggregateMetric) Enum() *AggregateMetric {
+	p := new(AggregateMetric)
+	*p = x
+	return p
+}
+
+func (x AggregateMetric) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AggregateMetric) Descriptor() protoreflect.EnumDescriptor {
+	return file_vehicle_v1_vehicle_proto_enumTypes[1].Descriptor()
+}
+
+func (AggregateMetric) Type() protoreflect.EnumType {
+	return &file_vehicle_v1_vehicle_proto_enumTypes[1]
+}
+
+func (x AggregateMetric) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AggregateMetric.Descriptor instead.
+func (AggregateMetric) EnumDescriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{1}
+}
+
+type VehicleEventType int32
+
+const (
+	VehicleEventType_VEHICLE_EVENT_TYPE_UNSPECIFIED VehicleEventType = 0
+	VehicleEventType_VEHICLE_EVENT_TYPE_CREATED     VehicleEventType = 1
+	VehicleEventType_VEHICLE_EVENT_TYPE_UPDATED     VehicleEventType = 2
+	VehicleEventType_VEHICLE_EVENT_TYPE_DELETED     VehicleEventType = 3
+)
+
+// Enum value maps for VehicleEventType.
+var (
+	VehicleEventType_name = map[int32]string{
+		0: "VEHICLE_EVENT_TYPE_UNSPECIFIED",
+		1: "VEHICLE_EVENT_TYPE_CREATED",
+		2: "VEHICLE_EVENT_TYPE_UPDATED",
+		3: "VEHICLE_EVENT_TYPE_DELETED",
+	}
+	VehicleEventType_value = map[string]int32{
+		"VEHICLE_EVENT_TYPE_UNSPECIFIED": 0,
+		"VEHICLE_EVENT_TYPE_CREATED":     1,
+		"VEHICLE_EVENT_TYPE_UPDATED":     2,
+		"VEHICLE_EVENT_TYPE_DELETED":     3,
+	}
+)
+
+func (x VehicleEventType) Enum() *VehicleEventType {
+	p := new(VehicleEventType)
+	*p = x
+	return p
+}
+
+func (x VehicleEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VehicleEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_vehicle_v1_vehicle_proto_enumTypes[2].Descriptor()
+}
+
+func (VehicleEventType) Type() protoreflect.EnumType {
+	return &file_vehicle_v1_vehicle_proto_enumTypes[2]
+}
+
+func (x VehicleEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VehicleEventType.Descriptor instead.
+func (VehicleEventType) EnumDescriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{2}
+}
+
+type Vehicle struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    int64  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Brand string `protobuf:"bytes,2,opt,name=brand,proto3" json:"brand,omitempty"`
+	Model string `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"`
+	// Empty if the caller may not read it.
+	Registration string  `protobuf:"bytes,4,opt,name=registration,proto3" json:"registration,omitempty"`
+	Color        string  `protobuf:"bytes,5,opt,name=color,proto3" json:"color,omitempty"`
+	Year         int32   `protobuf:"varint,6,opt,name=year,proto3" json:"year,omitempty"`
+	Passengers   int32   `protobuf:"varint,7,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	MaxSpeed     float64 `protobuf:"fixed64,8,opt,name=max_speed,json=maxSpeed,proto3" json:"max_speed,omitempty"`
+	FuelType     string  `protobuf:"bytes,9,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	Transmission string  `protobuf:"bytes,10,opt,name=transmission,proto3" json:"transmission,omitempty"`
+	Weight       float64 `protobuf:"fixed64,11,opt,name=weight,proto3" json:"weight,omitempty"`
+	Height       float64 `protobuf:"fixed64,12,opt,name=height,proto3" json:"height,omitempty"`
+	Length       float64 `protobuf:"fixed64,13,opt,name=length,proto3" json:"length,omitempty"`
+	Width        float64 `protobuf:"fixed64,14,opt,name=width,proto3" json:"width,omitempty"`
+}
+
+func (x *Vehicle) Reset() {
+	*x = Vehicle{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Vehicle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vehicle) ProtoMessage() {}
+
+func (x *Vehicle) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vehicle.ProtoReflect.Descriptor instead.
+func (*Vehicle) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Vehicle) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Vehicle) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *Vehicle) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Vehicle) GetRegistration() string {
+	if x != nil {
+		return x.Registration
+	}
+	return ""
+}
+
+func (x *Vehicle) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Vehicle) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *Vehicle) GetPassengers() int32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *Vehicle) GetMaxSpeed() float64 {
+	if x != nil {
+		return x.MaxSpeed
+	}
+	return 0
+}
+
+func (x *Vehicle) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *Vehicle) GetTransmission() string {
+	if x != nil {
+		return x.Transmission
+	}
+	return ""
+}
+
+func (x *Vehicle) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *Vehicle) GetHeight() float64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Vehicle) GetLength() float64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *Vehicle) GetWidth() float64 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+// VehiclePatch holds the attributes to change, the absent ones are left as they are.
+type VehiclePatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Brand        *string  `protobuf:"bytes,1,opt,name=brand,proto3,oneof" json:"brand,omitempty"`
+	Model        *string  `protobuf:"bytes,2,opt,name=model,proto3,oneof" json:"model,omitempty"`
+	Registration *string  `protobuf:"bytes,3,opt,name=registration,proto3,oneof" json:"registration,omitempty"`
+	Color        *string  `protobuf:"bytes,4,opt,name=color,proto3,oneof" json:"color,omitempty"`
+	Year         *int32   `protobuf:"varint,5,opt,name=year,proto3,oneof" json:"year,omitempty"`
+	Passengers   *int32   `protobuf:"varint,6,opt,name=passengers,proto3,oneof" json:"passengers,omitempty"`
+	MaxSpeed     *float64 `protobuf:"fixed64,7,opt,name=max_speed,json=maxSpeed,proto3,oneof" json:"max_speed,omitempty"`
+	FuelType     *string  `protobuf:"bytes,8,opt,name=fuel_type,json=fuelType,proto3,oneof" json:"fuel_type,omitempty"`
+	Transmission *string  `protobuf:"bytes,9,opt,name=transmission,proto3,oneof" json:"transmission,omitempty"`
+	Weight       *float64 `protobuf:"fixed64,10,opt,name=weight,proto3,oneof" json:"weight,omitempty"`
+	Height       *float64 `protobuf:"fixed64,11,opt,name=height,proto3,oneof" json:"height,omitempty"`
+	Length       *float64 `protobuf:"fixed64,12,opt,name=length,proto3,oneof" json:"length,omitempty"`
+	Width        *float64 `protobuf:"fixed64,13,opt,name=width,proto3,oneof" json:"width,omitempty"`
+}
+
+func (x *VehiclePatch) Reset() {
+	*x = VehiclePatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VehiclePatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VehiclePatch) ProtoMessage() {}
+
+func (x *VehiclePatch) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VehiclePatch.ProtoReflect.Descriptor instead.
+func (*VehiclePatch) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VehiclePatch) GetBrand() string {
+	if x != nil && x.Brand != nil {
+		return *x.Brand
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetModel() string {
+	if x != nil && x.Model != nil {
+		return *x.Model
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetRegistration() string {
+	if x != nil && x.Registration != nil {
+		return *x.Registration
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetColor() string {
+	if x != nil && x.Color != nil {
+		return *x.Color
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetYear() int32 {
+	if x != nil && x.Year != nil {
+		return *x.Year
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetPassengers() int32 {
+	if x != nil && x.Passengers != nil {
+		return *x.Passengers
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetMaxSpeed() float64 {
+	if x != nil && x.MaxSpeed != nil {
+		return *x.MaxSpeed
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetFuelType() string {
+	if x != nil && x.FuelType != nil {
+		return *x.FuelType
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetTransmission() string {
+	if x != nil && x.Transmission != nil {
+		return *x.Transmission
+	}
+	return ""
+}
+
+func (x *VehiclePatch) GetWeight() float64 {
+	if x != nil && x.Weight != nil {
+		return *x.Weight
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetHeight() float64 {
+	if x != nil && x.Height != nil {
+		return *x.Height
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetLength() float64 {
+	if x != nil && x.Length != nil {
+		return *x.Length
+	}
+	return 0
+}
+
+func (x *VehiclePatch) GetWidth() float64 {
+	if x != nil && x.Width != nil {
+		return *x.Width
+	}
+	return 0
+}
+
+// Range is an inclusive range, open on the absent bounds.
+type Range struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Min *float64 `protobuf:"fixed64,1,opt,name=min,proto3,oneof" json:"min,omitempty"`
+	Max *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+}
+
+func (x *Range) Reset() {
+	*x = Range{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Range) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Range) ProtoMessage() {}
+
+func (x *Range) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Range.ProtoReflect.Descriptor instead.
+func (*Range) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Range) GetMin() float64 {
+	if x != nil && x.Min != nil {
+		return *x.Min
+	}
+	return 0
+}
+
+func (x *Range) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+// VehicleFilter matches the vehicles satisfying every present constraint.
+type VehicleFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Brand        string `protobuf:"bytes,1,opt,name=brand,proto3" json:"brand,omitempty"`
+	Color        string `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	FuelType     string `protobuf:"bytes,3,opt,name=fuel_type,json=fuelType,proto3" json:"fuel_type,omitempty"`
+	Transmission string `protobuf:"bytes,4,opt,name=transmission,proto3" json:"transmission,omitempty"`
+	Year         *Range `protobuf:"bytes,5,opt,name=year,proto3" json:"year,omitempty"`
+	MaxSpeed     *Range `protobuf:"bytes,6,opt,name=max_speed,json=maxSpeed,proto3" json:"max_speed,omitempty"`
+	Weight       *Range `protobuf:"bytes,7,opt,name=weight,proto3" json:"weight,omitempty"`
+	Height       *Range `protobuf:"bytes,8,opt,name=height,proto3" json:"height,omitempty"`
+	Length       *Range `protobuf:"bytes,9,opt,name=length,proto3" json:"length,omitempty"`
+	Width        *Range `protobuf:"bytes,10,opt,name=width,proto3" json:"width,omitempty"`
+}
+
+func (x *VehicleFilter) Reset() {
+	*x = VehicleFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VehicleFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VehicleFilter) ProtoMessage() {}
+
+func (x *VehicleFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VehicleFilter.ProtoReflect.Descriptor instead.
+func (*VehicleFilter) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VehicleFilter) GetBrand() string {
+	if x != nil {
+		return x.Brand
+	}
+	return ""
+}
+
+func (x *VehicleFilter) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *VehicleFilter) GetFuelType() string {
+	if x != nil {
+		return x.FuelType
+	}
+	return ""
+}
+
+func (x *VehicleFilter) GetTransmission() string {
+	if x != nil {
+		return x.Transmission
+	}
+	return ""
+}
+
+func (x *VehicleFilter) GetYear() *Range {
+	if x != nil {
+		return x.Year
+	}
+	return nil
+}
+
+func (x *VehicleFilter) GetMaxSpeed() *Range {
+	if x != nil {
+		return x.MaxSpeed
+	}
+	return nil
+}
+
+func (x *VehicleFilter) GetWeight() *Range {
+	if x != nil {
+		return x.Weight
+	}
+	return nil
+}
+
+func (x *VehicleFilter) GetHeight() *Range {
+	if x != nil {
+		return x.Height
+	}
+	return nil
+}
+
+func (x *VehicleFilter) GetLength() *Range {
+	if x != nil {
+		return x.Length
+	}
+	return nil
+}
+
+func (x *VehicleFilter) GetWidth() *Range {
+	if x != nil {
+		return x.Width
+	}
+	return nil
+}
+
+type GetVehicleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetVehicleRequest) Reset() {
+	*x = GetVehicleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVehicleRequest) ProtoMessage() {}
+
+func (x *GetVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVehicleRequest.ProtoReflect.Descriptor instead.
+func (*GetVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetVehicleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListVehiclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filter *VehicleFilter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// The field to sort by, or -field for descending order: id (default), brand, model, year, passengers,
+	// max_speed, weight, height, length or width.
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// The number of vehicles of the page, 50 by default, at most 500.
+	PageSize int32 `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// The next_page_token of the previous page, empty for the first page.
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+}
+
+func (x *ListVehiclesRequest) Reset() {
+	*x = ListVehiclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesRequest) ProtoMessage() {}
+
+func (x *ListVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*ListVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListVehiclesRequest) GetFilter() *VehicleFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *ListVehiclesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListVehiclesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListVehiclesRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListVehiclesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicles []*Vehicle `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// The number of vehicles matching the filter, across every page.
+	TotalSize int32 `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+}
+
+func (x *ListVehiclesResponse) Reset() {
+	*x = ListVehiclesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVehiclesResponse) ProtoMessage() {}
+
+func (x *ListVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*ListVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListVehiclesResponse) GetVehicles() []*Vehicle {
+	if x != nil {
+		return x.Vehicles
+	}
+	return nil
+}
+
+func (x *ListVehiclesResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListVehiclesResponse) GetTotalSize() int32 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+type CreateVehicleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicle *Vehicle `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+}
+
+func (x *CreateVehicleRequest) Reset() {
+	*x = CreateVehicleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVehicleRequest) ProtoMessage() {}
+
+func (x *CreateVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVehicleRequest.ProtoReflect.Descriptor instead.
+func (*CreateVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CreateVehicleRequest) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+type CreateVehiclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicles []*Vehicle `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
+}
+
+func (x *CreateVehiclesRequest) Reset() {
+	*x = CreateVehiclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVehiclesRequest) ProtoMessage() {}
+
+func (x *CreateVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*CreateVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CreateVehiclesRequest) GetVehicles() []*Vehicle {
+	if x != nil {
+		return x.Vehicles
+	}
+	return nil
+}
+
+type CreateVehiclesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicles []*Vehicle `protobuf:"bytes,1,rep,name=vehicles,proto3" json:"vehicles,omitempty"`
+}
+
+func (x *CreateVehiclesResponse) Reset() {
+	*x = CreateVehiclesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVehiclesResponse) ProtoMessage() {}
+
+func (x *CreateVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*CreateVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateVehiclesResponse) GetVehicles() []*Vehicle {
+	if x != nil {
+		return x.Vehicles
+	}
+	return nil
+}
+
+type UpdateVehicleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id    int64         `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Patch *VehiclePatch `protobuf:"bytes,2,opt,name=patch,proto3" json:"patch,omitempty"`
+}
+
+func (x *UpdateVehicleRequest) Reset() {
+	*x = UpdateVehicleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVehicleRequest) ProtoMessage() {}
+
+func (x *UpdateVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVehicleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateVehicleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateVehicleRequest) GetPatch() *VehiclePatch {
+	if x != nil {
+		return x.Patch
+	}
+	return nil
+}
+
+type DeleteVehicleRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteVehicleRequest) Reset() {
+	*x = DeleteVehicleRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVehicleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVehicleRequest) ProtoMessage() {}
+
+func (x *DeleteVehicleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVehicleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVehicleRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteVehicleRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteVehicleResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteVehicleResponse) Reset() {
+	*x = DeleteVehicleResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVehicleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVehicleResponse) ProtoMessage() {}
+
+func (x *DeleteVehicleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVehicleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVehicleResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{12}
+}
+
+type SearchVehiclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// The maximum number of vehicles, 20 by default, at most 100.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (x *SearchVehiclesRequest) Reset() {
+	*x = SearchVehiclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVehiclesRequest) ProtoMessage() {}
+
+func (x *SearchVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*SearchVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SearchVehiclesRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SearchVehiclesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchVehiclesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*SearchVehiclesResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *SearchVehiclesResponse) Reset() {
+	*x = SearchVehiclesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVehiclesResponse) ProtoMessage() {}
+
+func (x *SearchVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*SearchVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchVehiclesResponse) GetResults() []*SearchVehiclesResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetSimilarVehiclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The number of vehicles, 5 by default, at most 100.
+	K int32 `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
+}
+
+func (x *GetSimilarVehiclesRequest) Reset() {
+	*x = GetSimilarVehiclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSimilarVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarVehiclesRequest) ProtoMessage() {}
+
+func (x *GetSimilarVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSimilarVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*GetSimilarVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetSimilarVehiclesRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *GetSimilarVehiclesRequest) GetK() int32 {
+	if x != nil {
+		return x.K
+	}
+	return 0
+}
+
+type GetSimilarVehiclesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Results []*GetSimilarVehiclesResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (x *GetSimilarVehiclesResponse) Reset() {
+	*x = GetSimilarVehiclesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSimilarVehiclesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarVehiclesResponse) ProtoMessage() {}
+
+func (x *GetSimilarVehiclesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSimilarVehiclesResponse.ProtoReflect.Descriptor instead.
+func (*GetSimilarVehiclesResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetSimilarVehiclesResponse) GetResults() []*GetSimilarVehiclesResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// By brand if unspecified.
+	GroupBy AggregateGroup `protobuf:"varint,1,opt,name=group_by,json=groupBy,proto3,enum=vehicle.v1.AggregateGroup" json:"group_by,omitempty"`
+	// The maximum speed if unspecified.
+	Metric AggregateMetric `protobuf:"varint,2,opt,name=metric,proto3,enum=vehicle.v1.AggregateMetric" json:"metric,omitempty"`
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetStatsRequest) GetGroupBy() AggregateGroup {
+	if x != nil {
+		return x.GroupBy
+	}
+	return AggregateGroup_AGGREGATE_GROUP_UNSPECIFIED
+}
+
+func (x *GetStatsRequest) GetMetric() AggregateMetric {
+	if x != nil {
+		return x.Metric
+	}
+	return AggregateMetric_AGGREGATE_METRIC_UNSPECIFIED
+}
+
+type Aggregate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Key    string  `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Count  int32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Mean   float64 `protobuf:"fixed64,3,opt,name=mean,proto3" json:"mean,omitempty"`
+	Min    float64 `protobuf:"fixed64,4,opt,name=min,proto3" json:"min,omitempty"`
+	Max    float64 `protobuf:"fixed64,5,opt,name=max,proto3" json:"max,omitempty"`
+	Stddev float64 `protobuf:"fixed64,6,opt,name=stddev,proto3" json:"stddev,omitempty"`
+}
+
+func (x *Aggregate) Reset() {
+	*x = Aggregate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Aggregate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregate) ProtoMessage() {}
+
+func (x *Aggregate) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregate.ProtoReflect.Descriptor instead.
+func (*Aggregate) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Aggregate) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *Aggregate) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Aggregate) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Aggregate) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Aggregate) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Aggregate) GetStddev() float64 {
+	if x != nil {
+		return x.Stddev
+	}
+	return 0
+}
+
+type GetStatsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Sorted by key.
+	Aggregates []*Aggregate `protobuf:"bytes,1,rep,name=aggregates,proto3" json:"aggregates,omitempty"`
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetStatsResponse) GetAggregates() []*Aggregate {
+	if x != nil {
+		return x.Aggregates
+	}
+	return nil
+}
+
+type WatchVehiclesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The types of the events, every type if empty.
+	Types []VehicleEventType `protobuf:"varint,1,rep,packed,name=types,proto3,enum=vehicle.v1.VehicleEventType" json:"types,omitempty"`
+}
+
+func (x *WatchVehiclesRequest) Reset() {
+	*x = WatchVehiclesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchVehiclesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchVehiclesRequest) ProtoMessage() {}
+
+func (x *WatchVehiclesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchVehiclesRequest.ProtoReflect.Descriptor instead.
+func (*WatchVehiclesRequest) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchVehiclesRequest) GetTypes() []VehicleEventType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+type VehicleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type VehicleEventType `protobuf:"varint,1,opt,name=type,proto3,enum=vehicle.v1.VehicleEventType" json:"type,omitempty"`
+	// The vehicle after the change, absent if deleted.
+	Vehicle *Vehicle `protobuf:"bytes,2,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// The vehicle before the change, absent if created.
+	Previous *Vehicle `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+}
+
+func (x *VehicleEvent) Reset() {
+	*x = VehicleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VehicleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VehicleEvent) ProtoMessage() {}
+
+func (x *VehicleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VehicleEvent.ProtoReflect.Descriptor instead.
+func (*VehicleEvent) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *VehicleEvent) GetType() VehicleEventType {
+	if x != nil {
+		return x.Type
+	}
+	return VehicleEventType_VEHICLE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *VehicleEvent) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *VehicleEvent) GetPrevious() *Vehicle {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+type SearchVehiclesResponse_Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicle *Vehicle `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// Higher is more relevant.
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+}
+
+func (x *SearchVehiclesResponse_Result) Reset() {
+	*x = SearchVehiclesResponse_Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchVehiclesResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchVehiclesResponse_Result) ProtoMessage() {}
+
+func (x *SearchVehiclesResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchVehiclesResponse_Result.ProtoReflect.Descriptor instead.
+func (*SearchVehiclesResponse_Result) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{14, 0}
+}
+
+func (x *SearchVehiclesResponse_Result) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *SearchVehiclesResponse_Result) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type GetSimilarVehiclesResponse_Result struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Vehicle *Vehicle `protobuf:"bytes,1,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	// Lower is more similar.
+	Distance float64 `protobuf:"fixed64,2,opt,name=distance,proto3" json:"distance,omitempty"`
+}
+
+func (x *GetSimilarVehiclesResponse_Result) Reset() {
+	*x = GetSimilarVehiclesResponse_Result{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_vehicle_v1_vehicle_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetSimilarVehiclesResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSimilarVehiclesResponse_Result) ProtoMessage() {}
+
+func (x *GetSimilarVehiclesResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_vehicle_v1_vehicle_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSimilarVehiclesResponse_Result.ProtoReflect.Descriptor instead.
+func (*GetSimilarVehiclesResponse_Result) Descriptor() ([]byte, []int) {
+	return file_vehicle_v1_vehicle_proto_rawDescGZIP(), []int{16, 0}
+}
+
+func (x *GetSimilarVehiclesResponse_Result) GetVehicle() *Vehicle {
+	if x != nil {
+		return x.Vehicle
+	}
+	return nil
+}
+
+func (x *GetSimilarVehiclesResponse_Result) GetDistance() float64 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+var File_vehicle_v1_vehicle_proto protoreflect.FileDescriptor
+
+var file_vehicle_v1_vehicle_proto_rawDesc = []byte{
+	0x0a, 0x18, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x76, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0a, 0x76, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x22, 0xef, 0x02, 0x0a, 0x07, 0x56, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x22,
+	0x0a, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x1e, 0x0a, 0x0a,
+	0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0a, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x09,
+	0x6d, 0x61, 0x78, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x08, 0x6d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x75, 0x65,
+	0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x75,
+	0x65, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x72,
+	0x61, 0x6e, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65,
+	0x6e, 0x67, 0x74, 0x68, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x01, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0xc4, 0x04, 0x0a, 0x0c, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x50, 0x61, 0x74, 0x63, 0x68, 0x12, 0x19, 0x0a, 0x05, 0x62, 0x72, 0x61,
+	0x6e, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e,
+	0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x48, 0x01, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x88, 0x01, 0x01, 0x12,
+	0x27, 0x0a, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x02, 0x52, 0x0c, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72,
+	0x88, 0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x04, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x88, 0x01, 0x01, 0x12, 0x23, 0x0a, 0x0a,
+	0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x05, 0x52, 0x0a, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x20, 0x0a, 0x09, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x06, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x20, 0x0a, 0x09, 0x66, 0x75, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x48, 0x07, 0x52, 0x08, 0x66, 0x75, 0x65, 0x6c, 0x54, 0x79,
+	0x70, 0x65, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x48, 0x08, 0x52, 0x0c, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x1b,
+	0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x01, 0x48, 0x09,
+	0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0a, 0x52, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x88, 0x01, 0x01, 0x12, 0x1b, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01, 0x48, 0x0b, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67,
+	0x74, 0x68, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x0d,
+	0x20, 0x01, 0x28, 0x01, 0x48, 0x0c, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x88, 0x01, 0x01,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x72, 0x65, 0x67, 0x69, 0x73, 0x74, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x42,
+	0x07, 0x0a, 0x05, 0x5f, 0x79, 0x65, 0x61, 0x72, 0x42, 0x0d, 0x0a, 0x0b, 0x5f, 0x70, 0x61, 0x73,
+	0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x70, 0x65, 0x65, 0x64, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x66, 0x75, 0x65, 0x6c, 0x5f, 0x74,
+	0x79, 0x70, 0x65, 0x42, 0x0f, 0x0a, 0x0d, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x6c,
+	0x65, 0x6e, 0x67, 0x74, 0x68, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22,
+	0x45, 0x0a, 0x05, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x15, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x01, 0x48, 0x00, 0x52, 0x03, 0x6d, 0x69, 0x6e, 0x88, 0x01, 0x01, 0x12,
+	0x15, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x48, 0x01, 0x52, 0x03,
+	0x6d, 0x61, 0x78, 0x88, 0x01, 0x01, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x6d, 0x69, 0x6e, 0x42, 0x06,
+	0x0a, 0x04, 0x5f, 0x6d, 0x61, 0x78, 0x22, 0xfd, 0x02, 0x0a, 0x0d, 0x56, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x72, 0x61, 0x6e,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x62, 0x72, 0x61, 0x6e, 0x64, 0x12, 0x14,
+	0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63,
+	0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x75, 0x65, 0x6c, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x75, 0x65, 0x6c, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x22, 0x0a, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x12, 0x2e, 0x0a, 0x09,
+	0x6d, 0x61, 0x78, 0x5f, 0x73, 0x70, 0x65, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x08, 0x6d, 0x61, 0x78, 0x53, 0x70, 0x65, 0x65, 0x64, 0x12, 0x29, 0x0a, 0x06,
+	0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x27, 0x0a,
+	0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x76,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52,
+	0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x22, 0x23, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x22, 0x98, 0x01, 0x0a, 0x13,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x06,
+	0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x70,
+	0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x67, 0x65, 0x5f,
+	0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x8e, 0x01, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x2f, 0x0a, 0x08, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x08, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73,
+	0x12, 0x26, 0x0a, 0x0f, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x70, 0x61, 0x67, 0x65, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50,
+	0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x74, 0x6f, 0x74, 0x61,
+	0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x22, 0x45, 0x0a, 0x14, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2d, 0x0a, 0x07, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x07, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x22, 0x48,
+	0x0a, 0x15, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x08, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x08,
+	0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x22, 0x49, 0x0a, 0x16, 0x43, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x08, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x73, 0x22, 0x56, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x2e, 0x0a, 0x05, 0x70,
+	0x61, 0x74, 0x63, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x50,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x05, 0x70, 0x61, 0x74, 0x63, 0x68, 0x22, 0x26, 0x0a, 0x14, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x02, 0x69, 0x64, 0x22, 0x17, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x41, 0x0a, 0x15,
+	0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x22,
+	0xac, 0x01, 0x0a, 0x16, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x43, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x76, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x1a,
+	0x4d, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x76, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52,
+	0x07, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0x39,
+	0x0a, 0x19, 0x47, 0x65, 0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x56, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69, 0x64, 0x12, 0x0c, 0x0a, 0x01, 0x6b,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x6b, 0x22, 0xba, 0x01, 0x0a, 0x1a, 0x47, 0x65,
+	0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x07, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x76, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61,
+	0x72, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x1a, 0x53, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2d, 0x0a, 0x07, 0x76,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x52, 0x07, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x22, 0x7d, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x67, 0x72, 0x6f,
+	0x75, 0x70, 0x5f, 0x62, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x76, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x07, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x42, 0x79,
+	0x12, 0x33, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1b, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x41, 0x67,
+	0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x52, 0x06, 0x6d,
+	0x65, 0x74, 0x72, 0x69, 0x63, 0x22, 0x83, 0x01, 0x0a, 0x09, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6d,
+	0x65, 0x61, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x6d, 0x65, 0x61, 0x6e, 0x12,
+	0x10, 0x0a, 0x03, 0x6d, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x6d, 0x69,
+	0x6e, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x78, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03,
+	0x6d, 0x61, 0x78, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x64, 0x64, 0x65, 0x76, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x06, 0x73, 0x74, 0x64, 0x64, 0x65, 0x76, 0x22, 0x49, 0x0a, 0x10, 0x47,
+	0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x35, 0x0a, 0x0a, 0x61, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x52, 0x0a, 0x61, 0x67, 0x67, 0x72,
+	0x65, 0x67, 0x61, 0x74, 0x65, 0x73, 0x22, 0x4a, 0x0a, 0x14, 0x57, 0x61, 0x74, 0x63, 0x68, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32,
+	0x0a, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1c, 0x2e,
+	0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70,
+	0x65, 0x73, 0x22, 0xa0, 0x01, 0x0a, 0x0c, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1c, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2d, 0x0a, 0x07, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x07, 0x76, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x08, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x2a, 0x85, 0x01, 0x0a, 0x0e, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67,
+	0x61, 0x74, 0x65, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1f, 0x0a, 0x1b, 0x41, 0x47, 0x47, 0x52,
+	0x45, 0x47, 0x41, 0x54, 0x45, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x19, 0x0a, 0x15, 0x41, 0x47, 0x47,
+	0x52, 0x45, 0x47, 0x41, 0x54, 0x45, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x42, 0x52, 0x41,
+	0x4e, 0x44, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54,
+	0x45, 0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x46, 0x55, 0x45, 0x4c, 0x5f, 0x54, 0x59, 0x50,
+	0x45, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45,
+	0x5f, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x59, 0x45, 0x41, 0x52, 0x10, 0x03, 0x2a, 0x8f, 0x01,
+	0x0a, 0x0f, 0x41, 0x67, 0x67, 0x72, 0x65, 0x67, 0x61, 0x74, 0x65, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x12, 0x20, 0x0a, 0x1c, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45, 0x5f, 0x4d,
+	0x45, 0x54, 0x52, 0x49, 0x43, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45,
+	0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x5f, 0x4d, 0x41, 0x58, 0x5f, 0x53, 0x50, 0x45, 0x45,
+	0x44, 0x10, 0x01, 0x12, 0x1d, 0x0a, 0x19, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45,
+	0x5f, 0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x5f, 0x43, 0x41, 0x50, 0x41, 0x43, 0x49, 0x54, 0x59,
+	0x10, 0x02, 0x12, 0x1b, 0x0a, 0x17, 0x41, 0x47, 0x47, 0x52, 0x45, 0x47, 0x41, 0x54, 0x45, 0x5f,
+	0x4d, 0x45, 0x54, 0x52, 0x49, 0x43, 0x5f, 0x57, 0x45, 0x49, 0x47, 0x48, 0x54, 0x10, 0x03, 0x2a,
+	0x96, 0x01, 0x0a, 0x10, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x22, 0x0a, 0x1e, 0x56, 0x45, 0x48, 0x49, 0x43, 0x4c, 0x45, 0x5f,
+	0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45,
+	0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1e, 0x0a, 0x1a, 0x56, 0x45, 0x48, 0x49,
+	0x43, 0x4c, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x43,
+	0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x56, 0x45, 0x48, 0x49,
+	0x43, 0x4c, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x55,
+	0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x56, 0x45, 0x48, 0x49,
+	0x43, 0x4c, 0x45, 0x5f, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f, 0x44,
+	0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x32, 0x82, 0x07, 0x0a, 0x0e, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0a, 0x47,
+	0x65, 0x74, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x1d, 0x2e, 0x76, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x51, 0x0a,
+	0x0c, 0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x1f, 0x2e,
+	0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20,
+	0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x73, 0x74,
+	0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x48, 0x0a, 0x0e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x73, 0x12, 0x1f, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e,
+	0x4c, 0x69, 0x73, 0x74, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x30, 0x01, 0x12, 0x46, 0x0a, 0x0d, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x20, 0x2e, 0x76, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x12, 0x57, 0x0a, 0x0e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0d, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x12, 0x20, 0x2e, 0x76,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13,
+	0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x68, 0x69,
+	0x63, 0x6c, 0x65, 0x12, 0x54, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x65, 0x68,
+	0x69, 0x63, 0x6c, 0x65, 0x12, 0x20, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76,
+	0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x57, 0x0a, 0x0e, 0x53, 0x65, 0x61,
+	0x72, 0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x76, 0x65,
+	0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22,
+	0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x63, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72,
+	0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x12, 0x25, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63,
+	0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72,
+	0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x26, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74,
+	0x53, 0x69, 0x6d, 0x69, 0x6c, 0x61, 0x72, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x08, 0x47, 0x65, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x73, 0x12, 0x1b, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31,
+	0x2e, 0x47, 0x65, 0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1c, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65,
+	0x74, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d,
+	0x0a, 0x0d, 0x57, 0x61, 0x74, 0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x12,
+	0x20, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74,
+	0x63, 0x68, 0x56, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x18, 0x2e, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x56,
+	0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x42, 0x2d, 0x5a,
+	0x2b, 0x61, 0x70, 0x70, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72,
+	0x70, 0x63, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65,
+	0x70, 0x62, 0x3b, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_vehicle_v1_vehicle_proto_rawDescOnce sync.Once
+	file_vehicle_v1_vehicle_proto_rawDescData = file_vehicle_v1_vehicle_proto_rawDesc
+)
+
+func file_vehicle_v1_vehicle_proto_rawDescGZIP() []byte {
+	file_vehicle_v1_vehicle_proto_rawDescOnce.Do(func() {
+		file_vehicle_v1_vehicle_proto_rawDescData = protoimpl.X.CompressGZIP(file_vehicle_v1_vehicle_proto_rawDescData)
+	})
+	return file_vehicle_v1_vehicle_proto_rawDescData
+}
+
+var file_vehicle_v1_vehicle_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_vehicle_v1_vehicle_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_vehicle_v1_vehicle_proto_goTypes = []any{
+	(AggregateGroup)(0),                       // 0: vehicle.v1.AggregateGroup
+	(AggregateMetric)(0),                      // 1: vehicle.v1.AggregateMetric
+	(VehicleEventType)(0),                     // 2: vehicle.v1.VehicleEventType
+	(*Vehicle)(nil),                           // 3: vehicle.v1.Vehicle
+	(*VehiclePatch)(nil),                      // 4: vehicle.v1.VehiclePatch
+	(*Range)(nil),                             // 5: vehicle.v1.Range
+	(*VehicleFilter)(nil),                     // 6: vehicle.v1.VehicleFilter
+	(*GetVehicleRequest)(nil),                 // 7: vehicle.v1.GetVehicleRequest
+	(*ListVehiclesRequest)(nil),               // 8: vehicle.v1.ListVehiclesRequest
+	(*ListVehiclesResponse)(nil),              // 9: vehicle.v1.ListVehiclesResponse
+	(*CreateVehicleRequest)(nil),              // 10: vehicle.v1.CreateVehicleRequest
+	(*CreateVehiclesRequest)(nil),             // 11: vehicle.v1.CreateVehiclesRequest
+	(*CreateVehiclesResponse)(nil),            // 12: vehicle.v1.CreateVehiclesResponse
+	(*UpdateVehicleRequest)(nil),              // 13: vehicle.v1.UpdateVehicleRequest
+	(*DeleteVehicleRequest)(nil),              // 14: vehicle.v1.DeleteVehicleRequest
+	(*DeleteVehicleResponse)(nil),             // 15: vehicle.v1.DeleteVehicleResponse
+	(*SearchVehiclesRequest)(nil),             // 16: vehicle.v1.SearchVehiclesRequest
+	(*SearchVehiclesResponse)(nil),            // 17: vehicle.v1.SearchVehiclesResponse
+	(*GetSimilarVehiclesRequest)(nil),         // 18: vehicle.v1.GetSimilarVehiclesRequest
+	(*GetSimilarVehiclesResponse)(nil),        // 19: vehicle.v1.GetSimilarVehiclesResponse
+	(*GetStatsRequest)(nil),                   // 20: vehicle.v1.GetStatsRequest
+	(*Aggregate)(nil),                         // 21: vehicle.v1.Aggregate
+	(*GetStatsResponse)(nil),                  // 22: vehicle.v1.GetStatsResponse
+	(*WatchVehiclesRequest)(nil),              // 23: vehicle.v1.WatchVehiclesRequest
+	(*VehicleEvent)(nil),                      // 24: vehicle.v1.VehicleEvent
+	(*SearchVehiclesResponse_Result)(nil),     // 25: vehicle.v1.SearchVehiclesResponse.Result
+	(*GetSimilarVehiclesResponse_Result)(nil), // 26: vehicle.v1.GetSimilarVehiclesResponse.Result
+}
+var file_vehicle_v1_vehicle_proto_depIdxs = []int32{
+	5,  // 0: vehicle.v1.VehicleFilter.year:type_name -> vehicle.v1.Range
+	5,  // 1: vehicle.v1.VehicleFilter.max_speed:type_name -> vehicle.v1.Range
+	5,  // 2: vehicle.v1.VehicleFilter.weight:type_name -> vehicle.v1.Range
+	5,  // 3: vehicle.v1.VehicleFilter.height:type_name -> vehicle.v1.Range
+	5,  // 4: vehicle.v1.VehicleFilter.length:type_name -> vehicle.v1.Range
+	5,  // 5: vehicle.v1.VehicleFilter.width:type_name -> vehicle.v1.Range
+	6,  // 6: vehicle.v1.ListVehiclesRequest.filter:type_name -> vehicle.v1.VehicleFilter
+	3,  // 7: vehicle.v1.ListVehiclesResponse.vehicles:type_name -> vehicle.v1.Vehicle
+	3,  // 8: vehicle.v1.CreateVehicleRequest.vehicle:type_name -> vehicle.v1.Vehicle
+	3,  // 9: vehicle.v1.CreateVehiclesRequest.vehicles:type_name -> vehicle.v1.Vehicle
+	3,  // 10: vehicle.v1.CreateVehiclesResponse.vehicles:type_name -> vehicle.v1.Vehicle
+	4,  // 11: vehicle.v1.UpdateVehicleRequest.patch:type_name -> vehicle.v1.VehiclePatch
+	25, // 12: vehicle.v1.SearchVehiclesResponse.results:type_name -> vehicle.v1.SearchVehiclesResponse.Result
+	26, // 13: vehicle.v1.GetSimilarVehiclesResponse.results:type_name -> vehicle.v1.GetSimilarVehiclesResponse.Result
+	0,  // 14: vehicle.v1.GetStatsRequest.group_by:type_name -> vehicle.v1.AggregateGroup
+	1,  // 15: vehicle.v1.GetStatsRequest.metric:type_name -> vehicle.v1.AggregateMetric
+	21, // 16: vehicle.v1.GetStatsResponse.aggregates:type_name -> vehicle.v1.Aggregate
+	2,  // 17: vehicle.v1.WatchVehiclesRequest.types:type_name -> vehicle.v1.VehicleEventType
+	2,  // 18: vehicle.v1.VehicleEvent.type:type_name -> vehicle.v1.VehicleEventType
+	3,  // 19: vehicle.v1.VehicleEvent.vehicle:type_name -> vehicle.v1.Vehicle
+	3,  // 20: vehicle.v1.VehicleEvent.previous:type_name -> vehicle.v1.Vehicle
+	3,  // 21: vehicle.v1.SearchVehiclesResponse.Result.vehicle:type_name -> vehicle.v1.Vehicle
+	3,  // 22: vehicle.v1.GetSimilarVehiclesResponse.Result.vehicle:type_name -> vehicle.v1.Vehicle
+	7,  // 23: vehicle.v1.VehicleService.GetVehicle:input_type -> vehicle.v1.GetVehicleRequest
+	8,  // 24: vehicle.v1.VehicleService.ListVehicles:input_type -> vehicle.v1.ListVehiclesRequest
+	8,  // 25: vehicle.v1.VehicleService.StreamVehicles:input_type -> vehicle.v1.ListVehiclesRequest
+	10, // 26: vehicle.v1.VehicleService.CreateVehicle:input_type -> vehicle.v1.CreateVehicleRequest
+	11, // 27: vehicle.v1.VehicleService.CreateVehicles:input_type -> vehicle.v1.CreateVehiclesRequest
+	13, // 28: vehicle.v1.VehicleService.UpdateVehicle:input_type -> vehicle.v1.UpdateVehicleRequest
+	14, // 29: vehicle.v1.VehicleService.DeleteVehicle:input_type -> vehicle.v1.DeleteVehicleRequest
+	16, // 30: vehicle.v1.VehicleService.SearchVehicles:input_type -> vehicle.v1.SearchVehiclesRequest
+	18, // 31: vehicle.v1.VehicleService.GetSimilarVehicles:input_type -> vehicle.v1.GetSimilarVehiclesRequest
+	20, // 32: vehicle.v1.VehicleService.GetStats:input_type -> vehicle.v1.GetStatsRequest
+	23, // 33: vehicle.v1.VehicleService.WatchVehicles:input_type -> vehicle.v1.WatchVehiclesRequest
+	3,  // 34: vehicle.v1.VehicleService.GetVehicle:output_type -> vehicle.v1.Vehicle
+	9,  // 35: vehicle.v1.VehicleService.ListVehicles:output_type -> vehicle.v1.ListVehiclesResponse
+	3,  // 36: vehicle.v1.VehicleService.StreamVehicles:output_type -> vehicle.v1.Vehicle
+	3,  // 37: vehicle.v1.VehicleService.CreateVehicle:output_type -> vehicle.v1.Vehicle
+	12, // 38: vehicle.v1.VehicleService.CreateVehicles:output_type -> vehicle.v1.CreateVehiclesResponse
+	3,  // 39: vehicle.v1.VehicleService.UpdateVehicle:output_type -> vehicle.v1.Vehicle
+	15, // 40: vehicle.v1.VehicleService.DeleteVehicle:output_type -> vehicle.v1.DeleteVehicleResponse
+	17, // 41: vehicle.v1.VehicleService.SearchVehicles:output_type -> vehicle.v1.SearchVehiclesResponse
+	19, // 42: vehicle.v1.VehicleService.GetSimilarVehicles:output_type -> vehicle.v1.GetSimilarVehiclesResponse
+	22, // 43: vehicle.v1.VehicleService.GetStats:output_type -> vehicle.v1.GetStatsResponse
+	24, // 44: vehicle.v1.VehicleService.WatchVehicles:output_type -> vehicle.v1.VehicleEvent
+	34, // [34:45] is the sub-list for method output_type
+	23, // [23:34] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_vehicle_v1_vehicle_proto_init() }
+func file_vehicle_v1_vehicle_proto_init() {
+	if File_vehicle_v1_vehicle_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_vehicle_v1_vehicle_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*Vehicle); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*VehiclePatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*Range); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*VehicleFilter); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetVehicleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ListVehiclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*ListVehiclesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateVehicleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateVehiclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateVehiclesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateVehicleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteVehicleRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteVehicleResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchVehiclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchVehiclesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSimilarVehiclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSimilarVehiclesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStatsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*Aggregate); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*GetStatsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[20].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchVehiclesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[21].Exporter = func(v any, i int) any {
+			switch v := v.(*VehicleEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[22].Exporter = func(v any, i int) any {
+			switch v := v.(*SearchVehiclesResponse_Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_vehicle_v1_vehicle_proto_msgTypes[23].Exporter = func(v any, i int) any {
+			switch v := v.(*GetSimilarVehiclesResponse_Result); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_vehicle_v1_vehicle_proto_msgTypes[1].OneofWrappers = []any{}
+	file_vehicle_v1_vehicle_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_vehicle_v1_vehicle_proto_rawDesc,
+			NumEnums:      3,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_vehicle_v1_vehicle_proto_goTypes,
+		DependencyIndexes: file_vehicle_v1_vehicle_proto_depIdxs,
+		EnumInfos:         file_vehicle_v1_vehicle_proto_enumTypes,
+		MessageInfos:      file_vehicle_v1_vehicle_proto_msgTypes,
+	}.Build()
+	File_vehicle_v1_vehicle_proto = out.File
+	file_vehicle_v1_vehicle_proto_rawDesc = nil
+	file_vehicle_v1_vehicle_proto_goTypes = nil
+	file_vehicle_v1_vehicle_proto_depIdxs = nil
+}
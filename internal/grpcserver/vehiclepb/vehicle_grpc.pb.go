@@ -0,0 +1,538 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: vehicle/v1/vehicle.proto
+
+package vehiclepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	VehicleService_GetVehicle_FullMethodName         = "/vehicle.v1.VehicleService/GetVehicle"
+	VehicleService_ListVehicles_FullMethodName       = "/vehicle.v1.VehicleService/ListVehicles"
+	VehicleService_StreamVehicles_FullMethodName     = "/vehicle.v1.VehicleService/StreamVehicles"
+	VehicleService_CreateVehicle_FullMethodName      = "/vehicle.v1.VehicleService/CreateVehicle"
+	VehicleService_CreateVehicles_FullMethodName     = "/vehicle.v1.VehicleService/CreateVehicles"
+	VehicleService_UpdateVehicle_FullMethodName      = "/vehicle.v1.VehicleService/UpdateVehicle"
+	VehicleService_DeleteVehicle_FullMethodName      = "/vehicle.v1.VehicleService/DeleteVehicle"
+	VehicleService_SearchVehicles_FullMethodName     = "/vehicle.v1.VehicleService/SearchVehicles"
+	VehicleService_GetSimilarVehicles_FullMethodName = "/vehicle.v1.VehicleService/GetSimilarVehicles"
+	VehicleService_GetStats_FullMethodName           = "/vehicle.v1.VehicleService/GetStats"
+	VehicleService_WatchVehicles_FullMethodName      = "/vehicle.v1.VehicleService/WatchVehicles"
+)
+
+// VehicleServiceClient is the client API for VehicleService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// VehicleService serves the vehicles of the fleet of the tenant of the caller, as the HTTP API does.
+// Measures are in metric units: km/h, kg and cm.
+type VehicleServiceClient interface {
+	// GetVehicle returns the vehicle with the id.
+	GetVehicle(ctx context.Context, in *GetVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error)
+	// ListVehicles returns a page of the vehicles matching the filter.
+	ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error)
+	// StreamVehicles streams the vehicles matching the filter, the page size and token are ignored.
+	StreamVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vehicle], error)
+	// CreateVehicle creates a vehicle and returns it.
+	CreateVehicle(ctx context.Context, in *CreateVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error)
+	// CreateVehicles creates every vehicle or none.
+	CreateVehicles(ctx context.Context, in *CreateVehiclesRequest, opts ...grpc.CallOption) (*CreateVehiclesResponse, error)
+	// UpdateVehicle changes the attributes present in the patch and returns the vehicle.
+	UpdateVehicle(ctx context.Context, in *UpdateVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error)
+	// DeleteVehicle deletes the vehicle with the id.
+	DeleteVehicle(ctx context.Context, in *DeleteVehicleRequest, opts ...grpc.CallOption) (*DeleteVehicleResponse, error)
+	// SearchVehicles returns the vehicles matching the text, sorted by relevance.
+	SearchVehicles(ctx context.Context, in *SearchVehiclesRequest, opts ...grpc.CallOption) (*SearchVehiclesResponse, error)
+	// GetSimilarVehicles returns the vehicles most similar to the vehicle with the id.
+	GetSimilarVehicles(ctx context.Context, in *GetSimilarVehiclesRequest, opts ...grpc.CallOption) (*GetSimilarVehiclesResponse, error)
+	// GetStats returns the statistics of a metric for every key of a group.
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// WatchVehicles streams the changes made to the vehicles from now on.
+	// The stream ends with RESOURCE_EXHAUSTED if the caller falls behind.
+	WatchVehicles(ctx context.Context, in *WatchVehiclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VehicleEvent], error)
+}
+
+type vehicleServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVehicleServiceClient(cc grpc.ClientConnInterface) VehicleServiceClient {
+	return &vehicleServiceClient{cc}
+}
+
+func (c *vehicleServiceClient) GetVehicle(ctx context.Context, in *GetVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vehicle)
+	err := c.cc.Invoke(ctx, VehicleService_GetVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) ListVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (*ListVehiclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVehiclesResponse)
+	err := c.cc.Invoke(ctx, VehicleService_ListVehicles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) StreamVehicles(ctx context.Context, in *ListVehiclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Vehicle], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VehicleService_ServiceDesc.Streams[0], VehicleService_StreamVehicles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListVehiclesRequest, Vehicle]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VehicleService_StreamVehiclesClient = grpc.ServerStreamingClient[Vehicle]
+
+func (c *vehicleServiceClient) CreateVehicle(ctx context.Context, in *CreateVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vehicle)
+	err := c.cc.Invoke(ctx, VehicleService_CreateVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) CreateVehicles(ctx context.Context, in *CreateVehiclesRequest, opts ...grpc.CallOption) (*CreateVehiclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVehiclesResponse)
+	err := c.cc.Invoke(ctx, VehicleService_CreateVehicles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) UpdateVehicle(ctx context.Context, in *UpdateVehicleRequest, opts ...grpc.CallOption) (*Vehicle, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Vehicle)
+	err := c.cc.Invoke(ctx, VehicleService_UpdateVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) DeleteVehicle(ctx context.Context, in *DeleteVehicleRequest, opts ...grpc.CallOption) (*DeleteVehicleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVehicleResponse)
+	err := c.cc.Invoke(ctx, VehicleService_DeleteVehicle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) SearchVehicles(ctx context.Context, in *SearchVehiclesRequest, opts ...grpc.CallOption) (*SearchVehiclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchVehiclesResponse)
+	err := c.cc.Invoke(ctx, VehicleService_SearchVehicles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) GetSimilarVehicles(ctx context.Context, in *GetSimilarVehiclesRequest, opts ...grpc.CallOption) (*GetSimilarVehiclesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSimilarVehiclesResponse)
+	err := c.cc.Invoke(ctx, VehicleService_GetSimilarVehicles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatsResponse)
+	err := c.cc.Invoke(ctx, VehicleService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *vehicleServiceClient) WatchVehicles(ctx context.Context, in *WatchVehiclesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[VehicleEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &VehicleService_ServiceDesc.Streams[1], VehicleService_WatchVehicles_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchVehiclesRequest, VehicleEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VehicleService_WatchVehiclesClient = grpc.ServerStreamingClient[VehicleEvent]
+
+// VehicleServiceServer is the server API for VehicleService service.
+// All implementations must embed UnimplementedVehicleServiceServer
+// for forward compatibility.
+//
+// VehicleService serves the vehicles of the fleet of the tenant of the caller, as the HTTP API does.
+// Measures are in metric units: km/h, kg and cm.
+type VehicleServiceServer interface {
+	// GetVehicle returns the vehicle with the id.
+	GetVehicle(context.Context, *GetVehicleRequest) (*Vehicle, error)
+	// ListVehicles returns a page of the vehicles matching the filter.
+	ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error)
+	// StreamVehicles streams the vehicles matching the filter, the page size and token are ignored.
+	StreamVehicles(*ListVehiclesRequest, grpc.ServerStreamingServer[Vehicle]) error
+	// CreateVehicle creates a vehicle and returns it.
+	CreateVehicle(context.Context, *CreateVehicleRequest) (*Vehicle, error)
+	// CreateVehicles creates every vehicle or none.
+	CreateVehicles(context.Context, *CreateVehiclesRequest) (*CreateVehiclesResponse, error)
+	// UpdateVehicle changes the attributes present in the patch and returns the vehicle.
+	UpdateVehicle(context.Context, *UpdateVehicleRequest) (*Vehicle, error)
+	// DeleteVehicle deletes the vehicle with the id.
+	DeleteVehicle(context.Context, *DeleteVehicleRequest) (*DeleteVehicleResponse, error)
+	// SearchVehicles returns the vehicles matching the text, sorted by relevance.
+	SearchVehicles(context.Context, *SearchVehiclesRequest) (*SearchVehiclesResponse, error)
+	// GetSimilarVehicles returns the vehicles most similar to the vehicle with the id.
+	GetSimilarVehicles(context.Context, *GetSimilarVehiclesRequest) (*GetSimilarVehiclesResponse, error)
+	// GetStats returns the statistics of a metric for every key of a group.
+	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// WatchVehicles streams the changes made to the vehicles from now on.
+	// The stream ends with RESOURCE_EXHAUSTED if the caller falls behind.
+	WatchVehicles(*WatchVehiclesRequest, grpc.ServerStreamingServer[VehicleEvent]) error
+	mustEmbedUnimplementedVehicleServiceServer()
+}
+
+// UnimplementedVehicleServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVehicleServiceServer struct{}
+
+func (UnimplementedVehicleServiceServer) GetVehicle(context.Context, *GetVehicleRequest) (*Vehicle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVehicle not implemented")
+}
+func (UnimplementedVehicleServiceServer) ListVehicles(context.Context, *ListVehiclesRequest) (*ListVehiclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) StreamVehicles(*ListVehiclesRequest, grpc.ServerStreamingServer[Vehicle]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) CreateVehicle(context.Context, *CreateVehicleRequest) (*Vehicle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVehicle not implemented")
+}
+func (UnimplementedVehicleServiceServer) CreateVehicles(context.Context, *CreateVehiclesRequest) (*CreateVehiclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) UpdateVehicle(context.Context, *UpdateVehicleRequest) (*Vehicle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVehicle not implemented")
+}
+func (UnimplementedVehicleServiceServer) DeleteVehicle(context.Context, *DeleteVehicleRequest) (*DeleteVehicleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVehicle not implemented")
+}
+func (UnimplementedVehicleServiceServer) SearchVehicles(context.Context, *SearchVehiclesRequest) (*SearchVehiclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) GetSimilarVehicles(context.Context, *GetSimilarVehiclesRequest) (*GetSimilarVehiclesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSimilarVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedVehicleServiceServer) WatchVehicles(*WatchVehiclesRequest, grpc.ServerStreamingServer[VehicleEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchVehicles not implemented")
+}
+func (UnimplementedVehicleServiceServer) mustEmbedUnimplementedVehicleServiceServer() {}
+func (UnimplementedVehicleServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeVehicleServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VehicleServiceServer will
+// result in compilation errors.
+type UnsafeVehicleServiceServer interface {
+	mustEmbedUnimplementedVehicleServiceServer()
+}
+
+func RegisterVehicleServiceServer(s grpc.ServiceRegistrar, srv VehicleServiceServer) {
+	// If the following call pancis, it indicates UnimplementedVehicleServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&VehicleService_ServiceDesc, srv)
+}
+
+func _VehicleService_GetVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).GetVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_GetVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).GetVehicle(ctx, req.(*GetVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_ListVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVehiclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).ListVehicles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_ListVehicles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).ListVehicles(ctx, req.(*ListVehiclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_StreamVehicles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListVehiclesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VehicleServiceServer).StreamVehicles(m, &grpc.GenericServerStream[ListVehiclesRequest, Vehicle]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VehicleService_StreamVehiclesServer = grpc.ServerStreamingServer[Vehicle]
+
+func _VehicleService_CreateVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).CreateVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_CreateVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).CreateVehicle(ctx, req.(*CreateVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_CreateVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVehiclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).CreateVehicles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_CreateVehicles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).CreateVehicles(ctx, req.(*CreateVehiclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_UpdateVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).UpdateVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_UpdateVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).UpdateVehicle(ctx, req.(*UpdateVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_DeleteVehicle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVehicleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).DeleteVehicle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_DeleteVehicle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).DeleteVehicle(ctx, req.(*DeleteVehicleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_SearchVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchVehiclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).SearchVehicles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_SearchVehicles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).SearchVehicles(ctx, req.(*SearchVehiclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_GetSimilarVehicles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSimilarVehiclesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).GetSimilarVehicles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_GetSimilarVehicles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).GetSimilarVehicles(ctx, req.(*GetSimilarVehiclesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VehicleServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VehicleService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VehicleServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _VehicleService_WatchVehicles_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchVehiclesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(VehicleServiceServer).WatchVehicles(m, &grpc.GenericServerStream[WatchVehiclesRequest, VehicleEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type VehicleService_WatchVehiclesServer = grpc.ServerStreamingServer[VehicleEvent]
+
+// VehicleService_ServiceDesc is the grpc.ServiceDesc for VehicleService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var VehicleService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vehicle.v1.VehicleService",
+	HandlerType: (*VehicleServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVehicle",
+			Handler:    _VehicleService_GetVehicle_Handler,
+		},
+		{
+			MethodName: "ListVehicles",
+			Handler:    _VehicleService_ListVehicles_Handler,
+		},
+		{
+			MethodName: "CreateVehicle",
+			Handler:    _VehicleService_CreateVehicle_Handler,
+		},
+		{
+			MethodName: "CreateVehicles",
+			Handler:    _VehicleService_CreateVehicles_Handler,
+		},
+		{
+			MethodName: "UpdateVehicle",
+			Handler:    _VehicleService_UpdateVehicle_Handler,
+		},
+		{
+			MethodName: "DeleteVehicle",
+			Handler:    _VehicleService_DeleteVehicle_Handler,
+		},
+		{
+			MethodName: "SearchVehicles",
+			Handler:    _VehicleService_SearchVehicles_Handler,
+		},
+		{
+			MethodName: "GetSimilarVehicles",
+			Handler:    _VehicleService_GetSimilarVehicles_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _VehicleService_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamVehicles",
+			Handler:       _VehicleService_StreamVehicles_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchVehicles",
+			Handler:       _VehicleService_WatchVehicles_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "vehicle/v1/vehicle.proto",
+}
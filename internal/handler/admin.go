@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"app/internal/logger"
+	"app/internal/render"
+	"app/internal/tenant"
+	"log/slog"
+	"net/http"
+)
+
+// CacheStatsJSON is a struct that represents the metrics of a cache in JSON format
+type CacheStatsJSON struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Expirations   uint64 `json:"expirations"`
+	Invalidations uint64 `json:"invalidations"`
+	Entries       int    `json:"entries"`
+}
+
+// AggregateJSON is a struct that represents the statistics of a metric in JSON format
+type AggregateJSON struct {
+	Count      int     `json:"count"`
+	Sum        float64 `json:"sum"`
+	SumSquares float64 `json:"sum_squares"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+}
+
+// AggregateMismatchJSON is a struct that represents an inconsistent aggregate in JSON format
+type AggregateMismatchJSON struct {
+	Group    string        `json:"group"`
+	Key      string        `json:"key"`
+	Metric   string        `json:"metric"`
+	Expected AggregateJSON `json:"expected"`
+	Actual   AggregateJSON `json:"actual"`
+}
+
+// LogLevelJSON is a struct that represents the level of the logger in JSON format
+type LogLevelJSON struct {
+	Level string `json:"level"`
+}
+
+// NewAdminDefault is a function that returns a new instance of AdminDefault
+func NewAdminDefault(tenants *tenant.Registry, logLevel *slog.LevelVar) *AdminDefault {
+	return &AdminDefault{tenants: tenants, logLevel: logLevel}
+}
+
+// AdminDefault is a struct with methods that represent handlers for the administration of the application
+// - the cache and the aggregates are the ones of the fleet of the tenant of the request
+type AdminDefault struct {
+	// tenants are the fleets of the tenants
+	tenants *tenant.Registry
+	// logLevel is the level of the logger of the application
+	logLevel *slog.LevelVar
+}
+
+// GetCacheStats is a method that returns a handler for the route GET /admin/cache
+func (h *AdminDefault) GetCacheStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := h.tenants.FromContext(r.Context())
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+		if f.Cache == nil {
+			render.Error(w, r, http.StatusNotFound, "cache disabled")
+			return
+		}
+
+		stats := f.Cache.Stats()
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data": CacheStatsJSON{
+				Hits:          stats.Hits,
+				Misses:        stats.Misses,
+				Evictions:     stats.Evictions,
+				Expirations:   stats.Expirations,
+				Invalidations: stats.Invalidations,
+				Entries:       stats.Entries,
+			},
+		})
+	}
+}
+
+// CheckAggregates is a method that returns a handler for the route GET /admin/aggregates/check
+func (h *AdminDefault) CheckAggregates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := h.tenants.FromContext(r.Context())
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		mismatches, err := f.Checker.CheckAggregates(r.Context())
+		if err != nil {
+			render.Error(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		data := make([]AggregateMismatchJSON, 0, len(mismatches))
+		for _, m := range mismatches {
+			data = append(data, AggregateMismatchJSON{
+				Group:    string(m.Group),
+				Key:      m.Key,
+				Metric:   string(m.Metric),
+				Expected: AggregateJSON(m.Expected),
+				Actual:   AggregateJSON(m.Actual),
+			})
+		}
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message":    "success",
+			"consistent": len(data) == 0,
+			"data":       data,
+		})
+	}
+}
+
+// GetLogLevel is a method that returns a handler for the route GET /admin/log_level
+func (h *AdminDefault) GetLogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    LogLevelJSON{Level: h.logLevel.Level().String()},
+		})
+	}
+}
+
+// UpdateLogLevel is a method that returns a handler for the route PUT /admin/log_level
+func (h *AdminDefault) UpdateLogLevel() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input LogLevelJSON
+		err := render.Decode(r, &input)
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(input.Level)); err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid level")
+			return
+		}
+		h.logLevel.Set(level)
+
+		logger.FromContext(r.Context()).Info("log level updated", slog.String("level", level.String()))
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    LogLevelJSON{Level: level.String()},
+		})
+	}
+}
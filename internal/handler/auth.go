@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"app/internal/auth"
+	"app/internal/render"
+	"net/http"
+)
+
+// PrincipalJSON is a struct that represents an authenticated caller in JSON format
+type PrincipalJSON struct {
+	Subject string   `json:"subject"`
+	Method  string   `json:"method"`
+	KeyID   string   `json:"key_id,omitempty"`
+	Roles   []string `json:"roles"`
+	Tenant  string   `json:"tenant,omitempty"`
+}
+
+// NewAuthDefault is a function that returns a new instance of AuthDefault
+func NewAuthDefault() *AuthDefault {
+	return &AuthDefault{}
+}
+
+// AuthDefault is a struct with methods that represent handlers for the authentication of the caller
+type AuthDefault struct{}
+
+// GetPrincipal is a method that returns a handler for the route GET /auth/whoami
+func (h *AuthDefault) GetPrincipal() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, ok := auth.PrincipalFromContext(r.Context())
+		if !ok {
+			render.Error(w, r, http.StatusNotFound, "authentication disabled")
+			return
+		}
+
+		roles := p.Roles
+		if roles == nil {
+			roles = []string{}
+		}
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data": PrincipalJSON{
+				Subject: p.Subject,
+				Method:  string(p.Method),
+				KeyID:   p.KeyID,
+				Roles:   roles,
+				Tenant:  p.Tenant,
+			},
+		})
+	}
+}
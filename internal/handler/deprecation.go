@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Deprecated is a function that returns a middleware announcing the deprecation of the routes it serves
+// - Deprecation is the date the routes were deprecated (RFC 9745), Sunset the date they stop being served (RFC 8594)
+// - successor is the path of the routes replacing them, linked with the successor-version relation
+// - zero dates are not announced
+func Deprecated(deprecation, sunset time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !deprecation.IsZero() {
+				w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecation.Unix(), 10))
+			}
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor != "" {
+				w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"app/internal/graphql"
+	"app/internal/render"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQLRequestJSON is a struct that represents the body of a GraphQL request in JSON format
+type GraphQLRequestJSON struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// keepAlive is the interval of the comments written to an idle event stream, so proxies keep it open
+const keepAlive = 15 * time.Second
+
+// NewGraphQL is a function that returns a new instance of GraphQL
+// - policy grants the permissions of the fields, nil if authorization is disabled
+// - maxDepth and maxComplexity limit the queries, zero means unlimited
+func NewGraphQL(sv internal.VehicleService, policy *auth.Policy, maxDepth, maxComplexity int) (h *GraphQL, err error) {
+	schema, err := graphql.NewSchema(graphql.NewResolver(sv, policy), maxDepth)
+	if err != nil {
+		return
+	}
+	h = &GraphQL{schema: schema, maxComplexity: maxComplexity}
+	return
+}
+
+// GraphQL is a struct with methods that represent the handlers of the GraphQL route
+// - responses are always JSON, subscriptions are served as server-sent events
+type GraphQL struct {
+	// schema is the schema of the vehicles
+	schema *gql.Schema
+	// maxComplexity is the maximum complexity of a query, zero means unlimited
+	maxComplexity int
+}
+
+// Serve is a method that returns a handler for the routes GET and POST /graphql
+// - GET takes the query, operationName and variables (JSON) from the query parameters, for queries only
+// - POST takes them from a GraphQLRequestJSON body
+// - with Accept: text/event-stream the responses are streamed, as subscriptions require
+func (h *GraphQL) Serve() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		var body GraphQLRequestJSON
+		switch r.Method {
+		case http.MethodGet:
+			params := r.URL.Query()
+			body.Query, body.OperationName = params.Get("query"), params.Get("operationName")
+			if v := params.Get("variables"); v != "" {
+				if err := json.Unmarshal([]byte(v), &body.Variables); err != nil {
+					render.Error(w, r, http.StatusBadRequest, "invalid variables")
+					return
+				}
+			}
+		default:
+			if err := render.Decode(r, &body); err != nil {
+				decodeError(w, r, err)
+				return
+			}
+		}
+		if strings.TrimSpace(body.Query) == "" {
+			render.Error(w, r, http.StatusBadRequest, "missing query")
+			return
+		}
+
+		// limits
+		// - queries that do not parse are left to the execution, which reports their errors
+		operation, complexity, err := graphql.Complexity(body.Query, body.OperationName, body.Variables)
+		if err == nil {
+			if h.maxComplexity > 0 && complexity > h.maxComplexity {
+				render.Respond(w, r, http.StatusOK, &gql.Response{Errors: []*gqlerrors.QueryError{{
+					Message:    fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, h.maxComplexity),
+					Extensions: map[string]interface{}{"code": "COMPLEXITY_LIMIT"},
+				}}})
+				return
+			}
+			if r.Method == http.MethodGet && operation != ast.Query {
+				w.Header().Set("Allow", http.MethodPost)
+				render.Error(w, r, http.StatusMethodNotAllowed, "only queries are allowed with GET")
+				return
+			}
+		}
+
+		// process
+		if acceptsEventStream(r) {
+			h.stream(w, r, body)
+			return
+		}
+		if operation == ast.Subscription {
+			render.Error(w, r, http.StatusNotAcceptable, "subscriptions require Accept: text/event-stream")
+			return
+		}
+		res := h.schema.Exec(r.Context(), body.Query, body.OperationName, body.Variables)
+
+		// response
+		render.Respond(w, r, http.StatusOK, res)
+	}
+}
+
+// stream is a method that writes the responses of an operation as server-sent events
+// - each response is a "next" event, the end of the operation is a "complete" event
+// - the stream lasts until the client disconnects, beyond the write timeout of the server
+func (h *GraphQL) stream(w http.ResponseWriter, r *http.Request, body GraphQLRequestJSON) {
+	responses, err := h.schema.Subscribe(r.Context(), body.Query, body.OperationName, body.Variables)
+	if err != nil {
+		render.Error(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && err != http.ErrNotSupported {
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	rc.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case res, ok := <-responses:
+			if !ok {
+				fmt.Fprint(w, "event: complete\ndata:\n\n")
+				rc.Flush()
+				return
+			}
+			data, err := json.Marshal(res)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+		case <-ticker.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-r.Context().Done():
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// acceptsEventStream is a function that returns true if the request accepts server-sent events
+func acceptsEventStream(r *http.Request) bool {
+	for _, part := range strings.Split(strings.Join(r.Header.Values("Accept"), ","), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && mediaType == "text/event-stream" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/render"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HealthJSON is a struct that represents the health of the application in JSON format
+type HealthJSON struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// NewHealthDefault is a function that returns a new instance of HealthDefault
+// - the application is not ready until Ready is called
+func NewHealthDefault() *HealthDefault {
+	return &HealthDefault{checks: make(map[string]internal.HealthChecker)}
+}
+
+// HealthDefault is a struct with methods that represent handlers for the probes of the application
+type HealthDefault struct {
+	// mu is the mutex that guards the state
+	mu sync.RWMutex
+	// loaded is true once the vehicles are loaded
+	loaded bool
+	// draining is true once the server is shutting down
+	draining bool
+	// checks are the components that must be healthy for the application to be ready, by name
+	checks map[string]internal.HealthChecker
+}
+
+// Ready is a method that marks the vehicles as loaded, adding the checks of the loaded components
+func (h *HealthDefault) Ready(checks map[string]internal.HealthChecker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for name, c := range checks {
+		h.checks[name] = c
+	}
+	h.loaded = true
+}
+
+// Drain is a method that marks the server as shutting down, so it is no longer ready
+func (h *HealthDefault) Drain() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.draining = true
+}
+
+// Healthz is a method that returns a handler for the route GET /healthz
+// - the application is alive as long as it serves requests
+func (h *HealthDefault) Healthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Respond(w, r, http.StatusOK, HealthJSON{Status: "ok"})
+	}
+}
+
+// Readyz is a method that returns a handler for the route GET /readyz
+// - the application is ready once the vehicles are loaded, while every check passes and until it shuts down
+func (h *HealthDefault) Readyz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.RLock()
+		loaded, draining := h.loaded, h.draining
+		checks := make(map[string]internal.HealthChecker, len(h.checks))
+		for name, c := range h.checks {
+			checks[name] = c
+		}
+		h.mu.RUnlock()
+
+		body := HealthJSON{Status: "ok", Checks: make(map[string]string)}
+		fail := func(name, reason string) {
+			body.Status = "unavailable"
+			body.Checks[name] = reason
+		}
+		if loaded {
+			body.Checks["loader"] = "ok"
+		} else {
+			fail("loader", "loading")
+		}
+		if draining {
+			fail("server", "shutting down")
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+		defer cancel()
+		for name, c := range checks {
+			if err := c.CheckHealth(ctx); err != nil {
+				fail(name, err.Error())
+				continue
+			}
+			body.Checks[name] = "ok"
+		}
+
+		code := http.StatusOK
+		if body.Status != "ok" {
+			code = http.StatusServiceUnavailable
+		}
+		render.Respond(w, r, code, body)
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"app/internal"
+	"strconv"
+	"strings"
+)
+
+// parseRange is a function that parses a range in the format min-max
+// - either bound can be omitted to express an open range (min- or -max)
+// - an empty string is the range that contains every value
+func parseRange(s string) (r internal.Range, err error) {
+	if s == "" {
+		return
+	}
+
+	bounds := strings.Split(s, "-")
+	if len(bounds) != 2 || (bounds[0] == "" && bounds[1] == "") {
+		err = internal.ErrRangeInvalid
+		return
+	}
+
+	if bounds[0] != "" {
+		min, e := strconv.ParseFloat(bounds[0], 64)
+		if e != nil {
+			err = internal.ErrRangeInvalid
+			return
+		}
+		r.Min = &min
+	}
+	if bounds[1] != "" {
+		max, e := strconv.ParseFloat(bounds[1], 64)
+		if e != nil {
+			err = internal.ErrRangeInvalid
+			return
+		}
+		r.Max = &max
+	}
+
+	err = r.Validate()
+	return
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"app/internal/loader"
+	"app/internal/logger"
+	"app/internal/render"
+	"app/internal/tenant"
+	"errors"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TenantJSON is a struct that represents a tenant in JSON format
+type TenantJSON struct {
+	ID       string `json:"id"`
+	Vehicles int    `json:"vehicles"`
+}
+
+// TenantSeedJSON is a struct that represents the seed of a tenant in JSON format
+type TenantSeedJSON struct {
+	// FilePath is the path of the JSON file of the vehicles, relative to the seed directory
+	FilePath string `json:"file_path"`
+}
+
+// NewTenantDefault is a function that returns a new instance of TenantDefault
+// - seedDir is the directory of the files tenants can be seeded from
+func NewTenantDefault(tenants *tenant.Registry, seedDir string) *TenantDefault {
+	return &TenantDefault{tenants: tenants, seedDir: seedDir}
+}
+
+// TenantDefault is a struct with methods that represent handlers for the administration of the tenants
+type TenantDefault struct {
+	// tenants are the fleets of the tenants
+	tenants *tenant.Registry
+	// seedDir is the directory of the files tenants can be seeded from
+	seedDir string
+}
+
+// GetAll is a method that returns a handler for the route GET /admin/tenants
+func (h *TenantDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := []TenantJSON{}
+		for _, f := range h.tenants.Fleets() {
+			v, err := f.Repository.FindAll(r.Context())
+			if err != nil {
+				render.Error(w, r, http.StatusInternalServerError, err.Error())
+				return
+			}
+			data = append(data, TenantJSON{ID: f.ID, Vehicles: len(v)})
+		}
+
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /admin/tenants
+func (h *TenantDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input TenantJSON
+		if err := render.Decode(r, &input); err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid body")
+			return
+		}
+
+		_, err := h.tenants.Create(input.ID, nil)
+		if err != nil {
+			switch {
+			case errors.Is(err, tenant.ErrTenantIDInvalid):
+				render.Error(w, r, http.StatusBadRequest, err.Error())
+			case errors.Is(err, tenant.ErrTenantExists):
+				render.Error(w, r, http.StatusConflict, err.Error())
+			default:
+				render.Error(w, r, http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+
+		logger.FromContext(r.Context()).Info("tenant created", slog.String("tenant_id", input.ID))
+		render.Respond(w, r, http.StatusCreated, map[string]any{
+			"message": "success",
+			"data":    TenantJSON{ID: input.ID},
+		})
+	}
+}
+
+// Seed is a method that returns a handler for the route POST /admin/tenants/{tenant}/seed
+// - the vehicles are loaded from a JSON file of the seed directory
+func (h *TenantDefault) Seed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "tenant")
+
+		var input TenantSeedJSON
+		if err := render.Decode(r, &input); err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid body")
+			return
+		}
+		// the file must stay within the seed directory
+		if h.seedDir == "" || !filepath.IsLocal(input.FilePath) {
+			render.Error(w, r, http.StatusBadRequest, "invalid file path")
+			return
+		}
+
+		n, err := h.tenants.Seed(r.Context(), id, loader.NewVehicleJSONFile(filepath.Join(h.seedDir, input.FilePath)))
+		if err != nil {
+			switch {
+			case errors.Is(err, tenant.ErrTenantNotFound):
+				render.Error(w, r, http.StatusNotFound, err.Error())
+			case errors.Is(err, tenant.ErrSeedInvalid):
+				render.Error(w, r, http.StatusBadRequest, err.Error())
+			default:
+				render.Respond(w, r, http.StatusConflict, map[string]any{
+					"message": err.Error(),
+					"seeded":  n,
+				})
+			}
+			return
+		}
+
+		logger.FromContext(r.Context()).Info("tenant seeded", slog.String("tenant_id", id), slog.Int("count", n))
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    TenantJSON{ID: id, Vehicles: n},
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/render"
+	"net/http"
+)
+
+// requestUnits is a function that returns the unit system requested by the client
+// - the query parameter ?units= takes precedence over the Accept-Units header
+// - on an invalid unit system it responds with a bad request and returns false
+func requestUnits(w http.ResponseWriter, r *http.Request) (u internal.UnitSystem, ok bool) {
+	name := r.URL.Query().Get("units")
+	if name == "" {
+		name = r.Header.Get("Accept-Units")
+	}
+
+	u, err := internal.ParseUnitSystem(name)
+	if err != nil {
+		render.Error(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// the response is always expressed in the requested unit system
+	w.Header().Set("Content-Units", string(u))
+	ok = true
+	return
+}
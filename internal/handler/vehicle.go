@@ -1,559 +1,610 @@
-package handler
-
-import (
-	"app/internal"
-	"net/http"
-	"strconv"
-	"strings"
-
-	"github.com/bootcamp-go/web/request"
-	"github.com/bootcamp-go/web/response"
-	"github.com/go-chi/chi/v5"
-)
-
-// VehicleJSON is a struct that represents a vehicle in JSON format
-type VehicleJSON struct {
-	ID              int     `json:"id"`
-	Brand           string  `json:"brand"`
-	Model           string  `json:"model"`
-	Registration    string  `json:"registration"`
-	Color           string  `json:"color"`
-	FabricationYear int     `json:"year"`
-	Capacity        int     `json:"passengers"`
-	MaxSpeed        float64 `json:"max_speed"`
-	FuelType        string  `json:"fuel_type"`
-	Transmission    string  `json:"transmission"`
-	Weight          float64 `json:"weight"`
-	Height          float64 `json:"height"`
-	Length          float64 `json:"length"`
-	Width           float64 `json:"width"`
-}
-
-// NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(sv internal.VehicleService) *VehicleDefault {
-	return &VehicleDefault{sv: sv}
-}
-
-// VehicleDefault is a struct with methods that represent handlers for vehicles
-type VehicleDefault struct {
-	// sv is the service that will be used by the handler
-	sv internal.VehicleService
-}
-
-// GetAll is a method that returns a handler for the route GET /vehicles
-func (h *VehicleDefault) GetAll() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// request
-		// ...
-
-		// process
-		// - get all vehicles
-		v, err := h.sv.FindAll()
-		if err != nil {
-			response.JSON(w, http.StatusInternalServerError, nil)
-			return
-		}
-
-		// response
-		data := make(map[int]VehicleJSON)
-		for key, value := range v {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint 1 - D4
-func (h *VehicleDefault) Create() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var input VehicleJSON
-
-		err := request.JSON(r, &input)
-		if err != nil {
-			response.JSON(w, http.StatusBadRequest, nil)
-			return
-		}
-
-		vehicle := internal.Vehicle{
-			Id: input.ID,
-			VehicleAttributes: internal.VehicleAttributes{
-				Brand:           input.Brand,
-				Model:           input.Model,
-				Registration:    input.Registration,
-				Color:           input.Color,
-				FabricationYear: input.FabricationYear,
-				Capacity:        input.Capacity,
-				MaxSpeed:        input.MaxSpeed,
-				FuelType:        input.FuelType,
-				Transmission:    input.Transmission,
-				Weight:          input.Weight,
-				Dimensions: internal.Dimensions{
-					Height: input.Height,
-					Length: input.Length,
-					Width:  input.Width,
-				},
-			},
-		}
-
-		err = h.sv.Create(vehicle)
-		// Sobrar tempo instanciar error e comparar com Is
-		if err != nil {
-			response.JSON(w, http.StatusConflict, nil)
-			return
-		}
-
-		response.JSON(w, http.StatusCreated, map[string]any{
-			"message": "success",
-			"data":    vehicle,
-		})
-	}
-}
-
-// Endpoint 2 - D2
-func (h *VehicleDefault) GetVehiclesByColorYear() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		color := chi.URLParam(r, "color")
-		year := chi.URLParam(r, "year")
-
-		vehicles, err := h.sv.GetVehiclesByColorYear(color, year)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint - D3
-func (h *VehicleDefault) GetVehiclesByBrandYears() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		brand := chi.URLParam(r, "brand")
-		startYear := chi.URLParam(r, "start_year")
-		endYear := chi.URLParam(r, "end_year")
-
-		vehicles, err := h.sv.GetVehiclesByBrandYears(brand, startYear, endYear)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		// response
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint 4 - D3
-func (h *VehicleDefault) GetAverageSpeedByBrand() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		brand := chi.URLParam(r, "brand")
-
-		averageSpeed, err := h.sv.GetAverageSpeedByBrand(brand)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		// response
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    averageSpeed,
-		})
-	}
-}
-
-// Endpoint 5 -> D5
-func (h *VehicleDefault) CreateVehicles() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var inputVehicles []VehicleJSON
-
-		err := request.JSON(r, &inputVehicles)
-		if err != nil {
-			response.JSON(w, http.StatusBadRequest, nil)
-			return
-		}
-
-		var vehiclesConvertedVehicle []internal.Vehicle
-
-		for _, value := range inputVehicles {
-			v := internal.Vehicle{
-				Id: value.ID,
-				VehicleAttributes: internal.VehicleAttributes{
-					Brand:           value.Brand,
-					Model:           value.Model,
-					Registration:    value.Registration,
-					Color:           value.Color,
-					FabricationYear: value.FabricationYear,
-					Capacity:        value.Capacity,
-					MaxSpeed:        value.MaxSpeed,
-					FuelType:        value.FuelType,
-					Transmission:    value.Transmission,
-					Weight:          value.Height,
-					Dimensions: internal.Dimensions{
-						Height: value.Height,
-						Length: value.Length,
-						Width:  value.Width,
-					},
-				},
-			}
-			vehiclesConvertedVehicle = append(vehiclesConvertedVehicle, v)
-		}
-
-		err = h.sv.CreateVehicles(vehiclesConvertedVehicle)
-		if err != nil {
-			response.JSON(w, http.StatusConflict, nil)
-			return
-		}
-
-		response.JSON(w, http.StatusCreated, map[string]any{
-			"message": "Veiculos criados com sucesso",
-			"data":    inputVehicles,
-		})
-	}
-}
-
-// Endpoint 6 -> D5
-type RequestUpdateSpeed struct {
-	NewSpeed float64 `json:"max_speed"`
-}
-
-func (h *VehicleDefault) UpdateVehicleSpeed() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-		idInt, err := strconv.Atoi(id)
-
-		if err != nil {
-			response.JSON(w, http.StatusBadRequest, nil)
-			return
-		}
-
-		var input RequestUpdateSpeed
-		err = request.JSON(r, &input)
-		if err != nil {
-			response.JSON(w, http.StatusBadRequest, nil)
-			return
-		}
-
-		err = h.sv.UpdateVehicleSpeed(idInt, input.NewSpeed)
-
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		response.JSON(w, http.StatusOK, nil)
-	}
-}
-
-// Endpoint 7 -> D2
-func (h *VehicleDefault) GetVehicleByFuelType() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		fuelType := chi.URLParam(r, "type")
-		vehicles, err := h.sv.GetVehicleByFuelType(fuelType)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		// response
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint 8 -> D1
-func (h *VehicleDefault) DeleteVehicle() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-		idInt, _ := strconv.Atoi(id)
-
-		err := h.sv.DeleteVehicle(idInt)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		response.JSON(w, http.StatusNoContent, nil)
-	}
-}
-
-// Endpoint 9 -> D1
-func (h *VehicleDefault) GetByTransmissionType() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		transmissionType := chi.URLParam(r, "type")
-
-		vehicles, err := h.sv.GetByTransmissionType(transmissionType)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint 10 -> D5
-type RequestUpdateFuelType struct {
-	FuelType string `json:"fuel_type"`
-}
-
-func (h *VehicleDefault) UpdateFuelType() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		id := chi.URLParam(r, "id")
-		idInt, _ := strconv.Atoi(id)
-
-		var input RequestUpdateFuelType
-		err := request.JSON(r, &input)
-		if err != nil {
-			response.JSON(w, http.StatusBadRequest, nil)
-			return
-		}
-
-		err = h.sv.UpdateFuelType(idInt, input.FuelType)
-		if err != nil {
-			response.JSON(w, http.StatusNotFound, nil)
-			return
-		}
-
-		response.JSON(w, http.StatusOK, nil)
-
-	}
-}
-
-// Endpoint 11 -> D3
-func (h *VehicleDefault) GetAverageCapacityByBrand() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		brand := chi.URLParam(r, "brand")
-
-		averageCapacity, err := h.sv.GetAverageCapacityByBrand(brand)
-		if err != nil {
-			response.Error(w, http.StatusNotFound, err.Error())
-			return
-		}
-
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    averageCapacity,
-		})
-
-	}
-}
-
-// Endpoint 12 -> D5
-func (h *VehicleDefault) GetByDimensions() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		length := r.URL.Query().Get("length")
-		width := r.URL.Query().Get("width")
-
-		lenghts := strings.Split(length, "-")
-		widths := strings.Split(width, "-")
-
-		var badFormattedData = "bad formatted data"
-		minLengthFloat, err := strconv.ParseFloat(lenghts[0], 64)
-		if err != nil {
-			response.Error(w, http.StatusBadRequest, badFormattedData)
-			return
-		}
-		maxLengthFloat, err := strconv.ParseFloat(lenghts[1], 64)
-		if err != nil {
-			response.Error(w, http.StatusBadRequest, badFormattedData)
-			return
-		}
-		minWidthFloat, err := strconv.ParseFloat(widths[0], 64)
-		if err != nil {
-			response.Error(w, http.StatusBadRequest, badFormattedData)
-			return
-		}
-		maxWidthFloat, err := strconv.ParseFloat(widths[1], 64)
-		if err != nil {
-			response.Error(w, http.StatusBadRequest, badFormattedData)
-			return
-		}
-
-		vehicles, err := h.sv.GetByDimensions(minLengthFloat, maxLengthFloat, minWidthFloat, maxWidthFloat)
-		if err != nil {
-			response.Error(w, http.StatusNotFound, err.Error())
-			return
-		}
-
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-	}
-}
-
-// Endpoint 13 -> D3
-func (h *VehicleDefault) GetByWeight() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		minWeigth := r.URL.Query().Get("min")
-		maxWeigth := r.URL.Query().Get("max")
-
-		minWeigthFloat, err := strconv.ParseFloat(minWeigth, 64)
-		if err != nil {
-			return
-		}
-		maxWeigthFloat, err := strconv.ParseFloat(maxWeigth, 64)
-		if err != nil {
-			return
-		}
-
-		vehicles, err := h.sv.GetByWeight(minWeigthFloat, maxWeigthFloat)
-		if err != nil {
-			response.Error(w, http.StatusNotFound, err.Error())
-			return
-		}
-
-		// response
-		data := make(map[int]VehicleJSON)
-		for key, value := range vehicles {
-			data[key] = VehicleJSON{
-				ID:              value.Id,
-				Brand:           value.Brand,
-				Model:           value.Model,
-				Registration:    value.Registration,
-				Color:           value.Color,
-				FabricationYear: value.FabricationYear,
-				Capacity:        value.Capacity,
-				MaxSpeed:        value.MaxSpeed,
-				FuelType:        value.FuelType,
-				Transmission:    value.Transmission,
-				Weight:          value.Weight,
-				Height:          value.Height,
-				Length:          value.Length,
-				Width:           value.Width,
-			}
-		}
-
-		response.JSON(w, http.StatusOK, map[string]any{
-			"message": "success",
-			"data":    data,
-		})
-
-	}
-}
+package handler
+
+import (
+	"app/internal"
+	"app/internal/render"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// VehicleJSON is a struct that represents a vehicle in JSON format
+type VehicleJSON struct {
+	ID              int     `json:"id"`
+	Brand           string  `json:"brand"`
+	Model           string  `json:"model"`
+	Registration    string  `json:"registration"`
+	Color           string  `json:"color"`
+	FabricationYear int     `json:"year"`
+	Capacity        int     `json:"passengers"`
+	MaxSpeed        float64 `json:"max_speed"`
+	FuelType        string  `json:"fuel_type"`
+	Transmission    string  `json:"transmission"`
+	Weight          float64 `json:"weight"`
+	Height          float64 `json:"height"`
+	Length          float64 `json:"length"`
+	Width           float64 `json:"width"`
+	// Footprint, Volume, Age and PowerToWeight are computed, only present if included
+	Footprint     *float64 `json:"footprint,omitempty"`
+	Volume        *float64 `json:"volume,omitempty"`
+	Age           *int     `json:"age,omitempty"`
+	PowerToWeight *float64 `json:"power_to_weight,omitempty"`
+}
+
+// NewVehicleDefault is a function that returns a new instance of VehicleDefault
+func NewVehicleDefault(sv internal.VehicleService) *VehicleDefault {
+	return &VehicleDefault{sv: sv}
+}
+
+// VehicleDefault is a struct with methods that represent handlers for vehicles
+type VehicleDefault struct {
+	// sv is the service that will be used by the handler
+	sv internal.VehicleService
+}
+
+// GetAll is a method that returns a handler for the route GET /vehicles
+func (h *VehicleDefault) GetAll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// request
+		// - unit system
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		// process
+		// - get all vehicles
+		v, err := h.sv.FindAll(r.Context())
+		if err != nil {
+			render.Respond(w, r, http.StatusInternalServerError, nil)
+			return
+		}
+
+		// response
+		data := s.Vehicles(v)
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint 1 - D4
+func (h *VehicleDefault) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		units, ok := requestUnits(w, r)
+		if !ok {
+			return
+		}
+
+		var input VehicleJSON
+
+		err := render.Decode(r, &input)
+		if err != nil {
+			render.Respond(w, r, http.StatusBadRequest, nil)
+			return
+		}
+
+		vehicle := internal.Vehicle{
+			Id: input.ID,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           input.Brand,
+				Model:           input.Model,
+				Registration:    input.Registration,
+				Color:           input.Color,
+				FabricationYear: input.FabricationYear,
+				Capacity:        input.Capacity,
+				MaxSpeed:        input.MaxSpeed,
+				FuelType:        input.FuelType,
+				Transmission:    input.Transmission,
+				Weight:          input.Weight,
+				Dimensions: internal.Dimensions{
+					Height: input.Height,
+					Length: input.Length,
+					Width:  input.Width,
+				},
+			},
+		}
+
+		// the vehicle is stored in metric units
+		err = h.sv.Create(r.Context(), units.VehicleToMetric(vehicle))
+		// Sobrar tempo instanciar error e comparar com Is
+		if err != nil {
+			render.Respond(w, r, http.StatusConflict, nil)
+			return
+		}
+
+		render.Respond(w, r, http.StatusCreated, map[string]any{
+			"message": "success",
+			"data":    vehicle,
+		})
+	}
+}
+
+// Endpoint 2 - D2
+func (h *VehicleDefault) GetVehiclesByColorYear() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		color := chi.URLParam(r, "color")
+		year := chi.URLParam(r, "year")
+
+		vehicles, err := h.sv.GetVehiclesByColorYear(r.Context(), color, year)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		data := s.Vehicles(vehicles)
+
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint - D3
+func (h *VehicleDefault) GetVehiclesByBrandYears() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		brand := chi.URLParam(r, "brand")
+		startYear := chi.URLParam(r, "start_year")
+		endYear := chi.URLParam(r, "end_year")
+
+		vehicles, err := h.sv.GetVehiclesByBrandYears(r.Context(), brand, startYear, endYear)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		// response
+		data := s.Vehicles(vehicles)
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint 4 - D3
+func (h *VehicleDefault) GetAverageSpeedByBrand() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		units, ok := requestUnits(w, r)
+		if !ok {
+			return
+		}
+
+		brand := chi.URLParam(r, "brand")
+
+		averageSpeed, err := h.sv.GetAverageSpeedByBrand(r.Context(), brand)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		// response
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    units.SpeedFromMetric(averageSpeed),
+		})
+	}
+}
+
+// Endpoint 5 -> D5
+func (h *VehicleDefault) CreateVehicles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		units, ok := requestUnits(w, r)
+		if !ok {
+			return
+		}
+
+		var inputVehicles []VehicleJSON
+
+		err := render.Decode(r, &inputVehicles)
+		if err != nil {
+			render.Respond(w, r, http.StatusBadRequest, nil)
+			return
+		}
+
+		var vehiclesConvertedVehicle []internal.Vehicle
+
+		for _, value := range inputVehicles {
+			v := internal.Vehicle{
+				Id: value.ID,
+				VehicleAttributes: internal.VehicleAttributes{
+					Brand:           value.Brand,
+					Model:           value.Model,
+					Registration:    value.Registration,
+					Color:           value.Color,
+					FabricationYear: value.FabricationYear,
+					Capacity:        value.Capacity,
+					MaxSpeed:        value.MaxSpeed,
+					FuelType:        value.FuelType,
+					Transmission:    value.Transmission,
+					Weight:          value.Weight,
+					Dimensions: internal.Dimensions{
+						Height: value.Height,
+						Length: value.Length,
+						Width:  value.Width,
+					},
+				},
+			}
+			vehiclesConvertedVehicle = append(vehiclesConvertedVehicle, units.VehicleToMetric(v))
+		}
+
+		err = h.sv.CreateVehicles(r.Context(), vehiclesConvertedVehicle)
+		if err != nil {
+			render.Respond(w, r, http.StatusConflict, nil)
+			return
+		}
+
+		render.Respond(w, r, http.StatusCreated, map[string]any{
+			"message": "Veiculos criados com sucesso",
+			"data":    inputVehicles,
+		})
+	}
+}
+
+// Endpoint 6 -> D5
+type RequestUpdateSpeed struct {
+	NewSpeed float64 `json:"max_speed"`
+}
+
+func (h *VehicleDefault) UpdateVehicleSpeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		units, ok := requestUnits(w, r)
+		if !ok {
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		idInt, err := strconv.Atoi(id)
+
+		if err != nil {
+			render.Respond(w, r, http.StatusBadRequest, nil)
+			return
+		}
+
+		var input RequestUpdateSpeed
+		err = render.Decode(r, &input)
+		if err != nil {
+			render.Respond(w, r, http.StatusBadRequest, nil)
+			return
+		}
+
+		err = h.sv.UpdateVehicleSpeed(r.Context(), idInt, units.SpeedToMetric(input.NewSpeed))
+
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		render.Respond(w, r, http.StatusOK, nil)
+	}
+}
+
+// Endpoint 7 -> D2
+func (h *VehicleDefault) GetVehicleByFuelType() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		fuelType := chi.URLParam(r, "type")
+		vehicles, err := h.sv.GetVehicleByFuelType(r.Context(), fuelType)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		// response
+		data := s.Vehicles(vehicles)
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint 8 -> D1
+func (h *VehicleDefault) DeleteVehicle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		idInt, err := strconv.Atoi(id)
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		err = h.sv.DeleteVehicle(r.Context(), idInt)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		render.Respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+// Endpoint 9 -> D1
+func (h *VehicleDefault) GetByTransmissionType() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		transmissionType := chi.URLParam(r, "type")
+
+		vehicles, err := h.sv.GetByTransmissionType(r.Context(), transmissionType)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		data := s.Vehicles(vehicles)
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint 10 -> D5
+type RequestUpdateFuelType struct {
+	FuelType string `json:"fuel_type"`
+}
+
+func (h *VehicleDefault) UpdateFuelType() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		idInt, err := strconv.Atoi(id)
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid id")
+			return
+		}
+
+		var input RequestUpdateFuelType
+		err = render.Decode(r, &input)
+		if err != nil {
+			render.Respond(w, r, http.StatusBadRequest, nil)
+			return
+		}
+
+		err = h.sv.UpdateFuelType(r.Context(), idInt, input.FuelType)
+		if err != nil {
+			render.Respond(w, r, http.StatusNotFound, nil)
+			return
+		}
+
+		render.Respond(w, r, http.StatusOK, nil)
+
+	}
+}
+
+// Endpoint 11 -> D3
+func (h *VehicleDefault) GetAverageCapacityByBrand() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		brand := chi.URLParam(r, "brand")
+
+		averageCapacity, err := h.sv.GetAverageCapacityByBrand(r.Context(), brand)
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    averageCapacity,
+		})
+
+	}
+}
+
+// Endpoint 12 -> D5
+func (h *VehicleDefault) GetByDimensions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		// request
+		// - ranges in the format min-max, min- or -max, expressed in the requested unit system
+		var query internal.DimensionsQuery
+		ranges := []struct {
+			param   string
+			target  *internal.Range
+			convert func(float64) float64
+		}{
+			{"height", &query.Height, s.units.LengthToMetric},
+			{"length", &query.Length, s.units.LengthToMetric},
+			{"width", &query.Width, s.units.LengthToMetric},
+			{"footprint", &query.Footprint, s.units.AreaToMetric},
+			{"volume", &query.Volume, s.units.VolumeToMetric},
+		}
+		for _, rg := range ranges {
+			parsed, err := parseRange(r.URL.Query().Get(rg.param))
+			if err != nil {
+				render.Error(w, r, http.StatusBadRequest, "bad formatted data: "+rg.param)
+				return
+			}
+			*rg.target = parsed.Map(rg.convert)
+		}
+
+		// process
+		vehicles, err := h.sv.GetByDimensions(r.Context(), query)
+		if err != nil {
+			switch {
+			case errors.Is(err, internal.ErrRangeInvalid):
+				render.Error(w, r, http.StatusBadRequest, err.Error())
+			default:
+				render.Error(w, r, http.StatusNotFound, err.Error())
+			}
+			return
+		}
+
+		// response
+		// - the vehicles are keyed by id as in every v1 response, sorting them is up to /v2/vehicles
+		byID := make(map[int]internal.Vehicle, len(vehicles))
+		for _, value := range vehicles {
+			byID[value.Id] = value
+		}
+		data := s.Vehicles(byID)
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// Endpoint 13 -> D3
+func (h *VehicleDefault) GetByWeight() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		minWeigth := r.URL.Query().Get("min")
+		maxWeigth := r.URL.Query().Get("max")
+
+		minWeigthFloat, err := strconv.ParseFloat(minWeigth, 64)
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "bad formatted data: min")
+			return
+		}
+		maxWeigthFloat, err := strconv.ParseFloat(maxWeigth, 64)
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "bad formatted data: max")
+			return
+		}
+
+		// the range is expressed in the requested unit system
+		vehicles, err := h.sv.GetByWeight(r.Context(), s.units.WeightToMetric(minWeigthFloat), s.units.WeightToMetric(maxWeigthFloat))
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := s.Vehicles(vehicles)
+
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+
+	}
+}
+
+// SimilarVehicleJSON is a struct that represents a similar vehicle in JSON format
+type SimilarVehicleJSON struct {
+	VehicleJSON
+	Distance float64 `json:"distance"`
+}
+
+// GetSimilarVehicles is a method that returns a handler for the route GET /vehicles/{id}/similar
+func (h *VehicleDefault) GetSimilarVehicles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, []string{"distance"})
+		if !ok {
+			return
+		}
+
+		// request
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			render.Error(w, r, http.StatusBadRequest, "invalid id")
+			return
+		}
+		// - k is the number of similar vehicles, 5 by default and up to 100
+		k := 5
+		if kParam := r.URL.Query().Get("k"); kParam != "" {
+			k, err = strconv.Atoi(kParam)
+			if err != nil || k < 1 || k > 100 {
+				render.Error(w, r, http.StatusBadRequest, "invalid k")
+				return
+			}
+		}
+
+		// process
+		vehicles, err := h.sv.GetSimilarVehicles(r.Context(), id, k)
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := make([]any, 0, len(vehicles))
+		for _, value := range vehicles {
+			data = append(data, s.project(SimilarVehicleJSON{VehicleJSON: s.vehicle(value.Vehicle), Distance: value.Distance}))
+		}
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
+
+// VehicleSearchResultJSON is a struct that represents a vehicle found by a text search in JSON format
+type VehicleSearchResultJSON struct {
+	VehicleJSON
+	Score float64 `json:"score"`
+}
+
+// SearchVehicles is a method that returns a handler for the route GET /vehicles/search
+func (h *VehicleDefault) SearchVehicles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, []string{"score"})
+		if !ok {
+			return
+		}
+
+		// request
+		text := r.URL.Query().Get("q")
+		if strings.TrimSpace(text) == "" {
+			render.Error(w, r, http.StatusBadRequest, "missing search text")
+			return
+		}
+		// - limit is the maximum number of vehicles, 20 by default and up to 100
+		limit := 20
+		if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+			var err error
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil || limit < 1 || limit > 100 {
+				render.Error(w, r, http.StatusBadRequest, "invalid limit")
+				return
+			}
+		}
+
+		// process
+		vehicles, err := h.sv.SearchVehicles(r.Context(), text, limit)
+		if err != nil {
+			render.Error(w, r, http.StatusNotFound, err.Error())
+			return
+		}
+
+		// response
+		data := make([]any, 0, len(vehicles))
+		for _, value := range vehicles {
+			data = append(data, s.project(VehicleSearchResultJSON{VehicleJSON: s.vehicle(value.Vehicle), Score: value.Score}))
+		}
+		render.Respond(w, r, http.StatusOK, map[string]any{
+			"message": "success",
+			"data":    data,
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/render"
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// computed are the fields computed from the attributes of a vehicle, written only if included with ?include=
+// - age is in years, as of the current year
+// - volume and footprint are derived from the dimensions
+// - power_to_weight is the maximum speed per unit of weight, the vehicles have no engine power
+var computed = map[string]bool{"age": true, "volume": true, "power_to_weight": true, "footprint": true}
+
+// vehicleFields are the fields of VehicleJSON, by their JSON names
+var vehicleFields = jsonFields(reflect.TypeOf(VehicleJSON{}))
+
+// jsonFields is a function that returns the JSON names of the fields of a struct
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
+
+// hiddenFieldsKey is the key of the fields hidden from the caller in the context of a request
+type hiddenFieldsKey struct{}
+
+// HiddenFields is a function that returns a middleware leaving out of the vehicles of the responses the fields hidden from the caller
+// - hidden returns the JSON names of the fields the caller of the request may not read
+func HiddenFields(hidden func(ctx context.Context) []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fields := hidden(r.Context()); len(fields) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), hiddenFieldsKey{}, fields))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// VehicleSerializer is a struct that represents how the vehicles of a response are written
+// - in the requested unit system, with the computed fields included and projected to the requested fields
+// - the fields hidden from the caller are left out, even if requested
+type VehicleSerializer struct {
+	// units is the unit system of the response
+	units internal.UnitSystem
+	// fields are the fields written, nil for every field
+	fields map[string]bool
+	// include are the computed fields written
+	include map[string]bool
+	// year is the current year, the age of the vehicles is computed from
+	year int
+}
+
+// requestSerializer is a function that returns the serializer of the vehicles requested by the client
+// - ?fields= is the comma separated list of the fields written, every field by default
+// - ?include= is the comma separated list of the computed fields written, a computed field in ?fields= is included too
+// - extra are the fields the route writes next to the vehicle (e.g. the distance of a similar vehicle), include are the computed fields it always writes
+// - on an invalid field it responds with a bad request and returns false
+func requestSerializer(w http.ResponseWriter, r *http.Request, extra []string, include ...string) (s VehicleSerializer, ok bool) {
+	s.units, ok = requestUnits(w, r)
+	if !ok {
+		return
+	}
+	ok = false
+	s.year = time.Now().Year()
+
+	// - computed fields
+	s.include = make(map[string]bool)
+	var invalid []string
+	for _, name := range split(r.URL.Query().Get("include")) {
+		if !computed[name] {
+			invalid = append(invalid, name)
+			continue
+		}
+		s.include[name] = true
+	}
+	if len(invalid) > 0 {
+		render.Error(w, r, http.StatusBadRequest, "invalid include: "+strings.Join(invalid, ","))
+		return
+	}
+
+	// - fields
+	if param := r.URL.Query().Get("fields"); param != "" {
+		s.fields = make(map[string]bool)
+		for _, name := range split(param) {
+			switch {
+			case computed[name]:
+				s.include[name] = true
+			case vehicleFields[name] || contains(extra, name):
+			default:
+				invalid = append(invalid, name)
+				continue
+			}
+			s.fields[name] = true
+		}
+		if len(invalid) > 0 {
+			render.Error(w, r, http.StatusBadRequest, "invalid fields: "+strings.Join(invalid, ","))
+			return
+		}
+		for name := range s.include {
+			s.fields[name] = true
+		}
+	}
+	// - computed fields of the route, projected as the others
+	for _, name := range include {
+		s.include[name] = true
+	}
+	// - hidden fields, projected out of every field if none were requested
+	if hidden, _ := r.Context().Value(hiddenFieldsKey{}).([]string); len(hidden) > 0 {
+		if s.fields == nil {
+			s.fields = make(map[string]bool)
+			for name := range vehicleFields {
+				s.fields[name] = true
+			}
+			for _, name := range extra {
+				s.fields[name] = true
+			}
+		}
+		for _, name := range hidden {
+			delete(s.fields, name)
+		}
+	}
+
+	ok = true
+	return
+}
+
+// split is a function that returns the non-empty items of a comma separated list, sorted and without duplicates
+func split(list string) (items []string) {
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	sort.Strings(items)
+	return
+}
+
+// contains is a function that returns true if a list contains an item
+func contains(list []string, item string) bool {
+	for _, e := range list {
+		if e == item {
+			return true
+		}
+	}
+	return false
+}
+
+// vehicle is a method that returns a vehicle in JSON format, with the computed fields included
+func (s VehicleSerializer) vehicle(v internal.Vehicle) VehicleJSON {
+	// computed from the metric attributes, then converted
+	footprint := s.units.AreaFromMetric(v.Footprint())
+	volume := s.units.VolumeFromMetric(v.Volume())
+	v = s.units.VehicleFromMetric(v)
+
+	data := VehicleJSON{
+		ID:              v.Id,
+		Brand:           v.Brand,
+		Model:           v.Model,
+		Registration:    v.Registration,
+		Color:           v.Color,
+		FabricationYear: v.FabricationYear,
+		Capacity:        v.Capacity,
+		MaxSpeed:        v.MaxSpeed,
+		FuelType:        v.FuelType,
+		Transmission:    v.Transmission,
+		Weight:          v.Weight,
+		Height:          v.Height,
+		Length:          v.Length,
+		Width:           v.Width,
+	}
+	if s.include["footprint"] {
+		data.Footprint = &footprint
+	}
+	if s.include["volume"] {
+		data.Volume = &volume
+	}
+	if s.include["age"] {
+		age := s.year - v.FabricationYear
+		data.Age = &age
+	}
+	if s.include["power_to_weight"] && v.Weight > 0 {
+		ratio := v.MaxSpeed / v.Weight
+		data.PowerToWeight = &ratio
+	}
+	return data
+}
+
+// project is a method that returns a value in JSON format with only the requested fields
+func (s VehicleSerializer) project(data any) any {
+	if s.fields == nil {
+		return data
+	}
+	o, err := render.Project(data, s.fields)
+	if err != nil {
+		return data
+	}
+	return o
+}
+
+// Vehicle is a method that returns a vehicle as written in a response
+func (s VehicleSerializer) Vehicle(v internal.Vehicle) any {
+	return s.project(s.vehicle(v))
+}
+
+// Vehicles is a method that returns vehicles by id as written in a response
+func (s VehicleSerializer) Vehicles(v map[int]internal.Vehicle) map[int]any {
+	data := make(map[int]any, len(v))
+	for key, value := range v {
+		data[key] = s.Vehicle(value)
+	}
+	return data
+}
+
+// VehicleList is a method that returns a list of vehicles as written in a response
+func (s VehicleSerializer) VehicleList(v []internal.Vehicle) []any {
+	data := make([]any, 0, len(v))
+	for _, value := range v {
+		data = append(data, s.Vehicle(value))
+	}
+	return data
+}
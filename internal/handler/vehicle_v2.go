@@ -0,0 +1,592 @@
+package handler
+
+import (
+	"app/internal"
+	"app/internal/render"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// EnvelopeJSON is a struct that represents the body of every response of the v2 routes
+// - data is the resource or the collection, error the failure, meta the metadata of a collection
+type EnvelopeJSON struct {
+	Data  any          `json:"data,omitempty"`
+	Meta  *MetaJSON    `json:"meta,omitempty"`
+	Error *ErrorV2JSON `json:"error,omitempty"`
+}
+
+// MetaJSON is a struct that represents the metadata of a collection in JSON format
+type MetaJSON struct {
+	// Total is the number of items of the collection, before pagination
+	Total int `json:"total"`
+	// Limit is the maximum number of items of the page, zero if not paginated
+	Limit int `json:"limit,omitempty"`
+	// Offset is the number of items skipped before the page
+	Offset int `json:"offset,omitempty"`
+}
+
+// ErrorV2JSON is a struct that represents an error of the v2 routes in JSON format
+type ErrorV2JSON struct {
+	// Status is the status code of the response
+	Status int `json:"status"`
+	// Code is the status in snake case (e.g. not_found), stable for clients to switch on
+	Code string `json:"code"`
+	// Message is the description of the error
+	Message string `json:"message"`
+}
+
+// ErrorEnvelope is a function that returns the body of an error response of the v2 routes
+func ErrorEnvelope(code int, message string) any {
+	return EnvelopeJSON{Error: &ErrorV2JSON{
+		Status:  code,
+		Code:    strings.ReplaceAll(strings.ToLower(http.StatusText(code)), " ", "_"),
+		Message: message,
+	}}
+}
+
+// VehiclePatchJSON is a struct that represents a partial update of a vehicle in JSON format
+// - absent fields are left as they are
+type VehiclePatchJSON struct {
+	ID              *int     `json:"id"`
+	Brand           *string  `json:"brand"`
+	Model           *string  `json:"model"`
+	Registration    *string  `json:"registration"`
+	Color           *string  `json:"color"`
+	FabricationYear *int     `json:"year"`
+	Capacity        *int     `json:"passengers"`
+	MaxSpeed        *float64 `json:"max_speed"`
+	FuelType        *string  `json:"fuel_type"`
+	Transmission    *string  `json:"transmission"`
+	Weight          *float64 `json:"weight"`
+	Height          *float64 `json:"height"`
+	Length          *float64 `json:"length"`
+	Width           *float64 `json:"width"`
+}
+
+// VehicleStatsJSON is a struct that represents the statistics of a metric over a group of vehicles in JSON format
+type VehicleStatsJSON struct {
+	Key    string  `json:"key"`
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	StdDev float64 `json:"stddev"`
+}
+
+const (
+	// defaultPageLimit is the number of vehicles of a page if not requested
+	defaultPageLimit = 50
+	// maxPageLimit is the maximum number of vehicles of a page
+	maxPageLimit = 500
+)
+
+// NewVehicleV2 is a function that returns a new instance of VehicleV2
+func NewVehicleV2(sv internal.VehicleService) *VehicleV2 {
+	return &VehicleV2{sv: sv}
+}
+
+// VehicleV2 is a struct with methods that represent the handlers of the v2 routes of the vehicles
+// - vehicles are a single resource, filtered with query parameters and updated with PATCH
+// - every response is an EnvelopeJSON, errors included
+type VehicleV2 struct {
+	// sv is the service that will be used by the handler
+	sv internal.VehicleService
+}
+
+// serviceError is a function that writes the error of the service with its status code
+func serviceError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, internal.ErrVehicleNotFound):
+		render.Error(w, r, http.StatusNotFound, "vehicle not found")
+	case errors.Is(err, internal.ErrVehicleExists):
+		render.Error(w, r, http.StatusConflict, "vehicle already exists")
+	case errors.Is(err, internal.ErrVehicleInvalid):
+		render.Error(w, r, http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, internal.ErrRangeInvalid), errors.Is(err, internal.ErrSortFieldInvalid):
+		render.Error(w, r, http.StatusBadRequest, err.Error())
+	default:
+		render.Error(w, r, http.StatusInternalServerError, "internal error")
+	}
+}
+
+// decodeError is a function that writes the error of the decoding of a request body
+func decodeError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, render.ErrContentType) {
+		render.Error(w, r, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+	render.Error(w, r, http.StatusBadRequest, err.Error())
+}
+
+// pathID is a function that returns the id of the vehicle of the path
+// - on an invalid id it responds with a bad request and returns false
+func pathID(w http.ResponseWriter, r *http.Request) (id int, ok bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		render.Error(w, r, http.StatusBadRequest, "invalid id")
+		return
+	}
+	ok = true
+	return
+}
+
+// queryInt is a function that returns the integer of a query parameter between min and max, def if absent
+// - on an invalid integer it responds with a bad request and returns false
+func queryInt(w http.ResponseWriter, r *http.Request, name string, def, min, max int) (n int, ok bool) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(param)
+	if err != nil || n < min || n > max {
+		render.Error(w, r, http.StatusBadRequest, "invalid "+name)
+		return
+	}
+	ok = true
+	return
+}
+
+// parseFilter is a function that returns the range of a filter: a single value or a range as in parseRange
+func parseFilter(s string) (r internal.Range, err error) {
+	if s == "" || strings.Contains(s, "-") {
+		return parseRange(s)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		err = internal.ErrRangeInvalid
+		return
+	}
+	return internal.NewRange(value, value), nil
+}
+
+// toVehicle is a function that returns the vehicle of a request body, in metric units
+func toVehicle(input VehicleJSON, units internal.UnitSystem) internal.Vehicle {
+	return units.VehicleToMetric(internal.Vehicle{
+		Id: input.ID,
+		VehicleAttributes: internal.VehicleAttributes{
+			Brand:           input.Brand,
+			Model:           input.Model,
+			Registration:    input.Registration,
+			Color:           input.Color,
+			FabricationYear: input.FabricationYear,
+			Capacity:        input.Capacity,
+			MaxSpeed:        input.MaxSpeed,
+			FuelType:        input.FuelType,
+			Transmission:    input.Transmission,
+			Weight:          input.Weight,
+			Dimensions: internal.Dimensions{
+				Height: input.Height,
+				Length: input.Length,
+				Width:  input.Width,
+			},
+		},
+	})
+}
+
+// List is a method that returns a handler for the route GET /v2/vehicles
+// - filters: brand, color, fuel_type and transmission match exactly, year, max_speed, weight, height, length, width,
+// footprint and volume match a value or a range in the format min-max, min- or -max, in the requested unit system
+// - sort is a field or -field for descending order, by id by default
+// - limit and offset paginate the vehicles, meta.total is the number of vehicles matching the filters
+func (h *VehicleV2) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		// request
+		// - filters
+		params := r.URL.Query()
+		query := internal.VehicleQuery{
+			Brand:        params.Get("brand"),
+			Color:        params.Get("color"),
+			FuelType:     params.Get("fuel_type"),
+			Transmission: params.Get("transmission"),
+		}
+		ranges := []struct {
+			param   string
+			target  *internal.Range
+			convert func(float64) float64
+		}{
+			{"year", &query.FabricationYear, nil},
+			{"max_speed", &query.MaxSpeed, s.units.SpeedToMetric},
+			{"weight", &query.Weight, s.units.WeightToMetric},
+			{"height", &query.Height, s.units.LengthToMetric},
+			{"length", &query.Length, s.units.LengthToMetric},
+			{"width", &query.Width, s.units.LengthToMetric},
+			{"footprint", &query.Footprint, s.units.AreaToMetric},
+			{"volume", &query.Volume, s.units.VolumeToMetric},
+		}
+		for _, rg := range ranges {
+			parsed, err := parseFilter(params.Get(rg.param))
+			if err != nil {
+				render.Error(w, r, http.StatusBadRequest, "invalid "+rg.param)
+				return
+			}
+			if rg.convert != nil {
+				parsed = parsed.Map(rg.convert)
+			}
+			*rg.target = parsed
+		}
+		// - sort
+		sortBy, descending := params.Get("sort"), false
+		if strings.HasPrefix(sortBy, "-") {
+			sortBy, descending = sortBy[1:], true
+		}
+		if _, ok := internal.VehicleSorters[sortBy]; sortBy != "" && !ok {
+			render.Error(w, r, http.StatusBadRequest, "invalid sort")
+			return
+		}
+		// - page
+		limit, ok := queryInt(w, r, "limit", defaultPageLimit, 1, maxPageLimit)
+		if !ok {
+			return
+		}
+		offset, ok := queryInt(w, r, "offset", 0, 0, math.MaxInt)
+		if !ok {
+			return
+		}
+
+		// process
+		v, err := h.sv.Query(r.Context(), query)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+		vehicles, err := internal.SortVehicles(v, sortBy, descending)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+		start := min(offset, len(vehicles))
+		page := vehicles[start:min(start+limit, len(vehicles))]
+
+		// response
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{
+			Data: s.VehicleList(page),
+			Meta: &MetaJSON{Total: len(vehicles), Limit: limit, Offset: offset},
+		})
+	}
+}
+
+// Get is a method that returns a handler for the route GET /v2/vehicles/{id}
+func (h *VehicleV2) Get() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+
+		v, err := h.sv.FindOne(r.Context(), id)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{Data: s.Vehicle(v)})
+	}
+}
+
+// Create is a method that returns a handler for the route POST /v2/vehicles
+// - the vehicle created is written as stored, with its location
+func (h *VehicleV2) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		// request
+		var input VehicleJSON
+		if err := render.Decode(r, &input); err != nil {
+			decodeError(w, r, err)
+			return
+		}
+		v := toVehicle(input, s.units)
+		if err := v.Validate(); err != nil {
+			render.Error(w, r, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+
+		// process
+		if err := h.sv.Create(r.Context(), v); err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		// response
+		w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+strconv.Itoa(v.Id))
+		render.Respond(w, r, http.StatusCreated, EnvelopeJSON{Data: s.Vehicle(v)})
+	}
+}
+
+// CreateBatch is a method that returns a handler for the route POST /v2/vehicles/batch
+// - every vehicle is validated before any is created
+func (h *VehicleV2) CreateBatch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+
+		// request
+		var input []VehicleJSON
+		if err := render.Decode(r, &input); err != nil {
+			decodeError(w, r, err)
+			return
+		}
+		if len(input) == 0 {
+			render.Error(w, r, http.StatusUnprocessableEntity, "no vehicles")
+			return
+		}
+		vehicles := make([]internal.Vehicle, 0, len(input))
+		ids := make(map[int]bool, len(input))
+		for i, value := range input {
+			v := toVehicle(value, s.units)
+			if err := v.Validate(); err != nil {
+				render.Error(w, r, http.StatusUnprocessableEntity, "vehicle "+strconv.Itoa(i)+": "+err.Error())
+				return
+			}
+			if ids[v.Id] {
+				render.Error(w, r, http.StatusUnprocessableEntity, "vehicle "+strconv.Itoa(i)+": duplicated id")
+				return
+			}
+			ids[v.Id] = true
+			vehicles = append(vehicles, v)
+		}
+
+		// process
+		if err := h.sv.CreateVehicles(r.Context(), vehicles); err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		// response
+		render.Respond(w, r, http.StatusCreated, EnvelopeJSON{
+			Data: s.VehicleList(vehicles),
+			Meta: &MetaJSON{Total: len(vehicles)},
+		})
+	}
+}
+
+// Patch is a method that returns a handler for the route PATCH /v2/vehicles/{id}
+// - the fields of the body replace the ones of the vehicle, in the requested unit system, the id can not be changed
+func (h *VehicleV2) Patch() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, nil)
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+
+		// request
+		var input VehiclePatchJSON
+		if err := render.Decode(r, &input); err != nil {
+			decodeError(w, r, err)
+			return
+		}
+		if input.ID != nil && *input.ID != id {
+			render.Error(w, r, http.StatusUnprocessableEntity, "id can not be changed")
+			return
+		}
+		for _, value := range []*float64{input.MaxSpeed, input.Weight, input.Height, input.Length, input.Width} {
+			if value != nil && *value < 0 {
+				render.Error(w, r, http.StatusUnprocessableEntity, "max_speed, weight, height, length and width must not be negative")
+				return
+			}
+		}
+		// - in metric units
+		convert := func(value *float64, fn func(float64) float64) *float64 {
+			if value == nil {
+				return nil
+			}
+			converted := fn(*value)
+			return &converted
+		}
+		patch := internal.VehiclePatch{
+			Brand:           input.Brand,
+			Model:           input.Model,
+			Registration:    input.Registration,
+			Color:           input.Color,
+			FabricationYear: input.FabricationYear,
+			Capacity:        input.Capacity,
+			MaxSpeed:        convert(input.MaxSpeed, s.units.SpeedToMetric),
+			FuelType:        input.FuelType,
+			Transmission:    input.Transmission,
+			Weight:          convert(input.Weight, s.units.WeightToMetric),
+			Height:          convert(input.Height, s.units.LengthToMetric),
+			Length:          convert(input.Length, s.units.LengthToMetric),
+			Width:           convert(input.Width, s.units.LengthToMetric),
+		}
+
+		// process
+		// - the patched vehicle is validated by the service, as invalid as a new one is 422
+		v, err := h.sv.Patch(r.Context(), id, patch)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		// response
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{Data: s.Vehicle(v)})
+	}
+}
+
+// Delete is a method that returns a handler for the route DELETE /v2/vehicles/{id}
+func (h *VehicleV2) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+
+		if err := h.sv.DeleteVehicle(r.Context(), id); err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		render.Respond(w, r, http.StatusNoContent, nil)
+	}
+}
+
+// Similar is a method that returns a handler for the route GET /v2/vehicles/{id}/similar
+// - k is the number of similar vehicles, 5 by default and up to 100
+func (h *VehicleV2) Similar() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, []string{"distance"})
+		if !ok {
+			return
+		}
+		id, ok := pathID(w, r)
+		if !ok {
+			return
+		}
+		k, ok := queryInt(w, r, "k", 5, 1, 100)
+		if !ok {
+			return
+		}
+
+		vehicles, err := h.sv.GetSimilarVehicles(r.Context(), id, k)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		data := make([]any, 0, len(vehicles))
+		for _, value := range vehicles {
+			data = append(data, s.project(SimilarVehicleJSON{VehicleJSON: s.vehicle(value.Vehicle), Distance: value.Distance}))
+		}
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{Data: data, Meta: &MetaJSON{Total: len(data)}})
+	}
+}
+
+// Search is a method that returns a handler for the route GET /v2/vehicles/search
+// - q is the text searched, limit the maximum number of vehicles, 20 by default and up to 100
+// - a search matching no vehicle is an empty collection
+func (h *VehicleV2) Search() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := requestSerializer(w, r, []string{"score"})
+		if !ok {
+			return
+		}
+		text := r.URL.Query().Get("q")
+		if strings.TrimSpace(text) == "" {
+			render.Error(w, r, http.StatusBadRequest, "missing search text")
+			return
+		}
+		limit, ok := queryInt(w, r, "limit", 20, 1, 100)
+		if !ok {
+			return
+		}
+
+		vehicles, err := h.sv.SearchVehicles(r.Context(), text, limit)
+		if err != nil && !errors.Is(err, internal.ErrVehicleNotFound) {
+			serviceError(w, r, err)
+			return
+		}
+
+		data := make([]any, 0, len(vehicles))
+		for _, value := range vehicles {
+			data = append(data, s.project(VehicleSearchResultJSON{VehicleJSON: s.vehicle(value.Vehicle), Score: value.Score}))
+		}
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{Data: data, Meta: &MetaJSON{Total: len(data)}})
+	}
+}
+
+// Stats is a method that returns a handler for the route GET /v2/vehicles/stats
+// - group_by is brand, fuel_type or year, brand by default
+// - metric is max_speed, capacity or weight, max_speed by default, in the requested unit system
+func (h *VehicleV2) Stats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		units, ok := requestUnits(w, r)
+		if !ok {
+			return
+		}
+
+		// request
+		group := internal.AggregateGroup(r.URL.Query().Get("group_by"))
+		if group == "" {
+			group = internal.AggregateByBrand
+		}
+		if !slices.Contains(internal.AggregateGroups, group) {
+			render.Error(w, r, http.StatusBadRequest, "invalid group_by")
+			return
+		}
+		metric := internal.AggregateMetric(r.URL.Query().Get("metric"))
+		if metric == "" {
+			metric = internal.MetricMaxSpeed
+		}
+		if !slices.Contains(internal.AggregateMetrics, metric) {
+			render.Error(w, r, http.StatusBadRequest, "invalid metric")
+			return
+		}
+		// - the statistics are linear in the values, converted as they are
+		convert := func(value float64) float64 { return value }
+		switch metric {
+		case internal.MetricMaxSpeed:
+			convert = units.SpeedFromMetric
+		case internal.MetricWeight:
+			convert = units.WeightFromMetric
+		}
+
+		// process
+		aggregates, err := h.sv.GetAggregates(r.Context(), group, metric)
+		if err != nil {
+			serviceError(w, r, err)
+			return
+		}
+
+		// response
+		data := make([]VehicleStatsJSON, 0, len(aggregates))
+		for key, a := range aggregates {
+			if a.Count == 0 {
+				continue
+			}
+			data = append(data, VehicleStatsJSON{
+				Key:    key,
+				Count:  a.Count,
+				Mean:   convert(a.Mean()),
+				Min:    convert(a.Min),
+				Max:    convert(a.Max),
+				StdDev: convert(a.StdDev()),
+			})
+		}
+		sort.Slice(data, func(i, j int) bool { return data[i].Key < data[j].Key })
+		render.Respond(w, r, http.StatusOK, EnvelopeJSON{Data: data, Meta: &MetaJSON{Total: len(data)}})
+	}
+}
@@ -0,0 +1,9 @@
+package internal
+
+import "context"
+
+// HealthChecker is an interface that represents a component able to report whether it can serve requests
+type HealthChecker interface {
+	// CheckHealth is a method that returns an error if the component is unhealthy
+	CheckHealth(ctx context.Context) error
+}
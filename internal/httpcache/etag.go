@@ -0,0 +1,98 @@
+package httpcache
+
+import (
+	"app/internal/auth"
+	"encoding/hex"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epoch tells apart the versions of the repositories of different runs of the server
+var epoch = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// Versioner is a function that returns the version of the resources of a request, ok is false if it is unknown
+type Versioner func(r *http.Request) (version string, ok bool)
+
+// ETag is a function that returns the weak entity tag of the response to a request at a version of its resources
+// - the tag changes with the version and with anything the representation depends on:
+// the URL, the negotiated format and unit system, and the roles of the principal (fields may be hidden)
+func ETag(r *http.Request, version string) string {
+	h := fnv.New64a()
+	io.WriteString(h, epoch+"\x00"+version+"\x00"+r.URL.RequestURI())
+	for _, name := range []string{"Accept", "Accept-Units"} {
+		io.WriteString(h, "\x00"+strings.Join(r.Header.Values(name), ","))
+	}
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		io.WriteString(h, "\x00"+strings.Join(p.Roles, ","))
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// match is a function that returns true if an If-None-Match header matches a tag, by weak comparison
+func match(ifNoneMatch string, tag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(tag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// etagWriter is a struct that represents a response writer dropping the entity tag of unsuccessful responses
+type etagWriter struct {
+	http.ResponseWriter
+	// wroteHeader is true once the status code is written
+	wroteHeader bool
+}
+
+// WriteHeader is a method that writes the status code, without the entity tag unless it is 200 OK
+func (w *etagWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if status != http.StatusOK {
+			w.Header().Del("ETag")
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write is a method that writes the body of the response
+func (w *etagWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Conditional is a function that returns a middleware answering conditional GET requests
+// - the responses are tagged with a weak ETag computed from the version of their resources, before serving them
+// - a request whose If-None-Match matches the tag is answered with 304 Not Modified, without serving it
+// - the version is read before serving, so a change made meanwhile only makes the next request miss
+func Conditional(version Versioner) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+			v, ok := version(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tag := ETag(r, v)
+			w.Header().Set("ETag", tag)
+			if inm := r.Header.Get("If-None-Match"); inm != "" && match(inm, tag) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			next.ServeHTTP(&etagWriter{ResponseWriter: w}, r)
+		})
+	}
+}
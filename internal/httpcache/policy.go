@@ -0,0 +1,47 @@
+package httpcache
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// NoStore is the policy of the responses that must not be cached, e.g. writes and administration
+	NoStore = "no-store"
+	// Revalidate is the policy of the responses that may be cached by the client, revalidated by their ETag on every use
+	Revalidate = "private, no-cache"
+)
+
+// MaxAge is a function that returns the policy of the responses that may be used by the client for some seconds
+func MaxAge(seconds int) string {
+	return "private, max-age=" + strconv.Itoa(seconds)
+}
+
+// Policies is a function that returns a middleware setting the Cache-Control header of the responses of each route
+// - the policies are by "METHOD pattern" (e.g. "GET /vehicles"), as the permissions of auth.Policy
+// - routes without a policy get the fallback, no header if it is empty
+// - the route is matched before serving the request, handlers can still override the header
+func Policies(policies map[string]string, fallback string, routes chi.Routes) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path := r.URL.Path
+			if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePath != "" {
+				path = rc.RoutePath
+			}
+
+			policy := fallback
+			match := chi.NewRouteContext()
+			if routes.Match(match, r.Method, path) {
+				if p, ok := policies[r.Method+" "+match.RoutePattern()]; ok {
+					policy = p
+				}
+			}
+			if policy != "" {
+				w.Header().Set("Cache-Control", policy)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package idempotency
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/render"
+	"app/internal/tenant"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// Header is the header of the idempotency key of a request
+	Header = "Idempotency-Key"
+	// ReplayedHeader is the header set on the responses replayed from a previous request
+	ReplayedHeader = "Idempotent-Replayed"
+	// MaxKeyLength is the maximum length of an idempotency key
+	MaxKeyLength = 255
+)
+
+// replayedHeaders are the headers of a response stored to be replayed
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// recorder is a struct that represents a response writer keeping a copy of the response
+type recorder struct {
+	http.ResponseWriter
+	// status is the status code of the response
+	status int
+	// body is the copy of the body of the response
+	body bytes.Buffer
+}
+
+// WriteHeader is a method that records the status code of the response
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write is a method that records the body of the response
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// scope is a function that returns the key of the store, so the keys of tenants and clients do not collide
+func scope(r *http.Request, key string) string {
+	client := ""
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		client = p.Subject
+	}
+	return tenant.FromContext(r.Context()) + "\x00" + client + "\x00" + key
+}
+
+// fingerprint is a function that returns the hash of the method, the path, the query, the content type and the body of a request
+// - the query and the content type change how a request is served, e.g. ?units= or a CSV body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery+"\x00"+r.Header.Get("Content-Type")+"\x00")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Middleware is a function that returns a middleware making the requests with an Idempotency-Key header idempotent
+// - the first request with a key is served and its response stored for ttl
+// - a retry with the same key and the same request replays the stored response, with an Idempotent-Replayed header
+// - a retry with the same key and a different request is rejected with 422, a retry while the first is in progress with 409
+// - server errors (5xx) are not stored, so the request can be retried
+// - requests without the header are served as usual
+func Middleware(store Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > MaxKeyLength {
+				render.Error(w, r, http.StatusBadRequest, "idempotency key too long")
+				return
+			}
+
+			// request
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				render.Error(w, r, http.StatusBadRequest, "invalid body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// process
+			ctx, lg := r.Context(), logger.FromContext(r.Context())
+			sk, now := scope(r, key), time.Now()
+			rec := Record{Fingerprint: fingerprint(r, body), Expires: now.Add(ttl)}
+			existing, created, err := store.Begin(ctx, sk, rec, now)
+			if err != nil {
+				// fail open: the request is served without idempotency
+				lg.Error("idempotency key not stored", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !created {
+				switch {
+				case existing.Fingerprint != rec.Fingerprint:
+					render.Error(w, r, http.StatusUnprocessableEntity, "idempotency key reused with a different request")
+				case !existing.Done:
+					render.Error(w, r, http.StatusConflict, "request with the same idempotency key in progress")
+				default:
+					for name, values := range existing.Header {
+						w.Header()[name] = values
+					}
+					w.Header().Set(ReplayedHeader, "true")
+					w.WriteHeader(existing.Status)
+					w.Write(existing.Body)
+				}
+				return
+			}
+
+			rw := &recorder{ResponseWriter: w}
+			defer func() {
+				if rw.status == 0 || rw.status >= http.StatusInternalServerError {
+					// - panics and server errors can be retried
+					if err := store.Abort(ctx, sk); err != nil {
+						lg.Error("idempotency key not released", slog.String("error", err.Error()))
+					}
+					return
+				}
+				rec.Done, rec.Status, rec.Body, rec.Header = true, rw.status, rw.body.Bytes(), make(http.Header)
+				for _, name := range replayedHeaders {
+					if values := rw.Header().Values(name); len(values) > 0 {
+						rec.Header[name] = values
+					}
+				}
+				if err := store.Complete(ctx, sk, rec); err != nil {
+					lg.Error("idempotency key not stored", slog.String("error", err.Error()))
+				}
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
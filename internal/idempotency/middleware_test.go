@@ -0,0 +1,273 @@
+package idempotency
+
+import (
+	"app/internal/auth"
+	"app/internal/tenant"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// request is a struct that represents a request made to the middleware
+type request struct {
+	method      string
+	target      string
+	contentType string
+	body        string
+	key         string
+	// subject is the principal of the request, none if empty
+	subject string
+	// tenant is the tenant of the request, the default one if empty
+	tenant string
+}
+
+// newRequest is a function that returns the http request of the request
+func (rq request) newRequest() *http.Request {
+	r := httptest.NewRequest(rq.method, rq.target, strings.NewReader(rq.body))
+	if rq.contentType != "" {
+		r.Header.Set("Content-Type", rq.contentType)
+	}
+	if rq.key != "" {
+		r.Header.Set(Header, rq.key)
+	}
+	ctx := r.Context()
+	if rq.subject != "" {
+		ctx = auth.ContextWithPrincipal(ctx, auth.Principal{Subject: rq.subject})
+	}
+	if rq.tenant != "" {
+		ctx = tenant.WithContext(ctx, rq.tenant)
+	}
+	return r.WithContext(ctx)
+}
+
+// counter is a struct that represents a handler creating a resource, numbered by its calls
+type counter struct {
+	// calls is the number of calls to the handler
+	calls atomic.Int32
+	// status is the status of the responses, 201 if zero
+	status int
+}
+
+// ServeHTTP is a method that responds with the number of the call and the body of the request
+func (c *counter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := c.calls.Add(1)
+	body, _ := io.ReadAll(r.Body)
+	status := c.status
+	if status == 0 {
+		status = http.StatusCreated
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Location", "/vehicles/"+strconv.Itoa(int(n)))
+	w.Header().Set("X-Call", strconv.Itoa(int(n)))
+	w.WriteHeader(status)
+	w.Write([]byte(strconv.Itoa(int(n)) + ":" + string(body)))
+}
+
+func TestMiddleware(t *testing.T) {
+	first := request{method: "POST", target: "/vehicles?units=metric", contentType: "application/json", body: `{"id":1}`, key: "key-1"}
+	// with is a function that returns the first request changed by fn
+	with := func(fn func(rq *request)) request {
+		rq := first
+		fn(&rq)
+		return rq
+	}
+
+	cases := []struct {
+		name   string
+		second request
+		// status is the status of the response to the second request
+		status int
+		// replayed is true if the second response is the first one replayed
+		replayed bool
+		// calls is the number of requests served by the handler
+		calls int32
+	}{
+		{name: "case 1: the same request is replayed", second: first, status: http.StatusCreated, replayed: true, calls: 1},
+		{name: "case 2: another body", second: with(func(rq *request) { rq.body = `{"id":2}` }), status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "case 3: another path", second: with(func(rq *request) { rq.target = "/vehicles/batch?units=metric" }), status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "case 4: another method", second: with(func(rq *request) { rq.method = "PUT" }), status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "case 5: another query", second: with(func(rq *request) { rq.target = "/vehicles?units=imperial" }), status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "case 6: another content type", second: with(func(rq *request) { rq.contentType = "application/x-yaml" }), status: http.StatusUnprocessableEntity, calls: 1},
+		{name: "case 7: another key", second: with(func(rq *request) { rq.key = "key-2" }), status: http.StatusCreated, calls: 2},
+		{name: "case 8: no key", second: with(func(rq *request) { rq.key = "" }), status: http.StatusCreated, calls: 2},
+		{name: "case 9: the key of another client", second: with(func(rq *request) { rq.subject = "bob" }), status: http.StatusCreated, calls: 2},
+		{name: "case 10: the key of another tenant", second: with(func(rq *request) { rq.tenant = "acme" }), status: http.StatusCreated, calls: 2},
+		{name: "case 11: a key too long", second: with(func(rq *request) { rq.key = strings.Repeat("k", MaxKeyLength+1) }), status: http.StatusBadRequest, calls: 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := &counter{}
+			mw := Middleware(NewMemoryStore(), time.Hour)(h)
+			res1 := httptest.NewRecorder()
+			mw.ServeHTTP(res1, first.newRequest())
+			require.Equal(t, http.StatusCreated, res1.Code)
+
+			// act
+			res2 := httptest.NewRecorder()
+			mw.ServeHTTP(res2, c.second.newRequest())
+
+			// assert
+			require.Equal(t, c.status, res2.Code, res2.Body.String())
+			require.Equal(t, c.calls, h.calls.Load())
+			if c.replayed {
+				require.Equal(t, "true", res2.Header().Get(ReplayedHeader))
+				require.Equal(t, res1.Body.String(), res2.Body.String())
+				require.Equal(t, "/vehicles/1", res2.Header().Get("Location"))
+				require.Equal(t, "text/plain", res2.Header().Get("Content-Type"))
+				// - only the headers of replayedHeaders are stored
+				require.Empty(t, res2.Header().Get("X-Call"))
+				return
+			}
+			require.Empty(t, res2.Header().Get(ReplayedHeader))
+		})
+	}
+}
+
+func TestMiddleware_Status(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		// calls is the number of requests served by the handler for two requests with the same key
+		calls int32
+	}{
+		{name: "case 1: a client error is replayed", status: http.StatusConflict, calls: 1},
+		{name: "case 2: a server error can be retried", status: http.StatusServiceUnavailable, calls: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := &counter{status: c.status}
+			mw := Middleware(NewMemoryStore(), time.Hour)(h)
+			rq := request{method: "POST", target: "/vehicles", body: "{}", key: "key-1"}
+
+			// act
+			mw.ServeHTTP(httptest.NewRecorder(), rq.newRequest())
+			res := httptest.NewRecorder()
+			mw.ServeHTTP(res, rq.newRequest())
+
+			// assert
+			require.Equal(t, c.status, res.Code)
+			require.Equal(t, c.calls, h.calls.Load())
+		})
+	}
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	// arrange
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	})
+	mw := Middleware(NewMemoryStore(), time.Hour)(h)
+	rq := request{method: "POST", target: "/vehicles", body: "{}", key: "key-1"}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	res1 := httptest.NewRecorder()
+	go func() {
+		defer wg.Done()
+		mw.ServeHTTP(res1, rq.newRequest())
+	}()
+	<-started
+
+	// act
+	res2 := httptest.NewRecorder()
+	mw.ServeHTTP(res2, rq.newRequest())
+	close(release)
+	wg.Wait()
+	res3 := httptest.NewRecorder()
+	mw.ServeHTTP(res3, rq.newRequest())
+
+	// assert
+	require.Equal(t, http.StatusConflict, res2.Code)
+	require.Equal(t, http.StatusCreated, res1.Code)
+	require.Equal(t, http.StatusCreated, res3.Code)
+	require.Equal(t, "true", res3.Header().Get(ReplayedHeader))
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestMiddleware_TTL(t *testing.T) {
+	// arrange
+	h := &counter{}
+	ttl := 10 * time.Millisecond
+	mw := Middleware(NewMemoryStore(), ttl)(h)
+	rq := request{method: "POST", target: "/vehicles", body: "{}", key: "key-1"}
+	mw.ServeHTTP(httptest.NewRecorder(), rq.newRequest())
+
+	// act
+	time.Sleep(2 * ttl)
+	res := httptest.NewRecorder()
+	mw.ServeHTTP(res, rq.newRequest())
+
+	// assert
+	require.Equal(t, http.StatusCreated, res.Code)
+	require.Empty(t, res.Header().Get(ReplayedHeader))
+	require.Equal(t, "2:{}", res.Body.String())
+	require.Equal(t, int32(2), h.calls.Load())
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name string
+		// at is the time of the second Begin, after the first one at now
+		at      time.Duration
+		created bool
+	}{
+		{name: "case 1: before the expiry", at: 59 * time.Second, created: false},
+		{name: "case 2: at the expiry", at: time.Minute, created: true},
+		{name: "case 3: after the expiry", at: time.Hour, created: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			s := NewMemoryStore()
+			rec := Record{Fingerprint: "a", Expires: now.Add(time.Minute)}
+			_, created, err := s.Begin(context.Background(), "key", rec, now)
+			require.NoError(t, err)
+			require.True(t, created)
+
+			// act
+			existing, created, err := s.Begin(context.Background(), "key", Record{Fingerprint: "b", Expires: now.Add(c.at + time.Minute)}, now.Add(c.at))
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, c.created, created)
+			if !created {
+				require.Equal(t, rec, existing)
+			}
+		})
+	}
+
+	t.Run("case 4: the expired records are swept", func(t *testing.T) {
+		// arrange
+		s := NewMemoryStore()
+		for i := 0; i < 3; i++ {
+			_, _, err := s.Begin(context.Background(), strconv.Itoa(i), Record{Expires: now.Add(time.Duration(i) * time.Hour)}, now)
+			require.NoError(t, err)
+		}
+
+		// act
+		_, _, err := s.Begin(context.Background(), "other", Record{Expires: now.Add(2 * time.Hour)}, now.Add(time.Hour))
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, s.records, 2)
+		require.Contains(t, s.records, "2")
+		require.Contains(t, s.records, "other")
+	})
+}
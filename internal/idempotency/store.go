@@ -0,0 +1,97 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Record is a struct that represents a request made with an idempotency key and its response
+type Record struct {
+	// Fingerprint is the hash of the method, the path, the query, the content type and the body of the request
+	Fingerprint string
+	// Done is false while the request is in progress
+	Done bool
+	// Status is the status code of the response
+	Status int
+	// Header are the headers of the response
+	Header http.Header
+	// Body is the body of the response
+	Body []byte
+	// Expires is when the record is forgotten
+	Expires time.Time
+}
+
+// Store is an interface that represents where the records of the idempotency keys are kept
+// - the memory store keeps them per process, a shared store (e.g. Redis) can be swapped in for several instances
+type Store interface {
+	// Begin is a method that stores a pending record for the key, or returns the record of the key if it exists
+	Begin(ctx context.Context, key string, rec Record, now time.Time) (existing Record, created bool, err error)
+	// Complete is a method that stores the response of the pending record of the key
+	Complete(ctx context.Context, key string, rec Record) (err error)
+	// Abort is a method that forgets the pending record of the key, so the request can be retried
+	Abort(ctx context.Context, key string) (err error)
+}
+
+// NewMemoryStore is a function that returns a new instance of MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// MemoryStore is a struct that represents a store keeping the records in memory
+// - expired records are swept once a minute
+type MemoryStore struct {
+	// mu is the mutex that guards the records
+	mu sync.Mutex
+	// records are the records by key
+	records map[string]Record
+	// swept is when the records were last swept
+	swept time.Time
+}
+
+// sweep is a method that drops the expired records, at most once a minute
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, rec := range s.records {
+		if !now.Before(rec.Expires) {
+			delete(s.records, key)
+		}
+	}
+}
+
+// Begin is a method that stores a pending record for the key, or returns the record of the key if it exists
+func (s *MemoryStore) Begin(ctx context.Context, key string, rec Record, now time.Time) (existing Record, created bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	existing, ok := s.records[key]
+	if ok && now.Before(existing.Expires) {
+		return
+	}
+	s.records[key] = rec
+	created = true
+	return
+}
+
+// Complete is a method that stores the response of the pending record of the key
+func (s *MemoryStore) Complete(ctx context.Context, key string, rec Record) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.records[key] = rec
+	return
+}
+
+// Abort is a method that forgets the pending record of the key, so the request can be retried
+func (s *MemoryStore) Abort(ctx context.Context, key string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return
+}
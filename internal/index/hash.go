@@ -0,0 +1,49 @@
+package index
+
+// NewHash is a function that returns a new instance of Hash
+func NewHash() *Hash {
+	return &Hash{keys: make(map[string]map[int]struct{})}
+}
+
+// Hash is a struct that represents a secondary index of vehicle ids by an exact key (e.g. brand)
+// - it is not safe for concurrent use, the owner of the index must guard it
+type Hash struct {
+	// keys are the ids of the vehicles for each key
+	keys map[string]map[int]struct{}
+}
+
+// Add is a method that adds the id to the key
+func (h *Hash) Add(key string, id int) {
+	ids, ok := h.keys[key]
+	if !ok {
+		ids = make(map[int]struct{})
+		h.keys[key] = ids
+	}
+	ids[id] = struct{}{}
+}
+
+// Remove is a method that removes the id from the key
+func (h *Hash) Remove(key string, id int) {
+	ids, ok := h.keys[key]
+	if !ok {
+		return
+	}
+	delete(ids, id)
+	if len(ids) == 0 {
+		delete(h.keys, key)
+	}
+}
+
+// Count is a method that returns the number of ids of the key
+func (h *Hash) Count(key string) int {
+	return len(h.keys[key])
+}
+
+// Each is a method that calls fn for every id of the key, in no particular order, until fn returns false
+func (h *Hash) Each(key string, fn func(id int) bool) {
+	for id := range h.keys[key] {
+		if !fn(id) {
+			return
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package index
+
+import (
+	"app/internal"
+	"math/rand"
+)
+
+// orderedMaxLevel is the maximum number of levels of the skip list, enough for 4^32 entries
+const orderedMaxLevel = 32
+
+// orderedKey is a struct that represents an entry of the ordered index
+type orderedKey struct {
+	// value is the indexed value
+	value float64
+	// id is the id of the vehicle, it breaks ties between equal values
+	id int
+}
+
+// less is a method that returns true if the key goes before k
+func (o orderedKey) less(k orderedKey) bool {
+	if o.value != k.value {
+		return o.value < k.value
+	}
+	return o.id < k.id
+}
+
+// orderedNode is a struct that represents a node of the skip list
+type orderedNode struct {
+	// key is the entry of the node
+	key orderedKey
+	// next is the next node on each level
+	next []*orderedNode
+	// span is the number of entries skipped by next on each level
+	span []int
+}
+
+// NewOrdered is a function that returns a new instance of Ordered
+func NewOrdered() *Ordered {
+	return &Ordered{
+		head: &orderedNode{
+			next: make([]*orderedNode, orderedMaxLevel),
+			span: make([]int, orderedMaxLevel),
+		},
+		level: 1,
+	}
+}
+
+// Ordered is a struct that represents a secondary index of vehicle ids ordered by a numeric value (e.g. weight)
+// - it is an indexable skip list, so both lookups and counts of a range are O(log n)
+// - it is not safe for concurrent use, the owner of the index must guard it
+type Ordered struct {
+	// head is the sentinel node of the skip list
+	head *orderedNode
+	// level is the number of levels in use
+	level int
+	// length is the number of entries
+	length int
+}
+
+// randomLevel is a function that returns the level of a new node, each level with a probability of 1/4
+func randomLevel() int {
+	level := 1
+	for level < orderedMaxLevel && rand.Intn(4) == 0 {
+		level++
+	}
+	return level
+}
+
+// Add is a method that adds the id with its value
+func (o *Ordered) Add(value float64, id int) {
+	key := orderedKey{value: value, id: id}
+
+	// find the last node before the key on each level, and its rank
+	var update [orderedMaxLevel]*orderedNode
+	var rank [orderedMaxLevel]int
+	x := o.head
+	for i := o.level - 1; i >= 0; i-- {
+		if i < o.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && x.next[i].key.less(key) {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	level := randomLevel()
+	if level > o.level {
+		for i := o.level; i < level; i++ {
+			update[i] = o.head
+			o.head.span[i] = o.length
+		}
+		o.level = level
+	}
+
+	n := &orderedNode{
+		key:  key,
+		next: make([]*orderedNode, level),
+		span: make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < o.level; i++ {
+		update[i].span[i]++
+	}
+	o.length++
+}
+
+// Remove is a method that removes the id with its value
+func (o *Ordered) Remove(value float64, id int) {
+	key := orderedKey{value: value, id: id}
+
+	var update [orderedMaxLevel]*orderedNode
+	x := o.head
+	for i := o.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key.less(key) {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+
+	x = x.next[0]
+	if x == nil || x.key != key {
+		return
+	}
+	for i := 0; i < o.level; i++ {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	for o.level > 1 && o.head.next[o.level-1] == nil {
+		o.level--
+	}
+	o.length--
+}
+
+// countWhile is a method that returns the number of leading entries for which before returns true
+// - before must be true for a prefix of the entries and false for the rest
+func (o *Ordered) countWhile(before func(k orderedKey) bool) (n int) {
+	x := o.head
+	for i := o.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && before(x.next[i].key) {
+			n += x.span[i]
+			x = x.next[i]
+		}
+	}
+	return
+}
+
+// Count is a method that returns the number of ids whose value is within the range
+func (o *Ordered) Count(r internal.Range) int {
+	low, high := 0, o.length
+	if r.Min != nil {
+		low = o.countWhile(func(k orderedKey) bool { return k.value < *r.Min })
+	}
+	if r.Max != nil {
+		high = o.countWhile(func(k orderedKey) bool { return k.value <= *r.Max })
+	}
+	if high < low {
+		return 0
+	}
+	return high - low
+}
+
+// Ascend is a method that calls fn for every id whose value is within the range, in ascending order,
+// until fn returns false
+func (o *Ordered) Ascend(r internal.Range, fn func(id int) bool) {
+	x := o.head
+	if r.Min != nil {
+		for i := o.level - 1; i >= 0; i-- {
+			for x.next[i] != nil && x.next[i].key.value < *r.Min {
+				x = x.next[i]
+			}
+		}
+	}
+
+	for x = x.next[0]; x != nil; x = x.next[0] {
+		if r.Max != nil && x.key.value > *r.Max {
+			return
+		}
+		if !fn(x.key.id) {
+			return
+		}
+	}
+}
+
+// First is a method that returns the lowest value of the index, ok is false if it is empty
+func (o *Ordered) First() (value float64, ok bool) {
+	if n := o.head.next[0]; n != nil {
+		return n.key.value, true
+	}
+	return
+}
+
+// Last is a method that returns the highest value of the index, ok is false if it is empty
+func (o *Ordered) Last() (value float64, ok bool) {
+	x := o.head
+	for i := o.level - 1; i >= 0; i-- {
+		for x.next[i] != nil {
+			x = x.next[i]
+		}
+	}
+	if x == o.head {
+		return
+	}
+	return x.key.value, true
+}
+
+// Len is a method that returns the number of ids in the index
+func (o *Ordered) Len() int {
+	return o.length
+}
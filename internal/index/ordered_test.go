@@ -0,0 +1,272 @@
+package index
+
+import (
+	"app/internal"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// float is a function that returns a pointer to the value, for the bounds of a range
+func float(v float64) *float64 {
+	return &v
+}
+
+// requireSpans is a function that checks the bookkeeping of the skip list
+// - on each level, the span of a node is the number of entries between it and its next node (or the end of the list)
+// - the entries are in ascending order of value, then id
+func requireSpans(t *testing.T, o *Ordered) {
+	t.Helper()
+
+	// rank of each node on the level 0
+	rank := map[*orderedNode]int{o.head: 0}
+	n := 0
+	for x := o.head.next[0]; x != nil; x = x.next[0] {
+		n++
+		rank[x] = n
+		if x.next[0] != nil {
+			require.True(t, x.key.less(x.next[0].key), "entries out of order: %v before %v", x.key, x.next[0].key)
+		}
+	}
+	require.Equal(t, o.length, n)
+
+	for i := 0; i < o.level; i++ {
+		for x := o.head; x != nil; x = x.next[i] {
+			next := o.length
+			if x.next[i] != nil {
+				r, ok := rank[x.next[i]]
+				require.True(t, ok, "node of level %d missing from level 0", i)
+				next = r
+			}
+			require.Equal(t, next-rank[x], x.span[i], "span of the node of rank %d on level %d", rank[x], i)
+		}
+	}
+}
+
+// ascend is a function that returns the ids of the range, in the order of Ascend
+func ascend(o *Ordered, r internal.Range) (ids []int) {
+	o.Ascend(r, func(id int) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return
+}
+
+func TestOrdered_Add(t *testing.T) {
+	t.Run("case 1: entries are ordered by value then id", func(t *testing.T) {
+		// arrange
+		o := NewOrdered()
+
+		// act
+		o.Add(2, 1)
+		o.Add(1, 2)
+		o.Add(2, 0)
+		o.Add(3, 3)
+
+		// assert
+		requireSpans(t, o)
+		require.Equal(t, 4, o.Len())
+		require.Equal(t, []int{2, 0, 1, 3}, ascend(o, internal.Range{}))
+	})
+
+	t.Run("case 2: duplicate values are counted once per id", func(t *testing.T) {
+		// arrange
+		o := NewOrdered()
+
+		// act
+		for id := 10; id > 0; id-- {
+			o.Add(5, id)
+		}
+		o.Add(4, 11)
+		o.Add(6, 12)
+
+		// assert
+		requireSpans(t, o)
+		require.Equal(t, 10, o.Count(internal.NewRange(5, 5)))
+		require.Equal(t, 11, o.Count(internal.Range{Max: float(5)}))
+		require.Equal(t, 11, o.Count(internal.Range{Min: float(5)}))
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ascend(o, internal.NewRange(5, 5)))
+	})
+}
+
+func TestOrdered_Remove(t *testing.T) {
+	// newOrdered returns an index of the values 1 to 5 with the ids 1 to 5, and 3 twice with the ids 3 and 6
+	newOrdered := func() *Ordered {
+		o := NewOrdered()
+		for id := 1; id <= 5; id++ {
+			o.Add(float64(id), id)
+		}
+		o.Add(3, 6)
+		return o
+	}
+
+	t.Run("case 1: remove the head", func(t *testing.T) {
+		// arrange
+		o := newOrdered()
+
+		// act
+		o.Remove(1, 1)
+
+		// assert
+		requireSpans(t, o)
+		first, ok := o.First()
+		require.True(t, ok)
+		require.Equal(t, 2.0, first)
+		require.Equal(t, []int{2, 3, 6, 4, 5}, ascend(o, internal.Range{}))
+	})
+
+	t.Run("case 2: remove the tail", func(t *testing.T) {
+		// arrange
+		o := newOrdered()
+
+		// act
+		o.Remove(5, 5)
+
+		// assert
+		requireSpans(t, o)
+		last, ok := o.Last()
+		require.True(t, ok)
+		require.Equal(t, 4.0, last)
+		require.Equal(t, 4, o.Count(internal.Range{Min: float(2)}))
+	})
+
+	t.Run("case 3: remove one of duplicate values", func(t *testing.T) {
+		// arrange
+		o := newOrdered()
+
+		// act
+		o.Remove(3, 3)
+
+		// assert
+		requireSpans(t, o)
+		require.Equal(t, 1, o.Count(internal.NewRange(3, 3)))
+		require.Equal(t, []int{6}, ascend(o, internal.NewRange(3, 3)))
+	})
+
+	t.Run("case 4: remove a missing entry", func(t *testing.T) {
+		// arrange
+		o := newOrdered()
+
+		// act
+		o.Remove(4, 3)
+		o.Remove(7, 7)
+
+		// assert
+		requireSpans(t, o)
+		require.Equal(t, 6, o.Len())
+	})
+
+	t.Run("case 5: remove every entry", func(t *testing.T) {
+		// arrange
+		o := newOrdered()
+
+		// act
+		for _, e := range []orderedKey{{5, 5}, {1, 1}, {3, 6}, {3, 3}, {4, 4}, {2, 2}} {
+			o.Remove(e.value, e.id)
+			requireSpans(t, o)
+		}
+
+		// assert
+		require.Equal(t, 0, o.Len())
+		require.Equal(t, 1, o.level)
+		_, ok := o.First()
+		require.False(t, ok)
+		_, ok = o.Last()
+		require.False(t, ok)
+	})
+}
+
+func TestOrdered_Count(t *testing.T) {
+	o := NewOrdered()
+	for id, value := range []float64{1, 2, 2, 3, 5, 8} {
+		o.Add(value, id)
+	}
+
+	cases := []struct {
+		name     string
+		rg       internal.Range
+		expected int
+	}{
+		{name: "case 1: every entry", rg: internal.Range{}, expected: 6},
+		{name: "case 2: closed range", rg: internal.NewRange(2, 5), expected: 4},
+		{name: "case 3: open below", rg: internal.Range{Max: float(2)}, expected: 3},
+		{name: "case 4: open above", rg: internal.Range{Min: float(3)}, expected: 3},
+		{name: "case 5: between entries", rg: internal.NewRange(6, 7), expected: 0},
+		{name: "case 6: out of the entries", rg: internal.Range{Min: float(9)}, expected: 0},
+		{name: "case 7: inverted range", rg: internal.NewRange(5, 2), expected: 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			count := o.Count(c.rg)
+
+			// assert
+			require.Equal(t, c.expected, count)
+			if c.expected > 0 {
+				require.Len(t, ascend(o, c.rg), c.expected)
+			}
+		})
+	}
+}
+
+func TestOrdered_Ascend(t *testing.T) {
+	t.Run("case 1: stop when fn returns false", func(t *testing.T) {
+		// arrange
+		o := NewOrdered()
+		for id := 0; id < 10; id++ {
+			o.Add(float64(id), id)
+		}
+
+		// act
+		var ids []int
+		o.Ascend(internal.Range{Min: float(4)}, func(id int) bool {
+			ids = append(ids, id)
+			return len(ids) < 3
+		})
+
+		// assert
+		require.Equal(t, []int{4, 5, 6}, ids)
+	})
+
+	t.Run("case 2: random adds and removes match a sorted slice", func(t *testing.T) {
+		// arrange
+		o := NewOrdered()
+		rng := rand.New(rand.NewSource(30))
+		entries := map[int]float64{}
+
+		// act
+		for i := 0; i < 2000; i++ {
+			id := rng.Intn(300)
+			if value, ok := entries[id]; ok {
+				o.Remove(value, id)
+				delete(entries, id)
+				continue
+			}
+			// - few distinct values, so most of them are duplicated
+			value := float64(rng.Intn(20))
+			o.Add(value, id)
+			entries[id] = value
+		}
+
+		// assert
+		requireSpans(t, o)
+		expected := make([]orderedKey, 0, len(entries))
+		for id, value := range entries {
+			expected = append(expected, orderedKey{value: value, id: id})
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i].less(expected[j]) })
+		for low := 0.0; low < 20; low += 3 {
+			rg := internal.NewRange(low, low+4)
+			var ids []int
+			for _, e := range expected {
+				if rg.Contains(e.value) {
+					ids = append(ids, e.id)
+				}
+			}
+			require.Equal(t, len(ids), o.Count(rg))
+			require.Equal(t, ids, ascend(o, rg))
+		}
+	})
+}
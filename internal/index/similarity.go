@@ -0,0 +1,246 @@
+package index
+
+import (
+	"app/internal"
+	"container/heap"
+	"math"
+	"sync"
+)
+
+// SimilarityWeights is a struct that represents the weight of each attribute in the distance between vehicles
+// - numeric attributes are compared after being normalized by their standard deviation across the fleet
+// - categorical attributes add their weight to the distance when they do not match
+type SimilarityWeights struct {
+	// MaxSpeed is the weight of the maximum speed
+	MaxSpeed float64
+	// Capacity is the weight of the capacity of people
+	Capacity float64
+	// Weight is the weight of the weight
+	Weight float64
+	// Height is the weight of the height
+	Height float64
+	// Length is the weight of the length
+	Length float64
+	// Width is the weight of the width
+	Width float64
+	// FuelType is the weight of a different fuel type
+	FuelType float64
+	// Transmission is the weight of a different transmission
+	Transmission float64
+}
+
+// DefaultSimilarityWeights is a function that returns the default weights, every attribute counts the same
+func DefaultSimilarityWeights() SimilarityWeights {
+	return SimilarityWeights{
+		MaxSpeed:     1,
+		Capacity:     1,
+		Weight:       1,
+		Height:       1,
+		Length:       1,
+		Width:        1,
+		FuelType:     1,
+		Transmission: 1,
+	}
+}
+
+// numericFeatures is the number of numeric attributes compared between vehicles
+const numericFeatures = 6
+
+// similarityEntry is a struct that represents a vehicle stored in the index
+type similarityEntry struct {
+	// vehicle is the indexed vehicle
+	vehicle internal.Vehicle
+	// features are the numeric attributes of the vehicle
+	features [numericFeatures]float64
+}
+
+// runningStats is a struct that represents the running moments of a numeric attribute
+type runningStats struct {
+	// n is the number of values
+	n float64
+	// sum is the sum of the values
+	sum float64
+	// sumSquares is the sum of the squares of the values
+	sumSquares float64
+}
+
+// add is a method that adds (sign 1) or removes (sign -1) a value from the stats
+func (s *runningStats) add(value, sign float64) {
+	s.n += sign
+	s.sum += sign * value
+	s.sumSquares += sign * value * value
+}
+
+// stdDev is a method that returns the standard deviation, or 1 if it is not defined
+func (s *runningStats) stdDev() float64 {
+	if s.n < 2 {
+		return 1
+	}
+	mean := s.sum / s.n
+	variance := s.sumSquares/s.n - mean*mean
+	if variance <= 0 {
+		return 1
+	}
+	return math.Sqrt(variance)
+}
+
+// NewSimilarity is a function that returns a new instance of Similarity
+func NewSimilarity(weights SimilarityWeights) *Similarity {
+	return &Similarity{
+		weights:  weights,
+		position: make(map[int]int),
+	}
+}
+
+// Similarity is a struct that represents an in-memory nearest neighbour index of vehicles
+// - it implements internal.VehicleObserver to stay updated with the repository
+type Similarity struct {
+	// mu is the mutex that guards the index
+	mu sync.RWMutex
+	// weights are the weights of the attributes in the distance
+	weights SimilarityWeights
+	// entries are the indexed vehicles, stored contiguously to be scanned fast
+	entries []similarityEntry
+	// position is the position of each vehicle id in entries
+	position map[int]int
+	// stats are the running moments of each numeric attribute, used for normalization
+	stats [numericFeatures]runningStats
+}
+
+// features is a function that returns the numeric attributes of a vehicle
+func features(v internal.Vehicle) [numericFeatures]float64 {
+	return [numericFeatures]float64{v.MaxSpeed, float64(v.Capacity), v.Weight, v.Height, v.Length, v.Width}
+}
+
+// OnVehicleEvent is a method that updates the index with a change made to a vehicle
+func (s *Similarity) OnVehicleEvent(e internal.VehicleEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Type {
+	case internal.VehicleCreated:
+		s.insert(e.Vehicle)
+	case internal.VehicleUpdated:
+		s.remove(e.Previous.Id)
+		s.insert(e.Vehicle)
+	case internal.VehicleDeleted:
+		s.remove(e.Previous.Id)
+	}
+}
+
+// insert is a method that adds a vehicle to the index
+func (s *Similarity) insert(v internal.Vehicle) {
+	if _, ok := s.position[v.Id]; ok {
+		s.remove(v.Id)
+	}
+
+	entry := similarityEntry{vehicle: v, features: features(v)}
+	for i, value := range entry.features {
+		s.stats[i].add(value, 1)
+	}
+	s.position[v.Id] = len(s.entries)
+	s.entries = append(s.entries, entry)
+}
+
+// remove is a method that removes a vehicle from the index, swapping it with the last entry
+func (s *Similarity) remove(id int) {
+	pos, ok := s.position[id]
+	if !ok {
+		return
+	}
+
+	for i, value := range s.entries[pos].features {
+		s.stats[i].add(value, -1)
+	}
+	last := len(s.entries) - 1
+	s.entries[pos] = s.entries[last]
+	s.position[s.entries[pos].vehicle.Id] = pos
+	s.entries = s.entries[:last]
+	delete(s.position, id)
+}
+
+// distance is a method that returns the weighted distance between two entries
+func (s *Similarity) distance(a, b *similarityEntry, scales *[numericFeatures]float64) (d float64) {
+	weights := [numericFeatures]float64{
+		s.weights.MaxSpeed, s.weights.Capacity, s.weights.Weight,
+		s.weights.Height, s.weights.Length, s.weights.Width,
+	}
+	for i := range a.features {
+		diff := (a.features[i] - b.features[i]) / scales[i]
+		d += weights[i] * diff * diff
+	}
+	if a.vehicle.FuelType != b.vehicle.FuelType {
+		d += s.weights.FuelType
+	}
+	if a.vehicle.Transmission != b.vehicle.Transmission {
+		d += s.weights.Transmission
+	}
+	return math.Sqrt(d)
+}
+
+// Nearest is a method that returns the k vehicles closest to the vehicle with the given id
+// - the vehicles are sorted by distance, ties are broken by id
+func (s *Similarity) Nearest(id int, k int) (v []internal.SimilarVehicle, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	pos, ok := s.position[id]
+	if !ok {
+		return nil, internal.ErrVehicleNotFound
+	}
+	reference := &s.entries[pos]
+
+	var scales [numericFeatures]float64
+	for i := range s.stats {
+		scales[i] = s.stats[i].stdDev()
+	}
+
+	// keep the k closest vehicles in a max-heap
+	h := &similarHeap{}
+	for i := range s.entries {
+		if i == pos {
+			continue
+		}
+		candidate := internal.SimilarVehicle{
+			Vehicle:  s.entries[i].vehicle,
+			Distance: s.distance(reference, &s.entries[i], &scales),
+		}
+		if h.Len() < k {
+			heap.Push(h, candidate)
+			continue
+		}
+		if k > 0 && h.less((*h)[0], candidate) {
+			(*h)[0] = candidate
+			heap.Fix(h, 0)
+		}
+	}
+
+	// pop from the farthest to the closest
+	v = make([]internal.SimilarVehicle, h.Len())
+	for i := len(v) - 1; i >= 0; i-- {
+		v[i] = heap.Pop(h).(internal.SimilarVehicle)
+	}
+	return v, nil
+}
+
+// similarHeap is a max-heap of similar vehicles, the farthest vehicle is on top
+type similarHeap []internal.SimilarVehicle
+
+// less is a method that returns true if a is farther than b from the reference vehicle
+func (h similarHeap) less(a, b internal.SimilarVehicle) bool {
+	if a.Distance != b.Distance {
+		return a.Distance > b.Distance
+	}
+	return a.Id > b.Id
+}
+
+func (h similarHeap) Len() int           { return len(h) }
+func (h similarHeap) Less(i, j int) bool { return h.less(h[i], h[j]) }
+func (h similarHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *similarHeap) Push(x any)        { *h = append(*h, x.(internal.SimilarVehicle)) }
+func (h *similarHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,229 @@
+package index
+
+import (
+	"app/internal"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// similarVehicle is a function that returns a vehicle with the attributes compared by the similarity
+func similarVehicle(id int, maxSpeed float64, fuelType, transmission string) internal.Vehicle {
+	return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{
+		MaxSpeed: maxSpeed, FuelType: fuelType, Transmission: transmission, Capacity: 5, Weight: 1500,
+		Dimensions: internal.Dimensions{Height: 150, Length: 450, Width: 180},
+	}}
+}
+
+// newTestSimilarity is a function that returns a similarity index of the vehicles, created in order
+func newTestSimilarity(weights SimilarityWeights, vehicles ...internal.Vehicle) *Similarity {
+	s := NewSimilarity(weights)
+	for _, v := range vehicles {
+		s.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: v})
+	}
+	return s
+}
+
+// nearestIds is a function that returns the ids of the similar vehicles, in order
+func nearestIds(v []internal.SimilarVehicle) (ids []int) {
+	ids = []int{}
+	for _, sv := range v {
+		ids = append(ids, sv.Id)
+	}
+	return
+}
+
+func TestSimilarity_Distance(t *testing.T) {
+	// weights is a function that returns the weights of every attribute zero, but the ones set by fn
+	weights := func(fn func(w *SimilarityWeights)) SimilarityWeights {
+		w := SimilarityWeights{}
+		fn(&w)
+		return w
+	}
+
+	cases := []struct {
+		name     string
+		weights  SimilarityWeights
+		other    internal.Vehicle
+		distance float64
+	}{
+		{
+			// - two speeds 100 apart have a standard deviation of 50, so they are 2 deviations apart
+			name:     "case 1: numeric attributes are normalized by their standard deviation",
+			weights:  DefaultSimilarityWeights(),
+			other:    similarVehicle(2, 200, "gasoline", "manual"),
+			distance: 2,
+		},
+		{
+			name:     "case 2: the distance does not depend on the scale of the attribute",
+			weights:  DefaultSimilarityWeights(),
+			other:    similarVehicle(2, 10100, "gasoline", "manual"),
+			distance: 2,
+		},
+		{
+			name:     "case 3: the weight of a numeric attribute multiplies its squared difference",
+			weights:  weights(func(w *SimilarityWeights) { w.MaxSpeed = 4 }),
+			other:    similarVehicle(2, 200, "gasoline", "manual"),
+			distance: 4,
+		},
+		{
+			name:     "case 4: a different fuel type adds its weight",
+			weights:  weights(func(w *SimilarityWeights) { w.FuelType = 9 }),
+			other:    similarVehicle(2, 100, "diesel", "manual"),
+			distance: 3,
+		},
+		{
+			name:     "case 5: a different transmission adds its weight",
+			weights:  weights(func(w *SimilarityWeights) { w.MaxSpeed = 1; w.Transmission = 12 }),
+			other:    similarVehicle(2, 200, "gasoline", "automatic"),
+			distance: 4,
+		},
+		{
+			name:     "case 6: attributes without weight do not count",
+			weights:  weights(func(w *SimilarityWeights) { w.FuelType = 1 }),
+			other:    similarVehicle(2, 200, "gasoline", "automatic"),
+			distance: 0,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			s := newTestSimilarity(c.weights, similarVehicle(1, 100, "gasoline", "manual"), c.other)
+
+			// act
+			v, err := s.Nearest(1, 1)
+
+			// assert
+			require.NoError(t, err)
+			require.Len(t, v, 1)
+			require.Equal(t, c.other, v[0].Vehicle)
+			require.InDelta(t, c.distance, v[0].Distance, 1e-9)
+		})
+	}
+}
+
+func TestSimilarity_Nearest(t *testing.T) {
+	// the vehicles 2 to 5 are all as far from the vehicle 1, the vehicle 6 is the closest
+	vehicles := []internal.Vehicle{
+		similarVehicle(5, 200, "gasoline", "manual"),
+		similarVehicle(1, 150, "gasoline", "manual"),
+		similarVehicle(3, 200, "gasoline", "manual"),
+		similarVehicle(4, 100, "gasoline", "manual"),
+		similarVehicle(2, 100, "gasoline", "manual"),
+		similarVehicle(6, 160, "gasoline", "manual"),
+	}
+
+	cases := []struct {
+		name string
+		id   int
+		k    int
+		// ids are the ids of the similar vehicles, in order
+		ids []int
+		err error
+	}{
+		{name: "case 1: sorted by distance, ties broken by id", id: 1, k: 5, ids: []int{6, 2, 3, 4, 5}},
+		{name: "case 2: the ties with the lowest ids are kept", id: 1, k: 3, ids: []int{6, 2, 3}},
+		{name: "case 3: k larger than the fleet", id: 1, k: 100, ids: []int{6, 2, 3, 4, 5}},
+		{name: "case 4: k zero", id: 1, k: 0, ids: []int{}},
+		{name: "case 5: the vehicle itself is never similar", id: 2, k: 1, ids: []int{4}},
+		{name: "case 6: unknown vehicle", id: 7, k: 1, err: internal.ErrVehicleNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			s := newTestSimilarity(DefaultSimilarityWeights(), vehicles...)
+
+			// act
+			v, err := s.Nearest(c.id, c.k)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			if c.err != nil {
+				return
+			}
+			require.Equal(t, c.ids, nearestIds(v))
+		})
+	}
+}
+
+func TestSimilarity_OnVehicleEvent(t *testing.T) {
+	cases := []struct {
+		name   string
+		events []internal.VehicleEvent
+		// fleet are the vehicles after the events
+		fleet []internal.Vehicle
+		// ids are the ids of the vehicles similar to the vehicle 1, in order
+		ids []int
+	}{
+		{
+			name: "case 1: an updated vehicle is compared by its new attributes",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleUpdated, Previous: similarVehicle(2, 110, "gasoline", "manual"), Vehicle: similarVehicle(2, 300, "gasoline", "manual")},
+			},
+			fleet: []internal.Vehicle{similarVehicle(1, 100, "gasoline", "manual"), similarVehicle(2, 300, "gasoline", "manual"), similarVehicle(3, 150, "gasoline", "manual")},
+			ids:   []int{3, 2},
+		},
+		{
+			name: "case 2: a deleted vehicle is not similar anymore",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: similarVehicle(2, 110, "gasoline", "manual")},
+			},
+			fleet: []internal.Vehicle{similarVehicle(1, 100, "gasoline", "manual"), similarVehicle(3, 150, "gasoline", "manual")},
+			ids:   []int{3},
+		},
+		{
+			name: "case 3: a vehicle created again after being deleted",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: similarVehicle(1, 100, "gasoline", "manual")},
+				{Type: internal.VehicleCreated, Vehicle: similarVehicle(1, 140, "diesel", "manual")},
+			},
+			fleet: []internal.Vehicle{similarVehicle(2, 110, "gasoline", "manual"), similarVehicle(3, 150, "gasoline", "manual"), similarVehicle(1, 140, "diesel", "manual")},
+			ids:   []int{3, 2},
+		},
+		{
+			name: "case 4: every vehicle but the reference deleted",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: similarVehicle(3, 150, "gasoline", "manual")},
+				{Type: internal.VehicleDeleted, Previous: similarVehicle(2, 110, "gasoline", "manual")},
+			},
+			fleet: []internal.Vehicle{similarVehicle(1, 100, "gasoline", "manual")},
+			ids:   []int{},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			s := newTestSimilarity(DefaultSimilarityWeights(),
+				similarVehicle(1, 100, "gasoline", "manual"),
+				similarVehicle(2, 110, "gasoline", "manual"),
+				similarVehicle(3, 150, "gasoline", "manual"),
+			)
+
+			// act
+			for _, e := range c.events {
+				s.OnVehicleEvent(e)
+			}
+
+			// assert
+			v, err := s.Nearest(1, 10)
+			require.NoError(t, err)
+			require.Equal(t, c.ids, nearestIds(v))
+			// - the positions and the stats are those of an index of the fleet
+			rebuilt := newTestSimilarity(DefaultSimilarityWeights(), c.fleet...)
+			require.Len(t, s.entries, len(c.fleet))
+			for id, pos := range s.position {
+				require.Equal(t, id, s.entries[pos].vehicle.Id)
+			}
+			for _, fv := range c.fleet {
+				got, err := s.Nearest(fv.Id, 10)
+				require.NoError(t, err)
+				expected, err := rebuilt.Nearest(fv.Id, 10)
+				require.NoError(t, err)
+				require.Equal(t, nearestIds(expected), nearestIds(got))
+				for i := range expected {
+					require.InDelta(t, expected[i].Distance, got[i].Distance, 1e-9)
+				}
+			}
+		})
+	}
+}
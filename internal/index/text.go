@@ -0,0 +1,268 @@
+package index
+
+import (
+	"app/internal"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// textField is a bitmask of the attributes of a vehicle where a term appears
+type textField uint8
+
+const (
+	// textFieldBrand is the brand of the vehicle
+	textFieldBrand textField = 1 << iota
+	// textFieldModel is the model of the vehicle
+	textFieldModel
+	// textFieldColor is the color of the vehicle
+	textFieldColor
+)
+
+// boost is a method that returns the weight of the most relevant field of the mask
+func (f textField) boost() float64 {
+	switch {
+	case f&textFieldBrand != 0:
+		return 3
+	case f&textFieldModel != 0:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// relevance of a term depending on how it matched a token of the search
+const (
+	scoreExact       = 1.0
+	scorePrefix      = 0.8
+	scoreFuzzy       = 0.6
+	scoreFuzzyPrefix = 0.4
+)
+
+// Tokenize is a function that splits a text into lower case terms of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxEdits is a function that returns the edit distance tolerated for a token
+// - short tokens must match exactly, longer tokens tolerate more typos
+func maxEdits(token string) int {
+	switch n := len([]rune(token)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// NewText is a function that returns a new instance of Text
+func NewText() *Text {
+	return &Text{
+		postings: make(map[string]map[int]textField),
+		docs:     make(map[int]internal.Vehicle),
+	}
+}
+
+// Text is a struct that represents an in-memory inverted index over the brand, model and color of the vehicles
+// - it implements internal.VehicleObserver to stay updated with the repository
+type Text struct {
+	// mu is the mutex that guards the index
+	mu sync.RWMutex
+	// postings are the vehicles where each term appears, with the fields it appears in
+	postings map[string]map[int]textField
+	// terms is the sorted vocabulary, used for prefix and fuzzy matching
+	terms []string
+	// docs are the indexed vehicles
+	docs map[int]internal.Vehicle
+}
+
+// OnVehicleEvent is a method that updates the index with a change made to a vehicle
+func (t *Text) OnVehicleEvent(e internal.VehicleEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch e.Type {
+	case internal.VehicleCreated:
+		t.insert(e.Vehicle)
+	case internal.VehicleUpdated:
+		t.remove(e.Previous.Id)
+		t.insert(e.Vehicle)
+	case internal.VehicleDeleted:
+		t.remove(e.Previous.Id)
+	}
+}
+
+// fields is a function that returns the terms of a vehicle and the fields they appear in
+func fields(v internal.Vehicle) map[string]textField {
+	terms := make(map[string]textField)
+	for field, text := range map[textField]string{
+		textFieldBrand: v.Brand,
+		textFieldModel: v.Model,
+		textFieldColor: v.Color,
+	} {
+		for _, term := range Tokenize(text) {
+			terms[term] |= field
+		}
+	}
+	return terms
+}
+
+// insert is a method that adds a vehicle to the index
+func (t *Text) insert(v internal.Vehicle) {
+	if _, ok := t.docs[v.Id]; ok {
+		t.remove(v.Id)
+	}
+
+	t.docs[v.Id] = v
+	for term, field := range fields(v) {
+		posting, ok := t.postings[term]
+		if !ok {
+			posting = make(map[int]textField)
+			t.postings[term] = posting
+
+			// keep the vocabulary sorted
+			i := sort.SearchStrings(t.terms, term)
+			t.terms = append(t.terms, "")
+			copy(t.terms[i+1:], t.terms[i:])
+			t.terms[i] = term
+		}
+		posting[v.Id] = field
+	}
+}
+
+// remove is a method that removes a vehicle from the index
+func (t *Text) remove(id int) {
+	v, ok := t.docs[id]
+	if !ok {
+		return
+	}
+
+	delete(t.docs, id)
+	for term := range fields(v) {
+		posting := t.postings[term]
+		delete(posting, id)
+		if len(posting) > 0 {
+			continue
+		}
+
+		// drop the term from the vocabulary
+		delete(t.postings, term)
+		i := sort.SearchStrings(t.terms, term)
+		t.terms = append(t.terms[:i], t.terms[i+1:]...)
+	}
+}
+
+// match is a method that returns the terms of the vocabulary matching a token, with their relevance
+// - exact match, prefix match, and typos within maxEdits of the whole term or of its prefix
+func (t *Text) match(token string) map[string]float64 {
+	matches := make(map[string]float64)
+	if _, ok := t.postings[token]; ok {
+		matches[token] = scoreExact
+	}
+
+	// prefix
+	for i := sort.SearchStrings(t.terms, token); i < len(t.terms) && strings.HasPrefix(t.terms[i], token); i++ {
+		if _, ok := matches[t.terms[i]]; !ok {
+			matches[t.terms[i]] = scorePrefix
+		}
+	}
+
+	// typos
+	edits := maxEdits(token)
+	if edits == 0 {
+		return matches
+	}
+	runes := []rune(token)
+	for _, term := range t.terms {
+		if _, ok := matches[term]; ok {
+			continue
+		}
+		termRunes := []rune(term)
+		if d := levenshtein(runes, termRunes, edits); d <= edits {
+			matches[term] = scoreFuzzy / float64(d)
+			continue
+		}
+		if len(termRunes) > len(runes) {
+			if d := levenshtein(runes, termRunes[:len(runes)], edits); d <= edits {
+				matches[term] = scoreFuzzyPrefix / float64(d)
+			}
+		}
+	}
+	return matches
+}
+
+// Search is a method that returns up to limit vehicles matching the text, sorted by relevance
+// - each token of the text adds the relevance of its best matching term, weighted by its rarity and field
+func (t *Text) Search(text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	scores := make(map[int]float64)
+	for _, token := range Tokenize(text) {
+		best := make(map[int]float64)
+		for term, relevance := range t.match(token) {
+			posting := t.postings[term]
+			idf := math.Log(1 + float64(len(t.docs))/float64(len(posting)))
+			for id, field := range posting {
+				if score := relevance * idf * field.boost(); score > best[id] {
+					best[id] = score
+				}
+			}
+		}
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	v = make([]internal.VehicleSearchResult, 0, len(scores))
+	for id, score := range scores {
+		v = append(v, internal.VehicleSearchResult{Vehicle: t.docs[id], Score: score})
+	}
+	sort.Slice(v, func(i, j int) bool {
+		if v[i].Score != v[j].Score {
+			return v[i].Score > v[j].Score
+		}
+		return v[i].Id < v[j].Id
+	})
+	if limit > 0 && len(v) > limit {
+		v = v[:limit]
+	}
+	return
+}
+
+// levenshtein is a function that returns the edit distance between a and b
+// - it gives up as soon as the distance exceeds maxDistance, returning maxDistance + 1
+func levenshtein(a, b []rune, maxDistance int) int {
+	if diff := len(a) - len(b); diff > maxDistance || -diff > maxDistance {
+		return maxDistance + 1
+	}
+
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			rowMin = min(rowMin, current[j])
+		}
+		if rowMin > maxDistance {
+			return maxDistance + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
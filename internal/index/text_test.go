@@ -0,0 +1,214 @@
+package index
+
+import (
+	"app/internal"
+	"app/internal/loader"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// textVehicle is a function that returns a vehicle with the attributes indexed by the text index
+func textVehicle(id int, brand, model, color string) internal.Vehicle {
+	return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{Brand: brand, Model: model, Color: color}}
+}
+
+// newTestText is a function that returns a text index of the vehicles
+func newTestText(vehicles ...internal.Vehicle) *Text {
+	t := NewText()
+	for _, v := range vehicles {
+		t.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: v})
+	}
+	return t
+}
+
+// searchIds is a function that returns the ids of the vehicles found for the text, in order
+func searchIds(t *testing.T, tx *Text, text string, limit int) (ids []int) {
+	t.Helper()
+
+	v, err := tx.Search(text, limit)
+	require.NoError(t, err)
+	ids = []int{}
+	for _, r := range v {
+		ids = append(ids, r.Id)
+	}
+	return
+}
+
+// requireVocabulary is a function that checks the terms of the index are the sorted terms of its postings
+func requireVocabulary(t *testing.T, tx *Text) {
+	t.Helper()
+
+	terms := []string{}
+	for term, posting := range tx.postings {
+		require.NotEmpty(t, posting, "empty posting of %q", term)
+		for id := range posting {
+			require.Contains(t, tx.docs, id, "posting of %q with a vehicle not indexed", term)
+		}
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+	require.Equal(t, terms, append([]string{}, tx.terms...))
+}
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		tokens []string
+	}{
+		{name: "case 1: words in lower case", text: "Chevrolet Cavalier", tokens: []string{"chevrolet", "cavalier"}},
+		{name: "case 2: digits are kept", text: "3500 Club Coupe", tokens: []string{"3500", "club", "coupe"}},
+		{name: "case 3: punctuation splits the terms", text: "G-Series 2500, Mercedes-Benz!", tokens: []string{"g", "series", "2500", "mercedes", "benz"}},
+		{name: "case 4: letters with accents", text: "Citroën Méhari", tokens: []string{"citroën", "méhari"}},
+		{name: "case 5: no term", text: " - ", tokens: []string{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			tokens := Tokenize(c.text)
+
+			// assert
+			require.Equal(t, c.tokens, tokens)
+		})
+	}
+}
+
+func TestText_Search(t *testing.T) {
+	tx := newTestText(
+		textVehicle(1, "Ford", "Focus", "Blue"),
+		textVehicle(2, "Ford", "Fiesta", "Red"),
+		textVehicle(3, "Fiat", "Panda", "Red"),
+		textVehicle(4, "Toyota", "Corolla", "Blue"),
+		textVehicle(5, "Cooper", "Mini", "Green"),
+		textVehicle(6, "Mini", "Cooper", "Black"),
+		textVehicle(7, "Acme", "Pandamonium", "Black"),
+	)
+
+	cases := []struct {
+		name  string
+		text  string
+		limit int
+		// ids are the ids of the vehicles found, in order
+		ids []int
+	}{
+		{name: "case 1: exact term, ties broken by id", text: "ford", ids: []int{1, 2}},
+		{name: "case 2: case insensitive", text: "FORD", ids: []int{1, 2}},
+		{name: "case 3: prefix", text: "cor", ids: []int{4}},
+		{name: "case 4: an exact match ranks above a prefix match", text: "panda", ids: []int{3, 7}},
+		{name: "case 5: a brand ranks above a model", text: "cooper", ids: []int{5, 6}},
+		{name: "case 6: a model ranks above a color", text: "mini", ids: []int{6, 5}},
+		{name: "case 7: one typo in a token of 4 to 7 letters", text: "fokus", ids: []int{1}},
+		{name: "case 8: two typos in a token of 4 to 7 letters", text: "fxcxs", ids: []int{}},
+		{name: "case 9: no typo in a token of less than 4 letters", text: "rad", ids: []int{}},
+		{name: "case 10: a typo in the prefix of a term", text: "pandq", ids: []int{3, 7}},
+		{name: "case 11: two typos in a token of 8 letters or more", text: "pondamonim", ids: []int{7}},
+		{name: "case 12: each token adds its relevance", text: "red fiat", ids: []int{3, 2}},
+		{name: "case 13: the rarer term ranks higher", text: "black green", ids: []int{5, 6, 7}},
+		{name: "case 14: limit", text: "ford", limit: 1, ids: []int{1}},
+		{name: "case 15: no match", text: "tesla", ids: []int{}},
+		{name: "case 16: empty text", text: "", ids: []int{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			ids := searchIds(t, tx, c.text, c.limit)
+
+			// assert
+			require.Equal(t, c.ids, ids)
+		})
+	}
+}
+
+func TestText_OnVehicleEvent(t *testing.T) {
+	cases := []struct {
+		name   string
+		events []internal.VehicleEvent
+		// found are the ids of the vehicles found by each text
+		found map[string][]int
+	}{
+		{
+			name: "case 1: an updated vehicle is found by its new terms only",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleUpdated, Previous: textVehicle(1, "Ford", "Focus", "Blue"), Vehicle: textVehicle(1, "Ford", "Mustang", "Blue")},
+			},
+			found: map[string][]int{"focus": {}, "mustang": {1}, "ford": {1, 2}},
+		},
+		{
+			name: "case 2: a deleted vehicle is not found",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: textVehicle(2, "Ford", "Fiesta", "Red")},
+			},
+			found: map[string][]int{"fiesta": {}, "ford": {1}, "red": {3}},
+		},
+		{
+			name: "case 3: a vehicle created again after being deleted",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: textVehicle(3, "Fiat", "Panda", "Red")},
+				{Type: internal.VehicleCreated, Vehicle: textVehicle(3, "Fiat", "Uno", "White")},
+			},
+			found: map[string][]int{"panda": {}, "uno": {3}, "red": {2}, "white": {3}},
+		},
+		{
+			name: "case 4: every vehicle deleted",
+			events: []internal.VehicleEvent{
+				{Type: internal.VehicleDeleted, Previous: textVehicle(1, "Ford", "Focus", "Blue")},
+				{Type: internal.VehicleDeleted, Previous: textVehicle(2, "Ford", "Fiesta", "Red")},
+				{Type: internal.VehicleDeleted, Previous: textVehicle(3, "Fiat", "Panda", "Red")},
+			},
+			found: map[string][]int{"ford": {}, "f": {}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			tx := newTestText(
+				textVehicle(1, "Ford", "Focus", "Blue"),
+				textVehicle(2, "Ford", "Fiesta", "Red"),
+				textVehicle(3, "Fiat", "Panda", "Red"),
+			)
+
+			// act
+			for _, e := range c.events {
+				tx.OnVehicleEvent(e)
+			}
+
+			// assert
+			requireVocabulary(t, tx)
+			for text, ids := range c.found {
+				require.Equal(t, ids, searchIds(t, tx, text, 0), "search %q", text)
+			}
+		})
+	}
+}
+
+func TestText_Fleet(t *testing.T) {
+	// arrange
+	db, err := loader.NewVehicleJSONFile("../../docs/db/vehicles_100.json").Load()
+	require.NoError(t, err)
+	tx := NewText()
+	for _, v := range db {
+		tx.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: v})
+	}
+	requireVocabulary(t, tx)
+
+	t.Run("case 1: a nickname and a typo find the Chevrolet Cavalier first", func(t *testing.T) {
+		// act
+		ids := searchIds(t, tx, "chevy cavalir", 5)
+
+		// assert
+		require.NotEmpty(t, ids)
+		require.Equal(t, 2, ids[0])
+	})
+
+	t.Run("case 2: a deleted vehicle is not found anymore", func(t *testing.T) {
+		// act
+		tx.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleDeleted, Previous: db[2]})
+
+		// assert
+		requireVocabulary(t, tx)
+		require.NotContains(t, searchIds(t, tx, "chevy cavalir", 0), 2)
+		require.Equal(t, []int{}, searchIds(t, tx, "cavalier", 0))
+	})
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// contextKey is the type of the key used to store the logger in a context
+type contextKey struct{}
+
+// New is a function that returns a new structured JSON logger
+// - level can be changed at runtime to adjust the verbosity of the logger
+func New(w io.Writer, level *slog.LevelVar) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// WithContext is a function that returns a copy of the context carrying the logger
+func WithContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext is a function that returns the logger carried by the context, or the default logger
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+// Middleware is a function that returns a middleware logging every request
+// - it carries a request-scoped logger (with the request id) in the context of the request
+// - it must be used after chi's middleware.RequestID
+func Middleware(l *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			// request-scoped logger
+			rl := l.With(slog.String("request_id", middleware.GetReqID(r.Context())))
+			r = r.WithContext(WithContext(r.Context(), rl))
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			// the status is implicit if the handler wrote nothing
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			// the route is only known once the router served the request
+			attrs := []any{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			}
+			if rc := chi.RouteContext(r.Context()); rc != nil {
+				attrs = append(attrs, slog.String("route", rc.RoutePattern()))
+				if id := rc.URLParam("id"); id != "" {
+					attrs = append(attrs, slog.String("vehicle_id", id))
+				}
+			}
+
+			level := slog.LevelInfo
+			switch {
+			case status >= http.StatusInternalServerError:
+				level = slog.LevelError
+			case status >= http.StatusBadRequest:
+				level = slog.LevelWarn
+			}
+			rl.Log(r.Context(), level, "request", attrs...)
+		})
+	}
+}
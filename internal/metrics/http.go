@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware is a function that returns a middleware instrumenting every request
+// - requests are labeled by route pattern rather than path, to keep the cardinality bounded
+func Middleware(r *Registry) func(http.Handler) http.Handler {
+	requests := r.NewCounterVec("http_requests_total", "Number of HTTP requests.", "method", "route", "status")
+	duration := r.NewHistogramVec("http_request_duration_seconds", "Duration of HTTP requests.", nil, "method", "route")
+	inFlight := r.NewGaugeVec("http_requests_in_flight", "Number of HTTP requests being served.")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			start := time.Now()
+			inFlight.WithLabelValues().Inc()
+			defer inFlight.WithLabelValues().Dec()
+
+			ww := middleware.NewWrapResponseWriter(w, req.ProtoMajor)
+			next.ServeHTTP(ww, req)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := "unmatched"
+			if rc := chi.RouteContext(req.Context()); rc != nil && rc.RoutePattern() != "" {
+				route = rc.RoutePattern()
+			}
+			requests.WithLabelValues(req.Method, route, strconv.Itoa(status)).Inc()
+			duration.WithLabelValues(req.Method, route).Observe(time.Since(start).Seconds())
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package metrics
+
+import (
+	"bufio"
+	"sort"
+	"strconv"
+)
+
+// Counter is a struct that represents a value that only goes up
+type Counter struct {
+	value atomicFloat
+}
+
+// Inc is a method that adds one to the counter
+func (c *Counter) Inc() {
+	c.value.add(1)
+}
+
+// Add is a method that adds a non-negative delta to the counter
+func (c *Counter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	c.value.add(delta)
+}
+
+// CounterVec is a struct that represents a family of counters partitioned by labels
+type CounterVec struct {
+	name string
+	help string
+	*vec[Counter]
+}
+
+// NewCounterVec is a method that registers a new family of counters
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, vec: newVec(labels, func() *Counter { return &Counter{} })}
+	r.register(name, c)
+	return c
+}
+
+// WithLabelValues is a method that returns the counter for the label values
+func (c *CounterVec) WithLabelValues(values ...string) *Counter {
+	return c.with(values...)
+}
+
+func (c *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, c.name, c.help, "counter")
+	c.each(func(values []string, child *Counter) {
+		writeSample(w, c.name, c.labels, values, child.value.load())
+	})
+}
+
+// Gauge is a struct that represents a value that can go up and down
+type Gauge struct {
+	value atomicFloat
+}
+
+// Inc is a method that adds one to the gauge
+func (g *Gauge) Inc() {
+	g.value.add(1)
+}
+
+// Dec is a method that subtracts one from the gauge
+func (g *Gauge) Dec() {
+	g.value.add(-1)
+}
+
+// Set is a method that sets the gauge
+func (g *Gauge) Set(v float64) {
+	g.value.set(v)
+}
+
+// GaugeVec is a struct that represents a family of gauges partitioned by labels
+type GaugeVec struct {
+	name string
+	help string
+	*vec[Gauge]
+}
+
+// NewGaugeVec is a method that registers a new family of gauges
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{name: name, help: help, vec: newVec(labels, func() *Gauge { return &Gauge{} })}
+	r.register(name, g)
+	return g
+}
+
+// WithLabelValues is a method that returns the gauge for the label values
+func (g *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	return g.with(values...)
+}
+
+func (g *GaugeVec) write(w *bufio.Writer) {
+	writeHeader(w, g.name, g.help, "gauge")
+	g.each(func(values []string, child *Gauge) {
+		writeSample(w, g.name, g.labels, values, child.value.load())
+	})
+}
+
+// Histogram is a struct that represents the distribution of observed values in buckets
+type Histogram struct {
+	// buckets are the upper bounds of the buckets, sorted
+	buckets []float64
+	// counts are the number of observations of each bucket, not cumulative
+	counts []atomicFloat
+	// sum is the sum of the observations
+	sum atomicFloat
+	// count is the number of observations
+	count atomicFloat
+}
+
+// Observe is a method that adds an observation to the histogram
+func (h *Histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.buckets, v)
+	if i < len(h.buckets) {
+		h.counts[i].add(1)
+	}
+	h.sum.add(v)
+	h.count.add(1)
+}
+
+// HistogramVec is a struct that represents a family of histograms partitioned by labels
+type HistogramVec struct {
+	name    string
+	help    string
+	buckets []float64
+	*vec[Histogram]
+}
+
+// NewHistogramVec is a method that registers a new family of histograms
+// - buckets are the upper bounds of the buckets, DefaultBuckets if nil
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	h := &HistogramVec{name: name, help: help, buckets: buckets}
+	h.vec = newVec(labels, func() *Histogram {
+		return &Histogram{buckets: buckets, counts: make([]atomicFloat, len(buckets))}
+	})
+	r.register(name, h)
+	return h
+}
+
+// WithLabelValues is a method that returns the histogram for the label values
+func (h *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return h.with(values...)
+}
+
+func (h *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, h.name, h.help, "histogram")
+	labels := append(append([]string(nil), h.labels...), "le")
+	h.each(func(values []string, child *Histogram) {
+		bucketValues := append(append([]string(nil), values...), "")
+		var cumulative float64
+		for i, bound := range h.buckets {
+			cumulative += child.counts[i].load()
+			bucketValues[len(values)] = strconv.FormatFloat(bound, 'g', -1, 64)
+			writeSample(w, h.name+"_bucket", labels, bucketValues, cumulative)
+		}
+		bucketValues[len(values)] = "+Inf"
+		writeSample(w, h.name+"_bucket", labels, bucketValues, child.count.load())
+		writeSample(w, h.name+"_sum", h.labels, values, child.sum.load())
+		writeSample(w, h.name+"_count", h.labels, values, child.count.load())
+	})
+}
+
+// Sample is a struct that represents a value collected on demand
+type Sample struct {
+	// LabelValues are the values of the labels of the sample
+	LabelValues []string
+	// Value is the value of the sample
+	Value float64
+}
+
+// funcCollector is a struct that represents a family whose samples are collected when the metrics are written
+type funcCollector struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	collect func() []Sample
+}
+
+// NewGaugeFunc is a method that registers a family of gauges collected by fn when the metrics are written
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, fn func() []Sample) {
+	r.register(name, &funcCollector{name: name, help: help, typ: "gauge", labels: labels, collect: fn})
+}
+
+// NewCounterFunc is a method that registers a family of counters collected by fn when the metrics are written
+func (r *Registry) NewCounterFunc(name, help string, labels []string, fn func() []Sample) {
+	r.register(name, &funcCollector{name: name, help: help, typ: "counter", labels: labels, collect: fn})
+}
+
+func (f *funcCollector) write(w *bufio.Writer) {
+	writeHeader(w, f.name, f.help, f.typ)
+	samples := f.collect()
+	sort.Slice(samples, func(i, j int) bool {
+		a, b := samples[i].LabelValues, samples[j].LabelValues
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	for _, s := range samples {
+		writeSample(w, f.name, f.labels, s.LabelValues, s.Value)
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import "time"
+
+// Operations is a struct that represents the instrumentation of the operations of a component
+type Operations struct {
+	// total is the number of operations by outcome
+	total *CounterVec
+	// duration is the duration of the operations
+	duration *HistogramVec
+}
+
+// NewOperations is a method that registers the metrics of the operations of a component
+// - prefix is the prefix of the metric names (e.g. vehicle_service)
+func (r *Registry) NewOperations(prefix, component string) *Operations {
+	return &Operations{
+		total:    r.NewCounterVec(prefix+"_operations_total", "Number of operations of the "+component+".", "operation", "outcome"),
+		duration: r.NewHistogramVec(prefix+"_operation_duration_seconds", "Duration of the operations of the "+component+".", nil, "operation"),
+	}
+}
+
+// Observe is a method that records an operation started at start
+// - err points to the error returned by the operation, so it can be deferred with time.Now() as start
+func (o *Operations) Observe(operation string, start time.Time, err *error) {
+	outcome := "success"
+	if err != nil && *err != nil {
+		outcome = "error"
+	}
+	o.total.WithLabelValues(operation, outcome).Inc()
+	o.duration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
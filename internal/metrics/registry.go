@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are the default upper bounds of histogram buckets, in seconds
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// collector is an interface that represents a metric family written in the Prometheus text format
+type collector interface {
+	// write is a method that writes the samples of the family
+	write(w *bufio.Writer)
+}
+
+// NewRegistry is a function that returns a new instance of Registry
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// Registry is a struct that represents a set of metric families exposed in the Prometheus text format
+type Registry struct {
+	// mu is the mutex that guards the collectors
+	mu sync.Mutex
+	// collectors are the metric families by name
+	collectors map[string]collector
+}
+
+// register is a method that adds a metric family, panicking on duplicated names as it is a programming error
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.collectors[name]; ok {
+		panic("metrics: duplicated metric " + name)
+	}
+	r.collectors[name] = c
+}
+
+// WriteTo is a method that writes every metric family in the Prometheus text exposition format
+func (r *Registry) WriteTo(w io.Writer) (n int64, err error) {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, c := range collectors {
+		c.write(bw)
+	}
+	err = bw.Flush()
+	return cw.n, err
+}
+
+// Handler is a method that returns a handler exposing the metrics, for the route GET /metrics
+func (r *Registry) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(w)
+	}
+}
+
+// countingWriter is a struct that counts the bytes written to a writer
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.n += int64(n)
+	return
+}
+
+// writeHeader is a function that writes the HELP and TYPE lines of a family
+func writeHeader(w *bufio.Writer, name, help, typ string) {
+	w.WriteString("# HELP " + name + " " + strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help) + "\n")
+	w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// writeSample is a function that writes a sample line
+func writeSample(w *bufio.Writer, name string, labels, values []string, value float64) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i, label := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(label + `="` + escapeLabel(values[i]) + `"`)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatValue(value))
+	w.WriteByte('\n')
+}
+
+// escapeLabel is a function that escapes a label value
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// formatValue is a function that formats a sample value
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// atomicFloat is a float64 that can be updated atomically
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+// add is a method that adds delta to the value
+func (f *atomicFloat) add(delta float64) {
+	for {
+		old := f.bits.Load()
+		if f.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+delta)) {
+			return
+		}
+	}
+}
+
+// set is a method that sets the value
+func (f *atomicFloat) set(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+// load is a method that returns the value
+func (f *atomicFloat) load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+// vec is a struct that represents the children of a family, one per combination of label values
+type vec[T any] struct {
+	// mu is the mutex that guards the children
+	mu sync.RWMutex
+	// labels are the names of the labels
+	labels []string
+	// children are the children by their joined label values
+	children map[string]*T
+	// values are the label values of each child
+	values map[string][]string
+	// create returns a new child
+	create func() *T
+}
+
+// newVec is a function that returns a new instance of vec
+func newVec[T any](labels []string, create func() *T) *vec[T] {
+	return &vec[T]{labels: labels, children: make(map[string]*T), values: make(map[string][]string), create: create}
+}
+
+// with is a method that returns the child for the label values, creating it if needed
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labels) {
+		panic("metrics: wrong number of label values")
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	child, ok := v.children[key]
+	v.mu.RUnlock()
+	if ok {
+		return child
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if child, ok = v.children[key]; !ok {
+		child = v.create()
+		v.children[key] = child
+		v.values[key] = append([]string(nil), values...)
+	}
+	return child
+}
+
+// each is a method that calls fn for every child, sorted by label values
+func (v *vec[T]) each(fn func(values []string, child *T)) {
+	v.mu.RLock()
+	keys := make([]string, 0, len(v.children))
+	for key := range v.children {
+		keys = append(keys, key)
+	}
+	v.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v.mu.RLock()
+		child, values := v.children[key], v.values[key]
+		v.mu.RUnlock()
+		fn(values, child)
+	}
+}
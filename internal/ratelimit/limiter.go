@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/render"
+	"context"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Class is the class of a route, each class has a rate of its own
+type Class string
+
+const (
+	// ClassRead are the requests reading vehicles
+	ClassRead Class = "read"
+	// ClassWrite are the requests creating, updating or deleting a vehicle
+	ClassWrite Class = "write"
+	// ClassBatch are the batch imports of vehicles
+	ClassBatch Class = "batch"
+	// ClassAdmin are the requests to the administration routes
+	ClassAdmin Class = "admin"
+)
+
+// Classify is a function that returns the class of a request
+func Classify(r *http.Request) Class {
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case strings.HasPrefix(path, "/admin"):
+		return ClassAdmin
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/batch"):
+		return ClassBatch
+	case r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions:
+		return ClassRead
+	}
+	return ClassWrite
+}
+
+// Limits is a struct that represents the limits of every client
+type Limits struct {
+	// Rates are the token buckets of each class of route, classes without a rate are not limited
+	Rates map[Class]Rate
+	// DailyWriteQuota is the number of write and batch requests of a client per UTC day, zero means unlimited
+	DailyWriteQuota int
+	// IP is the token bucket of every IP, checked before authentication so failed authentications are limited too
+	// - a zero burst means unlimited
+	IP Rate
+}
+
+// NewLimiter is a function that returns a new instance of Limiter
+func NewLimiter(store Store, limits Limits) *Limiter {
+	return &Limiter{store: store, limits: limits, now: time.Now}
+}
+
+// Limiter is a struct that represents the rate limits and quotas of the clients
+// - a client is the API key (or JWT subject) of the principal, or the IP of the request if not authenticated
+type Limiter struct {
+	// store keeps the state of the limits
+	store Store
+	// limits are the limits of every client
+	limits Limits
+	// now returns the current time
+	now func() time.Time
+}
+
+// client is a function that returns the identity a request is limited by
+func client(r *http.Request) string {
+	if p, ok := auth.PrincipalFromContext(r.Context()); ok {
+		if p.KeyID != "" && p.Method == auth.MethodAPIKey {
+			return "key:" + p.KeyID
+		}
+		return "sub:" + p.Subject
+	}
+	return "ip:" + remoteIP(r)
+}
+
+// remoteIP is a function that returns the IP of the remote address of a request
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// AllowIP is a method that returns the decision of the rate of the IP of a request, whoever its principal is
+func (l *Limiter) AllowIP(ctx context.Context, r *http.Request) (d Decision, limited bool, err error) {
+	if l.limits.IP.Burst <= 0 {
+		return
+	}
+	limited = true
+	d, err = l.store.Take(ctx, "ip:"+remoteIP(r), l.limits.IP, l.now())
+	return
+}
+
+// Allow is a method that returns the decision of the limits for a request
+// - the rate of the class is checked first, the quota is only counted for requests within the rate
+func (l *Limiter) Allow(ctx context.Context, r *http.Request) (d Decision, limited bool, err error) {
+	class, now, who := Classify(r), l.now(), client(r)
+
+	rate, ok := l.limits.Rates[class]
+	if ok {
+		limited = true
+		if d, err = l.store.Take(ctx, "rate:"+string(class)+":"+who, rate, now); err != nil || !d.Allowed {
+			return
+		}
+	}
+
+	if l.limits.DailyWriteQuota > 0 && (class == ClassWrite || class == ClassBatch) {
+		day := now.UTC().Truncate(24 * time.Hour)
+		var q Decision
+		q, err = l.store.Increment(ctx, "quota:"+day.Format("2006-01-02")+":"+who, l.limits.DailyWriteQuota, day.Add(24*time.Hour), now)
+		if err != nil {
+			return
+		}
+		// the headers report the limit closest to deny the client
+		if !limited || !q.Allowed || q.Remaining < d.Remaining {
+			d = q
+		}
+		limited = true
+	}
+	return
+}
+
+// Middleware is a function that returns a middleware enforcing the limits of the limiter
+// - requests over a limit are answered with 429 Too Many Requests and a Retry-After header
+// - the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers report the closest limit
+// - the limits fail open: a request is served if the store is not available
+// - it must be used after auth.Middleware, so clients are told apart by their API key
+func Middleware(l *Limiter) func(http.Handler) http.Handler {
+	return enforce(l.Allow)
+}
+
+// IPMiddleware is a function that returns a middleware enforcing the rate of every IP of the limiter
+// - it must be used before auth.Middleware, so requests with invalid credentials are limited too
+func IPMiddleware(l *Limiter) func(http.Handler) http.Handler {
+	return enforce(l.AllowIP)
+}
+
+// enforce is a function that returns a middleware answering the requests denied by allow with 429 Too Many Requests
+func enforce(allow func(ctx context.Context, r *http.Request) (d Decision, limited bool, err error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d, limited, err := allow(r.Context(), r)
+			if err != nil {
+				logger.FromContext(r.Context()).Error("rate limit not checked", slog.String("error", err.Error()))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !limited {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(max(d.Remaining, 0)))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+			if !d.Allowed {
+				h.Set("Retry-After", strconv.Itoa(ceilSeconds(d.RetryAfter)))
+				logger.FromContext(r.Context()).Warn("rate limited", slog.String("class", string(Classify(r))), slog.String("client", client(r)))
+				render.Error(w, r, http.StatusTooManyRequests, "too many requests")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ceilSeconds is a function that returns a duration as whole seconds, rounded up
+func ceilSeconds(d time.Duration) int {
+	if d >= time.Duration(math.MaxInt64) {
+		return math.MaxInt32
+	}
+	return int(math.Ceil(d.Seconds()))
+}
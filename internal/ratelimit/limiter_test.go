@@ -0,0 +1,221 @@
+package ratelimit
+
+import (
+	"app/internal/auth"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testNow is the time the clock of the tests starts at
+var testNow = time.Date(2026, time.October, 19, 23, 59, 0, 0, time.UTC)
+
+// step is a struct that represents a request made to a middleware and its expected response
+type step struct {
+	// at is the time of the request, after testNow
+	at     time.Duration
+	method string
+	// ip is the remote IP of the request
+	ip string
+	// keyID is the API key of the principal, none if empty
+	keyID  string
+	status int
+	// headers are the expected RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and Retry-After headers
+	headers [4]string
+}
+
+// serve is a function that makes the request of the step to the middleware, at the time of the step
+func (s step) serve(l *Limiter, mw func(http.Handler) http.Handler) *httptest.ResponseRecorder {
+	l.now = func() time.Time { return testNow.Add(s.at) }
+	method := s.method
+	if method == "" {
+		method = http.MethodGet
+	}
+	r := httptest.NewRequest(method, "/vehicles", nil)
+	r.RemoteAddr = s.ip + ":1234"
+	if s.keyID != "" {
+		r = r.WithContext(auth.ContextWithPrincipal(r.Context(), auth.Principal{Subject: "ci", Method: auth.MethodAPIKey, KeyID: s.keyID}))
+	}
+	res := httptest.NewRecorder()
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})).ServeHTTP(res, r)
+	return res
+}
+
+// assertSteps is a function that checks the response of each step
+func assertSteps(t *testing.T, l *Limiter, mw func(http.Handler) http.Handler, steps []step) {
+	t.Helper()
+
+	for i, s := range steps {
+		res := s.serve(l, mw)
+		require.Equal(t, s.status, res.Code, "step %d", i)
+		h := res.Header()
+		require.Equal(t, s.headers, [4]string{h.Get("RateLimit-Limit"), h.Get("RateLimit-Remaining"), h.Get("RateLimit-Reset"), h.Get("Retry-After")}, "step %d", i)
+	}
+}
+
+func TestMiddleware_TokenBucket(t *testing.T) {
+	cases := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "case 1: the burst is allowed at once, then one request each second",
+			steps: []step{
+				{ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "2", ""}},
+				{ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "2", "1"}},
+				{at: 500 * time.Millisecond, ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "2", "1"}},
+				{at: time.Second, ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "2", ""}},
+			},
+		},
+		{
+			name: "case 2: the bucket refills up to the burst",
+			steps: []step{
+				{ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "2", ""}},
+				{at: time.Hour, ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{at: time.Hour, ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "2", ""}},
+				{at: time.Hour, ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "2", "1"}},
+			},
+		},
+		{
+			name: "case 3: each API key has a bucket of its own, whatever its IP",
+			steps: []step{
+				{ip: "10.0.0.1", keyID: "ci", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{ip: "10.0.0.2", keyID: "ci", status: 200, headers: [4]string{"2", "0", "2", ""}},
+				{ip: "10.0.0.1", keyID: "ci", status: 429, headers: [4]string{"2", "0", "2", "1"}},
+				{ip: "10.0.0.1", keyID: "ops", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "1", ""}},
+			},
+		},
+		{
+			name: "case 4: the routes of another class are not limited",
+			steps: []step{
+				{ip: "10.0.0.1", method: http.MethodDelete, status: 200},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			l := NewLimiter(NewMemoryStore(), Limits{Rates: map[Class]Rate{ClassRead: {PerSecond: 1, Burst: 2}}})
+
+			// act and assert
+			assertSteps(t, l, Middleware(l), c.steps)
+		})
+	}
+}
+
+func TestMiddleware_DailyWriteQuota(t *testing.T) {
+	cases := []struct {
+		name  string
+		rates map[Class]Rate
+		steps []step
+	}{
+		{
+			name: "case 1: the quota resets at UTC midnight",
+			steps: []step{
+				{method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "60", ""}},
+				{method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "60", ""}},
+				{at: 30 * time.Second, method: "POST", ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "30", "30"}},
+				{at: 30 * time.Second, method: "GET", ip: "10.0.0.1", status: 200},
+				{at: time.Minute, method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "86400", ""}},
+			},
+		},
+		{
+			name:  "case 2: the headers report the limit closest to deny the client",
+			rates: map[Class]Rate{ClassWrite: {PerSecond: 1, Burst: 10}},
+			steps: []step{
+				{method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"2", "1", "60", ""}},
+				{method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "60", ""}},
+				{method: "POST", ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "60", "60"}},
+			},
+		},
+		{
+			name:  "case 3: requests over the rate are not counted",
+			rates: map[Class]Rate{ClassWrite: {PerSecond: 1, Burst: 1}},
+			steps: []step{
+				{method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"1", "0", "1", ""}},
+				{method: "POST", ip: "10.0.0.1", status: 429, headers: [4]string{"1", "0", "1", "1"}},
+				{at: time.Second, method: "POST", ip: "10.0.0.1", status: 200, headers: [4]string{"1", "0", "1", ""}},
+				{at: 2 * time.Second, method: "POST", ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "58", "58"}},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			l := NewLimiter(NewMemoryStore(), Limits{Rates: c.rates, DailyWriteQuota: 2})
+
+			// act and assert
+			assertSteps(t, l, Middleware(l), c.steps)
+		})
+	}
+}
+
+func TestIPMiddleware(t *testing.T) {
+	cases := []struct {
+		name  string
+		ip    Rate
+		steps []step
+	}{
+		{
+			name: "case 1: an IP is limited whatever its principal",
+			ip:   Rate{PerSecond: 1, Burst: 2},
+			steps: []step{
+				{ip: "10.0.0.1", keyID: "ci", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{ip: "10.0.0.1", keyID: "ops", status: 200, headers: [4]string{"2", "0", "2", ""}},
+				{ip: "10.0.0.1", status: 429, headers: [4]string{"2", "0", "2", "1"}},
+				{ip: "10.0.0.2", keyID: "ci", status: 200, headers: [4]string{"2", "1", "1", ""}},
+				{at: time.Second, ip: "10.0.0.1", status: 200, headers: [4]string{"2", "0", "2", ""}},
+			},
+		},
+		{
+			name: "case 2: a zero burst is unlimited",
+			steps: []step{
+				{ip: "10.0.0.1", status: 200},
+				{ip: "10.0.0.1", status: 200},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			l := NewLimiter(NewMemoryStore(), Limits{IP: c.ip})
+
+			// act and assert
+			assertSteps(t, l, IPMiddleware(l), c.steps)
+		})
+	}
+}
+
+// failingStore is a struct that represents a store that is not available
+type failingStore struct{}
+
+// Take is a method that returns an error
+func (failingStore) Take(ctx context.Context, key string, rate Rate, now time.Time) (d Decision, err error) {
+	return d, errors.New("store not available")
+}
+
+// Increment is a method that returns an error
+func (failingStore) Increment(ctx context.Context, key string, limit int, reset time.Time, now time.Time) (d Decision, err error) {
+	return d, errors.New("store not available")
+}
+
+func TestMiddleware_FailOpen(t *testing.T) {
+	// arrange
+	l := NewLimiter(failingStore{}, Limits{Rates: map[Class]Rate{ClassRead: {Burst: 1}}, IP: Rate{Burst: 1}})
+
+	// act and assert
+	assertSteps(t, l, func(next http.Handler) http.Handler { return IPMiddleware(l)(Middleware(l)(next)) }, []step{
+		{ip: "10.0.0.1", status: 200},
+		{ip: "10.0.0.1", status: 200},
+	})
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Rate is a struct that represents the limit of a token bucket
+type Rate struct {
+	// PerSecond is the number of tokens added to the bucket each second
+	PerSecond float64
+	// Burst is the capacity of the bucket, i.e. the requests allowed at once
+	Burst int
+}
+
+// Decision is a struct that represents the outcome of a limit for a request
+type Decision struct {
+	// Allowed is true if the request may be served
+	Allowed bool
+	// Limit is the number of requests allowed by the limit at once
+	Limit int
+	// Remaining is the number of requests still allowed at once
+	Remaining int
+	// Reset is the time until the limit is fully available again
+	Reset time.Duration
+	// RetryAfter is the time until the request would be allowed, zero if allowed
+	RetryAfter time.Duration
+}
+
+// Store is an interface that represents where the state of the limits is kept
+// - the memory store keeps it per process, a shared store (e.g. Redis) can be swapped in for several instances
+type Store interface {
+	// Take is a method that takes a token from the bucket of the key, created full if missing
+	Take(ctx context.Context, key string, rate Rate, now time.Time) (d Decision, err error)
+	// Increment is a method that counts a request in the window of the key ending at reset, up to limit
+	Increment(ctx context.Context, key string, limit int, reset time.Time, now time.Time) (d Decision, err error)
+}
+
+// bucket is a struct that represents a token bucket
+type bucket struct {
+	// tokens are the tokens left as of updated
+	tokens float64
+	// updated is when tokens was computed
+	updated time.Time
+	// full is when the bucket is full again, so it can be dropped
+	full time.Time
+}
+
+// window is a struct that represents a counter of requests in a fixed window
+type window struct {
+	// count is the number of requests
+	count int
+	// reset is when the window ends
+	reset time.Time
+}
+
+// NewMemoryStore is a function that returns a new instance of MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		windows: make(map[string]*window),
+	}
+}
+
+// MemoryStore is a struct that represents a store keeping the state of the limits in memory
+// - full buckets and ended windows are swept once a minute, so idle clients take no memory
+type MemoryStore struct {
+	// mu is the mutex that guards the state
+	mu sync.Mutex
+	// buckets are the token buckets by key
+	buckets map[string]*bucket
+	// windows are the counters by key
+	windows map[string]*window
+	// swept is when the state was last swept
+	swept time.Time
+}
+
+// sweep is a method that drops the full buckets and the ended windows, at most once a minute
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, w := range s.windows {
+		if !now.Before(w.reset) {
+			delete(s.windows, key)
+		}
+	}
+}
+
+// Take is a method that takes a token from the bucket of the key, created full if missing
+func (s *MemoryStore) Take(ctx context.Context, key string, rate Rate, now time.Time) (d Decision, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	burst := float64(rate.Burst)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, updated: now}
+		s.buckets[key] = b
+	}
+	// refill
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*rate.PerSecond)
+	b.updated = now
+
+	d.Limit = rate.Burst
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else if rate.PerSecond > 0 {
+		d.RetryAfter = seconds((1 - b.tokens) / rate.PerSecond)
+	} else {
+		d.RetryAfter = time.Duration(math.MaxInt64)
+	}
+	d.Remaining = int(b.tokens)
+	if rate.PerSecond > 0 {
+		d.Reset = seconds((burst - b.tokens) / rate.PerSecond)
+	}
+	b.full = now.Add(d.Reset)
+	return
+}
+
+// Increment is a method that counts a request in the window of the key ending at reset, up to limit
+func (s *MemoryStore) Increment(ctx context.Context, key string, limit int, reset time.Time, now time.Time) (d Decision, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.reset) {
+		w = &window{reset: reset}
+		s.windows[key] = w
+	}
+
+	d.Limit = limit
+	d.Reset = w.reset.Sub(now)
+	if w.count < limit {
+		w.count++
+		d.Allowed = true
+	} else {
+		d.RetryAfter = d.Reset
+	}
+	d.Remaining = limit - w.count
+	return
+}
+
+// seconds is a function that returns a duration of seconds
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
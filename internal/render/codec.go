@@ -0,0 +1,130 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+// Codec is an interface that represents a format the responses are encoded to and the requests decoded from
+type Codec interface {
+	// Format is a method that returns the name of the format, as in ?format=
+	Format() string
+	// MediaTypes is a method that returns the media types of the format, the first is the Content-Type of the responses
+	MediaTypes() []string
+	// Encode is a method that writes a value in the format
+	Encode(w io.Writer, v any) (err error)
+	// Decode is a method that reads a value in the format
+	Decode(r io.Reader, v any) (err error)
+}
+
+// Codecs is a function that returns the codecs of every supported format, JSON first
+func Codecs() []Codec {
+	return []Codec{JSON{}, CSV{}, XML{}, MessagePack{}, YAML{}}
+}
+
+// JSON is a struct that represents the JSON format
+type JSON struct{}
+
+// Format is a method that returns the name of the format
+func (JSON) Format() string { return "json" }
+
+// MediaTypes is a method that returns the media types of the format
+func (JSON) MediaTypes() []string { return []string{"application/json"} }
+
+// Encode is a method that writes a value as JSON, without a trailing newline
+func (JSON) Encode(w io.Writer, v any) (err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_, err = w.Write(b)
+	return
+}
+
+// Decode is a method that reads a value as JSON
+func (JSON) Decode(r io.Reader, v any) (err error) {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// MessagePack is a struct that represents the MessagePack format, with the field names of JSON
+type MessagePack struct{}
+
+// Format is a method that returns the name of the format
+func (MessagePack) Format() string { return "msgpack" }
+
+// MediaTypes is a method that returns the media types of the format
+func (MessagePack) MediaTypes() []string {
+	return []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"}
+}
+
+// Encode is a method that writes a value as MessagePack
+func (MessagePack) Encode(w io.Writer, v any) (err error) {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	enc.UseCompactInts(true)
+	return enc.Encode(v)
+}
+
+// Decode is a method that reads a value as MessagePack
+func (MessagePack) Decode(r io.Reader, v any) (err error) {
+	dec := msgpack.NewDecoder(r)
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// YAML is a struct that represents the YAML format, with the field names and the field order of JSON
+type YAML struct{}
+
+// Format is a method that returns the name of the format
+func (YAML) Format() string { return "yaml" }
+
+// MediaTypes is a method that returns the media types of the format
+func (YAML) MediaTypes() []string {
+	return []string{"application/yaml", "application/x-yaml", "text/yaml"}
+}
+
+// Encode is a method that writes a value as YAML
+// - the value is encoded as JSON first, JSON being YAML the node keeps the order of the fields
+func (YAML) Encode(w io.Writer, v any) (err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	var node yaml.Node
+	if err = yaml.Unmarshal(b, &node); err != nil {
+		return
+	}
+	blockStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return
+	}
+	return enc.Close()
+}
+
+// blockStyle is a function that clears the flow and quoting style of the nodes parsed from JSON
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
+
+// Decode is a method that reads a value as YAML, into the fields of JSON
+func (YAML) Decode(r io.Reader, v any) (err error) {
+	var doc any
+	if err = yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return
+	}
+	return JSON{}.Decode(bytes.NewReader(b), v)
+}
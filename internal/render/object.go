@@ -0,0 +1,105 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Field is a struct that represents a member of an Object
+type Field struct {
+	// Key is the name of the field
+	Key string
+	// Value is the value of the field
+	Value any
+}
+
+// Object is a list of fields that keeps their order in every format, unlike a map
+type Object []Field
+
+// MarshalJSON is a method that returns the object as JSON, with the fields in order
+func (o Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// EncodeMsgpack is a method that writes the object as a MessagePack map, with the fields in order
+func (o Object) EncodeMsgpack(enc *msgpack.Encoder) (err error) {
+	if err = enc.EncodeMapLen(len(o)); err != nil {
+		return
+	}
+	for _, f := range o {
+		if err = enc.EncodeString(f.Key); err != nil {
+			return
+		}
+		if err = enc.Encode(f.Value); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Project is a function that returns the fields of the JSON encoding of a value whose names are in fields, in order
+// - it is the projection of sparse fieldsets, fields missing from the encoding (e.g. omitted if empty) are left out
+func Project(v any, fields map[string]bool) (o Object, err error) {
+	t, err := tree(v)
+	if err != nil {
+		return
+	}
+	obj, ok := t.(object)
+	if !ok {
+		return nil, ErrShape
+	}
+	o = Object{}
+	for _, f := range obj {
+		if fields[f.key] {
+			o = append(o, Field{Key: f.key, Value: value(f.value)})
+		}
+	}
+	return
+}
+
+// value is a function that returns a value of a tree with plain numbers and objects, to be encoded in any format
+func value(t any) any {
+	switch t := t.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(t.String(), 10, 64); err == nil {
+			return i
+		}
+		f, _ := strconv.ParseFloat(t.String(), 64)
+		return f
+	case object:
+		o := make(Object, 0, len(t))
+		for _, f := range t {
+			o = append(o, Field{Key: f.key, Value: value(f.value)})
+		}
+		return o
+	case []any:
+		arr := make([]any, 0, len(t))
+		for _, e := range t {
+			arr = append(arr, value(e))
+		}
+		return arr
+	}
+	return t
+}
@@ -0,0 +1,227 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrContentType is the error returned when the Content-Type of a request body is missing or not supported
+	ErrContentType = errors.New("request content type not supported")
+	// ErrBody is the error returned when a request body can not be decoded
+	ErrBody = errors.New("request body invalid")
+)
+
+// NewNegotiator is a function that returns a new instance of Negotiator
+// - the first codec is the default, used if the request accepts any format
+func NewNegotiator(codecs ...Codec) *Negotiator {
+	return &Negotiator{codecs: codecs}
+}
+
+// Negotiator is a struct that represents the formats a server speaks, chosen per request
+type Negotiator struct {
+	// codecs are the codecs of the formats, the first is the default
+	codecs []Codec
+}
+
+// mediaRange is a struct that represents a media range of an Accept header
+type mediaRange struct {
+	// mediaType is the media type, possibly with wildcards
+	mediaType string
+	// q is the quality of the media range
+	q float64
+}
+
+// Negotiate is a method that returns the codec of the response to a request
+// - the ?format= query parameter takes precedence over the Accept header
+// - ok is false if no format is acceptable
+func (n *Negotiator) Negotiate(r *http.Request) (c Codec, ok bool) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		for _, c := range n.codecs {
+			if strings.EqualFold(c.Format(), format) {
+				return c, true
+			}
+		}
+		return nil, false
+	}
+
+	accept := r.Header.Values("Accept")
+	if len(accept) == 0 {
+		return n.codecs[0], true
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(strings.Join(accept, ","), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+		}
+	}
+	// - by quality, then by order
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, mr := range ranges {
+		if c, ok = n.match(mr.mediaType); ok {
+			return
+		}
+	}
+	return nil, false
+}
+
+// match is a method that returns the first codec of a media type, possibly with wildcards
+func (n *Negotiator) match(mediaType string) (c Codec, ok bool) {
+	for _, c := range n.codecs {
+		for _, mt := range c.MediaTypes() {
+			typ, _, _ := strings.Cut(mt, "/")
+			if mediaType == "*/*" || mediaType == typ+"/*" || mediaType == mt {
+				return c, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Formats is a method that returns the names of the formats
+func (n *Negotiator) Formats() (formats []string) {
+	for _, c := range n.codecs {
+		formats = append(formats, c.Format())
+	}
+	return
+}
+
+// key is the type of the keys of the context
+type key int
+
+const (
+	// keyNegotiation is the key of the negotiation of a request
+	keyNegotiation key = iota
+	// keyErrorBody is the key of the body of the error responses of a request
+	keyErrorBody
+)
+
+// negotiation is a struct that represents the formats of a request
+type negotiation struct {
+	// negotiator are the formats of the server, to decode the request body
+	negotiator *Negotiator
+	// codec is the format of the response
+	codec Codec
+}
+
+// defaultNegotiation is the negotiation of the requests not served by the middleware: JSON only
+var defaultNegotiation = negotiation{negotiator: NewNegotiator(JSON{}), codec: JSON{}}
+
+// fromContext is a function that returns the negotiation of a request
+func fromContext(ctx context.Context) negotiation {
+	if n, ok := ctx.Value(keyNegotiation).(negotiation); ok {
+		return n
+	}
+	return defaultNegotiation
+}
+
+// Middleware is a function that returns a middleware negotiating the format of the responses and the request bodies
+// - requests accepting none of the formats are answered with 406 Not Acceptable, in the default format
+func Middleware(n *Negotiator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept")
+			c, ok := n.Negotiate(r)
+			if !ok {
+				c = n.codecs[0]
+			}
+			r = r.WithContext(context.WithValue(r.Context(), keyNegotiation, negotiation{negotiator: n, codec: c}))
+			if !ok {
+				Error(w, r, http.StatusNotAcceptable, "not acceptable, formats: "+strings.Join(n.Formats(), ", "))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Respond is a function that writes a response in the format negotiated for the request
+// - a nil body writes the status code only
+func Respond(w http.ResponseWriter, r *http.Request, code int, body any) {
+	if body == nil {
+		w.WriteHeader(code)
+		return
+	}
+
+	c := fromContext(r.Context()).codec
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, body); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// - set header: before code due to it sets by default "text/plain"
+	w.Header().Set("Content-Type", c.MediaTypes()[0])
+	w.WriteHeader(code)
+	w.Write(buf.Bytes())
+}
+
+// ErrorJSON is a struct that represents the body of an error response
+type ErrorJSON struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+// ErrorBody is a function that returns the body of an error response
+type ErrorBody func(code int, message string) any
+
+// Errors is a function that returns a middleware writing the error responses of the requests with the body of fn
+// - used by the versions of the API with an envelope of their own, ErrorJSON otherwise
+func Errors(fn ErrorBody) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), keyErrorBody, fn)))
+		})
+	}
+}
+
+// Error is a function that writes an error response in the format negotiated for the request
+// - codes out of the error range are written as 500 Internal Server Error
+func Error(w http.ResponseWriter, r *http.Request, code int, message string) {
+	if code < 300 || code > 599 {
+		code = http.StatusInternalServerError
+	}
+	if fn, ok := r.Context().Value(keyErrorBody).(ErrorBody); ok {
+		Respond(w, r, code, fn(code, message))
+		return
+	}
+	Respond(w, r, code, ErrorJSON{Status: http.StatusText(code), Message: message})
+}
+
+// Decode is a function that reads a request body in the format of its Content-Type
+func Decode(r *http.Request, ptr any) (err error) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ErrContentType
+	}
+	n := fromContext(r.Context()).negotiator
+	for _, c := range n.codecs {
+		for _, mt := range c.MediaTypes() {
+			if mt == mediaType {
+				if err = c.Decode(r.Body, ptr); err != nil {
+					return fmt.Errorf("%w. %v", ErrBody, err)
+				}
+				return
+			}
+		}
+	}
+	return ErrContentType
+}
@@ -0,0 +1,379 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// codecVehicle is a struct that represents a row of the tests, with a scalar of each kind
+type codecVehicle struct {
+	ID       int     `json:"id"`
+	Brand    string  `json:"brand"`
+	MaxSpeed float64 `json:"max_speed"`
+	Electric bool    `json:"electric"`
+	// Registration is a pointer, absent if nil
+	Registration *string `json:"registration,omitempty"`
+}
+
+// codecFleet is a struct that represents a document of the tests, with an object, a list and a map
+type codecFleet struct {
+	Name     string         `json:"name"`
+	Owner    codecVehicle   `json:"owner"`
+	Vehicles []codecVehicle `json:"vehicles"`
+	// ByYear are the number of vehicles by year, keys that are not XML names
+	ByYear map[string]int `json:"by_year"`
+}
+
+// text is a function that returns a pointer to the value
+func text(s string) *string {
+	return &s
+}
+
+// testVehicles are the rows of the tests
+var testVehicles = []codecVehicle{
+	{ID: 1, Brand: "Chevrolet", MaxSpeed: 97.5, Electric: false, Registration: text("8371")},
+	{ID: 2, Brand: "Tesla, Inc.", MaxSpeed: 250, Electric: true},
+	{ID: 3, Brand: `Quote "Motors"`, MaxSpeed: 0, Electric: false, Registration: text("")},
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	fleet := codecFleet{
+		Name:     "Fleet <one> & two",
+		Owner:    testVehicles[0],
+		Vehicles: testVehicles,
+		ByYear:   map[string]int{"1995": 1, "2020": 2},
+	}
+
+	for _, c := range Codecs() {
+		t.Run("case "+c.Format()+": rows", func(t *testing.T) {
+			// arrange
+			var buf bytes.Buffer
+			require.NoError(t, c.Encode(&buf, testVehicles))
+
+			// act
+			var decoded []codecVehicle
+			err := c.Decode(&buf, &decoded)
+
+			// assert
+			require.NoError(t, err)
+			expected := testVehicles
+			if c.Format() == "csv" {
+				// - an empty cell is the zero value, so an empty string is a nil pointer
+				expected = append([]codecVehicle{}, testVehicles...)
+				expected[2].Registration = nil
+			}
+			require.Equal(t, expected, decoded)
+		})
+
+		if c.Format() == "csv" {
+			// - the columns of CSV are flattened, so only rows of scalars are decoded
+			continue
+		}
+		t.Run("case "+c.Format()+": document", func(t *testing.T) {
+			// arrange
+			var buf bytes.Buffer
+			require.NoError(t, c.Encode(&buf, fleet))
+
+			// act
+			var decoded codecFleet
+			err := c.Decode(&buf, &decoded)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, fleet, decoded)
+		})
+	}
+}
+
+func TestCodecs_Encode(t *testing.T) {
+	cases := []struct {
+		name     string
+		codec    Codec
+		value    any
+		expected string
+	}{
+		{
+			name:     "case 1: JSON without a trailing newline",
+			codec:    JSON{},
+			value:    testVehicles[1],
+			expected: `{"id":2,"brand":"Tesla, Inc.","max_speed":250,"electric":true}`,
+		},
+		{
+			name:     "case 2: CSV with a header row, quoted cells and empty cells",
+			codec:    CSV{},
+			value:    testVehicles[:2],
+			expected: "id,brand,max_speed,electric,registration\n1,Chevrolet,97.5,false,8371\n2,\"Tesla, Inc.\",250,true,\n",
+		},
+		{
+			name:     "case 3: CSV of the data of an envelope, a map of objects and nested columns",
+			codec:    CSV{},
+			value:    map[string]any{"data": map[string]any{"1": map[string]any{"id": 1, "dimensions": map[string]any{"height": 1.5}}}},
+			expected: "dimensions.height,id\n1.5,1\n",
+		},
+		{
+			name:     "case 4: XML with items and fields not named as XML elements",
+			codec:    XML{},
+			value:    map[string]any{"by_year": map[string]int{"1995": 1}, "vehicles": []int{1, 2}},
+			expected: xmlHeader + `<response><by_year><field name="1995">1</field></by_year><vehicles><item>1</item><item>2</item></vehicles></response>`,
+		},
+		{
+			name:     "case 5: YAML in the order of the JSON fields",
+			codec:    YAML{},
+			value:    testVehicles[1],
+			expected: "id: 2\nbrand: Tesla, Inc.\nmax_speed: 250\nelectric: true\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var buf bytes.Buffer
+
+			// act
+			err := c.codec.Encode(&buf, c.value)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, c.expected, buf.String())
+		})
+	}
+}
+
+// xmlHeader is the header of the XML documents
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8"?>` + "\n"
+
+func TestNegotiator_Negotiate(t *testing.T) {
+	n := NewNegotiator(Codecs()...)
+
+	cases := []struct {
+		name   string
+		target string
+		accept string
+		// format is the format negotiated, empty if none is acceptable
+		format string
+	}{
+		{name: "case 1: no Accept is the default format", target: "/vehicles", format: "json"},
+		{name: "case 2: a media type", target: "/vehicles", accept: "text/csv", format: "csv"},
+		{name: "case 3: another media type of a format", target: "/vehicles", accept: "application/x-yaml", format: "yaml"},
+		{name: "case 4: by quality", target: "/vehicles", accept: "application/xml;q=0.5, application/msgpack", format: "msgpack"},
+		{name: "case 5: by order for the same quality", target: "/vehicles", accept: "application/xml, application/msgpack", format: "xml"},
+		{name: "case 6: any subtype, the first format of the type", target: "/vehicles", accept: "text/*", format: "csv"},
+		{name: "case 7: any type", target: "/vehicles", accept: "*/*", format: "json"},
+		{name: "case 8: a quality of zero is not acceptable", target: "/vehicles", accept: "application/json;q=0, text/csv;q=0.1", format: "csv"},
+		{name: "case 9: unknown media type", target: "/vehicles", accept: "image/png", format: ""},
+		{name: "case 10: invalid media ranges are skipped", target: "/vehicles", accept: "???, application/yaml", format: "yaml"},
+		{name: "case 11: ?format= takes precedence over Accept", target: "/vehicles?format=xml", accept: "application/json", format: "xml"},
+		{name: "case 12: ?format= is case insensitive", target: "/vehicles?format=CSV", format: "csv"},
+		{name: "case 13: unknown ?format= is not acceptable, whatever the Accept", target: "/vehicles?format=pdf", accept: "application/json", format: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := httptest.NewRequest("GET", c.target, nil)
+			if c.accept != "" {
+				r.Header.Set("Accept", c.accept)
+			}
+
+			// act
+			codec, ok := n.Negotiate(r)
+
+			// assert
+			require.Equal(t, c.format != "", ok)
+			if ok {
+				require.Equal(t, c.format, codec.Format())
+			}
+		})
+	}
+}
+
+// errorEnvelope is a function that returns the body of the error responses of the tests with an envelope
+func errorEnvelope(code int, message string) any {
+	return map[string]any{"error": map[string]any{"status": code, "message": message}}
+}
+
+func TestMiddleware(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		accept string
+		// envelope is true if the errors are written by errorEnvelope
+		envelope    bool
+		status      int
+		contentType string
+		body        string
+	}{
+		{
+			name: "case 1: the negotiated format", target: "/vehicles", accept: "application/yaml",
+			status: http.StatusOK, contentType: "application/yaml", body: "id: 2\nbrand: Tesla, Inc.\nmax_speed: 250\nelectric: true\n",
+		},
+		{
+			name: "case 2: 406 in the default format", target: "/vehicles", accept: "image/png",
+			status: http.StatusNotAcceptable, contentType: "application/json",
+			body: `{"status":"Not Acceptable","message":"not acceptable, formats: json, csv, xml, msgpack, yaml"}`,
+		},
+		{
+			name: "case 3: 406 of an unknown ?format=", target: "/vehicles?format=pdf", accept: "application/yaml",
+			status: http.StatusNotAcceptable, contentType: "application/json",
+			body: `{"status":"Not Acceptable","message":"not acceptable, formats: json, csv, xml, msgpack, yaml"}`,
+		},
+		{
+			name: "case 4: 406 in the envelope of the errors", target: "/vehicles", accept: "image/png", envelope: true,
+			status: http.StatusNotAcceptable, contentType: "application/json",
+			body: `{"error":{"message":"not acceptable, formats: json, csv, xml, msgpack, yaml","status":406}}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			var h http.Handler = Middleware(NewNegotiator(Codecs()...))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Respond(w, r, http.StatusOK, testVehicles[1])
+			}))
+			if c.envelope {
+				h = Errors(errorEnvelope)(h)
+			}
+			r := httptest.NewRequest("GET", c.target, nil)
+			r.Header.Set("Accept", c.accept)
+			res := httptest.NewRecorder()
+
+			// act
+			h.ServeHTTP(res, r)
+
+			// assert
+			require.Equal(t, c.status, res.Code)
+			require.Equal(t, c.contentType, res.Header().Get("Content-Type"))
+			require.Equal(t, "Accept", res.Header().Get("Vary"))
+			require.Equal(t, c.body, res.Body.String())
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	cases := []struct {
+		name   string
+		accept string
+		code   int
+		// status is the status of the response
+		status      int
+		contentType string
+		body        string
+	}{
+		{name: "case 1: JSON", accept: "application/json", code: http.StatusNotFound, status: http.StatusNotFound, contentType: "application/json", body: `{"status":"Not Found","message":"vehicle not found"}`},
+		{name: "case 2: CSV", accept: "text/csv", code: http.StatusNotFound, status: http.StatusNotFound, contentType: "text/csv", body: "status,message\nNot Found,vehicle not found\n"},
+		{name: "case 3: XML", accept: "application/xml", code: http.StatusNotFound, status: http.StatusNotFound, contentType: "application/xml", body: xmlHeader + "<response><status>Not Found</status><message>vehicle not found</message></response>"},
+		{name: "case 4: YAML", accept: "application/yaml", code: http.StatusNotFound, status: http.StatusNotFound, contentType: "application/yaml", body: "status: Not Found\nmessage: vehicle not found\n"},
+		{name: "case 5: a code out of the error range", accept: "application/json", code: http.StatusOK, status: http.StatusInternalServerError, contentType: "application/json", body: `{"status":"Internal Server Error","message":"vehicle not found"}`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			h := Middleware(NewNegotiator(Codecs()...))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Error(w, r, c.code, "vehicle not found")
+			}))
+			r := httptest.NewRequest("GET", "/vehicles/1", nil)
+			r.Header.Set("Accept", c.accept)
+			res := httptest.NewRecorder()
+
+			// act
+			h.ServeHTTP(res, r)
+
+			// assert
+			require.Equal(t, c.status, res.Code)
+			require.Equal(t, c.contentType, res.Header().Get("Content-Type"))
+			require.Equal(t, c.body, res.Body.String())
+		})
+	}
+
+	t.Run("case 6: MessagePack", func(t *testing.T) {
+		// arrange
+		h := Middleware(NewNegotiator(Codecs()...))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Error(w, r, http.StatusConflict, "vehicle already exists")
+		}))
+		r := httptest.NewRequest("GET", "/vehicles?format=msgpack", nil)
+		res := httptest.NewRecorder()
+
+		// act
+		h.ServeHTTP(res, r)
+
+		// assert
+		require.Equal(t, http.StatusConflict, res.Code)
+		require.Equal(t, "application/msgpack", res.Header().Get("Content-Type"))
+		var body ErrorJSON
+		require.NoError(t, MessagePack{}.Decode(res.Body, &body))
+		require.Equal(t, ErrorJSON{Status: "Conflict", Message: "vehicle already exists"}, body)
+	})
+}
+
+func TestDecode(t *testing.T) {
+	// encoded is a function that returns the vehicle encoded by the codec
+	encoded := func(c Codec) string {
+		var buf bytes.Buffer
+		if err := c.Encode(&buf, testVehicles[0]); err != nil {
+			panic(err)
+		}
+		return buf.String()
+	}
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		// negotiated is true if the request went through the middleware
+		negotiated bool
+		err        error
+	}{
+		{name: "case 1: JSON", contentType: "application/json", body: encoded(JSON{}), negotiated: true},
+		{name: "case 2: JSON with parameters", contentType: "application/json; charset=utf-8", body: encoded(JSON{}), negotiated: true},
+		{name: "case 3: CSV", contentType: "text/csv", body: encoded(CSV{}), negotiated: true},
+		{name: "case 4: XML", contentType: "text/xml", body: encoded(XML{}), negotiated: true},
+		{name: "case 5: MessagePack", contentType: "application/x-msgpack", body: encoded(MessagePack{}), negotiated: true},
+		{name: "case 6: YAML", contentType: "application/yaml", body: encoded(YAML{}), negotiated: true},
+		{name: "case 7: no Content-Type", body: encoded(JSON{}), negotiated: true, err: ErrContentType},
+		{name: "case 8: unsupported Content-Type", contentType: "text/plain", body: encoded(JSON{}), negotiated: true, err: ErrContentType},
+		{name: "case 9: only JSON without the middleware", contentType: "application/yaml", body: encoded(YAML{}), err: ErrContentType},
+		{name: "case 10: JSON without the middleware", contentType: "application/json", body: encoded(JSON{})},
+		{name: "case 11: invalid body", contentType: "application/json", body: `{"id":`, negotiated: true, err: ErrBody},
+		{name: "case 12: body of another format", contentType: "application/json", body: encoded(YAML{}), negotiated: true, err: ErrBody},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := httptest.NewRequest("POST", "/vehicles", strings.NewReader(c.body))
+			if c.contentType != "" {
+				r.Header.Set("Content-Type", c.contentType)
+			}
+			if c.negotiated {
+				r = r.WithContext(context.WithValue(r.Context(), keyNegotiation, negotiation{negotiator: NewNegotiator(Codecs()...), codec: JSON{}}))
+			}
+
+			// act
+			var v codecVehicle
+			err := Decode(r, &v)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			if c.err == nil {
+				require.Equal(t, testVehicles[0], v)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	// arrange
+	fields := map[string]bool{"brand": true, "id": true, "unknown": true}
+
+	// act
+	o, err := Project(testVehicles[0], fields)
+
+	// assert
+	require.NoError(t, err)
+	b, err := json.Marshal(o)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":1,"brand":"Chevrolet"}`, string(b))
+}
@@ -0,0 +1,297 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// XML is a struct that represents the XML format
+// - the document is a response element, objects are elements named by their JSON fields and lists are item elements
+// - fields whose name is not an XML name (e.g. a year) are field elements with a name attribute
+type XML struct{}
+
+// Format is a method that returns the name of the format
+func (XML) Format() string { return "xml" }
+
+// MediaTypes is a method that returns the media types of the format
+func (XML) MediaTypes() []string { return []string{"application/xml", "text/xml"} }
+
+// Encode is a method that writes a value as XML
+func (XML) Encode(w io.Writer, v any) (err error) {
+	t, err := tree(v)
+	if err != nil {
+		return
+	}
+	if _, err = io.WriteString(w, xml.Header); err != nil {
+		return
+	}
+	enc := xml.NewEncoder(w)
+	if err = writeXML(enc, "response", t); err != nil {
+		return
+	}
+	return enc.Flush()
+}
+
+// xmlName is a function that returns true if a name is a valid XML element name
+func xmlName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// writeXML is a function that writes an element of a tree
+func writeXML(enc *xml.Encoder, name string, t any) (err error) {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !xmlName(name) {
+		start = xml.StartElement{Name: xml.Name{Local: "field"}, Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}}}
+	}
+	if err = enc.EncodeToken(start); err != nil {
+		return
+	}
+	switch t := t.(type) {
+	case object:
+		for _, f := range t {
+			if err = writeXML(enc, f.key, f.value); err != nil {
+				return
+			}
+		}
+	case []any:
+		for _, e := range t {
+			if err = writeXML(enc, "item", e); err != nil {
+				return
+			}
+		}
+	default:
+		if err = enc.EncodeToken(xml.CharData(scalar(t))); err != nil {
+			return
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// Decode is a method that reads a value as XML, as written by Encode
+func (XML) Decode(r io.Reader, v any) (err error) {
+	dec := xml.NewDecoder(r)
+	for {
+		var tok xml.Token
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		if _, ok := tok.(xml.StartElement); ok {
+			break
+		}
+	}
+	doc, err := readXML(dec)
+	if err != nil {
+		return
+	}
+	return assignTo(v, doc)
+}
+
+// readXML is a function that reads the content of an element as a document
+// - elements with children are objects, or lists if every child is an item element, other elements are text
+func readXML(dec *xml.Decoder) (doc any, err error) {
+	var text strings.Builder
+	var names []string
+	var values []any
+	for {
+		var tok xml.Token
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			name := t.Name.Local
+			for _, attr := range t.Attr {
+				if name == "field" && attr.Name.Local == "name" {
+					name = attr.Value
+				}
+			}
+			var child any
+			if child, err = readXML(dec); err != nil {
+				return
+			}
+			names, values = append(names, name), append(values, child)
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(names) == 0 {
+				return text.String(), nil
+			}
+			list := true
+			for _, name := range names {
+				list = list && name == "item"
+			}
+			if list {
+				return values, nil
+			}
+			m := make(map[string]any, len(names))
+			for i, name := range names {
+				m[name] = values[i]
+			}
+			return m, nil
+		}
+	}
+}
+
+// CSV is a struct that represents the CSV format
+// - the rows are the items of a list, the objects of a map (e.g. vehicles by id), or the object, of the data of the response
+// - nested fields are flattened into columns named by their path, e.g. dimensions.height
+type CSV struct{}
+
+// Format is a method that returns the name of the format
+func (CSV) Format() string { return "csv" }
+
+// MediaTypes is a method that returns the media types of the format
+func (CSV) MediaTypes() []string { return []string{"text/csv"} }
+
+// Encode is a method that writes a value as CSV, with a header row
+func (CSV) Encode(w io.Writer, v any) (err error) {
+	t, err := tree(v)
+	if err != nil {
+		return
+	}
+	// - the data of an envelope
+	if obj, ok := t.(object); ok {
+		for _, f := range obj {
+			if f.key == "data" {
+				t = f.value
+			}
+		}
+	}
+
+	var rows []object
+	switch t := t.(type) {
+	case nil:
+	case []any:
+		for _, e := range t {
+			rows = append(rows, flatten("", e, nil))
+		}
+	case object:
+		for _, f := range t {
+			if _, ok := f.value.(object); !ok {
+				rows = nil
+				break
+			}
+			rows = append(rows, flatten("", f.value, nil))
+		}
+		if rows == nil {
+			rows = append(rows, flatten("", t, nil))
+		}
+	default:
+		rows = append(rows, flatten("", t, nil))
+	}
+
+	// - the columns of every row, in order of appearance
+	var header []string
+	columns := make(map[string]int)
+	for _, row := range rows {
+		for _, f := range row {
+			if _, ok := columns[f.key]; !ok {
+				columns[f.key] = len(header)
+				header = append(header, f.key)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	if len(header) > 0 {
+		cw.Write(header)
+	}
+	for _, row := range rows {
+		record := make([]string, len(header))
+		for _, f := range row {
+			record[columns[f.key]] = scalar(f.value)
+		}
+		cw.Write(record)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten is a function that appends the scalars of a tree to a row, named by their path
+func flatten(path string, t any, row object) object {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch t := t.(type) {
+	case object:
+		for _, f := range t {
+			row = flatten(join(f.key), f.value, row)
+		}
+	case []any:
+		for i, e := range t {
+			row = flatten(join(strconv.Itoa(i)), e, row)
+		}
+	default:
+		if path == "" {
+			path = "value"
+		}
+		row = append(row, field{key: path, value: t})
+	}
+	return row
+}
+
+// Decode is a method that reads a value as CSV, with a header row naming the JSON fields
+// - a list is decoded from every row, other values from a single row
+func (CSV) Decode(r io.Reader, v any) (err error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("%w: header row missing", ErrShape)
+	}
+
+	header, rows := records[0], make([]any, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, value := range record {
+			// - empty cells are left to the zero value
+			if i < len(header) && value != "" {
+				row[header[i]] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && t.Kind() == reflect.Slice {
+		return assignTo(v, rows)
+	}
+	if len(rows) != 1 {
+		return fmt.Errorf("%w: one row expected, got %d", ErrShape, len(rows))
+	}
+	return assignTo(v, rows[0])
+}
+
+// assignTo is a function that sets the value a pointer points to from a document
+func assignTo(v any, doc any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return errors.New("decode into a non-pointer value")
+	}
+	return assign(rv.Elem(), doc)
+}
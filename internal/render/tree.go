@@ -0,0 +1,236 @@
+package render
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrShape is the error returned when a document does not have the shape of the value decoded into
+	ErrShape = errors.New("document does not match the value")
+)
+
+// field is a struct that represents a member of an object, objects keep the order of the fields of JSON
+type field struct {
+	// key is the name of the field
+	key string
+	// value is the value of the field
+	value any
+}
+
+// object is a list of fields
+type object []field
+
+// tree is a function that returns a value as a tree of objects, []any and scalars, as encoded by JSON
+// - the scalars are json.Number, string, bool and nil
+func tree(v any) (t any, err error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.UseNumber()
+	return treeValue(dec)
+}
+
+// treeValue is a function that reads the next value of a decoder as a tree
+func treeValue(dec *json.Decoder) (t any, err error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := object{}
+		for dec.More() {
+			var key json.Token
+			if key, err = dec.Token(); err != nil {
+				return
+			}
+			var value any
+			if value, err = treeValue(dec); err != nil {
+				return
+			}
+			obj = append(obj, field{key: key.(string), value: value})
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			var value any
+			if value, err = treeValue(dec); err != nil {
+				return
+			}
+			arr = append(arr, value)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// scalar is a function that returns a scalar of a tree as text
+func scalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// assign is a function that sets a value from a document of a text format
+// - the document is made of map[string]any, []any and string, the strings are parsed by the kind of the value
+// - the fields of structs are matched by their JSON names
+func assign(dst reflect.Value, src any) (err error) {
+	if src == nil {
+		return
+	}
+	if dst.Kind() == reflect.Pointer {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), src)
+	}
+	if s, ok := src.(string); ok && dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			return u.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(src))
+		return
+	case reflect.Struct:
+		m, ok := src.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w: %s must be an object", ErrShape, dst.Type())
+		}
+		for key, value := range m {
+			f, ok := structField(dst, key)
+			if !ok {
+				// - unknown fields are ignored, as by encoding/json
+				continue
+			}
+			if err = assign(f, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		return
+	case reflect.Map:
+		m, ok := src.(map[string]any)
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("%w: %s must be an object", ErrShape, dst.Type())
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		for key, value := range m {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err = assign(elem, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			dst.SetMapIndex(reflect.ValueOf(key).Convert(dst.Type().Key()), elem)
+		}
+		return
+	case reflect.Slice:
+		if s, ok := src.(string); ok && strings.TrimSpace(s) == "" {
+			// - an empty element is an empty list
+			dst.Set(reflect.MakeSlice(dst.Type(), 0, 0))
+			return
+		}
+		arr, ok := src.([]any)
+		if !ok {
+			return fmt.Errorf("%w: %s must be a list", ErrShape, dst.Type())
+		}
+		s := reflect.MakeSlice(dst.Type(), len(arr), len(arr))
+		for i, value := range arr {
+			if err = assign(s.Index(i), value); err != nil {
+				return fmt.Errorf("%d: %w", i, err)
+			}
+		}
+		dst.Set(s)
+		return
+	}
+
+	s, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("%w: %s must be a value", ErrShape, dst.Type())
+	}
+	s = strings.TrimSpace(s)
+	switch dst.Kind() {
+	case reflect.String:
+		dst.SetString(s)
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			dst.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, dst.Type().Bits()); err == nil {
+			dst.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, dst.Type().Bits()); err == nil {
+			dst.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, dst.Type().Bits()); err == nil {
+			dst.SetFloat(f)
+		}
+	default:
+		err = fmt.Errorf("%w: %s is not supported", ErrShape, dst.Type())
+	}
+	return
+}
+
+// structField is a function that returns the field of a struct with a JSON name, matched as by encoding/json
+func structField(v reflect.Value, name string) (f reflect.Value, ok bool) {
+	t := v.Type()
+	fold := -1
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+			// - fields of embedded structs are promoted
+			if f, ok = structField(v.Field(i), name); ok {
+				return
+			}
+			continue
+		}
+		if tag == "" {
+			tag = sf.Name
+		}
+		if tag == name {
+			return v.Field(i), true
+		}
+		if fold < 0 && strings.EqualFold(tag, name) {
+			fold = i
+		}
+	}
+	if fold >= 0 {
+		return v.Field(fold), true
+	}
+	return
+}
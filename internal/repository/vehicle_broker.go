@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"sync"
+)
+
+// NewVehicleBroker is a function that returns a new instance of VehicleBroker
+// - buffer is the number of events a watcher may fall behind before it is dropped
+func NewVehicleBroker(buffer int) *VehicleBroker {
+	return &VehicleBroker{buffer: buffer, watchers: make(map[chan internal.VehicleEvent]struct{})}
+}
+
+// VehicleBroker is a struct that represents the feed of the changes made to the vehicles of a repository
+// - it implements internal.VehicleObserver, to be registered with the repository, and internal.VehicleWatcher
+// - the repository is never blocked: the channel of a watcher falling behind is closed, it may watch again
+type VehicleBroker struct {
+	// mu is the mutex that guards the watchers
+	mu sync.Mutex
+	// buffer is the size of the channel of each watcher
+	buffer int
+	// watchers are the channels of the watchers
+	watchers map[chan internal.VehicleEvent]struct{}
+}
+
+// OnVehicleEvent is a method that sends the change to every watcher
+func (b *VehicleBroker) OnVehicleEvent(e internal.VehicleEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.watchers {
+		select {
+		case ch <- e:
+		default:
+			// - behind, dropped
+			delete(b.watchers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Watch is a method that returns the changes made to the vehicles from now on
+// - the channel is closed when the context is done, or when the reader falls behind
+func (b *VehicleBroker) Watch(ctx context.Context) <-chan internal.VehicleEvent {
+	ch := make(chan internal.VehicleEvent, b.buffer)
+
+	b.mu.Lock()
+	b.watchers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.watchers[ch]; ok {
+			delete(b.watchers, ch)
+			close(ch)
+		}
+	}()
+	return ch
+}
+
+// Watchers is a method that returns the number of watchers
+func (b *VehicleBroker) Watchers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return len(b.watchers)
+}
@@ -1,67 +1,272 @@
-package repository
-
-import (
-	"app/internal"
-	"errors"
-)
-
-// NewVehicleMap is a function that returns a new instance of VehicleMap
-func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
-	// default db
-	defaultDb := make(map[int]internal.Vehicle)
-	if db != nil {
-		defaultDb = db
-	}
-	return &VehicleMap{db: defaultDb}
-}
-
-// VehicleMap is a struct that represents a vehicle repository
-type VehicleMap struct {
-	// db is a map of vehicles
-	db map[int]internal.Vehicle
-}
-
-// FindAll is a method that returns a map of all vehicles
-func (r *VehicleMap) FindAll() (v map[int]internal.Vehicle, err error) {
-	v = make(map[int]internal.Vehicle)
-
-	// copy db
-	for key, value := range r.db {
-		v[key] = value
-	}
-
-	return
-}
-
-func (r *VehicleMap) FindOne(id int) (v internal.Vehicle, err error) {
-	v, ok := r.db[id]
-	if !ok {
-		return internal.Vehicle{}, errors.New("not found")
-	}
-
-	return v, nil
-}
-
-func (r *VehicleMap) Create(v internal.Vehicle) (err error) {
-	_, ok := r.db[v.Id]
-	if ok {
-		return errors.New("identificador do veículo já existente")
-	}
-	r.db[v.Id] = v
-	return nil
-}
-
-func (r *VehicleMap) Update(id int, v internal.Vehicle) (err error) {
-	r.db[id] = v
-	return nil
-}
-
-func (r *VehicleMap) Delete(id int) (err error) {
-	_, ok := r.db[id]
-	if !ok {
-		return errors.New("not found")
-	}
-
-	delete(r.db, id)
-	return
-}
+package repository
+
+import (
+	"app/internal"
+	"app/internal/logger"
+	"app/internal/tracing"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// NewVehicleMap is a function that returns a new instance of VehicleMap
+func NewVehicleMap(db map[int]internal.Vehicle) *VehicleMap {
+	// default db
+	defaultDb := make(map[int]internal.Vehicle)
+	if db != nil {
+		defaultDb = db
+	}
+	// secondary indexes and aggregates
+	indexes := newVehicleIndexes()
+	aggregates := newVehicleAggregates()
+	for _, id := range sortedIDs(defaultDb) {
+		indexes.add(defaultDb[id])
+		aggregates.add(defaultDb[id])
+	}
+	return &VehicleMap{db: defaultDb, indexes: indexes, aggregates: aggregates}
+}
+
+// VehicleMap is a struct that represents a vehicle repository
+type VehicleMap struct {
+	// mu is the mutex that guards the db and the observers
+	mu sync.RWMutex
+	// db is a map of vehicles
+	db map[int]internal.Vehicle
+	// indexes are the secondary indexes of the db, maintained on every write
+	indexes *vehicleIndexes
+	// aggregates are the running statistics of the db, maintained on every write
+	aggregates *vehicleAggregates
+	// observers are notified after every change made to the db
+	observers []internal.VehicleObserver
+	// version is the number of changes made to the db
+	version atomic.Uint64
+}
+
+// Observe is a method that registers an observer of the changes made to the vehicles
+// - the vehicles already stored are replayed to the observer as created events, in ascending order of id
+// - observers are called while the repository is locked, so they must not call it back
+func (r *VehicleMap) Observe(o internal.VehicleObserver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range sortedIDs(r.db) {
+		o.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: r.db[id]})
+	}
+	r.observers = append(r.observers, o)
+}
+
+// sortedIDs is a function that returns the ids of the vehicles in ascending order
+// - the statistics are running sums, adding the vehicles in the same order makes them the same on every start
+func sortedIDs(db map[int]internal.Vehicle) (ids []int) {
+	ids = make([]int, 0, len(db))
+	for id := range db {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return
+}
+
+// notify is a method that notifies the observers of a change
+func (r *VehicleMap) notify(e internal.VehicleEvent) {
+	r.version.Add(1)
+	for _, o := range r.observers {
+		o.OnVehicleEvent(e)
+	}
+}
+
+// Version is a method that returns the number of changes made to the vehicles
+func (r *VehicleMap) Version() uint64 {
+	return r.version.Load()
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (r *VehicleMap) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make(map[int]internal.Vehicle)
+
+	// copy db
+	for key, value := range r.db {
+		v[key] = value
+	}
+	tracing.SpanFromContext(ctx).SetAttributes(tracing.Int("vehicles", len(v)))
+
+	return
+}
+
+func (r *VehicleMap) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.db[id]
+	if !ok {
+		return internal.Vehicle{}, internal.ErrVehicleNotFound
+	}
+
+	return v, nil
+}
+
+func (r *VehicleMap) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.db[v.Id]
+	if ok {
+		return internal.ErrVehicleExists
+	}
+	r.db[v.Id] = v
+	r.indexes.add(v)
+	r.aggregates.add(v)
+	r.notify(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: v})
+	return nil
+}
+
+func (r *VehicleMap) Update(ctx context.Context, id int, v internal.Vehicle) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.db[id]
+	r.db[id] = v
+	if ok {
+		r.indexes.remove(previous)
+		r.aggregates.remove(previous)
+	}
+	r.indexes.add(v)
+	r.aggregates.add(v)
+	if !ok {
+		r.notify(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: v})
+		return nil
+	}
+	r.notify(internal.VehicleEvent{Type: internal.VehicleUpdated, Vehicle: v, Previous: previous})
+	return nil
+}
+
+func (r *VehicleMap) Delete(ctx context.Context, id int) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous, ok := r.db[id]
+	if !ok {
+		return internal.ErrVehicleNotFound
+	}
+
+	delete(r.db, id)
+	r.indexes.remove(previous)
+	r.aggregates.remove(previous)
+	r.notify(internal.VehicleEvent{Type: internal.VehicleDeleted, Previous: previous})
+	return
+}
+
+// Query is a method that returns the vehicles matching the query
+// - the candidates come from the most selective secondary index, falling back to a full scan
+func (r *VehicleMap) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v = make(map[int]internal.Vehicle)
+
+	p, ok := r.indexes.plan(q)
+	if !ok {
+		// full scan
+		logger.FromContext(ctx).Debug("query planned", slog.String("plan", "full scan"), slog.Int("candidates", len(r.db)))
+		for key, value := range r.db {
+			if q.Match(value) {
+				v[key] = value
+			}
+		}
+		tracing.SpanFromContext(ctx).SetAttributes(tracing.String("query.plan", "full scan"), tracing.Int("query.candidates", len(r.db)), tracing.Int("vehicles", len(v)))
+		return
+	}
+
+	logger.FromContext(ctx).Debug("query planned", slog.String("plan", "index"), slog.Int("candidates", p.count))
+	p.scan(func(id int) bool {
+		if value := r.db[id]; q.Match(value) {
+			v[id] = value
+		}
+		return true
+	})
+	tracing.SpanFromContext(ctx).SetAttributes(tracing.String("query.plan", "index"), tracing.Int("query.candidates", p.count), tracing.Int("vehicles", len(v)))
+	return
+}
+
+// CheckHealth is a method that returns an error if the secondary indexes are out of sync with the vehicles
+func (r *VehicleMap) CheckHealth(ctx context.Context) (err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if n := r.indexes.weight.Len(); n != len(r.db) {
+		return fmt.Errorf("indexes out of sync: %d indexed, %d stored", n, len(r.db))
+	}
+	return ctx.Err()
+}
+
+// Aggregate is a method that returns the statistics of the metric over the vehicles of a group, in O(1)
+func (r *VehicleMap) Aggregate(ctx context.Context, group internal.AggregateGroup, key string, metric internal.AggregateMetric) (a internal.Aggregate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.aggregates.get(group, key, metric)
+	if !ok {
+		return internal.Aggregate{}, errors.New("invalid aggregate group")
+	}
+	return a, nil
+}
+
+// Aggregates is a method that returns the statistics of the metric for every key of a group
+func (r *VehicleMap) Aggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys, ok := r.aggregates.groups[group]
+	if !ok {
+		return nil, errors.New("invalid aggregate group")
+	}
+
+	a = make(map[string]internal.Aggregate, len(keys))
+	for key, metrics := range keys {
+		ra, ok := metrics[metric]
+		if !ok {
+			return nil, errors.New("invalid aggregate metric")
+		}
+		a[key] = ra.aggregate()
+	}
+	return a, nil
+}
+
+// CheckAggregates is a method that compares the maintained aggregates against a full recompute of the db
+func (r *VehicleMap) CheckAggregates(ctx context.Context) (m []internal.AggregateMismatch, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expected := newVehicleAggregates()
+	for _, value := range r.db {
+		expected.add(value)
+	}
+
+	// compare every key present on either side
+	for _, group := range internal.AggregateGroups {
+		keys := make(map[string]struct{})
+		for key := range expected.groups[group] {
+			keys[key] = struct{}{}
+		}
+		for key := range r.aggregates.groups[group] {
+			keys[key] = struct{}{}
+		}
+
+		for key := range keys {
+			for _, metric := range internal.AggregateMetrics {
+				e, _ := expected.get(group, key, metric)
+				a, _ := r.aggregates.get(group, key, metric)
+				if !aggregatesEqual(e, a) {
+					m = append(m, internal.AggregateMismatch{Group: group, Key: key, Metric: metric, Expected: e, Actual: a})
+				}
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/index"
+	"math"
+	"strconv"
+)
+
+// aggregateKey is a function that returns the key of the vehicle in the group
+func aggregateKey(group internal.AggregateGroup, v internal.Vehicle) string {
+	switch group {
+	case internal.AggregateByBrand:
+		return v.Brand
+	case internal.AggregateByFuelType:
+		return v.FuelType
+	case internal.AggregateByYear:
+		return strconv.Itoa(v.FabricationYear)
+	}
+	return ""
+}
+
+// runningAggregate is a struct that represents the statistics of a metric, maintained on every write
+type runningAggregate struct {
+	// count is the number of values
+	count int
+	// sum is the sum of the values
+	sum float64
+	// sumSquares is the sum of the squares of the values
+	sumSquares float64
+	// values are the values ordered, so min and max survive removals
+	values *index.Ordered
+}
+
+// aggregate is a method that returns the statistics
+func (a *runningAggregate) aggregate() (s internal.Aggregate) {
+	s = internal.Aggregate{Count: a.count, Sum: a.sum, SumSquares: a.sumSquares}
+	s.Min, _ = a.values.First()
+	s.Max, _ = a.values.Last()
+	return
+}
+
+// newVehicleAggregates is a function that returns a new instance of vehicleAggregates
+func newVehicleAggregates() *vehicleAggregates {
+	groups := make(map[internal.AggregateGroup]map[string]map[internal.AggregateMetric]*runningAggregate)
+	for _, group := range internal.AggregateGroups {
+		groups[group] = make(map[string]map[internal.AggregateMetric]*runningAggregate)
+	}
+	return &vehicleAggregates{groups: groups}
+}
+
+// vehicleAggregates is a struct that represents the running aggregates of a vehicle repository
+// - per group (brand, fuel type, year), per key of the group and per metric
+type vehicleAggregates struct {
+	groups map[internal.AggregateGroup]map[string]map[internal.AggregateMetric]*runningAggregate
+}
+
+// add is a method that adds the vehicle to the aggregates
+func (x *vehicleAggregates) add(v internal.Vehicle) {
+	for group, keys := range x.groups {
+		key := aggregateKey(group, v)
+		metrics, ok := keys[key]
+		if !ok {
+			metrics = make(map[internal.AggregateMetric]*runningAggregate)
+			for _, metric := range internal.AggregateMetrics {
+				metrics[metric] = &runningAggregate{values: index.NewOrdered()}
+			}
+			keys[key] = metrics
+		}
+		for metric, a := range metrics {
+			value := metric.Value(v)
+			a.count++
+			a.sum += value
+			a.sumSquares += value * value
+			a.values.Add(value, v.Id)
+		}
+	}
+}
+
+// remove is a method that removes the vehicle from the aggregates
+func (x *vehicleAggregates) remove(v internal.Vehicle) {
+	for group, keys := range x.groups {
+		key := aggregateKey(group, v)
+		metrics, ok := keys[key]
+		if !ok {
+			continue
+		}
+		for metric, a := range metrics {
+			value := metric.Value(v)
+			a.count--
+			a.sum -= value
+			a.sumSquares -= value * value
+			a.values.Remove(value, v.Id)
+		}
+		// drop the key once it has no vehicles, so the sums start over from zero
+		if metrics[internal.AggregateMetrics[0]].count == 0 {
+			delete(keys, key)
+		}
+	}
+}
+
+// get is a method that returns the statistics of the metric for the key of the group
+func (x *vehicleAggregates) get(group internal.AggregateGroup, key string, metric internal.AggregateMetric) (a internal.Aggregate, ok bool) {
+	keys, ok := x.groups[group]
+	if !ok {
+		return
+	}
+	ra, ok := keys[key][metric]
+	if !ok {
+		// the key has no vehicles
+		return internal.Aggregate{}, true
+	}
+	return ra.aggregate(), true
+}
+
+// aggregatesEqual is a function that returns true if the aggregates are equal, tolerating rounding errors of the sums
+func aggregatesEqual(a, b internal.Aggregate) bool {
+	near := func(x, y float64) bool {
+		return math.Abs(x-y) <= 1e-9*math.Max(1, math.Max(math.Abs(x), math.Abs(y)))
+	}
+	return a.Count == b.Count &&
+		near(a.Sum, b.Sum) &&
+		near(a.SumSquares, b.SumSquares) &&
+		a.Min == b.Min &&
+		a.Max == b.Max
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVehicleMap_CheckAggregates(t *testing.T) {
+	cases := []struct {
+		name string
+		seed int64
+		// ops is the number of random creates, updates and deletes
+		ops int
+	}{
+		{name: "case 1: a few writes", seed: 1, ops: 500},
+		{name: "case 2: more writes", seed: 2, ops: 2000},
+		{name: "case 3: another seed", seed: 3, ops: 2000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			ctx := context.Background()
+			rng := rand.New(rand.NewSource(c.seed))
+			pool := randomVehicles(c.ops, c.seed)
+			r := NewVehicleMap(randomVehicles(20, c.seed+100))
+			ids := []int{}
+			for id := 1; id <= 20; id++ {
+				ids = append(ids, id)
+			}
+			next := 21
+
+			for i := 0; i < c.ops; i++ {
+				// act
+				switch op := rng.Intn(3); {
+				case op == 0 || len(ids) == 0:
+					v := pool[i+1]
+					v.Id = next
+					next++
+					require.NoError(t, r.Create(ctx, v))
+					ids = append(ids, v.Id)
+				case op == 1:
+					id := ids[rng.Intn(len(ids))]
+					v := pool[i+1]
+					v.Id = id
+					require.NoError(t, r.Update(ctx, id, v))
+				default:
+					j := rng.Intn(len(ids))
+					require.NoError(t, r.Delete(ctx, ids[j]))
+					ids = append(ids[:j], ids[j+1:]...)
+				}
+
+				// assert
+				if i%50 == 0 || i == c.ops-1 {
+					m, err := r.CheckAggregates(ctx)
+					require.NoError(t, err)
+					require.Nil(t, m, "operation %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestVehicleMap_AggregateMinMax(t *testing.T) {
+	// vehicle is a function that returns a Ford with the maximum speed
+	vehicle := func(id int, maxSpeed float64) internal.Vehicle {
+		return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", MaxSpeed: maxSpeed}}
+	}
+
+	cases := []struct {
+		name string
+		// change is the write made to the vehicles 1 (100), 2 (150) and 3 (200)
+		change func(r *VehicleMap) error
+		// expected is the aggregate of the maximum speed of the Fords after the change
+		expected internal.Aggregate
+	}{
+		{
+			name:     "case 1: the fastest vehicle deleted",
+			change:   func(r *VehicleMap) error { return r.Delete(context.Background(), 3) },
+			expected: internal.Aggregate{Count: 2, Sum: 250, SumSquares: 32500, Min: 100, Max: 150},
+		},
+		{
+			name:     "case 2: the slowest vehicle deleted",
+			change:   func(r *VehicleMap) error { return r.Delete(context.Background(), 1) },
+			expected: internal.Aggregate{Count: 2, Sum: 350, SumSquares: 62500, Min: 150, Max: 200},
+		},
+		{
+			name:     "case 3: the fastest vehicle slowed down",
+			change:   func(r *VehicleMap) error { return r.Update(context.Background(), 3, vehicle(3, 50)) },
+			expected: internal.Aggregate{Count: 3, Sum: 300, SumSquares: 35000, Min: 50, Max: 150},
+		},
+		{
+			name: "case 4: the fastest vehicle moved to another brand",
+			change: func(r *VehicleMap) error {
+				v := vehicle(3, 200)
+				v.Brand = "Fiat"
+				return r.Update(context.Background(), 3, v)
+			},
+			expected: internal.Aggregate{Count: 2, Sum: 250, SumSquares: 32500, Min: 100, Max: 150},
+		},
+		{
+			name: "case 5: every vehicle deleted",
+			change: func(r *VehicleMap) error {
+				for id := 1; id <= 3; id++ {
+					if err := r.Delete(context.Background(), id); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			expected: internal.Aggregate{},
+		},
+		{
+			name: "case 6: a tie for the fastest, one deleted",
+			change: func(r *VehicleMap) error {
+				if err := r.Create(context.Background(), vehicle(4, 200)); err != nil {
+					return err
+				}
+				return r.Delete(context.Background(), 3)
+			},
+			expected: internal.Aggregate{Count: 3, Sum: 450, SumSquares: 72500, Min: 100, Max: 200},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			ctx := context.Background()
+			r := NewVehicleMap(map[int]internal.Vehicle{1: vehicle(1, 100), 2: vehicle(2, 150), 3: vehicle(3, 200)})
+
+			// act
+			require.NoError(t, c.change(r))
+			a, err := r.Aggregate(ctx, internal.AggregateByBrand, "Ford", internal.MetricMaxSpeed)
+
+			// assert
+			require.NoError(t, err)
+			require.Equal(t, c.expected, a)
+			m, err := r.CheckAggregates(ctx)
+			require.NoError(t, err)
+			require.Nil(t, m)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/index"
+)
+
+// newVehicleIndexes is a function that returns a new instance of vehicleIndexes
+func newVehicleIndexes() *vehicleIndexes {
+	return &vehicleIndexes{
+		brand:           index.NewHash(),
+		color:           index.NewHash(),
+		fuelType:        index.NewHash(),
+		transmission:    index.NewHash(),
+		fabricationYear: index.NewOrdered(),
+		maxSpeed:        index.NewOrdered(),
+		weight:          index.NewOrdered(),
+		height:          index.NewOrdered(),
+		length:          index.NewOrdered(),
+		width:           index.NewOrdered(),
+	}
+}
+
+// vehicleIndexes is a struct that represents the secondary indexes of a vehicle repository
+// - hash indexes for exact attributes, ordered indexes for numeric attributes
+type vehicleIndexes struct {
+	brand        *index.Hash
+	color        *index.Hash
+	fuelType     *index.Hash
+	transmission *index.Hash
+
+	fabricationYear *index.Ordered
+	maxSpeed        *index.Ordered
+	weight          *index.Ordered
+	height          *index.Ordered
+	length          *index.Ordered
+	width           *index.Ordered
+}
+
+// add is a method that indexes the vehicle
+func (x *vehicleIndexes) add(v internal.Vehicle) {
+	x.brand.Add(v.Brand, v.Id)
+	x.color.Add(v.Color, v.Id)
+	x.fuelType.Add(v.FuelType, v.Id)
+	x.transmission.Add(v.Transmission, v.Id)
+	x.fabricationYear.Add(float64(v.FabricationYear), v.Id)
+	x.maxSpeed.Add(v.MaxSpeed, v.Id)
+	x.weight.Add(v.Weight, v.Id)
+	x.height.Add(v.Height, v.Id)
+	x.length.Add(v.Length, v.Id)
+	x.width.Add(v.Width, v.Id)
+}
+
+// remove is a method that removes the vehicle from the indexes
+func (x *vehicleIndexes) remove(v internal.Vehicle) {
+	x.brand.Remove(v.Brand, v.Id)
+	x.color.Remove(v.Color, v.Id)
+	x.fuelType.Remove(v.FuelType, v.Id)
+	x.transmission.Remove(v.Transmission, v.Id)
+	x.fabricationYear.Remove(float64(v.FabricationYear), v.Id)
+	x.maxSpeed.Remove(v.MaxSpeed, v.Id)
+	x.weight.Remove(v.Weight, v.Id)
+	x.height.Remove(v.Height, v.Id)
+	x.length.Remove(v.Length, v.Id)
+	x.width.Remove(v.Width, v.Id)
+}
+
+// vehiclePlan is a struct that represents how a query is executed
+type vehiclePlan struct {
+	// count is the number of candidate ids of the plan
+	count int
+	// scan calls fn for every candidate id, until fn returns false
+	scan func(fn func(id int) bool)
+}
+
+// plan is a method that returns the plan for the query with the fewest candidates
+// - ok is false if the query has no constraint, so every vehicle is a candidate
+// - the candidates must still be matched against the whole query
+func (x *vehicleIndexes) plan(q internal.VehicleQuery) (p vehiclePlan, ok bool) {
+	consider := func(count int, scan func(fn func(id int) bool)) {
+		if !ok || count < p.count {
+			p = vehiclePlan{count: count, scan: scan}
+			ok = true
+		}
+	}
+
+	// hash indexes
+	for _, c := range []struct {
+		idx *index.Hash
+		key string
+	}{
+		{x.brand, q.Brand},
+		{x.color, q.Color},
+		{x.fuelType, q.FuelType},
+		{x.transmission, q.Transmission},
+	} {
+		if c.key == "" {
+			continue
+		}
+		c := c
+		consider(c.idx.Count(c.key), func(fn func(id int) bool) { c.idx.Each(c.key, fn) })
+	}
+
+	// ordered indexes
+	for _, c := range []struct {
+		idx *index.Ordered
+		rg  internal.Range
+	}{
+		{x.fabricationYear, q.FabricationYear},
+		{x.maxSpeed, q.MaxSpeed},
+		{x.weight, q.Weight},
+		{x.height, q.Height},
+		{x.length, q.Length},
+		{x.width, q.Width},
+	} {
+		if !c.rg.Bounded() {
+			continue
+		}
+		c := c
+		consider(c.idx.Count(c.rg), func(fn func(id int) bool) { c.idx.Ascend(c.rg, fn) })
+	}
+
+	return
+}
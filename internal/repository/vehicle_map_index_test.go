@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"app/internal"
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomVehicles is a function that returns n vehicles with random attributes, the same ones for the same seed
+func randomVehicles(n int, seed int64) map[int]internal.Vehicle {
+	rng := rand.New(rand.NewSource(seed))
+	brands := []string{"Ford", "Chevrolet", "Toyota", "Honda", "BMW", "Audi", "Kia", "Mazda", "Volvo", "Tesla"}
+	colors := []string{"Red", "Blue", "Black", "White", "Green", "Silver"}
+	fuelTypes := []string{"gasoline", "diesel", "biodiesel", "electric"}
+	transmissions := []string{"automatic", "manual"}
+
+	v := make(map[int]internal.Vehicle, n)
+	for id := 1; id <= n; id++ {
+		v[id] = internal.Vehicle{
+			Id: id,
+			VehicleAttributes: internal.VehicleAttributes{
+				Brand:           brands[rng.Intn(len(brands))],
+				Model:           fmt.Sprintf("Model %d", rng.Intn(100)),
+				Color:           colors[rng.Intn(len(colors))],
+				FabricationYear: 1980 + rng.Intn(45),
+				Capacity:        1 + rng.Intn(8),
+				MaxSpeed:        float64(80 + rng.Intn(200)),
+				FuelType:        fuelTypes[rng.Intn(len(fuelTypes))],
+				Transmission:    transmissions[rng.Intn(len(transmissions))],
+				Weight:          float64(800+rng.Intn(2500)) + rng.Float64(),
+				Dimensions: internal.Dimensions{
+					Height: float64(120 + rng.Intn(150)),
+					Length: float64(300 + rng.Intn(400)),
+					Width:  float64(150 + rng.Intn(100)),
+				},
+			},
+		}
+	}
+	return v
+}
+
+// float is a function that returns a pointer to the value, for the bounds of a range
+func float(v float64) *float64 {
+	return &v
+}
+
+// scan is a function that returns the vehicles matching the query by a full scan of FindAll, as before the indexes
+func scan(ctx context.Context, r *VehicleMap, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	all, err := r.FindAll(ctx)
+	if err != nil {
+		return
+	}
+	v = make(map[int]internal.Vehicle)
+	for key, value := range all {
+		if q.Match(value) {
+			v[key] = value
+		}
+	}
+	return
+}
+
+// vehicleQueries are the queries of the tests and the benchmarks, from selective to unselective
+var vehicleQueries = []struct {
+	name  string
+	query internal.VehicleQuery
+}{
+	{name: "weight narrow range", query: internal.VehicleQuery{Weight: internal.NewRange(1000, 1010)}},
+	{name: "brand and year", query: internal.VehicleQuery{Brand: "Tesla", FabricationYear: internal.NewRange(2015, 2020)}},
+	{name: "color", query: internal.VehicleQuery{Color: "Red"}},
+	{name: "open height range", query: internal.VehicleQuery{Height: internal.Range{Min: float(150)}}},
+}
+
+func TestVehicleMap_Query(t *testing.T) {
+	ctx := context.Background()
+	r := NewVehicleMap(randomVehicles(2000, 30))
+
+	// the vehicles are changed, so the indexes must follow the writes
+	for id := 1; id <= 200; id++ {
+		v, err := r.FindOne(ctx, id)
+		require.NoError(t, err)
+		v.Weight, v.Brand = 1005, "Tesla"
+		require.NoError(t, r.Update(ctx, id, v))
+	}
+	for id := 201; id <= 400; id++ {
+		require.NoError(t, r.Delete(ctx, id))
+	}
+
+	for _, c := range vehicleQueries {
+		t.Run(c.name, func(t *testing.T) {
+			// act
+			indexed, err := r.Query(ctx, c.query)
+			require.NoError(t, err)
+			scanned, err := scan(ctx, r, c.query)
+			require.NoError(t, err)
+
+			// assert
+			require.NotEmpty(t, scanned)
+			require.Equal(t, scanned, indexed)
+		})
+	}
+	require.NoError(t, r.CheckHealth(ctx))
+}
+
+func BenchmarkVehicleMap_Query(b *testing.B) {
+	ctx := context.Background()
+	for _, n := range []int{10_000, 100_000} {
+		r := NewVehicleMap(randomVehicles(n, 30))
+		for _, c := range vehicleQueries {
+			b.Run(fmt.Sprintf("%d/%s/index", n, c.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := r.Query(ctx, c.query); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("%d/%s/scan", n, c.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := scan(ctx, r, c.query); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/metrics"
+	"context"
+	"time"
+)
+
+// NewVehicleMetrics is a function that returns a new instance of VehicleMetrics
+func NewVehicleMetrics(rp internal.VehicleRepository, op *metrics.Operations) *VehicleMetrics {
+	return &VehicleMetrics{rp: rp, op: op}
+}
+
+// VehicleMetrics is a struct that represents a decorator of a vehicle repository recording the count and duration of its operations
+type VehicleMetrics struct {
+	// rp is the decorated repository
+	rp internal.VehicleRepository
+	// op is the instrumentation of the operations
+	op *metrics.Operations
+}
+
+func (r *VehicleMetrics) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	defer r.op.Observe("FindAll", time.Now(), &err)
+	return r.rp.FindAll(ctx)
+}
+
+func (r *VehicleMetrics) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	defer r.op.Observe("FindOne", time.Now(), &err)
+	return r.rp.FindOne(ctx, id)
+}
+
+func (r *VehicleMetrics) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	defer r.op.Observe("Create", time.Now(), &err)
+	return r.rp.Create(ctx, v)
+}
+
+func (r *VehicleMetrics) Update(ctx context.Context, id int, v internal.Vehicle) (err error) {
+	defer r.op.Observe("Update", time.Now(), &err)
+	return r.rp.Update(ctx, id, v)
+}
+
+func (r *VehicleMetrics) Delete(ctx context.Context, id int) (err error) {
+	defer r.op.Observe("Delete", time.Now(), &err)
+	return r.rp.Delete(ctx, id)
+}
+
+func (r *VehicleMetrics) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	defer r.op.Observe("Query", time.Now(), &err)
+	return r.rp.Query(ctx, q)
+}
+
+func (r *VehicleMetrics) Aggregate(ctx context.Context, group internal.AggregateGroup, key string, metric internal.AggregateMetric) (a internal.Aggregate, err error) {
+	defer r.op.Observe("Aggregate", time.Now(), &err)
+	return r.rp.Aggregate(ctx, group, key, metric)
+}
+
+func (r *VehicleMetrics) Aggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	defer r.op.Observe("Aggregates", time.Now(), &err)
+	return r.rp.Aggregates(ctx, group, metric)
+}
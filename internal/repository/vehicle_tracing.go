@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"app/internal"
+	"app/internal/tracing"
+	"context"
+)
+
+// NewVehicleTracing is a function that returns a new instance of VehicleTracing
+func NewVehicleTracing(rp internal.VehicleRepository) *VehicleTracing {
+	return &VehicleTracing{rp: rp}
+}
+
+// VehicleTracing is a struct that represents a decorator of a vehicle repository starting a span for each operation
+type VehicleTracing struct {
+	// rp is the decorated repository
+	rp internal.VehicleRepository
+}
+
+func (r *VehicleTracing) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.FindAll")
+	defer span.End(&err)
+	return r.rp.FindAll(ctx)
+}
+
+func (r *VehicleTracing) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.FindOne")
+	defer span.End(&err)
+	return r.rp.FindOne(ctx, id)
+}
+
+func (r *VehicleTracing) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Create")
+	defer span.End(&err)
+	return r.rp.Create(ctx, v)
+}
+
+func (r *VehicleTracing) Update(ctx context.Context, id int, v internal.Vehicle) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Update")
+	defer span.End(&err)
+	return r.rp.Update(ctx, id, v)
+}
+
+func (r *VehicleTracing) Delete(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Delete")
+	defer span.End(&err)
+	return r.rp.Delete(ctx, id)
+}
+
+func (r *VehicleTracing) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Query")
+	defer span.End(&err)
+	return r.rp.Query(ctx, q)
+}
+
+func (r *VehicleTracing) Aggregate(ctx context.Context, group internal.AggregateGroup, key string, metric internal.AggregateMetric) (a internal.Aggregate, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Aggregate")
+	defer span.End(&err)
+	return r.rp.Aggregate(ctx, group, key, metric)
+}
+
+func (r *VehicleTracing) Aggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleRepository.Aggregates")
+	defer span.End(&err)
+	return r.rp.Aggregates(ctx, group, metric)
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"app/internal"
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// NewVehicleCache is a function that returns a new instance of VehicleCache
+// - capacity is the maximum number of entries, the least recently used entry is evicted first
+// - ttl is the time to live of an entry, zero means entries do not expire
+func NewVehicleCache(sv internal.VehicleService, capacity int, ttl time.Duration) *VehicleCache {
+	return &VehicleCache{
+		VehicleService: sv,
+		capacity:       capacity,
+		ttl:            ttl,
+		entries:        make(map[string]*list.Element),
+		lru:            list.New(),
+		now:            time.Now,
+	}
+}
+
+// VehicleCache is a struct that represents a read-through caching decorator of a vehicle service
+// - reads are cached, writes go straight to the decorated service
+// - it implements internal.VehicleObserver: each change made to a vehicle invalidates only the entries depending on it
+type VehicleCache struct {
+	// VehicleService is the decorated service, its writes are promoted as they are
+	internal.VehicleService
+
+	// mu is the mutex that guards the cache
+	mu sync.Mutex
+	// capacity is the maximum number of entries
+	capacity int
+	// ttl is the time to live of an entry
+	ttl time.Duration
+	// entries are the elements of the lru list by key
+	entries map[string]*list.Element
+	// lru is the list of entries, the most recently used at the front
+	lru *list.List
+	// generation is incremented on every invalidation, so results loaded meanwhile are not stored
+	generation uint64
+	// stats are the metrics of the cache
+	stats internal.CacheStats
+	// now returns the current time
+	now func() time.Time
+}
+
+// cacheEntry is a struct that represents a cached result
+type cacheEntry struct {
+	// key identifies the method and its arguments
+	key string
+	// value is the result of the method
+	value any
+	// err is the not found error of the method, so misses are cached as well
+	err error
+	// expires is when the entry stops being valid, zero if it does not expire
+	expires time.Time
+	// depends returns true if the result depends on the vehicle
+	depends func(v internal.Vehicle) bool
+}
+
+// dependsOnAll is a function that returns true for every vehicle
+func dependsOnAll(v internal.Vehicle) bool {
+	return true
+}
+
+// cached is a function that returns the result of load for the key, from the cache if present
+// - clone copies the result so callers never share the cached value
+// - only the results and the not found errors are cached, other errors (e.g. a canceled request) may not happen again
+func cached[T any](c *VehicleCache, key string, depends func(v internal.Vehicle) bool, load func() (T, error), clone func(T) T) (value T, err error) {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		if entry.expires.IsZero() || c.now().Before(entry.expires) {
+			c.lru.MoveToFront(el)
+			c.stats.Hits++
+			c.mu.Unlock()
+			value, _ = entry.value.(T)
+			return clone(value), entry.err
+		}
+		c.removeElement(el)
+		c.stats.Expirations++
+	}
+	c.stats.Misses++
+	generation := c.generation
+	c.mu.Unlock()
+
+	// load outside of the lock
+	value, err = load()
+	if err != nil && !errors.Is(err, internal.ErrVehicleNotFound) {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation || c.capacity <= 0 {
+		// a vehicle changed while loading, the result may already be stale
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	entry := &cacheEntry{key: key, value: clone(value), err: err, depends: depends}
+	if c.ttl > 0 {
+		entry.expires = c.now().Add(c.ttl)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.capacity {
+		c.removeElement(c.lru.Back())
+		c.stats.Evictions++
+	}
+	return
+}
+
+// removeElement is a method that removes an element of the lru list
+func (c *VehicleCache) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*cacheEntry).key)
+}
+
+// OnVehicleEvent is a method that invalidates the entries depending on the changed vehicle
+func (c *VehicleCache) OnVehicleEvent(e internal.VehicleEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for el := c.lru.Front(); el != nil; {
+		next := el.Next()
+		entry := el.Value.(*cacheEntry)
+		if (e.Type != internal.VehicleDeleted && entry.depends(e.Vehicle)) ||
+			(e.Type != internal.VehicleCreated && entry.depends(e.Previous)) {
+			c.removeElement(el)
+			c.stats.Invalidations++
+		}
+		el = next
+	}
+}
+
+// Stats is a method that returns the metrics of the cache
+func (c *VehicleCache) Stats() (s internal.CacheStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s = c.stats
+	s.Entries = c.lru.Len()
+	return
+}
+
+// cloneVehicles is a function that copies a map of vehicles
+func cloneVehicles(v map[int]internal.Vehicle) map[int]internal.Vehicle {
+	return maps.Clone(v)
+}
+
+// cloneValue is a function that returns the value as is, for immutable results
+func cloneValue[T any](v T) T {
+	return v
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (c *VehicleCache) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	return cached(c, "FindAll", dependsOnAll,
+		func() (map[int]internal.Vehicle, error) { return c.VehicleService.FindAll(ctx) },
+		cloneVehicles,
+	)
+}
+
+func (c *VehicleCache) GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]internal.Vehicle, err error) {
+	yearInt, _ := strconv.Atoi(year)
+	return cached(c, fmt.Sprintf("GetVehiclesByColorYear:%q:%q", color, year),
+		func(v internal.Vehicle) bool { return v.Color == color && v.FabricationYear == yearInt },
+		func() (map[int]internal.Vehicle, error) {
+			return c.VehicleService.GetVehiclesByColorYear(ctx, color, year)
+		},
+		cloneVehicles,
+	)
+}
+
+func (c *VehicleCache) GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
+	startYearInt, _ := strconv.Atoi(startYear)
+	endYearInt, _ := strconv.Atoi(endYear)
+	return cached(c, fmt.Sprintf("GetVehiclesByBrandYears:%q:%q:%q", brand, startYear, endYear),
+		func(v internal.Vehicle) bool {
+			return v.Brand == brand && v.FabricationYear >= startYearInt && v.FabricationYear <= endYearInt
+		},
+		func() (map[int]internal.Vehicle, error) {
+			return c.VehicleService.GetVehiclesByBrandYears(ctx, brand, startYear, endYear)
+		},
+		cloneVehicles,
+	)
+}
+
+func (c *VehicleCache) GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error) {
+	return cached(c, fmt.Sprintf("GetAverageSpeedByBrand:%q", brand),
+		func(v internal.Vehicle) bool { return v.Brand == brand },
+		func() (float64, error) { return c.VehicleService.GetAverageSpeedByBrand(ctx, brand) },
+		cloneValue[float64],
+	)
+}
+
+func (c *VehicleCache) GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("GetVehicleByFuelType:%q", fuelType),
+		func(v internal.Vehicle) bool { return v.FuelType == fuelType },
+		func() (map[int]internal.Vehicle, error) { return c.VehicleService.GetVehicleByFuelType(ctx, fuelType) },
+		cloneVehicles,
+	)
+}
+
+func (c *VehicleCache) GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("GetByTransmissionType:%q", transmissionType),
+		func(v internal.Vehicle) bool { return v.Transmission == transmissionType },
+		func() (map[int]internal.Vehicle, error) {
+			return c.VehicleService.GetByTransmissionType(ctx, transmissionType)
+		},
+		cloneVehicles,
+	)
+}
+
+func (c *VehicleCache) GetAverageCapacityByBrand(ctx context.Context, brand string) (average int, err error) {
+	return cached(c, fmt.Sprintf("GetAverageCapacityByBrand:%q", brand),
+		func(v internal.Vehicle) bool { return v.Brand == brand },
+		func() (int, error) { return c.VehicleService.GetAverageCapacityByBrand(ctx, brand) },
+		cloneValue[int],
+	)
+}
+
+func (c *VehicleCache) GetByDimensions(ctx context.Context, query internal.DimensionsQuery) (v []internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("GetByDimensions:%s", dimensionsQueryKey(query)),
+		func(v internal.Vehicle) bool { return query.Match(v.Dimensions) },
+		func() ([]internal.Vehicle, error) { return c.VehicleService.GetByDimensions(ctx, query) },
+		slices.Clone[[]internal.Vehicle],
+	)
+}
+
+func (c *VehicleCache) GetByWeight(ctx context.Context, min, max float64) (v map[int]internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("GetByWeight:%v:%v", min, max),
+		func(v internal.Vehicle) bool { return v.Weight >= min && v.Weight <= max },
+		func() (map[int]internal.Vehicle, error) { return c.VehicleService.GetByWeight(ctx, min, max) },
+		cloneVehicles,
+	)
+}
+
+// GetSimilarVehicles is a method that returns the k vehicles most similar to the vehicle with the given id
+// - distances are normalized across the fleet, so the result depends on every vehicle
+func (c *VehicleCache) GetSimilarVehicles(ctx context.Context, id int, k int) (v []internal.SimilarVehicle, err error) {
+	return cached(c, fmt.Sprintf("GetSimilarVehicles:%d:%d", id, k), dependsOnAll,
+		func() ([]internal.SimilarVehicle, error) { return c.VehicleService.GetSimilarVehicles(ctx, id, k) },
+		slices.Clone[[]internal.SimilarVehicle],
+	)
+}
+
+// SearchVehicles is a method that returns up to limit vehicles matching the text, sorted by relevance
+// - scores are weighted by the rarity of the terms, so the result depends on every vehicle
+func (c *VehicleCache) SearchVehicles(ctx context.Context, text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	return cached(c, fmt.Sprintf("SearchVehicles:%q:%d", text, limit), dependsOnAll,
+		func() ([]internal.VehicleSearchResult, error) {
+			return c.VehicleService.SearchVehicles(ctx, text, limit)
+		},
+		slices.Clone[[]internal.VehicleSearchResult],
+	)
+}
+
+// FindOne is a method that returns the vehicle with the given id
+func (c *VehicleCache) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("FindOne:%d", id),
+		func(v internal.Vehicle) bool { return v.Id == id },
+		func() (internal.Vehicle, error) { return c.VehicleService.FindOne(ctx, id) },
+		cloneValue[internal.Vehicle],
+	)
+}
+
+// Query is a method that returns the vehicles matching the query, an empty map if there are none
+func (c *VehicleCache) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	return cached(c, fmt.Sprintf("Query:%s", vehicleQueryKey(q)), q.Match,
+		func() (map[int]internal.Vehicle, error) { return c.VehicleService.Query(ctx, q) },
+		cloneVehicles,
+	)
+}
+
+// GetAggregates is a method that returns the statistics of the metric for every key of a group
+// - any vehicle may add a key to the group, so the result depends on every vehicle
+func (c *VehicleCache) GetAggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	return cached(c, fmt.Sprintf("GetAggregates:%q:%q", group, metric), dependsOnAll,
+		func() (map[string]internal.Aggregate, error) {
+			return c.VehicleService.GetAggregates(ctx, group, metric)
+		},
+		maps.Clone[map[string]internal.Aggregate],
+	)
+}
+
+// rangeKey is a function that returns a key identifying a range
+func rangeKey(r internal.Range) string {
+	bound := func(b *float64) string {
+		if b == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*b, 'g', -1, 64)
+	}
+	return bound(r.Min) + "-" + bound(r.Max)
+}
+
+// vehicleQueryKey is a function that returns a key identifying a vehicle query
+func vehicleQueryKey(q internal.VehicleQuery) string {
+	key := fmt.Sprintf("%q:%q:%q:%q", q.Brand, q.Color, q.FuelType, q.Transmission)
+	for _, r := range []internal.Range{q.FabricationYear, q.MaxSpeed, q.Weight, q.Height, q.Length, q.Width, q.Footprint, q.Volume} {
+		key += ":" + rangeKey(r)
+	}
+	return key
+}
+
+// dimensionsQueryKey is a function that returns a key identifying a dimensions query
+func dimensionsQueryKey(q internal.DimensionsQuery) string {
+	key := ""
+	for _, r := range []internal.Range{q.Height, q.Length, q.Width, q.Footprint, q.Volume} {
+		key += rangeKey(r) + ":"
+	}
+	return key
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// vehicleServiceStub is a struct that represents a vehicle service whose FindOne returns the given results in turn
+type vehicleServiceStub struct {
+	internal.VehicleService
+	// errs are the errors of the calls to FindOne, nil for a vehicle
+	errs []error
+	// calls is the number of calls to FindOne
+	calls int
+}
+
+// FindOne is a method that returns the vehicle with the given id, or the error of the call
+func (s *vehicleServiceStub) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	err = s.errs[s.calls]
+	s.calls++
+	if err != nil {
+		return
+	}
+	return internal.Vehicle{Id: id}, nil
+}
+
+func TestVehicleCache_FindOne(t *testing.T) {
+	cases := []struct {
+		name string
+		// err is the error of the first call
+		err error
+		// calls is the number of calls made to the service by two reads
+		calls int
+	}{
+		{name: "case 1: a vehicle is cached", err: nil, calls: 1},
+		{name: "case 2: not found is cached", err: internal.ErrVehicleNotFound, calls: 1},
+		{name: "case 3: a wrapped not found is cached", err: fmt.Errorf("vehicle 1: %w", internal.ErrVehicleNotFound), calls: 1},
+		{name: "case 4: a canceled request is not cached", err: context.Canceled, calls: 2},
+		{name: "case 5: a deadline exceeded is not cached", err: context.DeadlineExceeded, calls: 2},
+		{name: "case 6: an internal error is not cached", err: errors.New("internal error"), calls: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			sv := &vehicleServiceStub{errs: []error{c.err, nil}}
+			cache := NewVehicleCache(sv, 10, time.Minute)
+
+			// act
+			_, err1 := cache.FindOne(context.Background(), 1)
+			_, err2 := cache.FindOne(context.Background(), 1)
+
+			// assert
+			require.Equal(t, c.calls, sv.calls)
+			require.Equal(t, c.err, err1)
+			if c.calls == 1 {
+				require.Equal(t, c.err, err2)
+			} else {
+				require.NoError(t, err2)
+			}
+		})
+	}
+}
+
+// countingService is a struct that represents a vehicle service counting the calls to the averages by brand
+type countingService struct {
+	internal.VehicleService
+	// calls is the number of calls to the averages
+	calls int
+}
+
+// GetAverageSpeedByBrand is a method that counts the call and returns the average of the decorated service
+func (s *countingService) GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error) {
+	s.calls++
+	return s.VehicleService.GetAverageSpeedByBrand(ctx, brand)
+}
+
+// GetAverageCapacityByBrand is a method that counts the call and returns the average of the decorated service
+func (s *countingService) GetAverageCapacityByBrand(ctx context.Context, brand string) (average int, err error) {
+	s.calls++
+	return s.VehicleService.GetAverageCapacityByBrand(ctx, brand)
+}
+
+func TestVehicleCache_AverageByBrand(t *testing.T) {
+	cases := []struct {
+		name string
+		// average is the average of the cache for the brand
+		average func(cache *VehicleCache, brand string) (any, error)
+	}{
+		{name: "case 1: speed", average: func(cache *VehicleCache, brand string) (any, error) {
+			return cache.GetAverageSpeedByBrand(context.Background(), brand)
+		}},
+		{name: "case 2: capacity", average: func(cache *VehicleCache, brand string) (any, error) {
+			return cache.GetAverageCapacityByBrand(context.Background(), brand)
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			sv := &countingService{VehicleService: newTestVehicleDefault(testVehicles)}
+			cache := NewVehicleCache(sv, 10, time.Minute)
+
+			// act
+			_, err1 := c.average(cache, "Tesla")
+			_, err2 := c.average(cache, "Tesla")
+
+			// assert
+			// - a brand without vehicles is not found, and cached as such
+			require.ErrorIs(t, err1, internal.ErrVehicleNotFound)
+			require.ErrorIs(t, err2, internal.ErrVehicleNotFound)
+			require.Equal(t, 1, sv.calls)
+
+			// - a vehicle of the brand invalidates the miss
+			cache.OnVehicleEvent(internal.VehicleEvent{Type: internal.VehicleCreated, Vehicle: internal.Vehicle{Id: 3, VehicleAttributes: internal.VehicleAttributes{Brand: "Tesla"}}})
+			_, err := c.average(cache, "Tesla")
+			require.ErrorIs(t, err, internal.ErrVehicleNotFound)
+			require.Equal(t, 2, sv.calls)
+		})
+	}
+}
@@ -1,234 +1,377 @@
-package service
-
-import (
-	"app/internal"
-	"errors"
-	"strconv"
-)
-
-// NewVehicleDefault is a function that returns a new instance of VehicleDefault
-func NewVehicleDefault(rp internal.VehicleRepository) *VehicleDefault {
-	return &VehicleDefault{rp: rp}
-}
-
-// VehicleDefault is a struct that represents the default service for vehicles
-type VehicleDefault struct {
-	// rp is the repository that will be used by the service
-	rp internal.VehicleRepository
-}
-
-// FindAll is a method that returns a map of all vehicles
-func (s *VehicleDefault) FindAll() (v map[int]internal.Vehicle, err error) {
-	v, err = s.rp.FindAll()
-	return
-}
-
-func (s *VehicleDefault) Create(v internal.Vehicle) (err error) {
-	err = s.rp.Create(v)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (s *VehicleDefault) GetVehiclesByColorYear(color, year string) (v map[int]internal.Vehicle, err error) {
-	vehicles, err := s.rp.FindAll()
-	if err != nil {
-		return nil, err
-	}
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-	for key, value := range vehicles {
-		intData, _ := strconv.Atoi(year)
-		if value.VehicleAttributes.Color == color && value.VehicleAttributes.FabricationYear == intData {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
-
-func (s *VehicleDefault) GetVehiclesByBrandYears(brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
-	allVehicles, _ := s.rp.FindAll()
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-
-	for key, value := range allVehicles {
-		startYearInt, _ := strconv.Atoi(startYear)
-		endYearInt, _ := strconv.Atoi(endYear)
-
-		if value.Brand == brand && value.FabricationYear >= startYearInt && value.FabricationYear <= endYearInt {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
-
-func (s *VehicleDefault) GetAverageSpeedByBrand(brand string) (speed float64, err error) {
-	allVehicles, _ := s.rp.FindAll()
-
-	var totalSpeed float64
-	var qtd int
-
-	for _, value := range allVehicles {
-		if value.Brand == brand {
-			totalSpeed += value.MaxSpeed
-			qtd += 1
-		}
-	}
-
-	if qtd == 0 {
-		return 0.0, errors.New("not found")
-	}
-
-	return totalSpeed / float64(qtd), nil
-}
-
-func (s *VehicleDefault) CreateVehicles(vehicles []internal.Vehicle) (err error) {
-	for _, v := range vehicles {
-		err = s.rp.Create(v)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func (s *VehicleDefault) UpdateVehicleSpeed(id int, newSpeed float64) (err error) {
-	v, err := s.rp.FindOne(id)
-	if err != nil {
-		return err
-	}
-
-	v.MaxSpeed = newSpeed
-
-	err = s.rp.Update(id, v)
-	return
-}
-
-func (s *VehicleDefault) GetVehicleByFuelType(fuelType string) (v map[int]internal.Vehicle, err error) {
-	allVehicles, err := s.FindAll()
-	if err != nil {
-		return nil, err
-	}
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-
-	for key, value := range allVehicles {
-		println(fuelType)
-		if value.FuelType == fuelType {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
-
-func (s *VehicleDefault) DeleteVehicle(id int) (err error) {
-	err = s.rp.Delete(id)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
-func (s *VehicleDefault) GetByTransmissionType(transmissionType string) (v map[int]internal.Vehicle, err error) {
-	vehicles, _ := s.FindAll()
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-
-	for key, value := range vehicles {
-		if value.Transmission == transmissionType {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
-
-func (s *VehicleDefault) UpdateFuelType(id int, fuelType string) (err error) {
-	v, err := s.rp.FindOne(id)
-	if err != nil {
-		return err
-	}
-
-	v.FuelType = fuelType
-	s.rp.Update(id, v)
-
-	return
-}
-
-func (s *VehicleDefault) GetAverageCapacityByBrand(brand string) (average int, err error) {
-	allVehicles, _ := s.FindAll()
-
-	var totalCapacity int
-	var qtd int
-
-	for _, value := range allVehicles {
-		if value.Brand == brand {
-			qtd += 1
-			totalCapacity += value.Capacity
-		}
-	}
-
-	if qtd == 0 {
-		return 0, errors.New("not found")
-	}
-
-	return totalCapacity / qtd, nil
-}
-
-func (s *VehicleDefault) GetByDimensions(minLengthFloat, maxLengthFloat, minWidthFloat, maxWidthFloat float64) (v map[int]internal.Vehicle, err error) {
-	allVehicles, _ := s.FindAll()
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-
-	for key, value := range allVehicles {
-		if value.Height >= minLengthFloat && value.Height <= maxLengthFloat && value.Width >= minWidthFloat && value.Width <= maxWidthFloat {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
-
-func (s *VehicleDefault) GetByWeight(min, max float64) (v map[int]internal.Vehicle, err error) {
-	allVehicles, _ := s.rp.FindAll()
-
-	filteredVehicles := make(map[int]internal.Vehicle)
-
-	for key, value := range allVehicles {
-		if value.Weight >= min && value.Weight <= max {
-			filteredVehicles[key] = value
-		}
-	}
-
-	if len(filteredVehicles) == 0 {
-		return nil, errors.New("not found")
-	}
-
-	return filteredVehicles, nil
-}
+package service
+
+import (
+	"app/internal"
+	"app/internal/logger"
+	"app/internal/tracing"
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+// NewVehicleDefault is a function that returns a new instance of VehicleDefault
+func NewVehicleDefault(rp internal.VehicleRepository, sm internal.VehicleSimilarity, sr internal.VehicleSearcher, ev internal.VehicleWatcher) *VehicleDefault {
+	return &VehicleDefault{rp: rp, sm: sm, sr: sr, ev: ev}
+}
+
+// VehicleDefault is a struct that represents the default service for vehicles
+type VehicleDefault struct {
+	// rp is the repository that will be used by the service
+	rp internal.VehicleRepository
+	// sm is the index of similar vehicles that will be used by the service
+	sm internal.VehicleSimilarity
+	// sr is the full-text index of vehicles that will be used by the service
+	sr internal.VehicleSearcher
+	// ev is the feed of the changes made to the vehicles that will be used by the service
+	ev internal.VehicleWatcher
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (s *VehicleDefault) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.FindAll")
+	defer span.End(&err)
+
+	v, err = s.rp.FindAll(ctx)
+	return
+}
+
+func (s *VehicleDefault) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.Create")
+	defer span.End(&err)
+
+	err = s.rp.Create(ctx, v)
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("vehicle created", slog.Int("vehicle_id", v.Id))
+	return nil
+}
+
+func (s *VehicleDefault) GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetVehiclesByColorYear")
+	defer span.End(&err)
+
+	intData, _ := strconv.Atoi(year)
+
+	filteredVehicles, err := s.rp.Query(ctx, internal.VehicleQuery{
+		Color:           color,
+		FabricationYear: internal.NewRange(float64(intData), float64(intData)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filteredVehicles) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return filteredVehicles, nil
+}
+
+func (s *VehicleDefault) GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetVehiclesByBrandYears")
+	defer span.End(&err)
+
+	startYearInt, _ := strconv.Atoi(startYear)
+	endYearInt, _ := strconv.Atoi(endYear)
+
+	filteredVehicles, err := s.rp.Query(ctx, internal.VehicleQuery{
+		Brand:           brand,
+		FabricationYear: internal.NewRange(float64(startYearInt), float64(endYearInt)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filteredVehicles) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return filteredVehicles, nil
+}
+
+func (s *VehicleDefault) GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetAverageSpeedByBrand")
+	defer span.End(&err)
+
+	aggregate, err := s.rp.Aggregate(ctx, internal.AggregateByBrand, brand, internal.MetricMaxSpeed)
+	if err != nil {
+		return 0.0, err
+	}
+
+	if aggregate.Count == 0 {
+		return 0.0, internal.ErrVehicleNotFound
+	}
+
+	return aggregate.Mean(), nil
+}
+
+func (s *VehicleDefault) CreateVehicles(ctx context.Context, vehicles []internal.Vehicle) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.CreateVehicles")
+	defer span.End(&err)
+
+	for _, v := range vehicles {
+		err = s.rp.Create(ctx, v)
+		if err != nil {
+			return err
+		}
+	}
+
+	logger.FromContext(ctx).Info("vehicles created", slog.Int("count", len(vehicles)))
+	return nil
+}
+
+func (s *VehicleDefault) UpdateVehicleSpeed(ctx context.Context, id int, newSpeed float64) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.UpdateVehicleSpeed")
+	defer span.End(&err)
+
+	v, err := s.rp.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	v.MaxSpeed = newSpeed
+
+	err = s.rp.Update(ctx, id, v)
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("vehicle speed updated", slog.Int("vehicle_id", id), slog.Float64("max_speed", newSpeed))
+	return
+}
+
+func (s *VehicleDefault) GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetVehicleByFuelType")
+	defer span.End(&err)
+
+	filteredVehicles, err := s.rp.Query(ctx, internal.VehicleQuery{FuelType: fuelType})
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Debug("vehicles by fuel type", slog.String("fuel_type", fuelType), slog.Int("count", len(filteredVehicles)))
+
+	if len(filteredVehicles) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return filteredVehicles, nil
+}
+
+func (s *VehicleDefault) DeleteVehicle(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.DeleteVehicle")
+	defer span.End(&err)
+
+	err = s.rp.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("vehicle deleted", slog.Int("vehicle_id", id))
+	return nil
+}
+
+func (s *VehicleDefault) GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetByTransmissionType")
+	defer span.End(&err)
+
+	filteredVehicles, err := s.rp.Query(ctx, internal.VehicleQuery{Transmission: transmissionType})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filteredVehicles) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return filteredVehicles, nil
+}
+
+func (s *VehicleDefault) UpdateFuelType(ctx context.Context, id int, fuelType string) (err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.UpdateFuelType")
+	defer span.End(&err)
+
+	v, err := s.rp.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	v.FuelType = fuelType
+
+	err = s.rp.Update(ctx, id, v)
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx).Info("vehicle fuel type updated", slog.Int("vehicle_id", id), slog.String("fuel_type", fuelType))
+
+	return
+}
+
+func (s *VehicleDefault) GetAverageCapacityByBrand(ctx context.Context, brand string) (average int, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetAverageCapacityByBrand")
+	defer span.End(&err)
+
+	aggregate, err := s.rp.Aggregate(ctx, internal.AggregateByBrand, brand, internal.MetricCapacity)
+	if err != nil {
+		return 0, err
+	}
+
+	if aggregate.Count == 0 {
+		return 0, internal.ErrVehicleNotFound
+	}
+
+	// the capacities are integers, so the sum is exact
+	return int(aggregate.Sum) / aggregate.Count, nil
+}
+
+// GetByDimensions is a method that returns the vehicles whose dimensions match the query
+// - the vehicles are sorted by id
+func (s *VehicleDefault) GetByDimensions(ctx context.Context, query internal.DimensionsQuery) (v []internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetByDimensions")
+	defer span.End(&err)
+
+	if err = query.Validate(); err != nil {
+		return nil, err
+	}
+
+	// the indexed dimensions narrow the candidates, the derived ones are matched afterwards
+	candidates, err := s.rp.Query(ctx, internal.VehicleQuery{
+		Height: query.Height,
+		Length: query.Length,
+		Width:  query.Width,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range candidates {
+		if query.Match(value.Dimensions) {
+			v = append(v, value)
+		}
+	}
+
+	if len(v) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	// sort
+	sort.Slice(v, func(i, j int) bool { return v[i].Id < v[j].Id })
+
+	return v, nil
+}
+
+func (s *VehicleDefault) GetByWeight(ctx context.Context, min, max float64) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetByWeight")
+	defer span.End(&err)
+
+	filteredVehicles, err := s.rp.Query(ctx, internal.VehicleQuery{Weight: internal.NewRange(min, max)})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filteredVehicles) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return filteredVehicles, nil
+}
+
+// GetSimilarVehicles is a method that returns the k vehicles most similar to the vehicle with the given id
+func (s *VehicleDefault) GetSimilarVehicles(ctx context.Context, id int, k int) (v []internal.SimilarVehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetSimilarVehicles")
+	defer span.End(&err)
+
+	if k <= 0 {
+		return nil, errors.New("invalid number of vehicles")
+	}
+
+	v, err = s.sm.Nearest(id, k)
+	return
+}
+
+// SearchVehicles is a method that returns up to limit vehicles matching the text, sorted by relevance
+func (s *VehicleDefault) SearchVehicles(ctx context.Context, text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.SearchVehicles")
+	defer span.End(&err)
+
+	v, err = s.sr.Search(text, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v) == 0 {
+		return nil, internal.ErrVehicleNotFound
+	}
+
+	return v, nil
+}
+
+// FindOne is a method that returns the vehicle with the given id
+func (s *VehicleDefault) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.FindOne")
+	defer span.End(&err)
+
+	v, err = s.rp.FindOne(ctx, id)
+	return
+}
+
+// Query is a method that returns the vehicles matching the query, an empty map if there are none
+func (s *VehicleDefault) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.Query")
+	defer span.End(&err)
+
+	for _, r := range []internal.Range{q.FabricationYear, q.MaxSpeed, q.Weight, q.Height, q.Length, q.Width, q.Footprint, q.Volume} {
+		if err = r.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	v, err = s.rp.Query(ctx, q)
+	return
+}
+
+// Patch is a method that applies the patch to the vehicle with the given id and returns the patched vehicle
+// - the patched vehicle is validated as a new one, it is not updated if invalid
+func (s *VehicleDefault) Patch(ctx context.Context, id int, p internal.VehiclePatch) (v internal.Vehicle, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.Patch")
+	defer span.End(&err)
+
+	v, err = s.rp.FindOne(ctx, id)
+	if err != nil {
+		return
+	}
+	if p.Empty() {
+		return
+	}
+
+	v = p.Apply(v)
+	if err = v.Validate(); err != nil {
+		return internal.Vehicle{}, err
+	}
+	err = s.rp.Update(ctx, id, v)
+	if err != nil {
+		return internal.Vehicle{}, err
+	}
+
+	logger.FromContext(ctx).Info("vehicle patched", slog.Int("vehicle_id", id))
+	return
+}
+
+// GetAggregates is a method that returns the statistics of the metric for every key of a group
+func (s *VehicleDefault) GetAggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	ctx, span := tracing.Start(ctx, "VehicleDefault.GetAggregates")
+	defer span.End(&err)
+
+	a, err = s.rp.Aggregates(ctx, group, metric)
+	return
+}
+
+// WatchVehicles is a method that returns the changes made to the vehicles from now on, until the context is done
+func (s *VehicleDefault) WatchVehicles(ctx context.Context) (events <-chan internal.VehicleEvent, err error) {
+	events = s.ev.Watch(ctx)
+	return
+}
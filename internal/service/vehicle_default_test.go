@@ -0,0 +1,64 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/repository"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestVehicleDefault is a function that returns the default service of the vehicles, without indexes nor events
+func newTestVehicleDefault(db map[int]internal.Vehicle) *VehicleDefault {
+	return NewVehicleDefault(repository.NewVehicleMap(db), nil, nil, nil)
+}
+
+// testVehicles are two vehicles, the first one smaller in every dimension
+var testVehicles = map[int]internal.Vehicle{
+	1: {Id: 1, VehicleAttributes: internal.VehicleAttributes{Brand: "Fiat", Model: "500", FabricationYear: 2010, MaxSpeed: 150, Weight: 900, Dimensions: internal.Dimensions{Height: 150, Length: 350, Width: 160}}},
+	2: {Id: 2, VehicleAttributes: internal.VehicleAttributes{Brand: "Ford", Model: "Transit", FabricationYear: 2020, MaxSpeed: 160, Weight: 2500, Dimensions: internal.Dimensions{Height: 250, Length: 550, Width: 200}}},
+}
+
+func TestVehicleDefault_Query(t *testing.T) {
+	cases := []struct {
+		name  string
+		query internal.VehicleQuery
+		// ids are the ids of the vehicles matching the query
+		ids []int
+		err error
+	}{
+		{name: "case 1: every vehicle", query: internal.VehicleQuery{}, ids: []int{1, 2}},
+		{name: "case 2: by footprint", query: internal.VehicleQuery{Footprint: internal.NewRange(50000, 60000)}, ids: []int{1}},
+		{name: "case 3: by volume", query: internal.VehicleQuery{Volume: internal.NewRange(20000000, 30000000)}, ids: []int{2}},
+		{name: "case 4: none", query: internal.VehicleQuery{Footprint: internal.NewRange(1, 2)}, ids: []int{}},
+		{name: "case 5: inverted year", query: internal.VehicleQuery{FabricationYear: internal.NewRange(2020, 2010)}, err: internal.ErrRangeInvalid},
+		{name: "case 6: inverted max speed", query: internal.VehicleQuery{MaxSpeed: internal.NewRange(200, 100)}, err: internal.ErrRangeInvalid},
+		{name: "case 7: inverted weight", query: internal.VehicleQuery{Weight: internal.NewRange(2000, 1000)}, err: internal.ErrRangeInvalid},
+		{name: "case 8: inverted height", query: internal.VehicleQuery{Height: internal.NewRange(200, 100)}, err: internal.ErrRangeInvalid},
+		{name: "case 9: inverted length", query: internal.VehicleQuery{Length: internal.NewRange(500, 300)}, err: internal.ErrRangeInvalid},
+		{name: "case 10: inverted width", query: internal.VehicleQuery{Width: internal.NewRange(200, 100)}, err: internal.ErrRangeInvalid},
+		{name: "case 11: inverted footprint", query: internal.VehicleQuery{Footprint: internal.NewRange(60000, 50000)}, err: internal.ErrRangeInvalid},
+		{name: "case 12: inverted volume", query: internal.VehicleQuery{Volume: internal.NewRange(30000000, 20000000)}, err: internal.ErrRangeInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			sv := newTestVehicleDefault(testVehicles)
+
+			// act
+			v, err := sv.Query(context.Background(), c.query)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			if c.err != nil {
+				return
+			}
+			ids := []int{}
+			for id := range v {
+				ids = append(ids, id)
+			}
+			require.ElementsMatch(t, c.ids, ids)
+		})
+	}
+}
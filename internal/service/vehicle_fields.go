@@ -0,0 +1,164 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/auth"
+	"context"
+)
+
+// NewVehicleFields is a function that returns a new instance of VehicleFields
+func NewVehicleFields(sv internal.VehicleService, policy *auth.Policy) *VehicleFields {
+	return &VehicleFields{VehicleService: sv, policy: policy}
+}
+
+// VehicleFields is a struct that represents a decorator of a vehicle service hiding the fields the caller may not read
+// - the registration is hidden from principals without the vehicles:read:registration permission
+// - requests without a principal, i.e. authentication is disabled, see every field
+type VehicleFields struct {
+	// VehicleService is the decorated service, the methods not returning vehicles are promoted as they are
+	internal.VehicleService
+	// policy is the policy granting the permissions
+	policy *auth.Policy
+}
+
+// hideRegistration is a method that returns true if the registration must be hidden from the caller
+func (s *VehicleFields) hideRegistration(ctx context.Context) bool {
+	p, ok := auth.PrincipalFromContext(ctx)
+	return ok && !s.policy.Allowed(p, auth.PermissionVehiclesReadRegistration)
+}
+
+// HiddenFields is a method that returns the JSON names of the fields of the vehicles hidden from the caller, none if every field is read
+// - the responses leave the fields out, instead of writing them empty
+func (s *VehicleFields) HiddenFields(ctx context.Context) (fields []string) {
+	if s.hideRegistration(ctx) {
+		fields = append(fields, "registration")
+	}
+	return
+}
+
+// hide is a method that hides the fields of the vehicles of a map, the map is owned by the caller
+func (s *VehicleFields) hide(ctx context.Context, v map[int]internal.Vehicle) {
+	if s.hideRegistration(ctx) {
+		for id, value := range v {
+			value.Registration = ""
+			v[id] = value
+		}
+	}
+}
+
+// FindAll is a method that returns a map of all vehicles
+func (s *VehicleFields) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.FindAll(ctx)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetVehiclesByColorYear(ctx, color, year)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetVehiclesByBrandYears(ctx, brand, startYear, endYear)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetVehicleByFuelType(ctx, fuelType)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetByTransmissionType(ctx, transmissionType)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetByWeight(ctx context.Context, min, max float64) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetByWeight(ctx, min, max)
+	s.hide(ctx, v)
+	return
+}
+
+func (s *VehicleFields) GetByDimensions(ctx context.Context, query internal.DimensionsQuery) (v []internal.Vehicle, err error) {
+	v, err = s.VehicleService.GetByDimensions(ctx, query)
+	if s.hideRegistration(ctx) {
+		for i := range v {
+			v[i].Registration = ""
+		}
+	}
+	return
+}
+
+// FindOne is a method that returns the vehicle with the given id
+func (s *VehicleFields) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	v, err = s.VehicleService.FindOne(ctx, id)
+	if s.hideRegistration(ctx) {
+		v.Registration = ""
+	}
+	return
+}
+
+// Query is a method that returns the vehicles matching the query, an empty map if there are none
+func (s *VehicleFields) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	v, err = s.VehicleService.Query(ctx, q)
+	s.hide(ctx, v)
+	return
+}
+
+// Patch is a method that applies the patch to the vehicle with the given id and returns the patched vehicle
+func (s *VehicleFields) Patch(ctx context.Context, id int, p internal.VehiclePatch) (v internal.Vehicle, err error) {
+	v, err = s.VehicleService.Patch(ctx, id, p)
+	if s.hideRegistration(ctx) {
+		v.Registration = ""
+	}
+	return
+}
+
+// GetSimilarVehicles is a method that returns the k vehicles most similar to the vehicle with the given id
+func (s *VehicleFields) GetSimilarVehicles(ctx context.Context, id int, k int) (v []internal.SimilarVehicle, err error) {
+	v, err = s.VehicleService.GetSimilarVehicles(ctx, id, k)
+	if s.hideRegistration(ctx) {
+		for i := range v {
+			v[i].Registration = ""
+		}
+	}
+	return
+}
+
+// SearchVehicles is a method that returns up to limit vehicles matching the text, sorted by relevance
+func (s *VehicleFields) SearchVehicles(ctx context.Context, text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	v, err = s.VehicleService.SearchVehicles(ctx, text, limit)
+	if s.hideRegistration(ctx) {
+		for i := range v {
+			v[i].Registration = ""
+		}
+	}
+	return
+}
+
+// WatchVehicles is a method that returns the changes made to the vehicles from now on, until the context is done
+func (s *VehicleFields) WatchVehicles(ctx context.Context) (events <-chan internal.VehicleEvent, err error) {
+	watched, err := s.VehicleService.WatchVehicles(ctx)
+	if err != nil || !s.hideRegistration(ctx) {
+		return watched, err
+	}
+
+	hidden := make(chan internal.VehicleEvent, cap(watched))
+	go func() {
+		defer close(hidden)
+		for e := range watched {
+			e.Vehicle.Registration = ""
+			e.Previous.Registration = ""
+			select {
+			case hidden <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return hidden, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/metrics"
+	"context"
+	"time"
+)
+
+// NewVehicleMetrics is a function that returns a new instance of VehicleMetrics
+func NewVehicleMetrics(sv internal.VehicleService, op *metrics.Operations) *VehicleMetrics {
+	return &VehicleMetrics{sv: sv, op: op}
+}
+
+// VehicleMetrics is a struct that represents a decorator of a vehicle service recording the count and duration of its operations
+type VehicleMetrics struct {
+	// sv is the decorated service
+	sv internal.VehicleService
+	// op is the instrumentation of the operations
+	op *metrics.Operations
+}
+
+func (s *VehicleMetrics) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("FindAll", time.Now(), &err)
+	return s.sv.FindAll(ctx)
+}
+
+func (s *VehicleMetrics) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	defer s.op.Observe("Create", time.Now(), &err)
+	return s.sv.Create(ctx, v)
+}
+
+func (s *VehicleMetrics) GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("GetVehiclesByColorYear", time.Now(), &err)
+	return s.sv.GetVehiclesByColorYear(ctx, color, year)
+}
+
+func (s *VehicleMetrics) GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("GetVehiclesByBrandYears", time.Now(), &err)
+	return s.sv.GetVehiclesByBrandYears(ctx, brand, startYear, endYear)
+}
+
+func (s *VehicleMetrics) GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error) {
+	defer s.op.Observe("GetAverageSpeedByBrand", time.Now(), &err)
+	return s.sv.GetAverageSpeedByBrand(ctx, brand)
+}
+
+func (s *VehicleMetrics) CreateVehicles(ctx context.Context, vehicles []internal.Vehicle) (err error) {
+	defer s.op.Observe("CreateVehicles", time.Now(), &err)
+	return s.sv.CreateVehicles(ctx, vehicles)
+}
+
+func (s *VehicleMetrics) UpdateVehicleSpeed(ctx context.Context, id int, newSpeed float64) (err error) {
+	defer s.op.Observe("UpdateVehicleSpeed", time.Now(), &err)
+	return s.sv.UpdateVehicleSpeed(ctx, id, newSpeed)
+}
+
+func (s *VehicleMetrics) GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("GetVehicleByFuelType", time.Now(), &err)
+	return s.sv.GetVehicleByFuelType(ctx, fuelType)
+}
+
+func (s *VehicleMetrics) DeleteVehicle(ctx context.Context, id int) (err error) {
+	defer s.op.Observe("DeleteVehicle", time.Now(), &err)
+	return s.sv.DeleteVehicle(ctx, id)
+}
+
+func (s *VehicleMetrics) GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("GetByTransmissionType", time.Now(), &err)
+	return s.sv.GetByTransmissionType(ctx, transmissionType)
+}
+
+func (s *VehicleMetrics) UpdateFuelType(ctx context.Context, id int, fuelType string) (err error) {
+	defer s.op.Observe("UpdateFuelType", time.Now(), &err)
+	return s.sv.UpdateFuelType(ctx, id, fuelType)
+}
+
+func (s *VehicleMetrics) GetAverageCapacityByBrand(ctx context.Context, brand string) (averageCapacity int, err error) {
+	defer s.op.Observe("GetAverageCapacityByBrand", time.Now(), &err)
+	return s.sv.GetAverageCapacityByBrand(ctx, brand)
+}
+
+func (s *VehicleMetrics) GetByDimensions(ctx context.Context, query internal.DimensionsQuery) (v []internal.Vehicle, err error) {
+	defer s.op.Observe("GetByDimensions", time.Now(), &err)
+	return s.sv.GetByDimensions(ctx, query)
+}
+
+func (s *VehicleMetrics) GetByWeight(ctx context.Context, minWeigthFloat, maxWeigthFloat float64) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("GetByWeight", time.Now(), &err)
+	return s.sv.GetByWeight(ctx, minWeigthFloat, maxWeigthFloat)
+}
+
+func (s *VehicleMetrics) GetSimilarVehicles(ctx context.Context, id int, k int) (v []internal.SimilarVehicle, err error) {
+	defer s.op.Observe("GetSimilarVehicles", time.Now(), &err)
+	return s.sv.GetSimilarVehicles(ctx, id, k)
+}
+
+func (s *VehicleMetrics) SearchVehicles(ctx context.Context, text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	defer s.op.Observe("SearchVehicles", time.Now(), &err)
+	return s.sv.SearchVehicles(ctx, text, limit)
+}
+
+func (s *VehicleMetrics) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	defer s.op.Observe("FindOne", time.Now(), &err)
+	return s.sv.FindOne(ctx, id)
+}
+
+func (s *VehicleMetrics) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	defer s.op.Observe("Query", time.Now(), &err)
+	return s.sv.Query(ctx, q)
+}
+
+func (s *VehicleMetrics) Patch(ctx context.Context, id int, p internal.VehiclePatch) (v internal.Vehicle, err error) {
+	defer s.op.Observe("Patch", time.Now(), &err)
+	return s.sv.Patch(ctx, id, p)
+}
+
+func (s *VehicleMetrics) GetAggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	defer s.op.Observe("GetAggregates", time.Now(), &err)
+	return s.sv.GetAggregates(ctx, group, metric)
+}
+
+func (s *VehicleMetrics) WatchVehicles(ctx context.Context) (events <-chan internal.VehicleEvent, err error) {
+	defer s.op.Observe("WatchVehicles", time.Now(), &err)
+	return s.sv.WatchVehicles(ctx)
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"app/internal"
+	"app/internal/tenant"
+	"context"
+)
+
+// NewVehicleTenants is a function that returns a new instance of VehicleTenants
+func NewVehicleTenants(tenants *tenant.Registry) *VehicleTenants {
+	return &VehicleTenants{tenants: tenants}
+}
+
+// VehicleTenants is a struct that represents a vehicle service dispatching each call to the fleet of the tenant of the context
+type VehicleTenants struct {
+	// tenants are the fleets of the tenants
+	tenants *tenant.Registry
+}
+
+func (s *VehicleTenants) FindAll(ctx context.Context) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.FindAll(ctx)
+}
+
+func (s *VehicleTenants) Create(ctx context.Context, v internal.Vehicle) (err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.Create(ctx, v)
+}
+
+func (s *VehicleTenants) GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetVehiclesByColorYear(ctx, color, year)
+}
+
+func (s *VehicleTenants) GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetVehiclesByBrandYears(ctx, brand, startYear, endYear)
+}
+
+func (s *VehicleTenants) GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetAverageSpeedByBrand(ctx, brand)
+}
+
+func (s *VehicleTenants) CreateVehicles(ctx context.Context, vehicles []internal.Vehicle) (err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.CreateVehicles(ctx, vehicles)
+}
+
+func (s *VehicleTenants) UpdateVehicleSpeed(ctx context.Context, id int, newSpeed float64) (err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.UpdateVehicleSpeed(ctx, id, newSpeed)
+}
+
+func (s *VehicleTenants) GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetVehicleByFuelType(ctx, fuelType)
+}
+
+func (s *VehicleTenants) DeleteVehicle(ctx context.Context, id int) (err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.DeleteVehicle(ctx, id)
+}
+
+func (s *VehicleTenants) GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetByTransmissionType(ctx, transmissionType)
+}
+
+func (s *VehicleTenants) UpdateFuelType(ctx context.Context, id int, fuelType string) (err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.UpdateFuelType(ctx, id, fuelType)
+}
+
+func (s *VehicleTenants) GetAverageCapacityByBrand(ctx context.Context, brand string) (averageCapacity int, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetAverageCapacityByBrand(ctx, brand)
+}
+
+func (s *VehicleTenants) GetByDimensions(ctx context.Context, query internal.DimensionsQuery) (v []internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetByDimensions(ctx, query)
+}
+
+func (s *VehicleTenants) GetByWeight(ctx context.Context, minWeigthFloat, maxWeigthFloat float64) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetByWeight(ctx, minWeigthFloat, maxWeigthFloat)
+}
+
+func (s *VehicleTenants) GetSimilarVehicles(ctx context.Context, id int, k int) (v []internal.SimilarVehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetSimilarVehicles(ctx, id, k)
+}
+
+func (s *VehicleTenants) SearchVehicles(ctx context.Context, text string, limit int) (v []internal.VehicleSearchResult, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.SearchVehicles(ctx, text, limit)
+}
+
+func (s *VehicleTenants) FindOne(ctx context.Context, id int) (v internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.FindOne(ctx, id)
+}
+
+func (s *VehicleTenants) Query(ctx context.Context, q internal.VehicleQuery) (v map[int]internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.Query(ctx, q)
+}
+
+func (s *VehicleTenants) Patch(ctx context.Context, id int, p internal.VehiclePatch) (v internal.Vehicle, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.Patch(ctx, id, p)
+}
+
+func (s *VehicleTenants) GetAggregates(ctx context.Context, group internal.AggregateGroup, metric internal.AggregateMetric) (a map[string]internal.Aggregate, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.GetAggregates(ctx, group, metric)
+}
+
+func (s *VehicleTenants) WatchVehicles(ctx context.Context) (events <-chan internal.VehicleEvent, err error) {
+	f, err := s.tenants.FromContext(ctx)
+	if err != nil {
+		return
+	}
+	return f.Service.WatchVehicles(ctx)
+}
@@ -0,0 +1,22 @@
+package tenant
+
+import "context"
+
+// DefaultID is the tenant of the requests naming none, it owns the vehicles loaded on start
+const DefaultID = "default"
+
+// contextKey is the key of the tenant in a context
+type contextKey struct{}
+
+// WithContext is a function that returns a copy of the context carrying the id of the tenant
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext is a function that returns the id of the tenant carried by the context, or the default tenant
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok {
+		return id
+	}
+	return DefaultID
+}
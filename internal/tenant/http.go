@@ -0,0 +1,72 @@
+package tenant
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/render"
+	"app/internal/tracing"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+)
+
+const (
+	// Header is the header naming the tenant of a request
+	Header = "X-Tenant-ID"
+	// AdminRole is the role of the principals without a tenant allowed to name any tenant
+	AdminRole = "system-admin"
+)
+
+var (
+	// ErrTenantNotAllowed is the error returned when a principal names a tenant it may not act on
+	ErrTenantNotAllowed = errors.New("tenant not allowed")
+)
+
+// Middleware is a function that returns a middleware resolving the tenant of every request
+// - the tenant of the principal comes first, a principal bound to a tenant cannot name another one
+// - otherwise the tenant is named by the X-Tenant-ID header, or is the default tenant
+// - only a system-admin (or anyone if authentication is disabled) may name a tenant other than the default one
+// - it must be used after auth.Middleware
+func Middleware(r *Registry) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			id, err := Resolve(req)
+			if err != nil {
+				render.Error(w, req, http.StatusForbidden, err.Error())
+				return
+			}
+			if _, err = r.Get(id); err != nil {
+				render.Error(w, req, http.StatusNotFound, err.Error())
+				return
+			}
+
+			ctx := WithContext(req.Context(), id)
+			ctx = logger.WithContext(ctx, logger.FromContext(ctx).With(slog.String("tenant", id)))
+			tracing.SpanFromContext(ctx).SetAttributes(tracing.String("tenant.id", id))
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// Resolve is a function that returns the id of the tenant of the request
+// - it reads the headers and the principal of the request only, so other transports resolve the tenant through it too
+func Resolve(r *http.Request) (id string, err error) {
+	id = r.Header.Get(Header)
+	p, ok := auth.PrincipalFromContext(r.Context())
+	switch {
+	case !ok:
+		// authentication is disabled, the header names the tenant
+	case p.Tenant != "":
+		if id != "" && id != p.Tenant {
+			return "", ErrTenantNotAllowed
+		}
+		id = p.Tenant
+	case id != "" && id != DefaultID && !slices.Contains(p.Roles, AdminRole):
+		return "", ErrTenantNotAllowed
+	}
+	if id == "" {
+		id = DefaultID
+	}
+	return
+}
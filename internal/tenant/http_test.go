@@ -0,0 +1,98 @@
+package tenant
+
+import (
+	"app/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTenantRequest is a function that returns a request naming the tenant, made by the principal if any
+func newTenantRequest(header string, p *auth.Principal) *http.Request {
+	r := httptest.NewRequest("GET", "/vehicles", nil)
+	if header != "" {
+		r.Header.Set(Header, header)
+	}
+	if p != nil {
+		r = r.WithContext(auth.ContextWithPrincipal(r.Context(), *p))
+	}
+	return r
+}
+
+func TestResolve(t *testing.T) {
+	editor := &auth.Principal{Subject: "ci", Roles: []string{"editor"}}
+	admin := &auth.Principal{Subject: "ops", Roles: []string{"viewer", AdminRole}}
+	bound := &auth.Principal{Subject: "acme-ci", Roles: []string{AdminRole}, Tenant: "acme"}
+
+	cases := []struct {
+		name      string
+		header    string
+		principal *auth.Principal
+		id        string
+		err       error
+	}{
+		{name: "case 1: no tenant named", principal: editor, id: DefaultID},
+		{name: "case 2: the default tenant named", header: DefaultID, principal: editor, id: DefaultID},
+		{name: "case 3: another tenant named without a tenant", header: "acme", principal: editor, err: ErrTenantNotAllowed},
+		{name: "case 4: another tenant named by a system-admin", header: "acme", principal: admin, id: "acme"},
+		{name: "case 5: the tenant of the principal", principal: bound, id: "acme"},
+		{name: "case 6: the tenant of the principal named", header: "acme", principal: bound, id: "acme"},
+		{name: "case 7: another tenant named by a principal of a tenant", header: "globex", principal: bound, err: ErrTenantNotAllowed},
+		{name: "case 8: the default tenant named by a principal of a tenant", header: DefaultID, principal: bound, err: ErrTenantNotAllowed},
+		{name: "case 9: any tenant named without authentication", header: "acme", id: "acme"},
+		{name: "case 10: no tenant named without authentication", id: DefaultID},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r := newTenantRequest(c.header, c.principal)
+
+			// act
+			id, err := Resolve(r)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			require.Equal(t, c.id, id)
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	cases := []struct {
+		name      string
+		header    string
+		principal *auth.Principal
+		status    int
+		// id is the tenant of the context of the handler
+		id string
+	}{
+		{name: "case 1: the default tenant", status: http.StatusOK, id: DefaultID},
+		{name: "case 2: a tenant named by a system-admin", header: "acme", principal: &auth.Principal{Roles: []string{AdminRole}}, status: http.StatusOK, id: "acme"},
+		{name: "case 3: a tenant not allowed", header: "acme", principal: &auth.Principal{Roles: []string{"editor"}}, status: http.StatusForbidden},
+		{name: "case 4: an unknown tenant", header: "globex", principal: &auth.Principal{Roles: []string{AdminRole}}, status: http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r, _ := newTestRegistry()
+			for _, id := range []string{DefaultID, "acme"} {
+				_, err := r.Create(id, nil)
+				require.NoError(t, err)
+			}
+			var id string
+			h := Middleware(r)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				id = FromContext(req.Context())
+			}))
+			res := httptest.NewRecorder()
+
+			// act
+			h.ServeHTTP(res, newTenantRequest(c.header, c.principal))
+
+			// assert
+			require.Equal(t, c.status, res.Code)
+			require.Equal(t, c.id, id)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package tenant
+
+import (
+	"app/internal"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrTenantNotFound is the error returned when a tenant does not exist
+	ErrTenantNotFound = errors.New("tenant not found")
+	// ErrTenantExists is the error returned when a tenant already exists
+	ErrTenantExists = errors.New("tenant already exists")
+	// ErrTenantIDInvalid is the error returned when the id of a tenant is not valid
+	ErrTenantIDInvalid = errors.New("invalid tenant id: lowercase letters, digits and dashes, up to 63")
+	// ErrSeedInvalid is the error returned when the vehicles of a seed cannot be loaded
+	ErrSeedInvalid = errors.New("invalid seed")
+)
+
+// idPattern is the pattern of the id of a tenant
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// Fleet is a struct that represents the vehicles of a tenant, with a repository and a service of its own
+// - ids, uniqueness constraints, statistics and searches never cross fleets
+type Fleet struct {
+	// ID is the id of the tenant
+	ID string
+	// Repository is the repository of the vehicles of the tenant
+	Repository internal.VehicleRepository
+	// Service is the service of the vehicles of the tenant
+	Service internal.VehicleService
+	// Cache is the cache of the service, nil if the service is not cached
+	Cache internal.Cache
+	// Checker is the checker of the aggregates of the repository
+	Checker internal.AggregateChecker
+	// Health is the health of the repository
+	Health internal.HealthChecker
+	// Version is the version of the vehicles of the repository
+	Version internal.VehicleVersioner
+}
+
+// Factory is a function that returns a new fleet of a tenant, storing the vehicles
+type Factory func(id string, db map[int]internal.Vehicle) (f *Fleet, err error)
+
+// NewRegistry is a function that returns a new instance of Registry
+func NewRegistry(factory Factory) *Registry {
+	return &Registry{factory: factory, fleets: make(map[string]*Fleet)}
+}
+
+// Registry is a struct that represents the fleets of the tenants
+type Registry struct {
+	// factory returns the fleet of a new tenant
+	factory Factory
+	// mu is the mutex that guards the fleets
+	mu sync.RWMutex
+	// fleets are the fleets by tenant id
+	fleets map[string]*Fleet
+}
+
+// Create is a method that creates the fleet of a new tenant with the vehicles
+func (r *Registry) Create(id string, db map[int]internal.Vehicle) (f *Fleet, err error) {
+	if !idPattern.MatchString(id) {
+		return nil, ErrTenantIDInvalid
+	}
+	if db == nil {
+		db = make(map[int]internal.Vehicle)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.fleets[id]; ok {
+		return nil, ErrTenantExists
+	}
+	if f, err = r.factory(id, db); err != nil {
+		return nil, err
+	}
+	r.fleets[id] = f
+	return
+}
+
+// Get is a method that returns the fleet of a tenant
+func (r *Registry) Get(id string) (f *Fleet, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.fleets[id]
+	if !ok {
+		return nil, ErrTenantNotFound
+	}
+	return
+}
+
+// FromContext is a method that returns the fleet of the tenant of the context
+func (r *Registry) FromContext(ctx context.Context) (f *Fleet, err error) {
+	return r.Get(FromContext(ctx))
+}
+
+// Fleets is a method that returns the fleets, sorted by tenant id
+func (r *Registry) Fleets() (fleets []*Fleet) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, f := range r.fleets {
+		fleets = append(fleets, f)
+	}
+	sort.Slice(fleets, func(i, j int) bool { return fleets[i].ID < fleets[j].ID })
+	return
+}
+
+// Seed is a method that adds the vehicles of the loader to the fleet of a tenant
+// - the vehicles are created one by one, so the indexes and caches of the fleet follow
+// - it stops at the first vehicle that cannot be created, e.g. its id already exists
+func (r *Registry) Seed(ctx context.Context, id string, ld internal.VehicleLoader) (n int, err error) {
+	f, err := r.Get(id)
+	if err != nil {
+		return
+	}
+	db, err := ld.Load()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrSeedInvalid, err)
+	}
+
+	// ascending ids, so a failed seed is reproducible
+	ids := make([]int, 0, len(db))
+	for vid := range db {
+		ids = append(ids, vid)
+	}
+	sort.Ints(ids)
+	for _, vid := range ids {
+		if err = f.Repository.Create(ctx, db[vid]); err != nil {
+			return n, fmt.Errorf("vehicle %d: %w", vid, err)
+		}
+		n++
+	}
+	return
+}
+
+// CheckHealth is a method that returns an error if the repository of any tenant is unhealthy
+func (r *Registry) CheckHealth(ctx context.Context) error {
+	var errs []error
+	for _, f := range r.Fleets() {
+		if err := f.Health.CheckHealth(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", f.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
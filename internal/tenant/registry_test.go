@@ -0,0 +1,142 @@
+package tenant
+
+import (
+	"app/internal"
+	"app/internal/index"
+	"app/internal/repository"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry is a function that returns a registry whose fleets are a repository observed by a text index
+// - searches are the text index of each tenant, by tenant id
+func newTestRegistry() (r *Registry, searches map[string]*index.Text) {
+	searches = make(map[string]*index.Text)
+	r = NewRegistry(func(id string, db map[int]internal.Vehicle) (f *Fleet, err error) {
+		rp := repository.NewVehicleMap(db)
+		sr := index.NewText()
+		rp.Observe(sr)
+		searches[id] = sr
+		return &Fleet{ID: id, Repository: rp, Checker: rp, Health: rp, Version: rp}, nil
+	})
+	return
+}
+
+// tenantVehicle is a function that returns a vehicle of the brand and the maximum speed
+func tenantVehicle(id int, brand string, maxSpeed float64) internal.Vehicle {
+	return internal.Vehicle{Id: id, VehicleAttributes: internal.VehicleAttributes{Brand: brand, Model: "Model", MaxSpeed: maxSpeed}}
+}
+
+func TestRegistry_Create(t *testing.T) {
+	cases := []struct {
+		name string
+		id   string
+		err  error
+	}{
+		{name: "case 1: valid id", id: "acme"},
+		{name: "case 2: digits and dashes", id: "acme-2"},
+		{name: "case 3: existing tenant", id: DefaultID, err: ErrTenantExists},
+		{name: "case 4: uppercase letters", id: "Acme", err: ErrTenantIDInvalid},
+		{name: "case 5: empty id", id: "", err: ErrTenantIDInvalid},
+		{name: "case 6: leading dash", id: "-acme", err: ErrTenantIDInvalid},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			// arrange
+			r, _ := newTestRegistry()
+			_, err := r.Create(DefaultID, nil)
+			require.NoError(t, err)
+
+			// act
+			f, err := r.Create(c.id, nil)
+
+			// assert
+			require.ErrorIs(t, err, c.err)
+			if c.err != nil {
+				require.Nil(t, f)
+				return
+			}
+			got, err := r.Get(c.id)
+			require.NoError(t, err)
+			require.Same(t, f, got)
+		})
+	}
+}
+
+func TestRegistry_Isolation(t *testing.T) {
+	// arrange
+	ctx := context.Background()
+	r, searches := newTestRegistry()
+	acme, err := r.Create("acme", map[int]internal.Vehicle{1: tenantVehicle(1, "Fiat", 100)})
+	require.NoError(t, err)
+	globex, err := r.Create("globex", nil)
+	require.NoError(t, err)
+
+	t.Run("case 1: the ids of a tenant are its own", func(t *testing.T) {
+		// act
+		err := globex.Repository.Create(ctx, tenantVehicle(1, "Ford", 200))
+
+		// assert
+		require.NoError(t, err)
+		v, err := acme.Repository.FindOne(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "Fiat", v.Brand)
+		v, err = globex.Repository.FindOne(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "Ford", v.Brand)
+	})
+
+	t.Run("case 2: the ids are unique within a tenant", func(t *testing.T) {
+		// act
+		err := acme.Repository.Create(ctx, tenantVehicle(1, "Ford", 200))
+
+		// assert
+		require.ErrorIs(t, err, internal.ErrVehicleExists)
+	})
+
+	t.Run("case 3: the statistics of a tenant count its vehicles only", func(t *testing.T) {
+		// act
+		acmeStats, err := acme.Repository.Aggregates(ctx, internal.AggregateByBrand, internal.MetricMaxSpeed)
+		require.NoError(t, err)
+		globexStats, err := globex.Repository.Aggregates(ctx, internal.AggregateByBrand, internal.MetricMaxSpeed)
+		require.NoError(t, err)
+
+		// assert
+		require.Len(t, acmeStats, 1)
+		require.Equal(t, 1, acmeStats["Fiat"].Count)
+		require.Equal(t, 100.0, acmeStats["Fiat"].Mean())
+		require.Len(t, globexStats, 1)
+		require.Equal(t, 1, globexStats["Ford"].Count)
+		require.Equal(t, 200.0, globexStats["Ford"].Mean())
+	})
+
+	t.Run("case 4: the searches of a tenant find its vehicles only", func(t *testing.T) {
+		// act
+		acmeFiat, err := searches["acme"].Search("fiat", 10)
+		require.NoError(t, err)
+		globexFiat, err := searches["globex"].Search("fiat", 10)
+		require.NoError(t, err)
+
+		// assert
+		require.Len(t, acmeFiat, 1)
+		require.Equal(t, "Fiat", acmeFiat[0].Brand)
+		require.Empty(t, globexFiat)
+	})
+
+	t.Run("case 5: a vehicle deleted from a tenant is kept by the others", func(t *testing.T) {
+		// act
+		err := acme.Repository.Delete(ctx, 1)
+
+		// assert
+		require.NoError(t, err)
+		_, err = acme.Repository.FindOne(ctx, 1)
+		require.ErrorIs(t, err, internal.ErrVehicleNotFound)
+		_, err = globex.Repository.FindOne(ctx, 1)
+		require.NoError(t, err)
+		ford, err := searches["globex"].Search("ford", 10)
+		require.NoError(t, err)
+		require.Len(t, ford, 1)
+	})
+}
@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrTraceparentInvalid is the error returned when a traceparent header is malformed
+	ErrTraceparentInvalid = errors.New("invalid traceparent")
+)
+
+// TraceID is the identifier of a trace
+type TraceID [16]byte
+
+// IsValid is a method that returns true if the id is not all zeros
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// String is a method that returns the id hex-encoded
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID is the identifier of a span within a trace
+type SpanID [8]byte
+
+// IsValid is a method that returns true if the id is not all zeros
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// String is a method that returns the id hex-encoded
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext is a struct that represents the part of a span propagated to other spans and services
+type SpanContext struct {
+	// TraceID is the trace the span belongs to
+	TraceID TraceID
+	// SpanID is the id of the span
+	SpanID SpanID
+	// Sampled is true if the span is recorded and exported
+	Sampled bool
+	// TraceState is the vendor-specific tracestate header, propagated as it is
+	TraceState string
+}
+
+// IsValid is a method that returns true if both ids are valid
+func (c SpanContext) IsValid() bool {
+	return c.TraceID.IsValid() && c.SpanID.IsValid()
+}
+
+// Traceparent is a method that returns the span context as a W3C traceparent header
+func (c SpanContext) Traceparent() string {
+	flags := "00"
+	if c.Sampled {
+		flags = "01"
+	}
+	return "00-" + c.TraceID.String() + "-" + c.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent is a function that returns the span context of a W3C traceparent header
+// - version-00 headers must have exactly four fields, later versions may append more
+func ParseTraceparent(header string) (c SpanContext, err error) {
+	fields := strings.Split(strings.TrimSpace(header), "-")
+	if len(fields) < 4 || len(fields[0]) != 2 || len(fields[1]) != 32 || len(fields[2]) != 16 || len(fields[3]) != 2 {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+
+	var version, flags [1]byte
+	if _, err = hex.Decode(version[:], []byte(fields[0])); err != nil || version[0] == 0xff {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	if version[0] == 0 && len(fields) != 4 {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	// ids must be lowercase hex
+	for _, f := range fields[:4] {
+		if strings.ToLower(f) != f {
+			return SpanContext{}, ErrTraceparentInvalid
+		}
+	}
+	if _, err = hex.Decode(c.TraceID[:], []byte(fields[1])); err != nil {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	if _, err = hex.Decode(c.SpanID[:], []byte(fields[2])); err != nil {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	if _, err = hex.Decode(flags[:], []byte(fields[3])); err != nil {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	if !c.IsValid() {
+		return SpanContext{}, ErrTraceparentInvalid
+	}
+	c.Sampled = flags[0]&0x01 == 0x01
+	return c, nil
+}
@@ -0,0 +1,197 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// Exporter is an interface that receives the recorded spans once ended
+type Exporter interface {
+	// Export exports a span of the service
+	Export(service string, span SpanData) error
+}
+
+// NewStdoutExporter is a function that returns a new instance of StdoutExporter
+func NewStdoutExporter(w io.Writer) *StdoutExporter {
+	return &StdoutExporter{enc: json.NewEncoder(w)}
+}
+
+// StdoutExporter is a struct that writes each span as a readable JSON line, e.g. to the standard output
+type StdoutExporter struct {
+	// mu is the mutex that guards the encoder
+	mu sync.Mutex
+	// enc is the encoder of the spans
+	enc *json.Encoder
+}
+
+// stdoutSpanJSON is a struct that represents a span written by StdoutExporter
+type stdoutSpanJSON struct {
+	Service      string         `json:"service"`
+	Name         string         `json:"name"`
+	Kind         string         `json:"kind"`
+	TraceID      string         `json:"trace_id"`
+	SpanID       string         `json:"span_id"`
+	ParentSpanID string         `json:"parent_span_id,omitempty"`
+	Start        string         `json:"start"`
+	DurationMs   float64        `json:"duration_ms"`
+	Attributes   map[string]any `json:"attributes,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// Export is a method that writes the span
+func (e *StdoutExporter) Export(service string, span SpanData) error {
+	data := stdoutSpanJSON{
+		Service:    service,
+		Name:       span.Name,
+		Kind:       span.Kind.String(),
+		TraceID:    span.SpanContext.TraceID.String(),
+		SpanID:     span.SpanContext.SpanID.String(),
+		Start:      span.Start.UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		DurationMs: float64(span.End.Sub(span.Start).Microseconds()) / 1000,
+	}
+	if span.Parent.IsValid() {
+		data.ParentSpanID = span.Parent.String()
+	}
+	if len(span.Attributes) > 0 {
+		data.Attributes = make(map[string]any, len(span.Attributes))
+		for _, a := range span.Attributes {
+			data.Attributes[a.Key] = a.Value
+		}
+	}
+	if span.Err != nil {
+		data.Error = span.Err.Error()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(data)
+}
+
+// NewOTLPFileExporter is a function that returns a new instance of OTLPFileExporter
+func NewOTLPFileExporter(w io.Writer) *OTLPFileExporter {
+	return &OTLPFileExporter{enc: json.NewEncoder(w)}
+}
+
+// OTLPFileExporter is a struct that writes each span as a line of the OTLP JSON file format
+// - every line is an ExportTraceServiceRequest, so the file can be replayed to any OTLP collector
+type OTLPFileExporter struct {
+	// mu is the mutex that guards the encoder
+	mu sync.Mutex
+	// enc is the encoder of the requests
+	enc *json.Encoder
+}
+
+// otlpAnyValue is a struct that represents an OTLP AnyValue, int64 values are encoded as strings
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+}
+
+// otlpKeyValue is a struct that represents an OTLP KeyValue
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+// otlpStatus is a struct that represents an OTLP Status, code 1 is ok and 2 is error
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// otlpSpan is a struct that represents an OTLP Span
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+// otlpScopeSpans is a struct that represents the OTLP spans of an instrumentation scope
+type otlpScopeSpans struct {
+	Scope struct {
+		Name string `json:"name"`
+	} `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+// otlpResourceSpans is a struct that represents the OTLP spans of a resource, i.e. the service
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+// otlpRequest is a struct that represents an OTLP ExportTraceServiceRequest
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// otlpAttribute is a function that returns the OTLP representation of an attribute
+func otlpAttribute(a Attribute) (kv otlpKeyValue) {
+	kv.Key = a.Key
+	switch v := a.Value.(type) {
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		kv.Value.IntValue = &s
+	case float64:
+		kv.Value.DoubleValue = &v
+	case bool:
+		kv.Value.BoolValue = &v
+	case string:
+		kv.Value.StringValue = &v
+	default:
+		s := ""
+		if str, ok := v.(interface{ String() string }); ok {
+			s = str.String()
+		}
+		kv.Value.StringValue = &s
+	}
+	return
+}
+
+// Export is a method that writes the span
+func (e *OTLPFileExporter) Export(service string, span SpanData) error {
+	s := otlpSpan{
+		TraceID:           span.SpanContext.TraceID.String(),
+		SpanID:            span.SpanContext.SpanID.String(),
+		TraceState:        span.SpanContext.TraceState,
+		Name:              span.Name,
+		Kind:              int(span.Kind),
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+		Status:            otlpStatus{Code: 1},
+	}
+	if span.Parent.IsValid() {
+		s.ParentSpanID = span.Parent.String()
+	}
+	for _, a := range span.Attributes {
+		s.Attributes = append(s.Attributes, otlpAttribute(a))
+	}
+	if span.Err != nil {
+		s.Status = otlpStatus{Code: 2, Message: span.Err.Error()}
+	}
+
+	var ss otlpScopeSpans
+	ss.Scope.Name = "app/internal/tracing"
+	ss.Spans = []otlpSpan{s}
+	var rs otlpResourceSpans
+	rs.Resource.Attributes = []otlpKeyValue{otlpAttribute(String("service.name", service))}
+	rs.ScopeSpans = []otlpScopeSpans{ss}
+	req := otlpRequest{ResourceSpans: []otlpResourceSpans{rs}}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.enc.Encode(req)
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Middleware is a function that returns a middleware starting a server span for every request
+// - the trace is continued from the traceparent header if valid, otherwise a new trace is started
+// - the span is named by route pattern once routed, and its traceparent is echoed in the response
+func Middleware(t *Tracer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			parent, err := ParseTraceparent(r.Header.Get("traceparent"))
+			if err == nil {
+				parent.TraceState = r.Header.Get("tracestate")
+			}
+			s := t.newSpan("HTTP "+r.Method, SpanKindServer, parent, false, []Attribute{
+				String("http.method", r.Method),
+				String("http.target", r.URL.RequestURI()),
+			})
+			s.data.SpanContext.Sampled = t.sampler.ShouldSample(parent, s.data.SpanContext.TraceID)
+			if id := middleware.GetReqID(r.Context()); id != "" {
+				s.SetAttributes(String("http.request_id", id))
+			}
+			w.Header().Set("traceparent", s.SpanContext().Traceparent())
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ContextWithSpan(r.Context(), s)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			if rc := chi.RouteContext(r.Context()); rc != nil && rc.RoutePattern() != "" {
+				s.SetName(r.Method + " " + rc.RoutePattern())
+				s.SetAttributes(String("http.route", rc.RoutePattern()))
+			}
+			s.SetAttributes(Int("http.status_code", status))
+			if status >= http.StatusInternalServerError {
+				err = fmt.Errorf("%d %s", status, http.StatusText(status))
+			} else {
+				err = nil
+			}
+			s.End(&err)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrSamplerInvalid is the error returned when a sampler is unknown or its argument is out of range
+	ErrSamplerInvalid = errors.New("invalid sampler")
+)
+
+// Sampler is an interface that decides whether a new trace, or a trace continued from another service, is recorded
+type Sampler interface {
+	// ShouldSample returns true if the span is recorded
+	// - parent is the remote parent of the span, invalid if the span starts a trace
+	ShouldSample(parent SpanContext, traceID TraceID) bool
+}
+
+// AlwaysOn is a sampler that records every trace
+type AlwaysOn struct{}
+
+// ShouldSample is a method that returns true
+func (AlwaysOn) ShouldSample(parent SpanContext, traceID TraceID) bool {
+	return true
+}
+
+// AlwaysOff is a sampler that records no trace
+type AlwaysOff struct{}
+
+// ShouldSample is a method that returns false
+func (AlwaysOff) ShouldSample(parent SpanContext, traceID TraceID) bool {
+	return false
+}
+
+// TraceIDRatio is a sampler that records a ratio of the traces, chosen deterministically by trace id
+type TraceIDRatio float64
+
+// ShouldSample is a method that returns true if the lower 63 bits of the trace id fall under the ratio
+func (r TraceIDRatio) ShouldSample(parent SpanContext, traceID TraceID) bool {
+	if r >= 1 {
+		return true
+	}
+	bound := uint64(float64(r) * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:])>>1 < bound
+}
+
+// ParentBased is a sampler that follows the decision of the remote parent, and of Root if there is none
+type ParentBased struct {
+	// Root is the sampler of the traces started by this service
+	Root Sampler
+}
+
+// ShouldSample is a method that returns the decision of the parent if valid, of the root sampler otherwise
+func (p ParentBased) ShouldSample(parent SpanContext, traceID TraceID) bool {
+	if parent.IsValid() {
+		return parent.Sampled
+	}
+	return p.Root.ShouldSample(parent, traceID)
+}
+
+// ParseSampler is a function that returns the sampler with the given name, named as in OTEL_TRACES_SAMPLER
+// - always_on, always_off, traceidratio, parentbased_always_on, parentbased_always_off or parentbased_traceidratio
+// - arg is the ratio of the traceidratio samplers, between 0 and 1
+func ParseSampler(name string, arg float64) (s Sampler, err error) {
+	if arg < 0 || arg > 1 {
+		return nil, fmt.Errorf("%w: ratio %v out of range [0, 1]", ErrSamplerInvalid, arg)
+	}
+	switch name {
+	case "always_on":
+		return AlwaysOn{}, nil
+	case "always_off":
+		return AlwaysOff{}, nil
+	case "traceidratio":
+		return TraceIDRatio(arg), nil
+	case "parentbased_always_on":
+		return ParentBased{Root: AlwaysOn{}}, nil
+	case "parentbased_always_off":
+		return ParentBased{Root: AlwaysOff{}}, nil
+	case "parentbased_traceidratio":
+		return ParentBased{Root: TraceIDRatio(arg)}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrSamplerInvalid, name)
+}
@@ -0,0 +1,172 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SpanKind is the role of a span in a trace
+type SpanKind int
+
+const (
+	// SpanKindInternal is a span of an operation within the service
+	SpanKindInternal SpanKind = 1
+	// SpanKindServer is a span of a request served by the service
+	SpanKindServer SpanKind = 2
+)
+
+// String is a method that returns the name of the kind
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindServer:
+		return "server"
+	}
+	return "internal"
+}
+
+// Attribute is a struct that represents a key-value pair describing a span
+type Attribute struct {
+	// Key is the name of the attribute (e.g. http.method)
+	Key string
+	// Value is a string, int64, float64 or bool
+	Value any
+}
+
+// String is a function that returns a string attribute
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int is a function that returns an integer attribute
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Float is a function that returns a floating-point attribute
+func Float(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool is a function that returns a boolean attribute
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData is a struct that represents a finished span, as handed to the exporters
+type SpanData struct {
+	// Name is the name of the operation
+	Name string
+	// Kind is the role of the span
+	Kind SpanKind
+	// SpanContext identifies the span
+	SpanContext SpanContext
+	// Parent is the id of the parent span, invalid for the root span of a trace
+	Parent SpanID
+	// Start is when the operation started
+	Start time.Time
+	// End is when the operation ended
+	End time.Time
+	// Attributes describe the operation
+	Attributes []Attribute
+	// Err is the error of the operation, nil if it succeeded
+	Err error
+}
+
+// Span is a struct that represents an operation of a trace
+// - spans that are not sampled still carry their context, so it is propagated, but are never exported
+type Span struct {
+	// tracer exports the span once ended, nil for a span that does nothing
+	tracer *Tracer
+
+	// mu is the mutex that guards the data
+	mu sync.Mutex
+	// data is the span being recorded
+	data SpanData
+	// ended is true once the span is ended
+	ended bool
+}
+
+// SpanContext is a method that returns the context of the span
+func (s *Span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+// IsRecording is a method that returns true if the span is exported once ended
+func (s *Span) IsRecording() bool {
+	return s.tracer != nil && s.data.SpanContext.Sampled
+}
+
+// SetName is a method that renames the span, e.g. once the route of a request is known
+func (s *Span) SetName(name string) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Name = name
+}
+
+// SetAttributes is a method that adds attributes to the span
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// End is a method that ends the span and exports it
+// - err points to the error of the operation, so it can be deferred before the error is known; nil if it cannot fail
+func (s *Span) End(err *error) {
+	if !s.IsRecording() {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.tracer.now()
+	if err != nil {
+		s.data.Err = *err
+	}
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.export(data)
+}
+
+// spanKey is the key of the current span in a context
+type spanKey struct{}
+
+// noopSpan is the span returned when the context has none
+var noopSpan = &Span{}
+
+// ContextWithSpan is a function that returns a copy of the context carrying the span
+func ContextWithSpan(ctx context.Context, s *Span) context.Context {
+	return context.WithValue(ctx, spanKey{}, s)
+}
+
+// SpanFromContext is a function that returns the current span of the context
+// - it returns a span that does nothing if the context has none, so callers never check for nil
+func SpanFromContext(ctx context.Context) *Span {
+	if s, ok := ctx.Value(spanKey{}).(*Span); ok {
+		return s
+	}
+	return noopSpan
+}
+
+// Start is a function that starts a child of the current span of the context
+// - the child follows the sampling decision of its parent
+// - if the context has no span, e.g. tracing is disabled, the returned span does nothing
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	parent := SpanFromContext(ctx)
+	if parent.tracer == nil {
+		return ctx, noopSpan
+	}
+	s := parent.tracer.newSpan(name, SpanKindInternal, parent.data.SpanContext, parent.data.SpanContext.Sampled, attrs)
+	return ContextWithSpan(ctx, s), s
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"encoding/binary"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// NewTracer is a function that returns a new instance of Tracer
+// - service is the name of the service, reported by the exporters
+// - sampler decides which traces are recorded, ParentBased{Root: AlwaysOn{}} if nil
+func NewTracer(service string, exporter Exporter, sampler Sampler) *Tracer {
+	if sampler == nil {
+		sampler = ParentBased{Root: AlwaysOn{}}
+	}
+	return &Tracer{
+		service:  service,
+		exporter: exporter,
+		sampler:  sampler,
+		now:      time.Now,
+	}
+}
+
+// Tracer is a struct that represents the source of the spans of a service
+type Tracer struct {
+	// service is the name of the service
+	service string
+	// exporter receives the recorded spans once ended
+	exporter Exporter
+	// sampler decides which traces are recorded
+	sampler Sampler
+	// now returns the current time
+	now func() time.Time
+}
+
+// newSpan is a method that returns a new started span
+// - parent is the context of the parent span, invalid to start a new trace
+func (t *Tracer) newSpan(name string, kind SpanKind, parent SpanContext, sampled bool, attrs []Attribute) *Span {
+	sc := SpanContext{TraceID: parent.TraceID, Sampled: sampled, TraceState: parent.TraceState}
+	if !sc.TraceID.IsValid() {
+		binary.BigEndian.PutUint64(sc.TraceID[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(sc.TraceID[8:], rand.Uint64()|1)
+	}
+	binary.BigEndian.PutUint64(sc.SpanID[:], rand.Uint64()|1)
+
+	return &Span{
+		tracer: t,
+		data: SpanData{
+			Name:        name,
+			Kind:        kind,
+			SpanContext: sc,
+			Parent:      parent.SpanID,
+			Start:       t.now(),
+			Attributes:  attrs,
+		},
+	}
+}
+
+// export is a method that hands a span to the exporter
+func (t *Tracer) export(data SpanData) {
+	if t.exporter == nil {
+		return
+	}
+	if err := t.exporter.Export(t.service, data); err != nil {
+		slog.Warn("span not exported", slog.String("span", data.Name), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,136 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+// The domain model always stores the attributes of a vehicle in metric units:
+// - MaxSpeed in kilometers per hour (km/h)
+// - Weight in kilograms (kg)
+// - Dimensions in centimeters (cm)
+const (
+	// MilesPerHourToKilometersPerHour is the factor to convert mph to km/h
+	MilesPerHourToKilometersPerHour = 1.609344
+	// PoundsToKilograms is the factor to convert lb to kg
+	PoundsToKilograms = 0.45359237
+	// InchesToCentimeters is the factor to convert in to cm
+	InchesToCentimeters = 2.54
+)
+
+var (
+	// ErrUnitSystemInvalid is the error returned when a unit system is not supported
+	ErrUnitSystemInvalid = errors.New("invalid unit system")
+)
+
+// UnitSystem is a type that represents the unit system used to express the attributes of a vehicle
+type UnitSystem string
+
+const (
+	// UnitSystemMetric is the unit system used by the domain model (km/h, kg, cm)
+	UnitSystemMetric UnitSystem = "metric"
+	// UnitSystemImperial is the imperial unit system (mph, lb, in)
+	UnitSystemImperial UnitSystem = "imperial"
+)
+
+// ParseUnitSystem is a function that returns the unit system for the given name
+// - an empty name defaults to the metric system
+func ParseUnitSystem(name string) (u UnitSystem, err error) {
+	switch UnitSystem(strings.ToLower(strings.TrimSpace(name))) {
+	case "", UnitSystemMetric:
+		u = UnitSystemMetric
+	case UnitSystemImperial:
+		u = UnitSystemImperial
+	default:
+		err = ErrUnitSystemInvalid
+	}
+	return
+}
+
+// SpeedFromMetric is a method that converts a speed in km/h to the unit system
+func (u UnitSystem) SpeedFromMetric(speed float64) float64 {
+	if u == UnitSystemImperial {
+		return speed / MilesPerHourToKilometersPerHour
+	}
+	return speed
+}
+
+// SpeedToMetric is a method that converts a speed in the unit system to km/h
+func (u UnitSystem) SpeedToMetric(speed float64) float64 {
+	if u == UnitSystemImperial {
+		return speed * MilesPerHourToKilometersPerHour
+	}
+	return speed
+}
+
+// WeightFromMetric is a method that converts a weight in kg to the unit system
+func (u UnitSystem) WeightFromMetric(weight float64) float64 {
+	if u == UnitSystemImperial {
+		return weight / PoundsToKilograms
+	}
+	return weight
+}
+
+// WeightToMetric is a method that converts a weight in the unit system to kg
+func (u UnitSystem) WeightToMetric(weight float64) float64 {
+	if u == UnitSystemImperial {
+		return weight * PoundsToKilograms
+	}
+	return weight
+}
+
+// LengthFromMetric is a method that converts a length in cm to the unit system
+func (u UnitSystem) LengthFromMetric(length float64) float64 {
+	if u == UnitSystemImperial {
+		return length / InchesToCentimeters
+	}
+	return length
+}
+
+// LengthToMetric is a method that converts a length in the unit system to cm
+func (u UnitSystem) LengthToMetric(length float64) float64 {
+	if u == UnitSystemImperial {
+		return length * InchesToCentimeters
+	}
+	return length
+}
+
+// AreaFromMetric is a method that converts an area in cm² to the unit system
+func (u UnitSystem) AreaFromMetric(area float64) float64 {
+	return u.LengthFromMetric(u.LengthFromMetric(area))
+}
+
+// AreaToMetric is a method that converts an area in the unit system to cm²
+func (u UnitSystem) AreaToMetric(area float64) float64 {
+	return u.LengthToMetric(u.LengthToMetric(area))
+}
+
+// VolumeFromMetric is a method that converts a volume in cm³ to the unit system
+func (u UnitSystem) VolumeFromMetric(volume float64) float64 {
+	return u.LengthFromMetric(u.AreaFromMetric(volume))
+}
+
+// VolumeToMetric is a method that converts a volume in the unit system to cm³
+func (u UnitSystem) VolumeToMetric(volume float64) float64 {
+	return u.LengthToMetric(u.AreaToMetric(volume))
+}
+
+// VehicleFromMetric is a method that returns a copy of the vehicle with its attributes expressed in the unit system
+func (u UnitSystem) VehicleFromMetric(v Vehicle) Vehicle {
+	v.MaxSpeed = u.SpeedFromMetric(v.MaxSpeed)
+	v.Weight = u.WeightFromMetric(v.Weight)
+	v.Height = u.LengthFromMetric(v.Height)
+	v.Length = u.LengthFromMetric(v.Length)
+	v.Width = u.LengthFromMetric(v.Width)
+	return v
+}
+
+// VehicleToMetric is a method that returns a copy of the vehicle with its attributes expressed in metric units
+func (u UnitSystem) VehicleToMetric(v Vehicle) Vehicle {
+	v.MaxSpeed = u.SpeedToMetric(v.MaxSpeed)
+	v.Weight = u.WeightToMetric(v.Weight)
+	v.Height = u.LengthToMetric(v.Height)
+	v.Length = u.LengthToMetric(v.Length)
+	v.Width = u.LengthToMetric(v.Width)
+	return v
+}
@@ -1,46 +1,89 @@
-package internal
-
-// Dimensions is a struct that represents a dimension in 3d
-type Dimensions struct {
-	// Height is the height of the dimension
-	Height float64
-	// Length is the length of the dimension
-	Length float64
-	// Width is the width of the dimension
-	Width float64
-}
-
-// VehicleAttributes is a struct that represents the attributes of a vehicle
-type VehicleAttributes struct {
-	// Brand is the brand of the vehicle
-	Brand string
-	// Model is the model of the vehicle
-	Model string
-	// Registration is the registration of the vehicle
-	Registration string
-	// Color is the color of the vehicle
-	Color string
-	// FabricationYear is the fabrication year of the vehicle
-	FabricationYear int
-	// Capacity is the capacity of people of the vehicle
-	Capacity int
-	// MaxSpeed is the maximum speed of the vehicle
-	MaxSpeed float64
-	// FuelType is the fuel type of the vehicle
-	FuelType string
-	// Transmission is the transmission of the vehicle
-	Transmission string
-	// Weight is the weight of the vehicle
-	Weight float64
-	// Dimensions is the dimensions of the vehicle
-	Dimensions
-}
-
-// Vehicle is a struct that represents a vehicle
-type Vehicle struct {
-	// Id is the unique identifier of the vehicle
-	Id int
-
-	// VehicleAttribue is the attributes of a vehicle
-	VehicleAttributes
-}
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrVehicleInvalid is the error returned when the attributes of a new vehicle are not valid
+	ErrVehicleInvalid = errors.New("invalid vehicle")
+)
+
+// Dimensions is a struct that represents a dimension in 3d, in centimeters (cm)
+type Dimensions struct {
+	// Height is the height of the dimension
+	Height float64
+	// Length is the length of the dimension
+	Length float64
+	// Width is the width of the dimension
+	Width float64
+}
+
+// Footprint is a method that returns the area covered by the dimension on the ground (length x width), in cm²
+func (d Dimensions) Footprint() float64 {
+	return d.Length * d.Width
+}
+
+// Volume is a method that returns the volume of the dimension (height x length x width), in cm³
+func (d Dimensions) Volume() float64 {
+	return d.Height * d.Length * d.Width
+}
+
+// VehicleAttributes is a struct that represents the attributes of a vehicle
+type VehicleAttributes struct {
+	// Brand is the brand of the vehicle
+	Brand string
+	// Model is the model of the vehicle
+	Model string
+	// Registration is the registration of the vehicle
+	Registration string
+	// Color is the color of the vehicle
+	Color string
+	// FabricationYear is the fabrication year of the vehicle
+	FabricationYear int
+	// Capacity is the capacity of people of the vehicle
+	Capacity int
+	// MaxSpeed is the maximum speed of the vehicle in kilometers per hour (km/h)
+	MaxSpeed float64
+	// FuelType is the fuel type of the vehicle
+	FuelType string
+	// Transmission is the transmission of the vehicle
+	Transmission string
+	// Weight is the weight of the vehicle in kilograms (kg)
+	Weight float64
+	// Dimensions is the dimensions of the vehicle
+	Dimensions
+}
+
+// Vehicle is a struct that represents a vehicle
+type Vehicle struct {
+	// Id is the unique identifier of the vehicle
+	Id int
+
+	// VehicleAttribue is the attributes of a vehicle
+	VehicleAttributes
+}
+
+// Validate is a method that validates a new vehicle, the error names the first invalid attribute
+func (v Vehicle) Validate() (err error) {
+	reason := ""
+	switch {
+	case v.Id <= 0:
+		reason = "id must be positive"
+	case strings.TrimSpace(v.Brand) == "":
+		reason = "brand is required"
+	case strings.TrimSpace(v.Model) == "":
+		reason = "model is required"
+	case v.FabricationYear < 0:
+		reason = "year must not be negative"
+	case v.Capacity < 0:
+		reason = "passengers must not be negative"
+	case v.MaxSpeed < 0 || v.Weight < 0 || v.Height < 0 || v.Length < 0 || v.Width < 0:
+		reason = "max_speed, weight, height, length and width must not be negative"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrVehicleInvalid, reason)
+}
@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"context"
+	"math"
+)
+
+// AggregateGroup is a type that represents the attribute used to group vehicles in aggregates
+type AggregateGroup string
+
+const (
+	// AggregateByBrand groups the vehicles by brand
+	AggregateByBrand AggregateGroup = "brand"
+	// AggregateByFuelType groups the vehicles by fuel type
+	AggregateByFuelType AggregateGroup = "fuel_type"
+	// AggregateByYear groups the vehicles by fabrication year
+	AggregateByYear AggregateGroup = "year"
+)
+
+// AggregateGroups are the groups maintained by the repositories
+var AggregateGroups = []AggregateGroup{AggregateByBrand, AggregateByFuelType, AggregateByYear}
+
+// AggregateMetric is a type that represents the numeric attribute aggregated
+type AggregateMetric string
+
+const (
+	// MetricMaxSpeed is the maximum speed of the vehicles
+	MetricMaxSpeed AggregateMetric = "max_speed"
+	// MetricCapacity is the capacity of people of the vehicles
+	MetricCapacity AggregateMetric = "capacity"
+	// MetricWeight is the weight of the vehicles
+	MetricWeight AggregateMetric = "weight"
+)
+
+// AggregateMetrics are the metrics maintained by the repositories
+var AggregateMetrics = []AggregateMetric{MetricMaxSpeed, MetricCapacity, MetricWeight}
+
+// Value is a method that returns the value of the metric for the vehicle
+func (m AggregateMetric) Value(v Vehicle) float64 {
+	switch m {
+	case MetricMaxSpeed:
+		return v.MaxSpeed
+	case MetricCapacity:
+		return float64(v.Capacity)
+	case MetricWeight:
+		return v.Weight
+	}
+	return 0
+}
+
+// Aggregate is a struct that represents the statistics of a metric over a group of vehicles
+type Aggregate struct {
+	// Count is the number of vehicles
+	Count int
+	// Sum is the sum of the values
+	Sum float64
+	// SumSquares is the sum of the squares of the values
+	SumSquares float64
+	// Min is the lowest value
+	Min float64
+	// Max is the highest value
+	Max float64
+}
+
+// Mean is a method that returns the mean of the values, zero if there are none
+func (a Aggregate) Mean() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	return a.Sum / float64(a.Count)
+}
+
+// Variance is a method that returns the population variance of the values, zero if there are none
+func (a Aggregate) Variance() float64 {
+	if a.Count == 0 {
+		return 0
+	}
+	mean := a.Mean()
+	variance := a.SumSquares/float64(a.Count) - mean*mean
+	if variance < 0 {
+		// rounding errors
+		return 0
+	}
+	return variance
+}
+
+// StdDev is a method that returns the population standard deviation of the values, zero if there are none
+func (a Aggregate) StdDev() float64 {
+	return math.Sqrt(a.Variance())
+}
+
+// AggregateMismatch is a struct that represents an aggregate that differs from its full recompute
+type AggregateMismatch struct {
+	// Group is the attribute used to group the vehicles
+	Group AggregateGroup
+	// Key is the value of the group (e.g. the brand)
+	Key string
+	// Metric is the aggregated attribute
+	Metric AggregateMetric
+	// Expected is the aggregate computed from scratch
+	Expected Aggregate
+	// Actual is the aggregate maintained incrementally
+	Actual Aggregate
+}
+
+// AggregateChecker is an interface that represents a checker of the consistency of the aggregates
+type AggregateChecker interface {
+	// CheckAggregates is a method that compares the maintained aggregates against a full recompute
+	CheckAggregates(ctx context.Context) (m []AggregateMismatch, err error)
+}
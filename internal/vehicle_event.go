@@ -0,0 +1,44 @@
+package internal
+
+import "context"
+
+// VehicleEventType is a type that represents the kind of change made to a vehicle
+type VehicleEventType string
+
+const (
+	// VehicleCreated is the event of a vehicle added to the repository
+	VehicleCreated VehicleEventType = "created"
+	// VehicleUpdated is the event of a vehicle modified in the repository
+	VehicleUpdated VehicleEventType = "updated"
+	// VehicleDeleted is the event of a vehicle removed from the repository
+	VehicleDeleted VehicleEventType = "deleted"
+)
+
+// VehicleEvent is a struct that represents a change made to a vehicle in the repository
+type VehicleEvent struct {
+	// Type is the kind of change
+	Type VehicleEventType
+	// Vehicle is the vehicle after the change (zero value if deleted)
+	Vehicle Vehicle
+	// Previous is the vehicle before the change (zero value if created)
+	Previous Vehicle
+}
+
+// VehicleVersioner is an interface that represents the version of the vehicles of a repository
+type VehicleVersioner interface {
+	// Version is a method that returns the number of changes made to the vehicles, increased by every change
+	Version() uint64
+}
+
+// VehicleWatcher is an interface that represents a feed of the changes made to the vehicles
+type VehicleWatcher interface {
+	// Watch is a method that returns the changes made to the vehicles from now on
+	// - the channel is closed when the context is done, or when the reader falls behind
+	Watch(ctx context.Context) <-chan VehicleEvent
+}
+
+// VehicleObserver is an interface that represents an observer of the changes made to the vehicles
+type VehicleObserver interface {
+	// OnVehicleEvent is a method that is called after every change made to a vehicle
+	OnVehicleEvent(e VehicleEvent)
+}
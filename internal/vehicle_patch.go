@@ -0,0 +1,62 @@
+package internal
+
+// VehiclePatch is a struct that represents a partial update of the attributes of a vehicle
+// - nil fields are left as they are
+type VehiclePatch struct {
+	// Brand is the brand of the vehicle
+	Brand *string
+	// Model is the model of the vehicle
+	Model *string
+	// Registration is the registration of the vehicle
+	Registration *string
+	// Color is the color of the vehicle
+	Color *string
+	// FabricationYear is the fabrication year of the vehicle
+	FabricationYear *int
+	// Capacity is the capacity of people of the vehicle
+	Capacity *int
+	// MaxSpeed is the maximum speed of the vehicle in kilometers per hour (km/h)
+	MaxSpeed *float64
+	// FuelType is the fuel type of the vehicle
+	FuelType *string
+	// Transmission is the transmission of the vehicle
+	Transmission *string
+	// Weight is the weight of the vehicle in kilograms (kg)
+	Weight *float64
+	// Height is the height of the vehicle in centimeters (cm)
+	Height *float64
+	// Length is the length of the vehicle in centimeters (cm)
+	Length *float64
+	// Width is the width of the vehicle in centimeters (cm)
+	Width *float64
+}
+
+// Empty is a method that returns true if the patch changes no attribute
+func (p VehiclePatch) Empty() bool {
+	return p == VehiclePatch{}
+}
+
+// Apply is a method that returns a copy of the vehicle with the patch applied
+func (p VehiclePatch) Apply(v Vehicle) Vehicle {
+	set(&v.Brand, p.Brand)
+	set(&v.Model, p.Model)
+	set(&v.Registration, p.Registration)
+	set(&v.Color, p.Color)
+	set(&v.FabricationYear, p.FabricationYear)
+	set(&v.Capacity, p.Capacity)
+	set(&v.MaxSpeed, p.MaxSpeed)
+	set(&v.FuelType, p.FuelType)
+	set(&v.Transmission, p.Transmission)
+	set(&v.Weight, p.Weight)
+	set(&v.Height, p.Height)
+	set(&v.Length, p.Length)
+	set(&v.Width, p.Width)
+	return v
+}
+
+// set is a function that sets the target to the value, if present
+func set[T any](target *T, value *T) {
+	if value != nil {
+		*target = *value
+	}
+}
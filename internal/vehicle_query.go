@@ -0,0 +1,187 @@
+package internal
+
+import (
+	"cmp"
+	"errors"
+	"sort"
+)
+
+var (
+	// ErrRangeInvalid is the error returned when a range is malformed
+	ErrRangeInvalid = errors.New("invalid range")
+	// ErrSortFieldInvalid is the error returned when a field can not be used to sort
+	ErrSortFieldInvalid = errors.New("invalid sort field")
+)
+
+// Range is a struct that represents an open or closed range of values
+type Range struct {
+	// Min is the lower bound of the range (inclusive), nil if the range is open below
+	Min *float64
+	// Max is the upper bound of the range (inclusive), nil if the range is open above
+	Max *float64
+}
+
+// Validate is a method that validates the range
+func (r Range) Validate() (err error) {
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		err = ErrRangeInvalid
+	}
+	return
+}
+
+// Bounded is a method that returns true if the range has at least one bound
+func (r Range) Bounded() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// Contains is a method that returns true if the value is within the range
+func (r Range) Contains(value float64) bool {
+	if r.Min != nil && value < *r.Min {
+		return false
+	}
+	if r.Max != nil && value > *r.Max {
+		return false
+	}
+	return true
+}
+
+// Map is a method that returns a copy of the range with fn applied to its bounds
+// - used to convert a range between unit systems
+func (r Range) Map(fn func(float64) float64) (m Range) {
+	if r.Min != nil {
+		min := fn(*r.Min)
+		m.Min = &min
+	}
+	if r.Max != nil {
+		max := fn(*r.Max)
+		m.Max = &max
+	}
+	return
+}
+
+// DimensionsQuery is a struct that represents a search of vehicles by their dimensions
+type DimensionsQuery struct {
+	// Height is the range of the height of the vehicle
+	Height Range
+	// Length is the range of the length of the vehicle
+	Length Range
+	// Width is the range of the width of the vehicle
+	Width Range
+	// Footprint is the range of the footprint of the vehicle
+	Footprint Range
+	// Volume is the range of the volume of the vehicle
+	Volume Range
+}
+
+// Validate is a method that validates the query
+func (q DimensionsQuery) Validate() (err error) {
+	for _, r := range []Range{q.Height, q.Length, q.Width, q.Footprint, q.Volume} {
+		if err = r.Validate(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Match is a method that returns true if the dimensions satisfy every range of the query
+func (q DimensionsQuery) Match(d Dimensions) bool {
+	return q.Height.Contains(d.Height) &&
+		q.Length.Contains(d.Length) &&
+		q.Width.Contains(d.Width) &&
+		q.Footprint.Contains(d.Footprint()) &&
+		q.Volume.Contains(d.Volume())
+}
+
+// VehicleQuery is a struct that represents a search of vehicles by their attributes
+// - empty strings and open ranges match every vehicle
+type VehicleQuery struct {
+	// Brand is the brand of the vehicle
+	Brand string
+	// Color is the color of the vehicle
+	Color string
+	// FuelType is the fuel type of the vehicle
+	FuelType string
+	// Transmission is the transmission of the vehicle
+	Transmission string
+	// FabricationYear is the range of the fabrication year of the vehicle
+	FabricationYear Range
+	// MaxSpeed is the range of the maximum speed of the vehicle
+	MaxSpeed Range
+	// Weight is the range of the weight of the vehicle
+	Weight Range
+	// Height is the range of the height of the vehicle
+	Height Range
+	// Length is the range of the length of the vehicle
+	Length Range
+	// Width is the range of the width of the vehicle
+	Width Range
+	// Footprint is the range of the footprint of the vehicle
+	Footprint Range
+	// Volume is the range of the volume of the vehicle
+	Volume Range
+}
+
+// Match is a method that returns true if the vehicle satisfies every constraint of the query
+func (q VehicleQuery) Match(v Vehicle) bool {
+	return (q.Brand == "" || v.Brand == q.Brand) &&
+		(q.Color == "" || v.Color == q.Color) &&
+		(q.FuelType == "" || v.FuelType == q.FuelType) &&
+		(q.Transmission == "" || v.Transmission == q.Transmission) &&
+		q.FabricationYear.Contains(float64(v.FabricationYear)) &&
+		q.MaxSpeed.Contains(v.MaxSpeed) &&
+		q.Weight.Contains(v.Weight) &&
+		q.Height.Contains(v.Height) &&
+		q.Length.Contains(v.Length) &&
+		q.Width.Contains(v.Width) &&
+		q.Footprint.Contains(v.Footprint()) &&
+		q.Volume.Contains(v.Volume())
+}
+
+// VehicleSorters are the comparisons of the vehicles by the fields they can be sorted by, named as in the API
+var VehicleSorters = map[string]func(a, b Vehicle) int{
+	"id":         func(a, b Vehicle) int { return cmp.Compare(a.Id, b.Id) },
+	"brand":      func(a, b Vehicle) int { return cmp.Compare(a.Brand, b.Brand) },
+	"model":      func(a, b Vehicle) int { return cmp.Compare(a.Model, b.Model) },
+	"year":       func(a, b Vehicle) int { return cmp.Compare(a.FabricationYear, b.FabricationYear) },
+	"passengers": func(a, b Vehicle) int { return cmp.Compare(a.Capacity, b.Capacity) },
+	"max_speed":  func(a, b Vehicle) int { return cmp.Compare(a.MaxSpeed, b.MaxSpeed) },
+	"weight":     func(a, b Vehicle) int { return cmp.Compare(a.Weight, b.Weight) },
+	"height":     func(a, b Vehicle) int { return cmp.Compare(a.Height, b.Height) },
+	"length":     func(a, b Vehicle) int { return cmp.Compare(a.Length, b.Length) },
+	"width":      func(a, b Vehicle) int { return cmp.Compare(a.Width, b.Width) },
+	"footprint":  func(a, b Vehicle) int { return cmp.Compare(a.Footprint(), b.Footprint()) },
+	"volume":     func(a, b Vehicle) int { return cmp.Compare(a.Volume(), b.Volume()) },
+}
+
+// SortVehicles is a function that returns the vehicles of a map sorted by the field, ties are broken by id
+// - an empty field sorts by id
+func SortVehicles(v map[int]Vehicle, field string, descending bool) (sorted []Vehicle, err error) {
+	if field == "" {
+		field = "id"
+	}
+	compare, ok := VehicleSorters[field]
+	if !ok {
+		return nil, ErrSortFieldInvalid
+	}
+
+	sorted = make([]Vehicle, 0, len(v))
+	for _, value := range v {
+		sorted = append(sorted, value)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		c := compare(sorted[i], sorted[j])
+		if descending {
+			c = -c
+		}
+		if c == 0 {
+			return sorted[i].Id < sorted[j].Id
+		}
+		return c < 0
+	})
+	return
+}
+
+// NewRange is a function that returns the closed range [min, max]
+func NewRange(min, max float64) Range {
+	return Range{Min: &min, Max: &max}
+}
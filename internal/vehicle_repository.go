@@ -1,11 +1,29 @@
-package internal
-
-// VehicleRepository is an interface that represents a vehicle repository
-type VehicleRepository interface {
-	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
-	FindOne(id int) (v Vehicle, err error)
-	Create(v Vehicle) (err error)
-	Update(id int, v Vehicle) (err error)
-	Delete(id int) (err error)
-}
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrVehicleNotFound is the error returned when there is no vehicle with the given id
+	ErrVehicleNotFound = errors.New("not found")
+	// ErrVehicleExists is the error returned when the id of a new vehicle is already used
+	ErrVehicleExists = errors.New("identificador do veículo já existente")
+)
+
+// VehicleRepository is an interface that represents a vehicle repository
+type VehicleRepository interface {
+	// FindAll is a method that returns a map of all vehicles
+	FindAll(ctx context.Context) (v map[int]Vehicle, err error)
+	FindOne(ctx context.Context, id int) (v Vehicle, err error)
+	Create(ctx context.Context, v Vehicle) (err error)
+	Update(ctx context.Context, id int, v Vehicle) (err error)
+	Delete(ctx context.Context, id int) (err error)
+	// Query is a method that returns the vehicles matching the query, using the most selective index available
+	Query(ctx context.Context, q VehicleQuery) (v map[int]Vehicle, err error)
+	// Aggregate is a method that returns the statistics of the metric over the vehicles of a group
+	Aggregate(ctx context.Context, group AggregateGroup, key string, metric AggregateMetric) (a Aggregate, err error)
+	// Aggregates is a method that returns the statistics of the metric for every key of a group
+	Aggregates(ctx context.Context, group AggregateGroup, metric AggregateMetric) (a map[string]Aggregate, err error)
+}
@@ -0,0 +1,15 @@
+package internal
+
+// VehicleSearchResult is a struct that represents a vehicle found by a text search
+type VehicleSearchResult struct {
+	// Vehicle is the vehicle found
+	Vehicle
+	// Score is the relevance of the vehicle for the search, higher is more relevant
+	Score float64
+}
+
+// VehicleSearcher is an interface that represents a full-text index of vehicles
+type VehicleSearcher interface {
+	// Search is a method that returns up to limit vehicles matching the text, sorted by relevance
+	Search(text string, limit int) (v []VehicleSearchResult, err error)
+}
@@ -1,20 +1,37 @@
-package internal
-
-// VehicleService is an interface that represents a vehicle service
-type VehicleService interface {
-	// FindAll is a method that returns a map of all vehicles
-	FindAll() (v map[int]Vehicle, err error)
-	Create(v Vehicle) (err error)
-	GetVehiclesByColorYear(color, year string) (v map[int]Vehicle, err error)
-	GetVehiclesByBrandYears(brand, startYear, endYear string) (v map[int]Vehicle, err error)
-	GetAverageSpeedByBrand(brand string) (speed float64, err error)
-	CreateVehicles(vehicles []Vehicle) (err error)
-	UpdateVehicleSpeed(id int, newSpeed float64) (err error)
-	GetVehicleByFuelType(fuelType string) (v map[int]Vehicle, err error)
-	DeleteVehicle(id int) (err error)
-	GetByTransmissionType(transmissionType string) (v map[int]Vehicle, err error)
-	UpdateFuelType(id int, fuelType string) (err error)
-	GetAverageCapacityByBrand(brand string) (averageCapacity int, err error)
-	GetByDimensions(minLengthFloat, maxLengthFloat, minWidthFloat, maxWidthFloat float64) (v map[int]Vehicle, err error)
-	GetByWeight(minWeigthFloat, maxWeigthFloat float64) (v map[int]Vehicle, err error)
-}
+package internal
+
+import "context"
+
+// VehicleService is an interface that represents a vehicle service
+type VehicleService interface {
+	// FindAll is a method that returns a map of all vehicles
+	FindAll(ctx context.Context) (v map[int]Vehicle, err error)
+	Create(ctx context.Context, v Vehicle) (err error)
+	GetVehiclesByColorYear(ctx context.Context, color, year string) (v map[int]Vehicle, err error)
+	GetVehiclesByBrandYears(ctx context.Context, brand, startYear, endYear string) (v map[int]Vehicle, err error)
+	GetAverageSpeedByBrand(ctx context.Context, brand string) (speed float64, err error)
+	CreateVehicles(ctx context.Context, vehicles []Vehicle) (err error)
+	UpdateVehicleSpeed(ctx context.Context, id int, newSpeed float64) (err error)
+	GetVehicleByFuelType(ctx context.Context, fuelType string) (v map[int]Vehicle, err error)
+	DeleteVehicle(ctx context.Context, id int) (err error)
+	GetByTransmissionType(ctx context.Context, transmissionType string) (v map[int]Vehicle, err error)
+	UpdateFuelType(ctx context.Context, id int, fuelType string) (err error)
+	GetAverageCapacityByBrand(ctx context.Context, brand string) (averageCapacity int, err error)
+	GetByDimensions(ctx context.Context, query DimensionsQuery) (v []Vehicle, err error)
+	GetByWeight(ctx context.Context, minWeigthFloat, maxWeigthFloat float64) (v map[int]Vehicle, err error)
+	// GetSimilarVehicles is a method that returns the k vehicles most similar to the vehicle with the given id
+	GetSimilarVehicles(ctx context.Context, id int, k int) (v []SimilarVehicle, err error)
+	// SearchVehicles is a method that returns up to limit vehicles matching the text, sorted by relevance
+	SearchVehicles(ctx context.Context, text string, limit int) (v []VehicleSearchResult, err error)
+	// FindOne is a method that returns the vehicle with the given id
+	FindOne(ctx context.Context, id int) (v Vehicle, err error)
+	// Query is a method that returns the vehicles matching the query, an empty map if there are none
+	Query(ctx context.Context, q VehicleQuery) (v map[int]Vehicle, err error)
+	// Patch is a method that applies the patch to the vehicle with the given id and returns the patched vehicle
+	// - the error is ErrVehicleInvalid if the patched vehicle is not valid
+	Patch(ctx context.Context, id int, p VehiclePatch) (v Vehicle, err error)
+	// GetAggregates is a method that returns the statistics of the metric for every key of a group
+	GetAggregates(ctx context.Context, group AggregateGroup, metric AggregateMetric) (a map[string]Aggregate, err error)
+	// WatchVehicles is a method that returns the changes made to the vehicles from now on, until the context is done
+	WatchVehicles(ctx context.Context) (events <-chan VehicleEvent, err error)
+}
@@ -0,0 +1,15 @@
+package internal
+
+// SimilarVehicle is a struct that represents a vehicle and its distance to a reference vehicle
+type SimilarVehicle struct {
+	// Vehicle is the similar vehicle
+	Vehicle
+	// Distance is the weighted distance to the reference vehicle, lower is more similar
+	Distance float64
+}
+
+// VehicleSimilarity is an interface that represents an index of similar vehicles
+type VehicleSimilarity interface {
+	// Nearest is a method that returns the k vehicles closest to the vehicle with the given id
+	Nearest(id int, k int) (v []SimilarVehicle, err error)
+}